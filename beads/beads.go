@@ -91,6 +91,7 @@ func (m *Manager) GetIssueSummary(branch string) (IssueSummary, error) {
 	summary := IssueSummary{}
 	for _, issue := range issues {
 		summary.TotalCount++
+		summary.Titles = append(summary.Titles, issue.Title)
 		if issue.Status == "open" || issue.Status == "in_progress" {
 			summary.OpenCount++
 		} else if issue.Status == "closed" {
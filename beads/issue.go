@@ -20,4 +20,5 @@ type IssueSummary struct {
 	OpenCount   int
 	ClosedCount int
 	TotalCount  int
+	Titles      []string // Titles of all matching issues (used for searching)
 }
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/coollabsio/jean-tui/openai"
 )
 
@@ -42,8 +46,47 @@ type Config struct {
 	AIPrompts           *AIPrompts             `json:"ai_prompts,omitempty"` // Customizable AI prompts
 	WrapperChecksums    map[string]string      `json:"wrapper_checksums,omitempty"` // Shell -> SHA256 checksum of installed wrapper
 	Onboarded           bool                   `json:"onboarded"` // Whether the user has completed the onboarding flow
+	Agents              map[string]*AgentConfig `json:"agents,omitempty"` // name -> AI coding agent profile
+	DefaultAgent        string                 `json:"default_agent,omitempty"` // Agent profile for repositories without one, "" = claude
+	Notifications       *NotificationConfig    `json:"notifications,omitempty"` // How to notify when an agent needs input
+	Multiplexer         string                 `json:"multiplexer,omitempty"` // Terminal multiplexer hosting sessions ("tmux" or "zellij"), "" = tmux
+}
+
+// Multiplexers are the supported terminal multiplexers, the first one is the default
+var Multiplexers = []string{"tmux", "zellij"}
+
+// MergedPRCleanupPolicies are the supported policies for worktrees whose PR merged on GitHub
+// "ask" offers the cleanup, "auto" runs it and "off" leaves the worktree alone
+var MergedPRCleanupPolicies = []string{"ask", "auto", "off"}
+
+// NotificationConfig controls the notifications sent when an agent session needs input
+type NotificationConfig struct {
+	Methods          []string `json:"methods,omitempty"`            // "bell", "osc9", "osc777", "notify-send" and/or "command", default = bell
+	Command          string   `json:"command,omitempty"`            // Shell command for the "command" method, supports {title}, {message}, {repo} and {branch}
+	RateLimitSeconds int      `json:"rate_limit_seconds,omitempty"` // Minimum seconds between notifications for the same worktree, 0 = use default (60s)
+}
+
+// NotificationMethods are the supported notification methods
+var NotificationMethods = []string{"bell", "osc9", "osc777", "notify-send", "command"}
+
+// DefaultNotificationRateLimit is the default minimum time between notifications for a worktree, in seconds
+const DefaultNotificationRateLimit = 60
+
+// AgentConfig is an AI coding agent profile (duplicated from session package for JSON serialization)
+// The profile name is used as the tmux window name
+type AgentConfig struct {
+	Command         string   `json:"command"`                    // Executable to run (e.g. "codex", "aider")
+	Args            string   `json:"args,omitempty"`             // Argument template, supports {worktree}, {branch} and {repo}
+	ResumeFlag      string   `json:"resume_flag,omitempty"`      // Flag that resumes the previous conversation
+	PermissionFlag  string   `json:"permission_flag,omitempty"`  // Flag used to pass the permission mode
+	PermissionMode  string   `json:"permission_mode,omitempty"`  // Permission mode value
+	PromptArg       string   `json:"prompt_arg,omitempty"`       // Argument template giving a fresh agent its first prompt, {prompt} is replaced
+	WaitingPatterns []string `json:"waiting_patterns,omitempty"` // Screen text meaning the agent waits for input
 }
 
+// DefaultAgentName is the built-in agent used when no profile is chosen
+const DefaultAgentName = "claude"
+
 // PRInfo represents information about a pull request
 type PRInfo struct {
 	URL       string `json:"url"`
@@ -53,6 +96,17 @@ type PRInfo struct {
 	PRNumber  int    `json:"pr_number,omitempty"` // GitHub PR number (e.g., 42 from github.com/owner/repo/pull/42)
 	Title     string `json:"title,omitempty"`     // PR title for display
 	Author    string `json:"author,omitempty"`    // Author login for display
+	PRDetails                                       // Review, CI and merge state, refreshed from GitHub
+}
+
+// PRDetails holds the review, CI and merge state of a pull request shown as badges
+type PRDetails struct {
+	Draft             bool     `json:"draft,omitempty"`
+	Checks            string   `json:"checks,omitempty"`             // "passing", "failing", "pending", "" = no checks
+	ReviewDecision    string   `json:"review_decision,omitempty"`    // "approved", "changes_requested", "review_required", ""
+	Reviewers         []string `json:"reviewers,omitempty"`          // Requested reviewers who haven't reviewed yet
+	Mergeable         string   `json:"mergeable,omitempty"`          // "mergeable", "conflicting", "" = unknown
+	UnresolvedThreads int      `json:"unresolved_threads,omitempty"` // Review threads not marked as resolved
 }
 
 // RepoConfig represents configuration for a specific repository
@@ -67,6 +121,47 @@ type RepoConfig struct {
 	InitializedClaudes map[string]bool        `json:"initialized_claudes,omitempty"` // branch -> whether Claude has been started
 	AIProvider         *AIProviderConfig       `json:"ai_provider,omitempty"`       // AI provider profiles and settings
 	Hooks              *HooksConfig            `json:"hooks,omitempty"`              // Hooks configuration
+	AutoStash          bool                    `json:"auto_stash,omitempty"`         // Stash dirty worktrees during refresh, pull, then pop
+	WorktreeSort       string                  `json:"worktree_sort,omitempty"`      // Worktree list order: "recent", "name", "status" or "pr", "" = use default (recent)
+	TmuxLayout         *TmuxLayout             `json:"tmux_layout,omitempty"`        // Extra tmux windows/panes created with each session
+	Agent              string                  `json:"agent,omitempty"`              // AI coding agent profile name, "" = use global default
+	NotificationsMuted bool                    `json:"notifications_muted,omitempty"` // Don't notify when agents of this repository need input
+	Stacks             map[string]StackEntry   `json:"stacks,omitempty"`              // branch -> branch it is stacked on (stacked PRs)
+	MergedPRCleanup    string                  `json:"merged_pr_cleanup,omitempty"`   // What to do when a worktree's PR merged on GitHub: "ask", "auto" or "off", "" = ask
+	Issues             map[string]IssueInfo    `json:"issues,omitempty"`              // branch -> GitHub issue the worktree was created from
+}
+
+// IssueInfo records the GitHub issue a worktree was created from
+type IssueInfo struct {
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	URL    string `json:"url"`
+}
+
+// StackEntry records the branch a stacked worktree is based on
+type StackEntry struct {
+	Parent    string `json:"parent,omitempty"`     // Parent branch, "" = back on the base branch but not restacked yet
+	ForkPoint string `json:"fork_point,omitempty"` // Parent commit the branch was created or last restacked on
+}
+
+// TmuxLayout defines the tmux windows built for each worktree session (duplicated from session package for JSON serialization)
+type TmuxLayout struct {
+	Windows []TmuxWindow `json:"windows"`
+}
+
+// TmuxWindow is a named tmux window in a layout
+// The names "terminal" and "claude" add panes to the built-in windows
+type TmuxWindow struct {
+	Name   string     `json:"name"`
+	Layout string     `json:"layout,omitempty"` // tmux layout applied after splitting (e.g. "tiled", "main-vertical")
+	Panes  []TmuxPane `json:"panes,omitempty"`
+}
+
+// TmuxPane is a single pane of a layout window
+type TmuxPane struct {
+	Command string `json:"command,omitempty"` // Command to run in the pane, "" = plain shell
+	Split   string `json:"split,omitempty"`   // "horizontal" or "vertical" (default)
+	Size    string `json:"size,omitempty"`    // Pane size, e.g. "30%" or "10"
 }
 
 // Hook represents a single hook configuration (duplicated from hooks package for JSON serialization)
@@ -87,7 +182,9 @@ type HooksConfig struct {
 }
 
 // Manager handles configuration loading and saving
+// It is safe for concurrent use: background commands read and write it while the UI does
 type Manager struct {
+	mu         sync.Mutex // Guards config, exported methods lock it and unexported ones expect it held
 	configPath string
 	config     *Config
 }
@@ -146,11 +243,15 @@ func (m *Manager) save() error {
 
 // Reload reloads the configuration from disk
 func (m *Manager) Reload() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.load()
 }
 
 // GetBaseBranch returns the base branch for a repository
 func (m *Manager) GetBaseBranch(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		return repo.BaseBranch
 	}
@@ -159,6 +260,8 @@ func (m *Manager) GetBaseBranch(repoPath string) string {
 
 // SetBaseBranch sets the base branch for a repository
 func (m *Manager) SetBaseBranch(repoPath, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -173,6 +276,8 @@ func (m *Manager) SetBaseBranch(repoPath, branch string) error {
 
 // GetRepoConfig returns the configuration for a specific repository
 func (m *Manager) GetRepoConfig(repoPath string) *RepoConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		return repo
 	}
@@ -181,6 +286,8 @@ func (m *Manager) GetRepoConfig(repoPath string) *RepoConfig {
 
 // GetLastSelectedBranch returns the last selected branch for a repository
 func (m *Manager) GetLastSelectedBranch(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		return repo.LastSelectedBranch
 	}
@@ -189,6 +296,8 @@ func (m *Manager) GetLastSelectedBranch(repoPath string) string {
 
 // SetLastSelectedBranch sets the last selected branch for a repository
 func (m *Manager) SetLastSelectedBranch(repoPath, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -203,6 +312,8 @@ func (m *Manager) SetLastSelectedBranch(repoPath, branch string) error {
 
 // GetEditor returns the preferred editor for a repository
 func (m *Manager) GetEditor(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.Editor != "" {
 			return repo.Editor
@@ -213,6 +324,8 @@ func (m *Manager) GetEditor(repoPath string) string {
 
 // SetEditor sets the preferred editor for a repository
 func (m *Manager) SetEditor(repoPath, editor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -228,6 +341,8 @@ func (m *Manager) SetEditor(repoPath, editor string) error {
 // GetAutoFetchInterval returns the auto-fetch interval for a repository
 // Returns the configured interval in seconds, or 10 if not set
 func (m *Manager) GetAutoFetchInterval(repoPath string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.AutoFetchInterval > 0 {
 			return repo.AutoFetchInterval
@@ -238,6 +353,8 @@ func (m *Manager) GetAutoFetchInterval(repoPath string) int {
 
 // SetAutoFetchInterval sets the auto-fetch interval for a repository
 func (m *Manager) SetAutoFetchInterval(repoPath string, interval int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -252,11 +369,15 @@ func (m *Manager) SetAutoFetchInterval(repoPath string, interval int) error {
 
 // GetLastUpdateCheckTime returns the last update check time
 func (m *Manager) GetLastUpdateCheckTime() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.config.LastUpdateCheckTime
 }
 
 // SetLastUpdateCheckTime sets the last update check time
 func (m *Manager) SetLastUpdateCheckTime(timestamp string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.LastUpdateCheckTime = timestamp
 	return m.save()
 }
@@ -265,6 +386,8 @@ func (m *Manager) SetLastUpdateCheckTime(timestamp string) error {
 // Returns per-repo theme if set, otherwise returns global default theme
 // Returns "coolify" if no theme is configured
 func (m *Manager) GetTheme(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	// Check if repo has a per-repo theme override
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.Theme != "" {
@@ -284,6 +407,8 @@ func (m *Manager) GetTheme(repoPath string) string {
 // SetTheme sets the theme for a specific repository
 // If theme is empty string, it will use the global default
 func (m *Manager) SetTheme(repoPath, theme string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -298,6 +423,8 @@ func (m *Manager) SetTheme(repoPath, theme string) error {
 
 // SetGlobalTheme sets the global default theme for all repositories
 func (m *Manager) SetGlobalTheme(theme string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.DefaultTheme = theme
 	return m.save()
 }
@@ -305,6 +432,8 @@ func (m *Manager) SetGlobalTheme(theme string) error {
 // GetGlobalTheme returns the global default theme
 // Returns "coolify" if not set
 func (m *Manager) GetGlobalTheme() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.DefaultTheme != "" {
 		return m.config.DefaultTheme
 	}
@@ -313,9 +442,15 @@ func (m *Manager) GetGlobalTheme() string {
 
 // GetProviderProfiles returns all provider profiles for a repository
 func (m *Manager) GetProviderProfiles(repoPath string) map[string]*AIProviderProfile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.AIProvider != nil && repo.AIProvider.Profiles != nil {
-			return repo.AIProvider.Profiles
+			profiles := make(map[string]*AIProviderProfile, len(repo.AIProvider.Profiles))
+			for name, profile := range repo.AIProvider.Profiles {
+				profiles[name] = profile
+			}
+			return profiles
 		}
 	}
 	return make(map[string]*AIProviderProfile)
@@ -323,6 +458,8 @@ func (m *Manager) GetProviderProfiles(repoPath string) map[string]*AIProviderPro
 
 // AddProviderProfile adds a new provider profile for a repository
 func (m *Manager) AddProviderProfile(repoPath string, profile *AIProviderProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -348,6 +485,8 @@ func (m *Manager) AddProviderProfile(repoPath string, profile *AIProviderProfile
 
 // UpdateProviderProfile updates an existing provider profile
 func (m *Manager) UpdateProviderProfile(repoPath string, profile *AIProviderProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.AIProvider != nil && repo.AIProvider.Profiles != nil {
 			if _, exists := repo.AIProvider.Profiles[profile.Name]; exists {
@@ -361,6 +500,8 @@ func (m *Manager) UpdateProviderProfile(repoPath string, profile *AIProviderProf
 
 // DeleteProviderProfile deletes a provider profile
 func (m *Manager) DeleteProviderProfile(repoPath, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.AIProvider != nil && repo.AIProvider.Profiles != nil {
 			if repo.AIProvider.ActiveProfile == name {
@@ -378,6 +519,8 @@ func (m *Manager) DeleteProviderProfile(repoPath, name string) error {
 
 // GetActiveProfile returns the active profile name for a repository
 func (m *Manager) GetActiveProfile(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.AIProvider != nil {
 			return repo.AIProvider.ActiveProfile
@@ -388,6 +531,8 @@ func (m *Manager) GetActiveProfile(repoPath string) string {
 
 // SetActiveProfile sets the active profile for a repository
 func (m *Manager) SetActiveProfile(repoPath, profileName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -416,6 +561,8 @@ func (m *Manager) SetActiveProfile(repoPath, profileName string) error {
 
 // GetFallbackProfile returns the fallback profile name for a repository
 func (m *Manager) GetFallbackProfile(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.AIProvider != nil {
 			return repo.AIProvider.FallbackProfile
@@ -426,6 +573,8 @@ func (m *Manager) GetFallbackProfile(repoPath string) string {
 
 // SetFallbackProfile sets the fallback profile for a repository
 func (m *Manager) SetFallbackProfile(repoPath, profileName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -482,43 +631,58 @@ func (m *Manager) HasActiveAIProvider(repoPath string) bool {
 
 // GetAICommitEnabled returns whether AI commit message generation is enabled
 func (m *Manager) GetAICommitEnabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.config.AICommitEnabled
 }
 
 // SetAICommitEnabled sets whether AI commit message generation is enabled
 func (m *Manager) SetAICommitEnabled(enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.AICommitEnabled = enabled
 	return m.save()
 }
 
 // GetAIBranchNameEnabled returns whether AI branch name generation is enabled
 func (m *Manager) GetAIBranchNameEnabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.config.AIBranchNameEnabled
 }
 
 // SetAIBranchNameEnabled sets whether AI branch name generation is enabled
 func (m *Manager) SetAIBranchNameEnabled(enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.AIBranchNameEnabled = enabled
 	return m.save()
 }
 
 // GetDebugLoggingEnabled returns whether debug logging is enabled
 func (m *Manager) GetDebugLoggingEnabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.config.DebugLoggingEnabled
 }
 
 // SetDebugLoggingEnabled sets whether debug logging is enabled
 func (m *Manager) SetDebugLoggingEnabled(enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.DebugLoggingEnabled = enabled
 	return m.save()
 }
 
 // GetPRs returns all pull requests for a given branch
 func (m *Manager) GetPRs(repoPath, branch string) []PRInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.PRs != nil {
 			if prs, ok := repo.PRs[branch]; ok {
-				return prs
+				// A copy, the statuses are updated in place
+				return append([]PRInfo(nil), prs...)
 			}
 		}
 	}
@@ -536,6 +700,8 @@ func (m *Manager) GetLatestPR(repoPath, branch string) *PRInfo {
 
 // AddPR adds a pull request for a given branch
 func (m *Manager) AddPR(repoPath, branch, url string, prNumber int, title string, author string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -571,6 +737,8 @@ func (m *Manager) AddPR(repoPath, branch, url string, prNumber int, title string
 
 // UpdatePRStatus updates the status of a pull request
 func (m *Manager) UpdatePRStatus(repoPath, branch, url, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.PRs != nil {
 			if prs, ok := repo.PRs[branch]; ok {
@@ -586,8 +754,27 @@ func (m *Manager) UpdatePRStatus(repoPath, branch, url, status string) error {
 	return nil
 }
 
+// UpdatePRDetails updates the status and the review, CI and merge state of a pull request
+func (m *Manager) UpdatePRDetails(repoPath, branch, url, status string, details PRDetails) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok && repo.PRs != nil {
+		prs := repo.PRs[branch]
+		for i, pr := range prs {
+			if pr.URL == url {
+				prs[i].Status = status
+				prs[i].PRDetails = details
+				return m.save()
+			}
+		}
+	}
+	return nil
+}
+
 // RemovePR removes a pull request
 func (m *Manager) RemovePR(repoPath, branch, url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.PRs != nil {
 			if prs, ok := repo.PRs[branch]; ok {
@@ -613,6 +800,8 @@ func (m *Manager) HasPRs(repoPath, branch string) bool {
 
 // IsClaudeInitialized checks if a Claude session has been initialized for a branch
 func (m *Manager) IsClaudeInitialized(repoPath, branch string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.InitializedClaudes != nil {
 			return repo.InitializedClaudes[branch]
@@ -623,6 +812,8 @@ func (m *Manager) IsClaudeInitialized(repoPath, branch string) bool {
 
 // SetClaudeInitialized marks a branch as having an initialized Claude session
 func (m *Manager) SetClaudeInitialized(repoPath, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -640,12 +831,144 @@ func (m *Manager) SetClaudeInitialized(repoPath, branch string) error {
 	return m.save()
 }
 
+// GetStackEntry returns what a branch is stacked on, nil if it's based on the base branch
+func (m *Manager) GetStackEntry(repoPath, branch string) *StackEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		if entry, ok := repo.Stacks[branch]; ok {
+			return &entry
+		}
+	}
+	return nil
+}
+
+// GetStacks returns all stacked branches of a repository (branch -> entry)
+func (m *Manager) GetStacks(repoPath string) map[string]StackEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stacks := make(map[string]StackEntry)
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		for branch, entry := range repo.Stacks {
+			stacks[branch] = entry
+		}
+	}
+	return stacks
+}
+
+// SetStackEntry records what a branch is stacked on, a nil entry puts it back on the base branch
+func (m *Manager) SetStackEntry(repoPath, branch string, entry *StackEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	repo := m.config.Repositories[repoPath]
+	if entry == nil {
+		delete(repo.Stacks, branch)
+	} else {
+		if repo.Stacks == nil {
+			repo.Stacks = make(map[string]StackEntry)
+		}
+		repo.Stacks[branch] = *entry
+	}
+	return m.save()
+}
+
+// RenameStackBranch moves a branch's stack entry, and the entries of branches stacked on it, to its new name
+func (m *Manager) RenameStackBranch(repoPath, oldName, newName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok || repo.Stacks == nil {
+		return nil
+	}
+
+	changed := false
+	if entry, ok := repo.Stacks[oldName]; ok {
+		delete(repo.Stacks, oldName)
+		repo.Stacks[newName] = entry
+		changed = true
+	}
+	for child, entry := range repo.Stacks {
+		if entry.Parent == oldName {
+			entry.Parent = newName
+			repo.Stacks[child] = entry
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return m.save()
+}
+
+// GetBranchIssue returns the GitHub issue a branch was created from, nil if none
+func (m *Manager) GetBranchIssue(repoPath, branch string) *IssueInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		if issue, ok := repo.Issues[branch]; ok {
+			return &issue
+		}
+	}
+	return nil
+}
+
+// SetBranchIssue records the GitHub issue a branch was created from, a nil issue removes it
+func (m *Manager) SetBranchIssue(repoPath, branch string, issue *IssueInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	repo := m.config.Repositories[repoPath]
+	if issue == nil {
+		delete(repo.Issues, branch)
+	} else {
+		if repo.Issues == nil {
+			repo.Issues = make(map[string]IssueInfo)
+		}
+		repo.Issues[branch] = *issue
+	}
+	return m.save()
+}
+
+// RenameBranchIssue moves a branch's issue to its new name
+func (m *Manager) RenameBranchIssue(repoPath, oldName, newName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok {
+		return nil
+	}
+	issue, ok := repo.Issues[oldName]
+	if !ok {
+		return nil
+	}
+	delete(repo.Issues, oldName)
+	repo.Issues[newName] = issue
+	return m.save()
+}
+
 // CleanupBranch removes all branch-specific data from config when a worktree is deleted
 // This includes:
 // - All pull requests for the branch
 // - Claude initialization flag
+// - Stack entry (branches stacked on it move to its parent)
+// - Issue the branch was created from
 // - Last selected branch reference (if it matches the deleted branch)
 func (m *Manager) CleanupBranch(repoPath, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	repo, ok := m.config.Repositories[repoPath]
 	if !ok {
 		return nil // Nothing to clean up
@@ -661,6 +984,24 @@ func (m *Manager) CleanupBranch(repoPath, branch string) error {
 		delete(repo.InitializedClaudes, branch)
 	}
 
+	// Branches stacked on this one move down to its parent, keeping their fork point so
+	// restacking still skips the deleted branch's commits
+	if repo.Stacks != nil {
+		parent := repo.Stacks[branch].Parent
+		for child, childEntry := range repo.Stacks {
+			if childEntry.Parent == branch {
+				childEntry.Parent = parent
+				repo.Stacks[child] = childEntry
+			}
+		}
+		delete(repo.Stacks, branch)
+	}
+
+	// Remove the issue the branch was created from
+	if repo.Issues != nil {
+		delete(repo.Issues, branch)
+	}
+
 	// Clear last selected branch if it matches the deleted branch
 	if repo.LastSelectedBranch == branch {
 		repo.LastSelectedBranch = ""
@@ -669,9 +1010,42 @@ func (m *Manager) CleanupBranch(repoPath, branch string) error {
 	return m.save()
 }
 
+// GetBranchEntries returns the branches that have per-branch data (PRs, Claude initialization, stacks or issues) for a repository
+func (m *Manager) GetBranchEntries(repoPath string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	repo, ok := m.config.Repositories[repoPath]
+	if !ok {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for branch := range repo.PRs {
+		seen[branch] = true
+	}
+	for branch := range repo.InitializedClaudes {
+		seen[branch] = true
+	}
+	for branch := range repo.Stacks {
+		seen[branch] = true
+	}
+	for branch := range repo.Issues {
+		seen[branch] = true
+	}
+
+	branches := make([]string, 0, len(seen))
+	for branch := range seen {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+	return branches
+}
+
 // GetCommitPrompt returns the custom commit message prompt
 // Returns the custom prompt if set, otherwise returns the default prompt
 func (m *Manager) GetCommitPrompt() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.AIPrompts != nil && m.config.AIPrompts.CommitMessage != "" {
 		return m.config.AIPrompts.CommitMessage
 	}
@@ -680,6 +1054,8 @@ func (m *Manager) GetCommitPrompt() string {
 
 // SetCommitPrompt sets the custom commit message prompt
 func (m *Manager) SetCommitPrompt(prompt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.AIPrompts == nil {
 		m.config.AIPrompts = &AIPrompts{}
 	}
@@ -690,6 +1066,8 @@ func (m *Manager) SetCommitPrompt(prompt string) error {
 // GetBranchNamePrompt returns the custom branch name prompt
 // Returns the custom prompt if set, otherwise returns the default prompt
 func (m *Manager) GetBranchNamePrompt() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.AIPrompts != nil && m.config.AIPrompts.BranchName != "" {
 		return m.config.AIPrompts.BranchName
 	}
@@ -698,6 +1076,8 @@ func (m *Manager) GetBranchNamePrompt() string {
 
 // SetBranchNamePrompt sets the custom branch name prompt
 func (m *Manager) SetBranchNamePrompt(prompt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.AIPrompts == nil {
 		m.config.AIPrompts = &AIPrompts{}
 	}
@@ -708,6 +1088,8 @@ func (m *Manager) SetBranchNamePrompt(prompt string) error {
 // GetPRPrompt returns the custom PR content prompt
 // Returns the custom prompt if set, otherwise returns the default prompt
 func (m *Manager) GetPRPrompt() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.AIPrompts != nil && m.config.AIPrompts.PRContent != "" {
 		return m.config.AIPrompts.PRContent
 	}
@@ -716,6 +1098,8 @@ func (m *Manager) GetPRPrompt() string {
 
 // SetPRPrompt sets the custom PR content prompt
 func (m *Manager) SetPRPrompt(prompt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.AIPrompts == nil {
 		m.config.AIPrompts = &AIPrompts{}
 	}
@@ -725,6 +1109,8 @@ func (m *Manager) SetPRPrompt(prompt string) error {
 
 // ResetAIPromptsToDefaults resets all AI prompts to their default values
 func (m *Manager) ResetAIPromptsToDefaults() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.AIPrompts = &AIPrompts{} // Empty AIPrompts means use defaults
 	return m.save()
 }
@@ -732,6 +1118,8 @@ func (m *Manager) ResetAIPromptsToDefaults() error {
 // GetWrapperChecksum returns the stored checksum for a shell wrapper
 // Returns empty string if no checksum is stored
 func (m *Manager) GetWrapperChecksum(shell string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.WrapperChecksums == nil {
 		return ""
 	}
@@ -740,6 +1128,8 @@ func (m *Manager) GetWrapperChecksum(shell string) string {
 
 // SetWrapperChecksum stores the checksum for a shell wrapper
 func (m *Manager) SetWrapperChecksum(shell, checksum string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.WrapperChecksums == nil {
 		m.config.WrapperChecksums = make(map[string]string)
 	}
@@ -749,11 +1139,15 @@ func (m *Manager) SetWrapperChecksum(shell, checksum string) error {
 
 // IsOnboarded returns whether the user has completed the onboarding flow
 func (m *Manager) IsOnboarded() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.config.Onboarded
 }
 
 // SetOnboarded marks the onboarding flow as completed
 func (m *Manager) SetOnboarded() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config.Onboarded = true
 	return m.save()
 }
@@ -761,6 +1155,8 @@ func (m *Manager) SetOnboarded() error {
 // GetPRDefaultState returns the default PR state for a repository
 // Returns "draft" or "ready", defaults to "ready" if not set
 func (m *Manager) GetPRDefaultState(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		if repo.PRDefaultState == "draft" || repo.PRDefaultState == "ready" {
 			return repo.PRDefaultState
@@ -771,6 +1167,8 @@ func (m *Manager) GetPRDefaultState(repoPath string) string {
 
 // SetPRDefaultState sets the default PR state for a repository
 func (m *Manager) SetPRDefaultState(repoPath, state string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -783,8 +1181,102 @@ func (m *Manager) SetPRDefaultState(repoPath, state string) error {
 	return m.save()
 }
 
+// GetAutoStash returns whether refresh should autostash dirty worktrees for a repository
+func (m *Manager) GetAutoStash(repoPath string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		return repo.AutoStash
+	}
+	return false
+}
+
+// SetAutoStash sets whether refresh should autostash dirty worktrees for a repository
+func (m *Manager) SetAutoStash(repoPath string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].AutoStash = enabled
+	return m.save()
+}
+
+// GetMergedPRCleanup returns the cleanup policy for worktrees whose PR merged, "ask" by default
+func (m *Manager) GetMergedPRCleanup(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok && repo.MergedPRCleanup != "" {
+		return repo.MergedPRCleanup
+	}
+	return MergedPRCleanupPolicies[0]
+}
+
+// SetMergedPRCleanup sets the cleanup policy for worktrees whose PR merged
+func (m *Manager) SetMergedPRCleanup(repoPath, policy string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	valid := false
+	for _, known := range MergedPRCleanupPolicies {
+		if policy == known {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid merged PR cleanup policy '%s': must be one of %s", policy, strings.Join(MergedPRCleanupPolicies, ", "))
+	}
+
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].MergedPRCleanup = policy
+	return m.save()
+}
+
+// GetWorktreeSort returns the worktree list sort order for a repository
+// Returns "recent", "name", "status" or "pr", defaults to "recent" if not set
+func (m *Manager) GetWorktreeSort(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		switch repo.WorktreeSort {
+		case "recent", "name", "status", "pr":
+			return repo.WorktreeSort
+		}
+	}
+	return "recent"
+}
+
+// SetWorktreeSort sets the worktree list sort order for a repository
+func (m *Manager) SetWorktreeSort(repoPath, sortOrder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].WorktreeSort = sortOrder
+	return m.save()
+}
+
 // GetHooks returns the hooks configuration for a repository
 func (m *Manager) GetHooks(repoPath string) *HooksConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if repo, ok := m.config.Repositories[repoPath]; ok {
 		return repo.Hooks
 	}
@@ -793,6 +1285,8 @@ func (m *Manager) GetHooks(repoPath string) *HooksConfig {
 
 // SetHooks sets the entire hooks configuration for a repository
 func (m *Manager) SetHooks(repoPath string, hooks *HooksConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -807,6 +1301,8 @@ func (m *Manager) SetHooks(repoPath string, hooks *HooksConfig) error {
 
 // AddHook adds a hook to a specific hook type for a repository
 func (m *Manager) AddHook(repoPath, hookType string, hook Hook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.config.Repositories == nil {
 		m.config.Repositories = make(map[string]*RepoConfig)
 	}
@@ -840,6 +1336,8 @@ func (m *Manager) AddHook(repoPath, hookType string, hook Hook) error {
 
 // RemoveHook removes a hook from a specific hook type by index
 func (m *Manager) RemoveHook(repoPath, hookType string, index int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	repo, ok := m.config.Repositories[repoPath]
 	if !ok || repo.Hooks == nil {
 		return fmt.Errorf("no hooks configured for repository")
@@ -880,6 +1378,8 @@ func (m *Manager) RemoveHook(repoPath, hookType string, index int) error {
 
 // UpdateHook updates a hook at a specific index for a hook type
 func (m *Manager) UpdateHook(repoPath, hookType string, index int, hook Hook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	repo, ok := m.config.Repositories[repoPath]
 	if !ok || repo.Hooks == nil {
 		return fmt.Errorf("no hooks configured for repository")
@@ -917,3 +1417,226 @@ func (m *Manager) UpdateHook(repoPath, hookType string, index int, hook Hook) er
 
 	return m.save()
 }
+
+// GetTmuxLayout returns the tmux session layout for a repository
+// Returns nil if no layout is configured (sessions get the default terminal and claude windows)
+func (m *Manager) GetTmuxLayout(repoPath string) *TmuxLayout {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok && repo.TmuxLayout != nil && len(repo.TmuxLayout.Windows) > 0 {
+		return repo.TmuxLayout
+	}
+	return nil
+}
+
+// SetTmuxLayout sets the tmux session layout for a repository (nil removes it)
+func (m *Manager) SetTmuxLayout(repoPath string, layout *TmuxLayout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if layout != nil {
+		for _, window := range layout.Windows {
+			if window.Name == "" {
+				return fmt.Errorf("tmux layout window name cannot be empty")
+			}
+			for _, pane := range window.Panes {
+				if pane.Split != "" && pane.Split != "horizontal" && pane.Split != "vertical" {
+					return fmt.Errorf("invalid split %q in window %s: must be horizontal or vertical", pane.Split, window.Name)
+				}
+			}
+		}
+	}
+
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].TmuxLayout = layout
+	return m.save()
+}
+
+// GetAgentNames returns the selectable agent profile names: the built-in claude agent followed by configured profiles
+func (m *Manager) GetAgentNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := []string{DefaultAgentName}
+	var custom []string
+	for name := range m.config.Agents {
+		if name != DefaultAgentName {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// GetAgentName returns the agent profile name used for a repository
+// Falls back to the global default agent, then to the built-in claude agent
+func (m *Manager) GetAgentName(repoPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.agentName(repoPath)
+}
+
+// agentName is GetAgentName for callers holding the lock
+func (m *Manager) agentName(repoPath string) string {
+	if repo, ok := m.config.Repositories[repoPath]; ok && repo.Agent != "" {
+		if _, exists := m.config.Agents[repo.Agent]; exists || repo.Agent == DefaultAgentName {
+			return repo.Agent
+		}
+	}
+	if m.config.DefaultAgent != "" {
+		if _, exists := m.config.Agents[m.config.DefaultAgent]; exists {
+			return m.config.DefaultAgent
+		}
+	}
+	return DefaultAgentName
+}
+
+// GetAgent returns the agent profile used for a repository
+// Returns nil for the built-in claude agent (unless a "claude" profile overrides it)
+func (m *Manager) GetAgent(repoPath string) *AgentConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.config.Agents[m.agentName(repoPath)]
+}
+
+// SetAgent sets the agent profile name for a repository ("" = use global default)
+func (m *Manager) SetAgent(repoPath, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if name != "" && name != DefaultAgentName {
+		if _, exists := m.config.Agents[name]; !exists {
+			return fmt.Errorf("agent profile '%s' not found", name)
+		}
+	}
+
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].Agent = name
+	return m.save()
+}
+
+// AddAgentProfile adds or updates a global agent profile
+func (m *Manager) AddAgentProfile(name string, agent *AgentConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if name == "" {
+		return fmt.Errorf("agent profile name cannot be empty")
+	}
+	// The name becomes the tmux window name, where ':' and '.' separate session, window and pane
+	if strings.ContainsAny(name, ":. \t") {
+		return fmt.Errorf("agent profile name '%s' cannot contain ':', '.' or spaces", name)
+	}
+	if agent == nil || agent.Command == "" {
+		return fmt.Errorf("agent profile '%s' must have a command", name)
+	}
+
+	if m.config.Agents == nil {
+		m.config.Agents = make(map[string]*AgentConfig)
+	}
+
+	m.config.Agents[name] = agent
+	return m.save()
+}
+
+// GetNotificationConfig returns the agent notification settings with defaults applied
+func (m *Manager) GetNotificationConfig() NotificationConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := NotificationConfig{}
+	if m.config.Notifications != nil {
+		cfg = *m.config.Notifications
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = []string{"bell"}
+	}
+	if cfg.RateLimitSeconds <= 0 {
+		cfg.RateLimitSeconds = DefaultNotificationRateLimit
+	}
+	return cfg
+}
+
+// SetNotificationConfig sets the global agent notification settings
+func (m *Manager) SetNotificationConfig(cfg *NotificationConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cfg != nil {
+		for _, method := range cfg.Methods {
+			valid := false
+			for _, known := range NotificationMethods {
+				if method == known {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return fmt.Errorf("invalid notification method '%s': must be one of %s", method, strings.Join(NotificationMethods, ", "))
+			}
+			if method == "command" && strings.TrimSpace(cfg.Command) == "" {
+				return fmt.Errorf("notification method 'command' requires a command")
+			}
+		}
+	}
+
+	m.config.Notifications = cfg
+	return m.save()
+}
+
+// GetMultiplexer returns the terminal multiplexer hosting sessions ("tmux" or "zellij")
+func (m *Manager) GetMultiplexer() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Multiplexer == "" {
+		return Multiplexers[0]
+	}
+	return m.config.Multiplexer
+}
+
+// SetMultiplexer sets the terminal multiplexer hosting sessions
+func (m *Manager) SetMultiplexer(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, known := range Multiplexers {
+		if name == known {
+			m.config.Multiplexer = name
+			return m.save()
+		}
+	}
+	return fmt.Errorf("invalid multiplexer '%s': must be one of %s", name, strings.Join(Multiplexers, ", "))
+}
+
+// GetNotificationsMuted returns whether agent notifications are muted for a repository
+func (m *Manager) GetNotificationsMuted(repoPath string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if repo, ok := m.config.Repositories[repoPath]; ok {
+		return repo.NotificationsMuted
+	}
+	return false
+}
+
+// SetNotificationsMuted sets whether agent notifications are muted for a repository
+func (m *Manager) SetNotificationsMuted(repoPath string, muted bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.Repositories == nil {
+		m.config.Repositories = make(map[string]*RepoConfig)
+	}
+
+	if _, ok := m.config.Repositories[repoPath]; !ok {
+		m.config.Repositories[repoPath] = &RepoConfig{}
+	}
+
+	m.config.Repositories[repoPath].NotificationsMuted = muted
+	return m.save()
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/coollabsio/jean-tui/openai"
@@ -1296,6 +1298,107 @@ func TestProfilePersistence(t *testing.T) {
 	})
 }
 
+func TestTmuxLayout(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+	repoPath := "/test/repo"
+
+	m1 := &Manager{
+		configPath: configPath,
+		config: &Config{
+			Repositories: make(map[string]*RepoConfig),
+		},
+	}
+
+	if m1.GetTmuxLayout(repoPath) != nil {
+		t.Error("Expected no layout by default")
+	}
+
+	invalid := &TmuxLayout{Windows: []TmuxWindow{{Name: "dev", Panes: []TmuxPane{{Split: "diagonal"}}}}}
+	if err := m1.SetTmuxLayout(repoPath, invalid); err == nil {
+		t.Error("Expected error for invalid split direction")
+	}
+
+	layout := &TmuxLayout{Windows: []TmuxWindow{
+		{Name: "dev", Layout: "main-vertical", Panes: []TmuxPane{
+			{Command: "npm run dev"},
+			{Command: "npm test -- --watch", Split: "horizontal", Size: "40%"},
+		}},
+	}}
+	if err := m1.SetTmuxLayout(repoPath, layout); err != nil {
+		t.Fatalf("Failed to set layout: %v", err)
+	}
+
+	// Layout persists when reloading from disk
+	m2 := &Manager{configPath: configPath}
+	if err := m2.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	got := m2.GetTmuxLayout(repoPath)
+	if got == nil || len(got.Windows) != 1 || len(got.Windows[0].Panes) != 2 {
+		t.Fatalf("Expected layout with one window and two panes, got %+v", got)
+	}
+	if got.Windows[0].Panes[1].Split != "horizontal" || got.Windows[0].Panes[1].Size != "40%" {
+		t.Errorf("Unexpected pane after reload: %+v", got.Windows[0].Panes[1])
+	}
+
+	if err := m2.SetTmuxLayout(repoPath, nil); err != nil {
+		t.Fatalf("Failed to clear layout: %v", err)
+	}
+	if m2.GetTmuxLayout(repoPath) != nil {
+		t.Error("Expected layout to be cleared")
+	}
+}
+
+func TestAgentProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	repoPath := "/test/repo"
+
+	m := &Manager{
+		configPath: filepath.Join(tempDir, "config.json"),
+		config: &Config{
+			Repositories: make(map[string]*RepoConfig),
+		},
+	}
+
+	if name := m.GetAgentName(repoPath); name != DefaultAgentName || m.GetAgent(repoPath) != nil {
+		t.Errorf("Expected built-in claude agent by default, got %q", name)
+	}
+
+	if err := m.SetAgent(repoPath, "codex"); err == nil {
+		t.Error("Expected error for unknown agent profile")
+	}
+	if err := m.AddAgentProfile("aider", &AgentConfig{}); err == nil {
+		t.Error("Expected error for agent profile without command")
+	}
+	for _, name := range []string{"my:agent", "codex.v2", "my agent"} {
+		if err := m.AddAgentProfile(name, &AgentConfig{Command: "codex"}); err == nil {
+			t.Errorf("Expected error for agent profile name %q", name)
+		}
+	}
+
+	if err := m.AddAgentProfile("codex", &AgentConfig{Command: "codex", Args: "--cd {worktree}"}); err != nil {
+		t.Fatalf("Failed to add agent profile: %v", err)
+	}
+	if names := m.GetAgentNames(); len(names) != 2 || names[0] != DefaultAgentName || names[1] != "codex" {
+		t.Errorf("Unexpected agent names: %v", names)
+	}
+
+	// Global default applies to repositories without their own choice
+	m.config.DefaultAgent = "codex"
+	if name := m.GetAgentName(repoPath); name != "codex" {
+		t.Errorf("Expected global default agent codex, got %q", name)
+	}
+
+	// Repository choice overrides the global default
+	if err := m.SetAgent(repoPath, DefaultAgentName); err != nil {
+		t.Fatalf("Failed to set agent: %v", err)
+	}
+	if name := m.GetAgentName(repoPath); name != DefaultAgentName {
+		t.Errorf("Expected repository agent claude, got %q", name)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -1310,3 +1413,202 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// TestNotificationConfig tests notification defaults, validation and per-repo mute
+func TestNotificationConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	repoPath := "/test/repo"
+
+	m := &Manager{
+		configPath: filepath.Join(tempDir, "config.json"),
+		config: &Config{
+			Repositories: make(map[string]*RepoConfig),
+		},
+	}
+
+	cfg := m.GetNotificationConfig()
+	if len(cfg.Methods) != 1 || cfg.Methods[0] != "bell" || cfg.RateLimitSeconds != DefaultNotificationRateLimit {
+		t.Errorf("Unexpected default notification config: %+v", cfg)
+	}
+
+	if err := m.SetNotificationConfig(&NotificationConfig{Methods: []string{"email"}}); err == nil {
+		t.Error("Expected error for unknown notification method")
+	}
+	if err := m.SetNotificationConfig(&NotificationConfig{Methods: []string{"command"}}); err == nil {
+		t.Error("Expected error for command method without a command")
+	}
+	if err := m.SetNotificationConfig(&NotificationConfig{Methods: []string{"osc9", "notify-send"}, RateLimitSeconds: 10}); err != nil {
+		t.Fatalf("Failed to set notification config: %v", err)
+	}
+	if cfg := m.GetNotificationConfig(); len(cfg.Methods) != 2 || cfg.RateLimitSeconds != 10 {
+		t.Errorf("Unexpected notification config: %+v", cfg)
+	}
+
+	if m.GetNotificationsMuted(repoPath) {
+		t.Error("Expected notifications not to be muted by default")
+	}
+	if err := m.SetNotificationsMuted(repoPath, true); err != nil {
+		t.Fatalf("Failed to mute notifications: %v", err)
+	}
+	if !m.GetNotificationsMuted(repoPath) || m.GetNotificationsMuted("/other/repo") {
+		t.Error("Expected notifications to be muted only for the repository")
+	}
+}
+
+// TestMultiplexer tests selecting the terminal multiplexer
+func TestMultiplexer(t *testing.T) {
+	m := &Manager{
+		configPath: filepath.Join(t.TempDir(), "config.json"),
+		config: &Config{
+			Repositories: make(map[string]*RepoConfig),
+		},
+	}
+
+	if got := m.GetMultiplexer(); got != "tmux" {
+		t.Errorf("Expected tmux by default, got %q", got)
+	}
+	if err := m.SetMultiplexer("screen"); err == nil {
+		t.Error("Expected error for unknown multiplexer")
+	}
+	if err := m.SetMultiplexer("zellij"); err != nil {
+		t.Fatalf("Failed to set multiplexer: %v", err)
+	}
+	if got := m.GetMultiplexer(); got != "zellij" {
+		t.Errorf("Expected zellij, got %q", got)
+	}
+}
+
+// TestMergedPRCleanup tests the per-repository cleanup policy for merged PRs
+func TestMergedPRCleanup(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	if got := m.GetMergedPRCleanup("/repo"); got != "ask" {
+		t.Errorf("Expected ask by default, got %q", got)
+	}
+	if err := m.SetMergedPRCleanup("/repo", "always"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+	if err := m.SetMergedPRCleanup("/repo", "auto"); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	if got := m.GetMergedPRCleanup("/repo"); got != "auto" {
+		t.Errorf("Expected auto, got %q", got)
+	}
+	if got := m.GetMergedPRCleanup("/other"); got != "ask" {
+		t.Errorf("Expected other repositories to keep asking, got %q", got)
+	}
+}
+
+// TestUpdatePRDetails tests storing PR badges and reading them back from disk
+func TestUpdatePRDetails(t *testing.T) {
+	m, _ := createTestManager(t)
+	url := "https://github.com/o/r/pull/7"
+	if err := m.AddPR("/repo", "feature", url, 7, "Feature", "me"); err != nil {
+		t.Fatalf("AddPR failed: %v", err)
+	}
+
+	details := PRDetails{Checks: "failing", ReviewDecision: "approved", Reviewers: []string{"alice"}, Mergeable: "conflicting", UnresolvedThreads: 3}
+	if err := m.UpdatePRDetails("/repo", "feature", url, "open", details); err != nil {
+		t.Fatalf("UpdatePRDetails failed: %v", err)
+	}
+
+	reloaded := &Manager{configPath: m.configPath, config: &Config{}}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	prs := reloaded.GetPRs("/repo", "feature")
+	if len(prs) != 1 || prs[0].Checks != "failing" || prs[0].UnresolvedThreads != 3 || len(prs[0].Reviewers) != 1 || prs[0].Title != "Feature" {
+		t.Errorf("Expected PR details to persist, got %+v", prs)
+	}
+}
+
+// TestStackEntries tests recording stacked branches and re-parenting them when their parent is deleted
+func TestStackEntries(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	if m.GetStackEntry("/repo", "api") != nil {
+		t.Fatal("Expected no stack entry for a new branch")
+	}
+	if err := m.SetStackEntry("/repo", "api", &StackEntry{Parent: "models", ForkPoint: "aaa"}); err != nil {
+		t.Fatalf("SetStackEntry failed: %v", err)
+	}
+	if err := m.SetStackEntry("/repo", "ui", &StackEntry{Parent: "api", ForkPoint: "bbb"}); err != nil {
+		t.Fatalf("SetStackEntry failed: %v", err)
+	}
+
+	// Deleting api moves ui onto api's parent, keeping the fork point for restacking
+	if err := m.CleanupBranch("/repo", "api"); err != nil {
+		t.Fatalf("CleanupBranch failed: %v", err)
+	}
+	if entry := m.GetStackEntry("/repo", "ui"); entry == nil || entry.Parent != "models" || entry.ForkPoint != "bbb" {
+		t.Errorf("Expected ui to be stacked on models, got %+v", entry)
+	}
+	if stacks := m.GetStacks("/repo"); len(stacks) != 1 {
+		t.Errorf("Expected only ui to be stacked, got %v", stacks)
+	}
+
+	if err := m.SetStackEntry("/repo", "ui", nil); err != nil {
+		t.Fatalf("SetStackEntry failed: %v", err)
+	}
+	if m.GetStackEntry("/repo", "ui") != nil {
+		t.Error("Expected ui to be back on the base branch")
+	}
+}
+
+// TestBranchIssues tests recording, renaming and cleaning up the issue of a branch
+func TestBranchIssues(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	if m.GetBranchIssue("/repo", "12-login") != nil {
+		t.Fatal("Expected no issue for a new branch")
+	}
+	issue := &IssueInfo{Number: 12, Title: "Login times out", URL: "https://github.com/o/r/issues/12"}
+	if err := m.SetBranchIssue("/repo", "12-login", issue); err != nil {
+		t.Fatalf("SetBranchIssue failed: %v", err)
+	}
+	if err := m.RenameBranchIssue("/repo", "12-login", "12-fix-login"); err != nil {
+		t.Fatalf("RenameBranchIssue failed: %v", err)
+	}
+	if m.GetBranchIssue("/repo", "12-login") != nil {
+		t.Error("Expected the issue to leave the old name")
+	}
+	if got := m.GetBranchIssue("/repo", "12-fix-login"); got == nil || *got != *issue {
+		t.Errorf("Expected the issue under the new name, got %+v", got)
+	}
+	if entries := m.GetBranchEntries("/repo"); len(entries) != 1 || entries[0] != "12-fix-login" {
+		t.Errorf("Expected the issue branch in the branch entries, got %v", entries)
+	}
+
+	if err := m.CleanupBranch("/repo", "12-fix-login"); err != nil {
+		t.Fatalf("CleanupBranch failed: %v", err)
+	}
+	if m.GetBranchIssue("/repo", "12-fix-login") != nil {
+		t.Error("Expected the issue to be removed with the branch")
+	}
+}
+
+// TestConcurrentAccess tests that background commands can read and write the config at the same time
+func TestConcurrentAccess(t *testing.T) {
+	m, _ := createTestManager(t)
+	repoPath := "/test/repo"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		branch := fmt.Sprintf("feature-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_ = m.AddPR(repoPath, branch, fmt.Sprintf("https://github.com/o/r/pull/%d", j), j, "PR", "alice")
+				_ = m.UpdatePRStatus(repoPath, branch, fmt.Sprintf("https://github.com/o/r/pull/%d", j), "merged")
+				_ = m.GetPRs(repoPath, branch)
+				_ = m.GetBranchEntries(repoPath)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entries := m.GetBranchEntries(repoPath); len(entries) != 8 {
+		t.Errorf("Expected PRs for 8 branches, got %v", entries)
+	}
+}
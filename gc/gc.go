@@ -0,0 +1,179 @@
+// Package gc finds and removes what deleted worktrees leave behind: tmux sessions,
+// git worktree records, merged branches and per-branch config entries
+package gc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/git"
+	"github.com/coollabsio/jean-tui/session"
+)
+
+// Kind is the type of a garbage item
+type Kind string
+
+const (
+	OrphanSession    Kind = "session"  // jean tmux session without a worktree
+	PrunableWorktree Kind = "worktree" // git worktree record whose directory was deleted
+	MergedBranch     Kind = "branch"   // Local branch merged into the base branch, not checked out anywhere
+	StaleConfig      Kind = "config"   // PR or Claude state kept in config for a branch that no longer exists
+)
+
+// Item is a single thing gc can clean up
+type Item struct {
+	Kind   Kind
+	Name   string // Session name, worktree record, branch name or config branch key
+	Reason string // Why the item is considered garbage
+}
+
+// Result is the outcome of cleaning an item
+type Result struct {
+	Item Item
+	Err  error
+}
+
+// Collector finds and cleans garbage for one repository
+type Collector struct {
+	RepoPath   string // Main repository root
+	BaseBranch string // Branches merged into it are garbage, "" = skip merged branches
+	Git        *git.Manager
+	Sessions   *session.Manager
+	Config     *config.Manager // Optional, stale config entries are skipped without it
+}
+
+// Find lists the garbage of the repository without changing anything (the dry run)
+func (c *Collector) Find() ([]Item, error) {
+	worktrees, err := c.Git.ListLightweight()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	items = append(items, c.findOrphanSessions(worktrees)...)
+
+	prunable, err := c.Git.PrunableWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range prunable {
+		name, reason, _ := strings.Cut(record, ": ")
+		items = append(items, Item{Kind: PrunableWorktree, Name: name, Reason: reason})
+	}
+
+	// Branches of deleted worktrees are free once the records are pruned (Clean prunes before deleting branches)
+	checkedOut := make(map[string]bool)
+	for _, wt := range worktrees {
+		if _, err := os.Stat(wt.Path); err == nil {
+			checkedOut[wt.Branch] = true
+		}
+	}
+
+	if c.BaseBranch != "" {
+		merged, err := c.Git.MergedBranches(c.BaseBranch)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range merged {
+			if !checkedOut[branch] {
+				items = append(items, Item{Kind: MergedBranch, Name: branch, Reason: "merged into " + c.BaseBranch})
+			}
+		}
+	}
+
+	if c.Config != nil {
+		branches, err := c.Git.ListBranches()
+		if err != nil {
+			return nil, err
+		}
+		exists := make(map[string]bool)
+		for _, branch := range branches {
+			exists[branch] = true
+		}
+		for _, branch := range c.Config.GetBranchEntries(c.RepoPath) {
+			if !exists[branch] && !checkedOut[branch] {
+				items = append(items, Item{Kind: StaleConfig, Name: branch, Reason: "branch no longer exists"})
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// findOrphanSessions lists the repository's jean sessions that don't belong to a worktree
+func (c *Collector) findOrphanSessions(worktrees []git.Worktree) []Item {
+	sessions, err := c.Sessions.List("")
+	if err != nil {
+		return nil
+	}
+
+	repoName := filepath.Base(c.RepoPath)
+	expected := make(map[string]bool)
+	for _, wt := range worktrees {
+		if _, err := os.Stat(wt.Path); err != nil {
+			continue // Prunable worktree, its sessions are orphans too
+		}
+		name := c.Sessions.SanitizeName(repoName, wt.Branch)
+		expected[name] = true
+		expected[name+"-terminal"] = true
+	}
+
+	// Session names only contain the repository's base name, so a session must also live in the
+	// repository or in a deleted directory to avoid touching a same-named repository elsewhere
+	prefix := c.Sessions.SanitizeName(repoName, "")
+	var items []Item
+	for _, sess := range sessions {
+		if expected[sess.Name] || !strings.HasPrefix(sess.Name, prefix) {
+			continue
+		}
+		if sess.Path == "" {
+			// Zellij doesn't report session directories, the repository name in the session name has to do
+			items = append(items, Item{Kind: OrphanSession, Name: sess.Name, Reason: "no worktree for this branch"})
+		} else if _, err := os.Stat(sess.Path); err != nil {
+			items = append(items, Item{Kind: OrphanSession, Name: sess.Name, Reason: "worktree directory deleted"})
+		} else if session.InRepository(sess.Path, c.RepoPath) {
+			items = append(items, Item{Kind: OrphanSession, Name: sess.Name, Reason: "no worktree for this branch"})
+		}
+	}
+	return items
+}
+
+// Clean removes the given items and reports the outcome of each one
+// Worktree records are pruned together, deleted branches also lose their config entries
+func (c *Collector) Clean(items []Item) []Result {
+	results := make([]Result, 0, len(items))
+	pruned := false
+	var pruneErr error
+
+	for _, item := range items {
+		var err error
+		switch item.Kind {
+		case OrphanSession:
+			err = c.Sessions.Kill(item.Name)
+		case PrunableWorktree:
+			if !pruned {
+				pruneErr = c.Git.PruneWorktrees()
+				pruned = true
+			}
+			err = pruneErr
+		case MergedBranch:
+			err = c.Git.DeleteMergedBranch(item.Name)
+			if err == nil && c.Config != nil {
+				_ = c.Config.CleanupBranch(c.RepoPath, item.Name) // Ignore error, not critical
+			}
+		case StaleConfig:
+			if c.Config == nil {
+				err = fmt.Errorf("no config available")
+			} else {
+				err = c.Config.CleanupBranch(c.RepoPath, item.Name)
+			}
+		default:
+			err = fmt.Errorf("unknown item kind '%s'", item.Kind)
+		}
+		results = append(results, Result{Item: item, Err: err})
+	}
+	return results
+}
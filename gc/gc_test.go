@@ -0,0 +1,153 @@
+package gc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/git"
+	"github.com/coollabsio/jean-tui/session"
+)
+
+// run runs a command in dir and fails the test on error
+func run(t *testing.T, dir string, name string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %s failed: %v\n%s", name, strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TestFindAndClean tests the dry run and cleanup of every kind of garbage
+func TestFindAndClean(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available")
+	}
+	// Private tmux server and config, never touch the user's
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	repoPath := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	run(t, repoPath, "git", "init", "-q")
+	run(t, repoPath, "git", "config", "user.email", "test@example.com")
+	run(t, repoPath, "git", "config", "user.name", "Test User")
+	run(t, repoPath, "git", "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	base := run(t, repoPath, "git", "branch", "--show-current")
+
+	// "live" is a worktree in use, "gone" was deleted by hand, "done" is merged and not checked out
+	livePath := filepath.Join(repoPath, ".workspaces", "live")
+	gonePath := filepath.Join(repoPath, ".workspaces", "gone")
+	run(t, repoPath, "git", "worktree", "add", "-q", "-b", "live", livePath)
+	run(t, repoPath, "git", "worktree", "add", "-q", "-b", "gone", gonePath)
+	run(t, repoPath, "git", "branch", "done")
+	run(t, livePath, "git", "commit", "-q", "--allow-empty", "-m", "wip")
+
+	sessions := session.NewManager()
+	otherRepo := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(otherRepo, 0755); err != nil {
+		t.Fatalf("Failed to create other repo dir: %v", err)
+	}
+	siblingRepo := repoPath + "-v2"
+	if err := os.MkdirAll(siblingRepo, 0755); err != nil {
+		t.Fatalf("Failed to create sibling repo dir: %v", err)
+	}
+	for name, path := range map[string]string{
+		sessions.SanitizeName("app", "live"):    livePath,    // Belongs to a worktree
+		sessions.SanitizeName("app", "gone"):    gonePath,    // Directory deleted below
+		sessions.SanitizeName("app", "old"):     repoPath,    // Branch has no worktree anymore
+		sessions.SanitizeName("app", "mine"):    otherRepo,   // Same repository name elsewhere, not ours
+		sessions.SanitizeName("app-v2", "main"): siblingRepo, // Sibling repository sharing name and path prefix
+	} {
+		run(t, repoPath, "tmux", "new-session", "-d", "-s", name, "-c", path)
+	}
+	if err := os.RemoveAll(gonePath); err != nil {
+		t.Fatalf("Failed to remove worktree directory: %v", err)
+	}
+
+	cfg, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	if err := cfg.AddPR(repoPath, "removed", "https://github.com/o/r/pull/1", 1, "Removed", "me"); err != nil {
+		t.Fatalf("AddPR failed: %v", err)
+	}
+	if err := cfg.SetClaudeInitialized(repoPath, "live"); err != nil {
+		t.Fatalf("SetClaudeInitialized failed: %v", err)
+	}
+
+	collector := &Collector{
+		RepoPath:   repoPath,
+		BaseBranch: base,
+		Git:        git.NewManager(repoPath),
+		Sessions:   sessions,
+		Config:     cfg,
+	}
+
+	items, err := collector.Find()
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, item := range items {
+		found[string(item.Kind)+" "+item.Name] = true
+	}
+	want := []string{
+		"session " + sessions.SanitizeName("app", "gone"),
+		"session " + sessions.SanitizeName("app", "old"),
+		"worktree worktrees/gone",
+		"branch done",
+		"branch gone",
+		"config removed",
+	}
+	for _, key := range want {
+		if !found[key] {
+			t.Errorf("Expected %q in dry run, got %v", key, items)
+		}
+	}
+	if len(items) != len(want) {
+		t.Errorf("Expected %d items, got %d: %v", len(want), len(items), items)
+	}
+
+	// The dry run changes nothing
+	if !sessions.SessionExists(sessions.SanitizeName("app", "old")) {
+		t.Error("Find must not kill sessions")
+	}
+
+	for _, result := range collector.Clean(items) {
+		if result.Err != nil {
+			t.Errorf("Cleaning %s %s failed: %v", result.Item.Kind, result.Item.Name, result.Err)
+		}
+	}
+
+	if sessions.SessionExists(sessions.SanitizeName("app", "old")) {
+		t.Error("Expected orphan session to be killed")
+	}
+	if !sessions.SessionExists(sessions.SanitizeName("app-v2", "main")) {
+		t.Error("Expected the sibling repository's session to be kept")
+	}
+	if !sessions.SessionExists(sessions.SanitizeName("app", "live")) || !sessions.SessionExists(sessions.SanitizeName("app", "mine")) {
+		t.Error("Expected sessions of live worktrees and other repositories to be kept")
+	}
+	if entries := cfg.GetBranchEntries(repoPath); len(entries) != 1 || entries[0] != "live" {
+		t.Errorf("Expected only the live branch in config, got %v", entries)
+	}
+
+	items, err = collector.Find()
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Expected nothing left after cleaning, got %v", items)
+	}
+}
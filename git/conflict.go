@@ -0,0 +1,307 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictChoice describes how a single conflict hunk should be resolved
+type ConflictChoice int
+
+const (
+	ConflictUnresolved ConflictChoice = iota // Keep conflict markers in place
+	ConflictOurs                             // Keep the current branch side
+	ConflictTheirs                           // Keep the incoming branch side
+	ConflictBoth                             // Keep ours followed by theirs
+	ConflictCustom                           // Use Resolution text (e.g. an AI suggestion)
+)
+
+// ConflictHunk represents one conflicted region of a file
+type ConflictHunk struct {
+	OursLabel   string // Label after <<<<<<< (usually HEAD)
+	TheirsLabel string // Label after >>>>>>> (usually the merged branch)
+	BaseLabel   string // Label after ||||||| (diff3 style, usually the common ancestor)
+	Ours        string
+	Base        string // Only populated when diff3 conflict style is used
+	Theirs      string
+	Choice      ConflictChoice
+	Resolution  string // Replacement text when Choice is ConflictCustom
+
+	// hasBase is set for diff3 style hunks, whose base section may be empty
+	hasBase bool
+}
+
+// ConflictFile represents a file containing merge conflict markers
+type ConflictFile struct {
+	Path  string // Path relative to the worktree root
+	Hunks []ConflictHunk
+
+	// chunks holds the text surrounding the hunks; len(chunks) == len(Hunks)+1
+	chunks []string
+}
+
+// ParseConflictFile splits file content into unconflicted chunks and conflict hunks
+// Supports both the default merge style and the diff3 style (with a base section)
+func ParseConflictFile(path, content string) *ConflictFile {
+	file := &ConflictFile{Path: path}
+
+	const (
+		stateText = iota
+		stateOurs
+		stateBase
+		stateTheirs
+	)
+
+	state := stateText
+	var text, ours, base, theirs strings.Builder
+	var hunk ConflictHunk
+
+	lines := strings.SplitAfter(content, "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, "\r\n")
+
+		switch {
+		case state == stateText && strings.HasPrefix(trimmed, "<<<<<<<"):
+			hunk = ConflictHunk{OursLabel: strings.TrimSpace(strings.TrimPrefix(trimmed, "<<<<<<<"))}
+			ours.Reset()
+			base.Reset()
+			theirs.Reset()
+			state = stateOurs
+
+		case state == stateOurs && strings.HasPrefix(trimmed, "|||||||"):
+			hunk.BaseLabel = strings.TrimSpace(strings.TrimPrefix(trimmed, "|||||||"))
+			hunk.hasBase = true
+			state = stateBase
+
+		case (state == stateOurs || state == stateBase) && trimmed == "=======":
+			state = stateTheirs
+
+		case state == stateTheirs && strings.HasPrefix(trimmed, ">>>>>>>"):
+			hunk.TheirsLabel = strings.TrimSpace(strings.TrimPrefix(trimmed, ">>>>>>>"))
+			hunk.Ours = ours.String()
+			hunk.Base = base.String()
+			hunk.Theirs = theirs.String()
+			file.chunks = append(file.chunks, text.String())
+			file.Hunks = append(file.Hunks, hunk)
+			text.Reset()
+			state = stateText
+
+		case state == stateOurs:
+			ours.WriteString(line)
+		case state == stateBase:
+			base.WriteString(line)
+		case state == stateTheirs:
+			theirs.WriteString(line)
+		default:
+			text.WriteString(line)
+		}
+	}
+
+	// An unterminated hunk is kept verbatim so no content is lost
+	if state != stateText {
+		text.WriteString(hunkMarkers(hunk, ours.String(), base.String(), theirs.String(), state == stateTheirs))
+	}
+	file.chunks = append(file.chunks, text.String())
+
+	return file
+}
+
+// hunkMarkers rebuilds conflict markers for a hunk that has not been resolved
+func hunkMarkers(h ConflictHunk, ours, base, theirs string, closed bool) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace("<<<<<<< "+h.OursLabel) + "\n")
+	b.WriteString(ours)
+	if h.hasBase {
+		b.WriteString(strings.TrimSpace("||||||| "+h.BaseLabel) + "\n")
+		b.WriteString(base)
+	}
+	if closed {
+		b.WriteString("=======\n")
+		b.WriteString(theirs)
+		b.WriteString(strings.TrimSpace(">>>>>>> "+h.TheirsLabel) + "\n")
+	}
+	return b.String()
+}
+
+// Text returns the replacement text for the hunk based on its current choice
+func (h ConflictHunk) Text() string {
+	switch h.Choice {
+	case ConflictOurs:
+		return h.Ours
+	case ConflictTheirs:
+		return h.Theirs
+	case ConflictBoth:
+		return h.Ours + h.Theirs
+	case ConflictCustom:
+		if h.Resolution != "" && !strings.HasSuffix(h.Resolution, "\n") {
+			return h.Resolution + "\n"
+		}
+		return h.Resolution
+	}
+	return hunkMarkers(h, h.Ours, h.Base, h.Theirs, true)
+}
+
+// Content rebuilds the file content, applying the choice made for each hunk
+// Hunks that are still unresolved keep their conflict markers
+func (f *ConflictFile) Content() string {
+	var b strings.Builder
+	for i, hunk := range f.Hunks {
+		b.WriteString(f.chunks[i])
+		b.WriteString(hunk.Text())
+	}
+	b.WriteString(f.chunks[len(f.chunks)-1])
+	return b.String()
+}
+
+// Unresolved returns the number of hunks that have no choice yet
+func (f *ConflictFile) Unresolved() int {
+	count := 0
+	for _, hunk := range f.Hunks {
+		if hunk.Choice == ConflictUnresolved {
+			count++
+		}
+	}
+	return count
+}
+
+// MergeOperation returns the in-progress operation that can have conflicts ("merge", "rebase" or "")
+func (m *Manager) MergeOperation(worktreePath string) string {
+	gitPath := func(name string) string {
+		cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--git-path", name)
+		output, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		p := strings.TrimSpace(string(output))
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(worktreePath, p)
+		}
+		return p
+	}
+
+	if p := gitPath("MERGE_HEAD"); p != "" {
+		if _, err := os.Stat(p); err == nil {
+			return "merge"
+		}
+	}
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		if p := gitPath(name); p != "" {
+			if _, err := os.Stat(p); err == nil {
+				return "rebase"
+			}
+		}
+	}
+	return ""
+}
+
+// GetConflictedFiles returns the paths of files with unresolved conflicts
+func (m *Manager) GetConflictedFiles(worktreePath string) ([]string, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "diff", "--name-only", "--diff-filter=U")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %s", string(output))
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// LoadConflictFile reads a conflicted file from the worktree and parses its conflict hunks
+func (m *Manager) LoadConflictFile(worktreePath, path string) (*ConflictFile, error) {
+	data, err := os.ReadFile(filepath.Join(worktreePath, path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ParseConflictFile(path, string(data)), nil
+}
+
+// ResolveConflictFile writes the resolved content of a conflicted file and stages it
+func (m *Manager) ResolveConflictFile(worktreePath string, file *ConflictFile) error {
+	if n := file.Unresolved(); n > 0 {
+		return fmt.Errorf("%s still has %d unresolved conflict(s)", file.Path, n)
+	}
+
+	fullPath := filepath.Join(worktreePath, file.Path)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", file.Path, err)
+	}
+	if err := os.WriteFile(fullPath, []byte(file.Content()), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Path, err)
+	}
+
+	return m.MarkConflictResolved(worktreePath, file.Path)
+}
+
+// MarkConflictResolved stages a file after its conflicts were fixed (e.g. in an editor)
+// Refuses to stage files that still contain conflict markers
+func (m *Manager) MarkConflictResolved(worktreePath, path string) error {
+	conflict, err := m.LoadConflictFile(worktreePath, path)
+	if err != nil {
+		return err
+	}
+	if len(conflict.Hunks) > 0 {
+		return fmt.Errorf("%s still contains conflict markers", path)
+	}
+
+	cmd := exec.Command("git", "-C", worktreePath, "add", "--", path)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %s", path, string(output))
+	}
+	return nil
+}
+
+// ContinueMerge completes an in-progress merge or rebase once all conflicts are resolved
+func (m *Manager) ContinueMerge(worktreePath string) error {
+	files, err := m.GetConflictedFiles(worktreePath)
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return fmt.Errorf("%d file(s) still have unresolved conflicts", len(files))
+	}
+
+	var cmd *exec.Cmd
+	switch m.MergeOperation(worktreePath) {
+	case "merge":
+		cmd = exec.Command("git", "-C", worktreePath, "commit", "--no-edit")
+	case "rebase":
+		cmd = exec.Command("git", "-C", worktreePath, "rebase", "--continue")
+		// Keep the existing commit messages instead of opening an editor
+		cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	default:
+		return fmt.Errorf("no merge or rebase in progress")
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		outputStr := string(output)
+		if strings.Contains(outputStr, "CONFLICT") {
+			return fmt.Errorf("merge conflict occurred while continuing: resolve the new conflicts and continue again")
+		}
+		return fmt.Errorf("failed to continue: %s", outputStr)
+	}
+	return nil
+}
+
+// AbortConflictedOperation aborts whichever merge or rebase is in progress
+func (m *Manager) AbortConflictedOperation(worktreePath string) error {
+	if m.MergeOperation(worktreePath) == "rebase" {
+		cmd := exec.Command("git", "-C", worktreePath, "rebase", "--abort")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to abort rebase: %s", string(output))
+		}
+		return nil
+	}
+	return m.AbortMerge(worktreePath)
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseConflictFile tests parsing of default and diff3 style conflict markers
+func TestParseConflictFile(t *testing.T) {
+	content := "header\n" +
+		"<<<<<<< HEAD\n" +
+		"ours line\n" +
+		"||||||| base\n" +
+		"base line\n" +
+		"=======\n" +
+		"theirs line\n" +
+		">>>>>>> feature\n" +
+		"middle\n" +
+		"<<<<<<< HEAD\n" +
+		"a\n" +
+		"=======\n" +
+		"b\n" +
+		">>>>>>> feature\n" +
+		"footer\n"
+
+	file := ParseConflictFile("file.txt", content)
+	if len(file.Hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(file.Hunks))
+	}
+
+	first := file.Hunks[0]
+	if first.Ours != "ours line\n" || first.Base != "base line\n" || first.Theirs != "theirs line\n" {
+		t.Errorf("Unexpected first hunk: %+v", first)
+	}
+	if first.OursLabel != "HEAD" || first.TheirsLabel != "feature" {
+		t.Errorf("Unexpected labels: %q / %q", first.OursLabel, first.TheirsLabel)
+	}
+	if file.Hunks[1].Base != "" {
+		t.Errorf("Expected empty base for default style hunk, got %q", file.Hunks[1].Base)
+	}
+
+	t.Run("unresolved diff3 hunks keep the base label", func(t *testing.T) {
+		if got := file.Hunks[0].Text(); got != "<<<<<<< HEAD\nours line\n||||||| base\nbase line\n=======\ntheirs line\n>>>>>>> feature\n" {
+			t.Errorf("Expected the markers unchanged, got:\n%s", got)
+		}
+		empty := ParseConflictFile("file.txt", "<<<<<<< HEAD\na\n||||||| abc123\n=======\nb\n>>>>>>> feature\n")
+		if got := empty.Content(); got != "<<<<<<< HEAD\na\n||||||| abc123\n=======\nb\n>>>>>>> feature\n" {
+			t.Errorf("Expected the empty base section kept, got:\n%s", got)
+		}
+	})
+
+	t.Run("unresolved hunks keep markers", func(t *testing.T) {
+		if file.Unresolved() != 2 {
+			t.Errorf("Expected 2 unresolved hunks, got %d", file.Unresolved())
+		}
+		if !strings.Contains(file.Content(), "<<<<<<< HEAD\na\n=======\nb\n>>>>>>> feature\n") {
+			t.Errorf("Expected markers to be preserved, got:\n%s", file.Content())
+		}
+	})
+
+	t.Run("choices are applied", func(t *testing.T) {
+		file.Hunks[0].Choice = ConflictTheirs
+		file.Hunks[1].Choice = ConflictBoth
+		expected := "header\ntheirs line\nmiddle\na\nb\nfooter\n"
+		if got := file.Content(); got != expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+		}
+
+		file.Hunks[1].Choice = ConflictCustom
+		file.Hunks[1].Resolution = "merged"
+		expected = "header\ntheirs line\nmiddle\nmerged\nfooter\n"
+		if got := file.Content(); got != expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", expected, got)
+		}
+	})
+}
+
+// TestResolveConflictsAndContinueMerge tests the full conflict resolution flow
+func TestResolveConflictsAndContinueMerge(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoPath, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write README: %v", err)
+		}
+	}
+
+	run("checkout", "-b", "feature")
+	write("# Feature Repo\n")
+	run("commit", "-am", "feature change")
+	run("checkout", "-")
+	write("# Main Repo\n")
+	run("commit", "-am", "main change")
+
+	gitMgr := NewManager(repoPath)
+	err := gitMgr.MergeBranch(repoPath, "feature")
+	if err == nil || !strings.Contains(err.Error(), "merge conflict") {
+		t.Fatalf("Expected merge conflict error, got %v", err)
+	}
+
+	if op := gitMgr.MergeOperation(repoPath); op != "merge" {
+		t.Errorf("Expected merge operation in progress, got %q", op)
+	}
+
+	files, err := gitMgr.GetConflictedFiles(repoPath)
+	if err != nil {
+		t.Fatalf("GetConflictedFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0] != "README.md" {
+		t.Fatalf("Expected README.md to be conflicted, got %v", files)
+	}
+
+	file, err := gitMgr.LoadConflictFile(repoPath, "README.md")
+	if err != nil {
+		t.Fatalf("LoadConflictFile failed: %v", err)
+	}
+	if len(file.Hunks) != 1 || file.Hunks[0].Base != "# Test Repo\n" {
+		t.Fatalf("Expected one diff3 hunk with base content, got %+v", file.Hunks)
+	}
+
+	if err := gitMgr.ContinueMerge(repoPath); err == nil {
+		t.Error("Expected ContinueMerge to fail while conflicts remain")
+	}
+
+	file.Hunks[0].Choice = ConflictTheirs
+	if err := gitMgr.ResolveConflictFile(repoPath, file); err != nil {
+		t.Fatalf("ResolveConflictFile failed: %v", err)
+	}
+	if err := gitMgr.ContinueMerge(repoPath); err != nil {
+		t.Fatalf("ContinueMerge failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(repoPath, "README.md"))
+	if string(data) != "# Feature Repo\n" {
+		t.Errorf("Expected resolved content, got %q", string(data))
+	}
+	if op := gitMgr.MergeOperation(repoPath); op != "" {
+		t.Errorf("Expected no operation in progress after continue, got %q", op)
+	}
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestChangedFiles tests reading the touched files from a diff
+func TestChangedFiles(t *testing.T) {
+	diff := `diff --git a/api/server.go b/api/server.go
+index 1111111..2222222 100644
+--- a/api/server.go
++++ b/api/server.go
+@@ -1 +1 @@
+-old
++new
+diff --git a/docs/old name.md b/docs/new name.md
+similarity index 100%
+rename from docs/old name.md
+rename to docs/new name.md
+diff --git a/web/logo.png b/web/logo.png
+Binary files a/web/logo.png and b/web/logo.png differ
+diff --git a/api/server.go b/api/server.go
+`
+	want := []string{"api/server.go", "docs/old name.md", "docs/new name.md", "web/logo.png"}
+	if got := ChangedFiles(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFiles() = %q, want %q", got, want)
+	}
+	if got := ChangedFiles(""); len(got) != 0 {
+		t.Errorf("Expected no files for an empty diff, got %q", got)
+	}
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PrunableWorktrees lists worktree records git would prune (their directory was deleted)
+// Each entry is git's description, e.g. "worktrees/x: gitdir file points to non-existent location"
+func (m *Manager) PrunableWorktrees() ([]string, error) {
+	cmd := exec.Command("git", "-C", m.repoPath, "worktree", "prune", "--dry-run", "--verbose")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check prunable worktrees: %s", strings.TrimSpace(string(output)))
+	}
+
+	var prunable []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			prunable = append(prunable, strings.TrimPrefix(line, "Removing "))
+		}
+	}
+	return prunable, nil
+}
+
+// PruneWorktrees removes the records of worktrees whose directory was deleted
+func (m *Manager) PruneWorktrees() error {
+	cmd := exec.Command("git", "-C", m.repoPath, "worktree", "prune")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MergedBranches lists local branches fully merged into the base branch
+// The base branch and protected branches (main, master, develop...) are never listed
+func (m *Manager) MergedBranches(baseBranch string) ([]string, error) {
+	if baseBranch == "" {
+		return nil, fmt.Errorf("base branch not set")
+	}
+
+	cmd := exec.Command("git", "-C", m.repoPath, "branch", "--merged", baseBranch, "--format=%(refname:short)")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merged branches: %w", err)
+	}
+
+	var merged []string
+	for _, branch := range strings.Split(string(output), "\n") {
+		branch = strings.TrimSpace(branch)
+		if branch == "" || branch == baseBranch || getLocalBranchName(baseBranch) == branch || isProtectedBranch(branch) {
+			continue
+		}
+		merged = append(merged, branch)
+	}
+	return merged, nil
+}
+
+// DeleteMergedBranch deletes a local branch only if it is fully merged (git branch -d)
+func (m *Manager) DeleteMergedBranch(branchName string) error {
+	if branchName == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+
+	cmd := exec.Command("git", "-C", m.repoPath, "branch", "-d", branchName)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete branch: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
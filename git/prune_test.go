@@ -0,0 +1,83 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// runGit runs a git command in dir and fails the test on error
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TestPruneWorktreesAndMergedBranches tests finding and cleaning deleted worktrees and merged branches
+func TestPruneWorktreesAndMergedBranches(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	gitMgr := NewManager(repoPath)
+	base := runGit(t, repoPath, "branch", "--show-current")
+
+	// "done" is merged, "wip" has its own commit, "gone" lives in a worktree deleted by hand
+	runGit(t, repoPath, "branch", "done")
+	runGit(t, repoPath, "checkout", "-q", "-b", "wip")
+	if err := os.WriteFile(filepath.Join(repoPath, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	runGit(t, repoPath, "add", "wip.txt")
+	runGit(t, repoPath, "commit", "-q", "-m", "wip")
+	runGit(t, repoPath, "checkout", "-q", base)
+
+	worktreePath := filepath.Join(repoPath, ".workspaces", "gone")
+	runGit(t, repoPath, "worktree", "add", "-q", "-b", "gone", worktreePath)
+	if err := os.RemoveAll(worktreePath); err != nil {
+		t.Fatalf("Failed to remove worktree directory: %v", err)
+	}
+
+	prunable, err := gitMgr.PrunableWorktrees()
+	if err != nil {
+		t.Fatalf("PrunableWorktrees failed: %v", err)
+	}
+	if len(prunable) != 1 || !strings.HasPrefix(prunable[0], "worktrees/gone:") {
+		t.Errorf("Expected the deleted worktree to be prunable, got %v", prunable)
+	}
+
+	merged, err := gitMgr.MergedBranches(base)
+	if err != nil {
+		t.Fatalf("MergedBranches failed: %v", err)
+	}
+	if !reflect.DeepEqual(merged, []string{"done", "gone"}) {
+		t.Errorf("Expected merged branches [done gone], got %v", merged)
+	}
+	if _, err := gitMgr.MergedBranches(""); err == nil {
+		t.Error("Expected MergedBranches to fail without a base branch")
+	}
+
+	if err := gitMgr.PruneWorktrees(); err != nil {
+		t.Fatalf("PruneWorktrees failed: %v", err)
+	}
+	if prunable, _ := gitMgr.PrunableWorktrees(); len(prunable) != 0 {
+		t.Errorf("Expected nothing left to prune, got %v", prunable)
+	}
+
+	if err := gitMgr.DeleteMergedBranch("done"); err != nil {
+		t.Errorf("DeleteMergedBranch failed: %v", err)
+	}
+	if err := gitMgr.DeleteMergedBranch("wip"); err == nil {
+		t.Error("Expected DeleteMergedBranch to refuse an unmerged branch")
+	}
+	if branches := runGit(t, repoPath, "branch", "--format=%(refname:short)", "--list", "done", "wip"); branches != "wip" {
+		t.Errorf("Expected only wip to remain, got %q", branches)
+	}
+}
@@ -0,0 +1,120 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// commitFile writes a file and commits it in dir
+func commitFile(t *testing.T, dir, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-q", "-m", message)
+}
+
+// TestRebaseOnto_RestacksAfterSquashMerge tests moving a stacked branch onto the base branch
+// once its parent was squash-merged, without replaying the parent's commits
+func TestRebaseOnto_RestacksAfterSquashMerge(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	gitMgr := NewManager(repoPath)
+	base := runGit(t, repoPath, "branch", "--show-current")
+
+	// models <- api, each in its own commit
+	runGit(t, repoPath, "checkout", "-q", "-b", "models")
+	commitFile(t, repoPath, "models.go", "package models\n", "Add models")
+	commitFile(t, repoPath, "README.md", "# Test Repo\nmodels\n", "Document models")
+	runGit(t, repoPath, "checkout", "-q", base)
+	forkPoint, err := gitMgr.ResolveRef("models")
+	if err != nil {
+		t.Fatalf("ResolveRef failed: %v", err)
+	}
+	apiPath := filepath.Join(repoPath, ".workspaces", "api")
+	runGit(t, repoPath, "worktree", "add", "-q", "-b", "api", apiPath, "models")
+	commitFile(t, apiPath, "api.go", "package api\n", "Add api")
+
+	// models is squash-merged: the base gets the same changes in a different commit
+	runGit(t, repoPath, "merge", "-q", "--squash", "models")
+	runGit(t, repoPath, "commit", "-q", "-m", "Models (#1)")
+	commitFile(t, repoPath, "README.md", "# Test Repo\nmodels, edited on main\n", "Edit README")
+
+	if err := gitMgr.RebaseOnto(apiPath, base, forkPoint); err != nil {
+		t.Fatalf("RebaseOnto failed: %v", err)
+	}
+	if log := runGit(t, apiPath, "log", "--format=%s", base+"..HEAD"); log != "Add api" {
+		t.Errorf("Expected only api's commit on top of %s, got %q", base, log)
+	}
+
+	if err := gitMgr.RebaseOnto(apiPath, "missing", ""); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected an error for a missing base, got %v", err)
+	}
+}
+
+// TestRebaseOnto_LeavesConflictsToResolve tests that conflicting rebases stay in progress
+func TestRebaseOnto_LeavesConflictsToResolve(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	gitMgr := NewManager(repoPath)
+	base := runGit(t, repoPath, "branch", "--show-current")
+
+	featurePath := filepath.Join(repoPath, ".workspaces", "feature")
+	runGit(t, repoPath, "worktree", "add", "-q", "-b", "feature", featurePath)
+	commitFile(t, featurePath, "README.md", "# Feature\n", "Feature title")
+	commitFile(t, repoPath, "README.md", "# Main\n", "Main title")
+
+	err := gitMgr.RebaseOnto(featurePath, base, "")
+	if err == nil || !strings.Contains(err.Error(), "rebase conflict") {
+		t.Fatalf("Expected a rebase conflict, got %v", err)
+	}
+	if op := gitMgr.MergeOperation(featurePath); op != "rebase" {
+		t.Errorf("Expected the rebase to stay in progress, got %q", op)
+	}
+}
+
+// TestFastForwardWithOutput tests fast-forwarding to the upstream and refusing diverged branches
+func TestFastForwardWithOutput(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+	base := runGit(t, repoPath, "branch", "--show-current")
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	runGit(t, repoPath, "clone", "-q", repoPath, clonePath)
+	runGit(t, clonePath, "config", "user.email", "test@example.com")
+	runGit(t, clonePath, "config", "user.name", "Test User")
+	gitMgr := NewManager(clonePath)
+
+	if upstream := gitMgr.UpstreamRef(clonePath, base); upstream != "origin/"+base {
+		t.Fatalf("Expected upstream origin/%s, got %q", base, upstream)
+	}
+	runGit(t, clonePath, "checkout", "-q", "-b", "local")
+	if upstream := gitMgr.UpstreamRef(clonePath, "local"); upstream != "origin/local" {
+		t.Errorf("Expected origin/local for a branch without upstream, got %q", upstream)
+	}
+	runGit(t, clonePath, "checkout", "-q", base)
+
+	commitFile(t, repoPath, "remote.txt", "remote\n", "Remote change")
+	runGit(t, clonePath, "fetch", "-q")
+	if _, err := gitMgr.FastForwardWithOutput(clonePath, "origin/"+base); err != nil {
+		t.Fatalf("FastForwardWithOutput failed: %v", err)
+	}
+	if head, remote := runGit(t, clonePath, "rev-parse", "HEAD"), runGit(t, repoPath, "rev-parse", "HEAD"); head != remote {
+		t.Errorf("Expected HEAD %s, got %s", remote, head)
+	}
+
+	// Diverged branches are left alone instead of getting a merge commit
+	commitFile(t, repoPath, "remote2.txt", "remote\n", "Another remote change")
+	commitFile(t, clonePath, "local.txt", "local\n", "Local change")
+	runGit(t, clonePath, "fetch", "-q")
+	head := runGit(t, clonePath, "rev-parse", "HEAD")
+	if _, err := gitMgr.FastForwardWithOutput(clonePath, "origin/"+base); err == nil {
+		t.Error("Expected diverged branch to fail")
+	}
+	if after := runGit(t, clonePath, "rev-parse", "HEAD"); after != head {
+		t.Errorf("Expected HEAD to stay at %s, got %s", head, after)
+	}
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Stash represents a single entry in the git stash list
+type Stash struct {
+	Ref     string // Stash reference (e.g. stash@{0})
+	Branch  string // Branch the stash was created on
+	Message string // Stash message without the "On <branch>:" prefix
+	Date    string // Relative creation date (e.g. "2 hours ago")
+}
+
+// ListStashes returns the stash entries of the repository, newest first
+// Stashes are shared by all worktrees of a repository; use Branch to filter per worktree
+func (m *Manager) ListStashes(worktreePath string) ([]Stash, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "stash", "list", "--format=%gd%x00%gs%x00%cr")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %s", string(output))
+	}
+
+	var stashes []Stash
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		branch, message := parseStashSubject(parts[1])
+		stashes = append(stashes, Stash{
+			Ref:     parts[0],
+			Branch:  branch,
+			Message: message,
+			Date:    parts[2],
+		})
+	}
+	return stashes, nil
+}
+
+// parseStashSubject splits "WIP on <branch>: <msg>" or "On <branch>: <msg>" into branch and message
+func parseStashSubject(subject string) (string, string) {
+	rest := subject
+	if strings.HasPrefix(rest, "WIP on ") {
+		rest = strings.TrimPrefix(rest, "WIP on ")
+	} else if strings.HasPrefix(rest, "On ") {
+		rest = strings.TrimPrefix(rest, "On ")
+	} else {
+		return "", subject
+	}
+
+	branch, message, found := strings.Cut(rest, ": ")
+	if !found {
+		return "", subject
+	}
+	return branch, message
+}
+
+// PushStash stashes all uncommitted changes (including untracked files) in the worktree
+func (m *Manager) PushStash(worktreePath, message string) error {
+	args := []string{"-C", worktreePath, "stash", "push", "--include-untracked"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+
+	cmd := exec.Command("git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to stash changes: %s", string(output))
+	}
+	if strings.Contains(string(output), "No local changes to save") {
+		return fmt.Errorf("no local changes to stash")
+	}
+	return nil
+}
+
+// PopStash applies the given stash to the worktree and removes it from the stash list
+func (m *Manager) PopStash(worktreePath, ref string) error {
+	return m.runStashCommand(worktreePath, "pop", ref)
+}
+
+// ApplyStash applies the given stash to the worktree and keeps it in the stash list
+func (m *Manager) ApplyStash(worktreePath, ref string) error {
+	return m.runStashCommand(worktreePath, "apply", ref)
+}
+
+// DropStash removes the given stash from the stash list
+func (m *Manager) DropStash(worktreePath, ref string) error {
+	return m.runStashCommand(worktreePath, "drop", ref)
+}
+
+// runStashCommand runs a stash subcommand against a stash reference
+func (m *Manager) runStashCommand(worktreePath, action, ref string) error {
+	args := []string{"-C", worktreePath, "stash", action}
+	if ref != "" {
+		args = append(args, ref)
+	}
+
+	cmd := exec.Command("git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		outputStr := string(output)
+		if strings.Contains(outputStr, "CONFLICT") {
+			return fmt.Errorf("stash %s caused a merge conflict; the stash was kept", action)
+		}
+		return fmt.Errorf("failed to %s stash: %s", action, outputStr)
+	}
+	return nil
+}
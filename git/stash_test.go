@@ -0,0 +1,71 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseStashSubject tests extracting the branch from stash subjects
+func TestParseStashSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		branch  string
+		message string
+	}{
+		{"WIP on main: abc123 Initial commit", "main", "abc123 Initial commit"},
+		{"On feature/x: my changes", "feature/x", "my changes"},
+		{"autostash", "", "autostash"},
+	}
+
+	for _, tt := range tests {
+		branch, message := parseStashSubject(tt.subject)
+		if branch != tt.branch || message != tt.message {
+			t.Errorf("parseStashSubject(%q) = (%q, %q), want (%q, %q)", tt.subject, branch, message, tt.branch, tt.message)
+		}
+	}
+}
+
+// TestStashPushListPop tests the stash lifecycle in a worktree
+func TestStashPushListPop(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	gitMgr := NewManager(repoPath)
+
+	if err := gitMgr.PushStash(repoPath, "nothing"); err == nil {
+		t.Error("Expected PushStash to fail without changes")
+	}
+
+	untracked := filepath.Join(repoPath, "notes.txt")
+	if err := os.WriteFile(untracked, []byte("notes\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := gitMgr.PushStash(repoPath, "my notes"); err != nil {
+		t.Fatalf("PushStash failed: %v", err)
+	}
+	if _, err := os.Stat(untracked); !os.IsNotExist(err) {
+		t.Error("Expected untracked file to be stashed")
+	}
+
+	stashes, err := gitMgr.ListStashes(repoPath)
+	if err != nil {
+		t.Fatalf("ListStashes failed: %v", err)
+	}
+	if len(stashes) != 1 || stashes[0].Ref != "stash@{0}" || stashes[0].Message != "my notes" || stashes[0].Branch == "" {
+		t.Fatalf("Unexpected stash list: %+v", stashes)
+	}
+
+	if err := gitMgr.PopStash(repoPath, stashes[0].Ref); err != nil {
+		t.Fatalf("PopStash failed: %v", err)
+	}
+	if _, err := os.Stat(untracked); err != nil {
+		t.Error("Expected untracked file to be restored")
+	}
+
+	stashes, _ = gitMgr.ListStashes(repoPath)
+	if len(stashes) != 0 {
+		t.Errorf("Expected empty stash list after pop, got %+v", stashes)
+	}
+}
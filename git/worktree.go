@@ -29,12 +29,15 @@ type Worktree struct {
 	LastModified      time.Time        // Last modification time of the worktree directory
 	ClaudeSessionName string           // Sanitized tmux session name for Claude (e.g., "jean-feature-add-status")
 	// Beads integration
-	OpenIssues   int  `json:"-"` // Number of open beads issues
-	ClosedIssues int  `json:"-"` // Number of closed beads issues
-	HasBeads     bool `json:"-"` // Whether beads is initialized for this worktree
+	OpenIssues   int      `json:"-"` // Number of open beads issues
+	ClosedIssues int      `json:"-"` // Number of closed beads issues
+	HasBeads     bool     `json:"-"` // Whether beads is initialized for this worktree
+	IssueTitles  []string `json:"-"` // Titles of beads issues linked to this branch
 	// Enhanced info
-	AIWaiting bool   `json:"-"` // Whether AI session is waiting for input
-	Ports     []int  `json:"-"` // Active ports parsed from config files
+	AIWaiting    bool   `json:"-"` // Whether AI session is waiting for input
+	AgentState   string `json:"-"` // Agent state reported by hooks ("working", "waiting", "idle", "errored"), "" = unknown
+	AgentMessage string `json:"-"` // Last prompt, notification or error reported by the agent
+	Ports        []int  `json:"-"` // Active ports parsed from config files
 }
 
 // Manager handles Git worktree operations
@@ -480,6 +483,33 @@ func (m *Manager) executeSetupScript(workspacePath string) error {
 // Remove removes a worktree and automatically deletes the associated branch
 // Protects common base branches (main, master, develop, etc.) from deletion
 func (m *Manager) Remove(path string, force bool) error {
+	branchName, err := m.removeWorktree(path, force)
+	if err != nil {
+		return err
+	}
+
+	// Delete the branch if it's not a protected base branch
+	if branchName != "" && !isProtectedBranch(branchName) {
+		// Attempt to delete the branch - don't fail the operation if this fails
+		if err := m.DeleteBranch(branchName); err != nil {
+			// Log the warning but don't return error - worktree was already removed successfully
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete branch '%s': %v\n", branchName, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveWorktree removes a worktree like Remove but keeps its branch, leaving it to the caller
+// to delete (e.g. with DeleteMergedBranch)
+func (m *Manager) RemoveWorktree(path string, force bool) error {
+	_, err := m.removeWorktree(path, force)
+	return err
+}
+
+// removeWorktree runs the delete hooks around removing a worktree and returns its branch,
+// "" when it couldn't be determined
+func (m *Manager) removeWorktree(path string, force bool) (string, error) {
 	// Get the branch name before removing the worktree
 	branchName, err := m.GetCurrentBranchForWorktree(path)
 	if err != nil {
@@ -491,7 +521,7 @@ func (m *Manager) Remove(path string, force bool) error {
 	// Execute pre_delete hooks (blocking - if they fail, abort worktree removal)
 	ctx := m.getHookContext(path, branchName)
 	if err := m.executeHooksByName("pre_delete", ctx, true); err != nil {
-		return err
+		return "", err
 	}
 
 	// Remove the worktree
@@ -505,22 +535,13 @@ func (m *Manager) Remove(path string, force bool) error {
 
 	cmd := exec.Command("git", args...)
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to remove worktree: %s", string(output))
+		return "", fmt.Errorf("failed to remove worktree: %s", string(output))
 	}
 
 	// Execute post_delete hooks (non-blocking - errors are warnings)
 	m.executeHooksByName("post_delete", ctx, false)
 
-	// Delete the branch if it's not a protected base branch
-	if branchName != "" && !isProtectedBranch(branchName) {
-		// Attempt to delete the branch - don't fail the operation if this fails
-		if err := m.DeleteBranch(branchName); err != nil {
-			// Log the warning but don't return error - worktree was already removed successfully
-			fmt.Fprintf(os.Stderr, "Warning: failed to delete branch '%s': %v\n", branchName, err)
-		}
-	}
-
-	return nil
+	return branchName, nil
 }
 
 // isProtectedBranch checks if a branch name is a common base branch that should not be deleted
@@ -1029,8 +1050,8 @@ func (m *Manager) MergeBranch(worktreePath, baseBranch string) error {
 		return fmt.Errorf("base branch '%s' does not exist", baseBranch)
 	}
 
-	// Perform the merge
-	cmd = exec.Command("git", "-C", worktreePath, "merge", baseBranch, "--no-edit")
+	// Perform the merge (diff3 style keeps the base version in conflict markers)
+	cmd = exec.Command("git", "-C", worktreePath, "-c", "merge.conflictStyle=diff3", "merge", baseBranch, "--no-edit")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		outputStr := string(output)
@@ -1044,6 +1065,84 @@ func (m *Manager) MergeBranch(worktreePath, baseBranch string) error {
 	return nil
 }
 
+// ResolveRef returns the commit a branch or other ref points to
+func (m *Manager) ResolveRef(ref string) (string, error) {
+	cmd := exec.Command("git", "-C", m.repoPath, "rev-parse", "--verify", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("'%s' does not exist", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsAncestor reports whether commit is contained in target (git merge-base --is-ancestor)
+func (m *Manager) IsAncestor(worktreePath, commit, target string) (bool, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "merge-base", "--is-ancestor", commit, target)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to compare %s with %s: %s", commit, target, strings.TrimSpace(string(output)))
+}
+
+// FetchOriginRef fetches a ref from origin (e.g. "refs/heads/main" or "refs/pull/7/head") and
+// returns the commit it points to, "" when origin doesn't have it
+func (m *Manager) FetchOriginRef(worktreePath, ref string) (string, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "ls-remote", "--exit-code", "origin", ref)
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit code 2 means origin has no such ref
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 2 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to look up %s on origin: %w", ref, err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	fetchCmd := exec.Command("git", "-C", worktreePath, "fetch", "--quiet", "origin", ref)
+	if output, err := fetchCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	return fields[0], nil
+}
+
+// RebaseOnto moves the commits of the worktree's branch made since forkPoint onto newBase
+// (git rebase --onto newBase forkPoint). Without a fork point the branch is rebased onto
+// newBase as a whole. On conflicts the rebase is left in progress to be resolved
+func (m *Manager) RebaseOnto(worktreePath, newBase, forkPoint string) error {
+	if newBase == "" {
+		return fmt.Errorf("base branch not specified")
+	}
+	if _, err := m.ResolveRef(newBase); err != nil {
+		return fmt.Errorf("base branch %s", err.Error())
+	}
+
+	args := []string{"-C", worktreePath, "-c", "merge.conflictStyle=diff3", "rebase"}
+	if forkPoint != "" {
+		args = append(args, "--onto", newBase, forkPoint)
+	} else {
+		args = append(args, newBase)
+	}
+	cmd := exec.Command("git", args...)
+	// Keep the existing commit messages instead of opening an editor
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		outputStr := string(output)
+		if strings.Contains(outputStr, "CONFLICT") || strings.Contains(outputStr, "could not apply") {
+			return fmt.Errorf("rebase conflict occurred. Resolve the conflicts to continue the rebase")
+		}
+		return fmt.Errorf("failed to rebase: %s", strings.TrimSpace(outputStr))
+	}
+	return nil
+}
+
 // AbortMerge aborts an in-progress merge and returns to a clean state
 func (m *Manager) AbortMerge(worktreePath string) error {
 	cmd := exec.Command("git", "-C", worktreePath, "merge", "--abort")
@@ -1171,6 +1270,28 @@ func (m *Manager) PullBranchInPathWithOutput(path, branch string) (string, error
 	return outputStr, nil
 }
 
+// UpstreamRef returns the upstream (@{u}) of the branch checked out in path
+// Branches without an upstream fall back to origin/<branch>
+func (m *Manager) UpstreamRef(path, branch string) string {
+	cmd := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	output, err := cmd.Output()
+	if err != nil || strings.TrimSpace(string(output)) == "" {
+		return "origin/" + branch
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// FastForwardWithOutput fast-forwards the branch checked out in path to upstream and returns the git output
+// Unlike a pull it never creates a merge commit, a branch that diverged from upstream fails instead
+func (m *Manager) FastForwardWithOutput(path, upstream string) (string, error) {
+	cmd := exec.Command("git", "-C", path, "merge", "--ff-only", upstream)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("failed to fast-forward: %s", strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
 // ParsePullOutput extracts commit count information from git pull output
 // Returns (upToDate, commitsCount)
 func (m *Manager) ParsePullOutput(output string) (bool, int) {
@@ -1359,6 +1480,36 @@ func (m *Manager) GetDiffFromBase(worktreePath, baseBranch string) (string, erro
 	return string(output), nil
 }
 
+// ChangedFiles returns the paths of the files a diff touches, both sides of renames included
+// Used to match the changes of a branch against CODEOWNERS
+func ChangedFiles(diff string) []string {
+	var files []string
+	seen := map[string]bool{}
+	add := func(file string) {
+		if file != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			// "diff --git a/<path> b/<path>" names the same path twice unless the file is renamed
+			paths := strings.TrimPrefix(line, "diff --git ")
+			if half := (len(paths) - 1) / 2; len(paths)%2 == 1 && strings.HasPrefix(paths, "a/") &&
+				paths[half:half+3] == " b/" && paths[2:half] == paths[half+3:] {
+				add(paths[2:half])
+			}
+		case strings.HasPrefix(line, "rename from "):
+			add(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			add(strings.TrimPrefix(line, "rename to "))
+		}
+	}
+	return files
+}
+
 // GetBranchRemoteURL constructs a GitHub URL for a given branch
 // Returns the branch URL if the branch exists on remote, otherwise returns the repo URL
 func (m *Manager) GetBranchRemoteURL(branchName string) (string, error) {
@@ -0,0 +1,204 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WorkflowRun is a GitHub Actions workflow run
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`          // Workflow name, e.g. "CI"
+	Title      string    `json:"display_title"` // Commit message or PR title the run is for
+	Event      string    `json:"event"`         // What triggered the run, e.g. "push"
+	Status     string    `json:"status"`        // "queued", "in_progress", "completed", ...
+	Conclusion string    `json:"conclusion"`    // "success", "failure", "cancelled", ... once completed
+	HeadSHA    string    `json:"head_sha"`
+	URL        string    `json:"html_url"`
+	Attempt    int       `json:"run_attempt"`
+	StartedAt  time.Time `json:"run_started_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WorkflowJob is a job of a workflow run
+type WorkflowJob struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	Conclusion  string         `json:"conclusion"`
+	URL         string         `json:"html_url"`
+	StartedAt   time.Time      `json:"started_at"`
+	CompletedAt time.Time      `json:"completed_at"`
+	Steps       []WorkflowStep `json:"steps"`
+}
+
+// WorkflowStep is a step of a job
+type WorkflowStep struct {
+	Name       string `json:"name"`
+	Conclusion string `json:"conclusion"`
+}
+
+// State combines the status and conclusion of a run into one of "success", "failure",
+// "cancelled", "skipped", "running" or "queued"
+func (r WorkflowRun) State() string {
+	return workflowState(r.Status, r.Conclusion)
+}
+
+// Duration returns how long the run took, or has been running
+func (r WorkflowRun) Duration(now time.Time) time.Duration {
+	if r.StartedAt.IsZero() {
+		return 0
+	}
+	if r.Status != "completed" {
+		return now.Sub(r.StartedAt)
+	}
+	return r.UpdatedAt.Sub(r.StartedAt)
+}
+
+// State combines the status and conclusion of a job like WorkflowRun.State
+func (j WorkflowJob) State() string {
+	return workflowState(j.Status, j.Conclusion)
+}
+
+// Duration returns how long the job took, or has been running
+func (j WorkflowJob) Duration(now time.Time) time.Duration {
+	if j.StartedAt.IsZero() {
+		return 0
+	}
+	if j.CompletedAt.IsZero() {
+		return now.Sub(j.StartedAt)
+	}
+	return j.CompletedAt.Sub(j.StartedAt)
+}
+
+// FailedStep returns the name of the step the job failed at, "" if none failed
+func (j WorkflowJob) FailedStep() string {
+	for _, step := range j.Steps {
+		if step.Conclusion == "failure" {
+			return step.Name
+		}
+	}
+	return ""
+}
+
+// workflowState maps the status and conclusion of a run or job to its state
+func workflowState(status, conclusion string) string {
+	switch status {
+	case "completed":
+		switch conclusion {
+		case "success", "neutral":
+			return "success"
+		case "cancelled", "skipped":
+			return conclusion
+		default:
+			// failure, timed_out, action_required, startup_failure, stale
+			return "failure"
+		}
+	case "in_progress":
+		return "running"
+	default:
+		// queued, requested, waiting, pending
+		return "queued"
+	}
+}
+
+// GetWorkflowRuns gets the latest workflow runs of a branch, newest first
+func (m *Manager) GetWorkflowRuns(worktreePath, branch string, limit int) ([]WorkflowRun, error) {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Runs []WorkflowRun `json:"workflow_runs"`
+	}
+	path := fmt.Sprintf("/repos/%s/actions/runs?branch=%s&per_page=%d", repo, url.QueryEscape(branch), limit)
+	if err := m.rest(http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get workflow runs: %w", err)
+	}
+	return response.Runs, nil
+}
+
+// GetRunJobs gets the jobs of the latest attempt of a workflow run
+func (m *Manager) GetRunJobs(worktreePath string, runID int64) ([]WorkflowJob, error) {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Jobs []WorkflowJob `json:"jobs"`
+	}
+	path := fmt.Sprintf("/repos/%s/actions/runs/%d/jobs?filter=latest&per_page=100", repo, runID)
+	if err := m.rest(http.MethodGet, path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	return response.Jobs, nil
+}
+
+// GetJobLog gets the log of a job as plain text
+// GitHub only serves the log once the job has finished
+func (m *Manager) GetJobLog(worktreePath string, jobID int64) (string, error) {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	data, err := m.do(http.MethodGet, fmt.Sprintf("%s/repos/%s/actions/jobs/%d/logs", m.apiURL, repo, jobID), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job log: %w", err)
+	}
+	return string(data), nil
+}
+
+// RerunFailedJobs re-runs the failed jobs of a workflow run, and the jobs depending on them
+func (m *Manager) RerunFailedJobs(worktreePath string, runID int64) error {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return err
+	}
+	if err := m.rest(http.MethodPost, fmt.Sprintf("/repos/%s/actions/runs/%d/rerun-failed-jobs", repo, runID), nil, nil); err != nil {
+		return fmt.Errorf("failed to re-run failed jobs: %w", err)
+	}
+	return nil
+}
+
+// logTimestamp is the timestamp GitHub puts in front of every log line
+var logTimestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+
+// CleanJobLog strips the timestamps and group markers of a job log, keeping errors visible
+func CleanJobLog(log string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(log, "\r\n", "\n"), "\n") {
+		line = logTimestamp.ReplaceAllString(line, "")
+		switch {
+		case strings.HasPrefix(line, "##[group]"):
+			line = "▸ " + strings.TrimPrefix(line, "##[group]")
+		case strings.HasPrefix(line, "##[endgroup]"):
+			continue
+		case strings.HasPrefix(line, "##[error]"):
+			line = "Error: " + strings.TrimPrefix(line, "##[error]")
+		case strings.HasPrefix(line, "##[warning]"):
+			line = "Warning: " + strings.TrimPrefix(line, "##[warning]")
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// FailureExcerpt returns the part of a cleaned job log explaining the failure: up to maxLines
+// lines ending at the last error, or the end of the log when it has no error
+func FailureExcerpt(lines []string, maxLines int) []string {
+	end := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "Error: ") {
+			end = i + 1
+			break
+		}
+	}
+	return lines[max(0, end-maxLines):end]
+}
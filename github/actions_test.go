@@ -0,0 +1,81 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestWorkflowRuns_REST tests listing runs and jobs, fetching a log and re-running failed jobs
+func TestWorkflowRuns_REST(t *testing.T) {
+	m, requests := newFakeGitHub(t, map[string]string{
+		"GET /repos/o/r/actions/runs": `{"workflow_runs":[
+			{"id":11,"name":"CI","display_title":"Fix login","event":"push","status":"completed","conclusion":"failure","run_attempt":1,
+			 "run_started_at":"2026-10-01T10:00:00Z","updated_at":"2026-10-01T10:04:30Z"},
+			{"id":10,"name":"Lint","status":"in_progress","conclusion":null,"run_started_at":"2026-10-01T10:00:00Z"}]}`,
+		"GET /repos/o/r/actions/runs/11/jobs": `{"jobs":[
+			{"id":101,"name":"test","status":"completed","conclusion":"failure","started_at":"2026-10-01T10:00:10Z","completed_at":"2026-10-01T10:02:10Z",
+			 "steps":[{"name":"Checkout","conclusion":"success"},{"name":"Run tests","conclusion":"failure"}]},
+			{"id":102,"name":"build","status":"completed","conclusion":"success"}]}`,
+		"GET /repos/o/r/actions/jobs/101/logs":              "2026-10-01T10:01:00.1234567Z ok\n",
+		"POST /repos/o/r/actions/runs/11/rerun-failed-jobs": `201 {}`,
+	})
+	dir := gitRepoWithOrigin(t, "https://github.com/o/r.git")
+
+	runs, err := m.GetWorkflowRuns(dir, "feature/login", 10)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("Expected 2 runs, got %d (%v)", len(runs), err)
+	}
+	now := time.Date(2026, 10, 1, 10, 1, 0, 0, time.UTC)
+	if runs[0].State() != "failure" || runs[0].Duration(now) != 4*time.Minute+30*time.Second || runs[0].Title != "Fix login" {
+		t.Errorf("Unexpected completed run: %+v", runs[0])
+	}
+	if runs[1].State() != "running" || runs[1].Duration(now) != time.Minute {
+		t.Errorf("Unexpected running run: %+v", runs[1])
+	}
+
+	jobs, err := m.GetRunJobs(dir, 11)
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d (%v)", len(jobs), err)
+	}
+	if jobs[0].State() != "failure" || jobs[0].FailedStep() != "Run tests" || jobs[0].Duration(now) != 2*time.Minute {
+		t.Errorf("Unexpected failed job: %+v", jobs[0])
+	}
+
+	if log, err := m.GetJobLog(dir, 101); err != nil || log != "2026-10-01T10:01:00.1234567Z ok\n" {
+		t.Errorf("Unexpected log %q (%v)", log, err)
+	}
+	if err := m.RerunFailedJobs(dir, 11); err != nil {
+		t.Errorf("RerunFailedJobs failed: %v", err)
+	}
+	if last := (*requests)[len(*requests)-1]; last.Method != http.MethodPost {
+		t.Errorf("Expected the re-run to be posted, got %+v", last)
+	}
+	if err := m.RerunFailedJobs(dir, 12); err == nil {
+		t.Error("Expected an error for an unknown run")
+	}
+}
+
+// TestFailureExcerpt tests cleaning a job log and keeping the lines leading to the failure
+func TestFailureExcerpt(t *testing.T) {
+	log := "2026-10-01T10:00:00.0000000Z ##[group]Run go test ./...\r\n" +
+		"2026-10-01T10:00:01.0000000Z go test ./...\r\n" +
+		"2026-10-01T10:00:02.0000000Z ##[endgroup]\r\n" +
+		"2026-10-01T10:00:03.0000000Z --- FAIL: TestLogin\r\n" +
+		"2026-10-01T10:00:04.0000000Z ##[error]Process completed with exit code 1.\r\n" +
+		"2026-10-01T10:00:05.0000000Z Post job cleanup.\r\n\r\n"
+
+	lines := CleanJobLog(log)
+	expected := []string{"▸ Run go test ./...", "go test ./...", "--- FAIL: TestLogin", "Error: Process completed with exit code 1.", "Post job cleanup."}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Unexpected cleaned log: %q", lines)
+	}
+
+	if excerpt := FailureExcerpt(lines, 2); !reflect.DeepEqual(excerpt, expected[2:4]) {
+		t.Errorf("Expected the lines up to the error, got %q", excerpt)
+	}
+	if excerpt := FailureExcerpt(expected[:2], 5); len(excerpt) != 2 {
+		t.Errorf("Expected the whole log without errors, got %q", excerpt)
+	}
+}
@@ -0,0 +1,268 @@
+package github
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// defaultAPIURL is the GitHub.com REST API, GITHUB_API_URL overrides it (GitHub Enterprise)
+const defaultAPIURL = "https://api.github.com"
+
+// graphQLURL returns the GraphQL endpoint that goes with the REST API root
+// GitHub Enterprise serves REST at /api/v3 and GraphQL at /api/graphql
+func (m *Manager) graphQLURL() string {
+	if root, ok := strings.CutSuffix(m.apiURL, "/v3"); ok {
+		return root + "/graphql"
+	}
+	return m.apiURL + "/graphql"
+}
+
+// authToken returns the token used for API requests, looked up on first use and again once
+// GitHub rejected it (see forgetToken), so a later 'gh auth login' is picked up
+func (m *Manager) authToken() (string, error) {
+	m.tokenMu.Lock()
+	defer m.tokenMu.Unlock()
+	if m.token == "" {
+		token, err := resolveToken(apiHost(m.apiURL))
+		if err != nil {
+			return "", err
+		}
+		m.token = token
+	}
+	return m.token, nil
+}
+
+// forgetToken drops a token GitHub rejected so the next request looks it up again
+func (m *Manager) forgetToken(token string) {
+	m.tokenMu.Lock()
+	defer m.tokenMu.Unlock()
+	if m.token == token {
+		m.token = ""
+	}
+}
+
+// apiHost returns the host gh keeps the login for an API root under: github.com for
+// api.github.com, the enterprise host for GitHub Enterprise
+func apiHost(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return "github.com"
+	}
+	// GitHub.com and GitHub Enterprise Cloud serve their API from an "api." subdomain
+	if host, ok := strings.CutPrefix(u.Host, "api."); ok && (host == "github.com" || strings.HasSuffix(host, ".ghe.com")) {
+		return host
+	}
+	return u.Host
+}
+
+// resolveToken finds a token for a GitHub host in the environment, gh's login or the system keyring
+func resolveToken(host string) (string, error) {
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token, nil
+		}
+	}
+	// gh knows where it stored the token, but may not be installed
+	if output, err := exec.Command("gh", "auth", "token", "--hostname", host).Output(); err == nil {
+		if token := strings.TrimSpace(string(output)); token != "" {
+			return token, nil
+		}
+	}
+	if token := hostsFileToken(host); token != "" {
+		return token, nil
+	}
+	if token := keyringToken(host); token != "" {
+		return token, nil
+	}
+	if host != "github.com" {
+		return "", fmt.Errorf("not authenticated with %s. Set GH_TOKEN or run 'gh auth login --hostname %s' to authenticate", host, host)
+	}
+	return "", fmt.Errorf("not authenticated with GitHub. Set GH_TOKEN or run 'gh auth login' to authenticate")
+}
+
+// hostsFileToken reads the token of a host from gh's hosts.yml, where gh keeps it
+// when no keyring is available
+func hostsFileToken(host string) string {
+	dir := os.Getenv("GH_CONFIG_DIR")
+	if dir == "" {
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			dir = filepath.Join(xdg, "gh")
+		} else if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".config", "gh")
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+
+	// Only the oauth_token of the host's block matters, no need for a YAML parser
+	inHost := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inHost = strings.TrimSpace(line) == host+":"
+			continue
+		}
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), ":"); inHost && ok && key == "oauth_token" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// keyringToken reads the token gh stored for a host in the system keyring
+func keyringToken(host string) string {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", "gh:"+host, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", "gh:"+host)
+	default:
+		return ""
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	token := strings.TrimSpace(string(output))
+	// The keyring library gh uses encodes values on macOS
+	if encoded, ok := strings.CutPrefix(token, "go-keyring-base64:"); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return ""
+		}
+		token = string(decoded)
+	}
+	return token
+}
+
+// do sends an authenticated API request and returns the response body
+// Responses outside 2xx are returned as errors with GitHub's message. A rejected token is
+// looked up again and the request retried once with the new one
+func (m *Manager) do(method, url string, payload interface{}) ([]byte, error) {
+	var body []byte
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	token, err := m.authToken()
+	if err != nil {
+		return nil, err
+	}
+	status, data, err := m.send(method, url, body, token)
+	if err == nil && status == http.StatusUnauthorized {
+		m.forgetToken(token)
+		if fresh, lookupErr := m.authToken(); lookupErr == nil && fresh != token {
+			status, data, err = m.send(method, url, body, fresh)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status < 200 || status > 299 {
+		return nil, apiError(status, data)
+	}
+	return data, nil
+}
+
+// send sends one API request with the given token and returns the status and body
+func (m *Manager) send(method, url string, body []byte, token string) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
+
+// apiError turns an error response into an error with GitHub's message and the details of
+// failed validations, e.g. "Validation Failed: A pull request already exists"
+func apiError(status int, data []byte) error {
+	var response struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil || response.Message == "" {
+		return fmt.Errorf("GitHub API returned %d", status)
+	}
+	message := response.Message
+	for _, e := range response.Errors {
+		if e.Message != "" {
+			message += ": " + e.Message
+		}
+	}
+	if status == http.StatusUnauthorized {
+		message += " (check GH_TOKEN or run 'gh auth login')"
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// rest sends a REST API request, decoding the response into result when not nil
+func (m *Manager) rest(method, path string, payload, result interface{}) error {
+	data, err := m.do(method, m.apiURL+path, payload)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// graphQL runs a GraphQL query and returns the raw response, which may contain errors
+// alongside partial data (see graphQLError)
+func (m *Manager) graphQL(query string, variables map[string]interface{}) ([]byte, error) {
+	return m.do(http.MethodPost, m.graphQLURL(), map[string]interface{}{"query": query, "variables": variables})
+}
+
+// graphQLError returns the first error of a GraphQL response, nil if there is none
+func graphQLError(data []byte) error {
+	var response struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("%s", response.Errors[0].Message)
+	}
+	return nil
+}
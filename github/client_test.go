@@ -0,0 +1,307 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeRequest is a request received by the fake GitHub server
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// newFakeGitHub starts a fake GitHub API answering "METHOD /path" routes with the given
+// status and JSON, and returns a manager talking to it with the requests it received
+func newFakeGitHub(t *testing.T, routes map[string]string) (*Manager, *[]fakeRequest) {
+	t.Helper()
+	var requests []fakeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		request := fakeRequest{Method: r.Method, Path: r.URL.Path}
+		_ = json.Unmarshal(data, &request.Body)
+		requests = append(requests, request)
+
+		response, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+			return
+		}
+		// "422 {...}" answers with a status other than 200
+		status := http.StatusOK
+		if code, body, found := strings.Cut(response, " "); found && len(code) == 3 && code[0] >= '1' && code[0] <= '5' {
+			fmt.Sscanf(code, "%d", &status)
+			response = body
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return &Manager{apiURL: server.URL, httpClient: server.Client(), token: "test-token"}, &requests
+}
+
+// gitRepoWithOrigin creates a git repository whose origin is a GitHub remote
+func gitRepoWithOrigin(t *testing.T, remote string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{{"init", "-q"}, {"remote", "add", "origin", remote}} {
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return dir
+}
+
+// TestPRLifecycle_REST tests creating, updating, retargeting, merging and reading the status of a PR
+func TestPRLifecycle_REST(t *testing.T) {
+	m, requests := newFakeGitHub(t, map[string]string{
+		"GET /repos/o/r":               `{"fork":false}`,
+		"POST /repos/o/r/pulls":        `201 {"html_url":"https://github.com/o/r/pull/7"}`,
+		"PATCH /repos/o/r/pulls/7":     `{}`,
+		"PUT /repos/o/r/pulls/7/merge": `{"merged":true}`,
+		"GET /repos/o/r/pulls/7":       `{"node_id":"PR_1","state":"closed","merged":true}`,
+		"POST /graphql":                `{"data":{"repository":{"pullRequests":{"nodes":[{"number":7,"title":"Login","headRefName":"login","url":"https://github.com/o/r/pull/7","state":"OPEN","author":{"login":"alice"}}]}}}}`,
+	})
+	repo := gitRepoWithOrigin(t, "git@github.com:o/r.git")
+
+	url, err := m.CreatePR(repo, "login", "main", "Login", "Adds login", true)
+	if err != nil || url != "https://github.com/o/r/pull/7" {
+		t.Fatalf("CreatePR returned %q, %v", url, err)
+	}
+	created := (*requests)[1].Body
+	if created["head"] != "login" || created["base"] != "main" || created["draft"] != true || created["body"] != "Adds login" {
+		t.Errorf("Unexpected create payload: %v", created)
+	}
+
+	pr, err := m.GetPRForBranch(repo, "login")
+	if err != nil || pr == nil || pr.Number != 7 || pr.Status != "open" || pr.Author.Login != "alice" {
+		t.Fatalf("GetPRForBranch returned %+v, %v", pr, err)
+	}
+
+	// PRs are updated by branch name, as jean does after pushing
+	if err := m.UpdatePR(repo, "login", "Better login", ""); err != nil {
+		t.Fatalf("UpdatePR failed: %v", err)
+	}
+	update := (*requests)[len(*requests)-1]
+	if update.Method != http.MethodPatch || update.Body["title"] != "Better login" || update.Body["body"] != nil {
+		t.Errorf("Unexpected update request: %+v", update)
+	}
+
+	// Stacked PRs are retargeted when the PR below them merges
+	if err := m.SetPRBase(url, "main"); err != nil {
+		t.Fatalf("SetPRBase failed: %v", err)
+	}
+	if retarget := (*requests)[len(*requests)-1]; retarget.Method != http.MethodPatch || retarget.Body["base"] != "main" {
+		t.Errorf("Unexpected retarget request: %+v", retarget)
+	}
+
+	if err := m.MergePR(repo, url, "squash"); err != nil {
+		t.Fatalf("MergePR failed: %v", err)
+	}
+	if merge := (*requests)[len(*requests)-1]; merge.Body["merge_method"] != "squash" {
+		t.Errorf("Unexpected merge payload: %v", merge.Body)
+	}
+	if err := m.MergePR(repo, url, "octopus"); err == nil {
+		t.Error("Expected an invalid merge method to be refused")
+	}
+
+	if status, err := m.GetPRStatus(url); err != nil || status != "merged" {
+		t.Errorf("GetPRStatus returned %q, %v", status, err)
+	}
+}
+
+// TestCreatePR_FromFork tests opening PRs from a fork on the repository it was forked from
+func TestCreatePR_FromFork(t *testing.T) {
+	m, requests := newFakeGitHub(t, map[string]string{
+		"GET /repos/me/r":       `{"fork":true,"parent":{"full_name":"o/r"}}`,
+		"POST /repos/o/r/pulls": `201 {"html_url":"https://github.com/o/r/pull/9"}`,
+	})
+	repo := gitRepoWithOrigin(t, "git@github.com:me/r.git")
+
+	url, err := m.CreatePR(repo, "login", "main", "Login", "", false)
+	if err != nil || url != "https://github.com/o/r/pull/9" {
+		t.Fatalf("CreatePR returned %q, %v", url, err)
+	}
+	if created := (*requests)[len(*requests)-1].Body; created["head"] != "me:login" {
+		t.Errorf("Expected the fork's branch as head, got %v", created["head"])
+	}
+	if name, err := m.GetRepoName(repo); err != nil || name != "o/r" {
+		t.Errorf("GetRepoName returned %q, %v", name, err)
+	}
+	if len(*requests) != 2 {
+		t.Errorf("Expected the parent lookup to be cached, got %d requests", len(*requests))
+	}
+}
+
+// TestDo_RetriesRejectedToken tests looking the token up again after GitHub rejected it
+func TestDo_RetriesRejectedToken(t *testing.T) {
+	m, _ := newFakeGitHub(t, map[string]string{
+		"GET /repos/o/r/pulls/7": `{"state":"open"}`,
+	})
+	m.token = "expired-token"
+	t.Setenv("GH_TOKEN", "test-token")
+
+	if status, err := m.GetPRStatus("https://github.com/o/r/pull/7"); err != nil || status != "open" {
+		t.Errorf("GetPRStatus returned %q, %v", status, err)
+	}
+	if m.token != "test-token" {
+		t.Errorf("Expected the new token to be kept, got %q", m.token)
+	}
+}
+
+// TestAPIErrors tests surfacing GitHub's error messages
+func TestAPIErrors(t *testing.T) {
+	m, _ := newFakeGitHub(t, map[string]string{
+		"POST /repos/o/r/pulls": `422 {"message":"Validation Failed","errors":[{"message":"A pull request already exists for o:login."}]}`,
+	})
+	repo := gitRepoWithOrigin(t, "https://github.com/o/r.git")
+
+	_, err := m.CreatePR(repo, "login", "main", "Login", "", false)
+	if err == nil || !strings.Contains(err.Error(), "Validation Failed: A pull request already exists") {
+		t.Errorf("Expected the validation error, got %v", err)
+	}
+
+	m.token = "wrong"
+	if _, err := m.GetPRStatus("https://github.com/o/r/pull/1"); err == nil || !strings.Contains(err.Error(), "gh auth login") {
+		t.Errorf("Expected an authentication hint, got %v", err)
+	}
+}
+
+// TestGetPRDetailsBatch tests fetching many PRs in few requests, skipping the ones that fail
+func TestGetPRDetailsBatch(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		queries = append(queries, request.Query)
+
+		data := map[string]interface{}{}
+		missing := false
+		for name, value := range request.Variables {
+			if !strings.HasPrefix(name, "p") {
+				continue
+			}
+			alias := "pr" + strings.TrimPrefix(name, "p")
+			if value.(float64) == 404 {
+				data[alias] = nil
+				missing = true
+				continue
+			}
+			data[alias] = map[string]interface{}{"pullRequest": map[string]interface{}{"state": "OPEN", "mergeable": "CONFLICTING"}}
+		}
+		response := map[string]interface{}{"data": data}
+		if missing {
+			response["errors"] = []map[string]string{{"message": "Could not resolve to a Repository"}}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+	m := &Manager{apiURL: server.URL, httpClient: server.Client(), token: "test-token"}
+
+	var urls []string
+	for i := 1; i <= prDetailsBatchSize+5; i++ {
+		urls = append(urls, fmt.Sprintf("https://github.com/o/r/pull/%d", i))
+	}
+	urls = append(urls, "https://github.com/o/r/pull/404", "https://gitlab.com/o/r/-/merge_requests/1")
+
+	details, err := m.GetPRDetailsBatch(urls)
+	if err != nil {
+		t.Fatalf("GetPRDetailsBatch failed: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Errorf("Expected 2 requests for %d PRs, got %d", len(urls), len(queries))
+	}
+	if len(details) != prDetailsBatchSize+5 {
+		t.Errorf("Expected details of the PRs found, got %d", len(details))
+	}
+	if d := details["https://github.com/o/r/pull/3"]; d == nil || d.State != "open" || d.Mergeable != "conflicting" {
+		t.Errorf("Unexpected details: %+v", d)
+	}
+	if _, ok := details["https://github.com/o/r/pull/404"]; ok {
+		t.Error("Expected the missing PR to be left out")
+	}
+	if !strings.Contains(queries[0], "pr0: repository(owner: $o0, name: $n0) { pullRequest(number: $p0)") {
+		t.Errorf("Expected aliased repositories in the query, got:\n%s", queries[0])
+	}
+}
+
+// TestResolveToken tests the token sources in order of precedence
+func TestResolveToken(t *testing.T) {
+	configDir := t.TempDir()
+	hosts := "gitlab.com:\n    oauth_token: glpat\ngithub.com:\n    user: alice\n    oauth_token: gho_fromfile\n    git_protocol: ssh\nghe.example.com:\n    oauth_token: gho_enterprise\n"
+	if err := os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte(hosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GH_CONFIG_DIR", configDir)
+	if token := hostsFileToken("github.com"); token != "gho_fromfile" {
+		t.Errorf("Expected the github.com token from hosts.yml, got %q", token)
+	}
+
+	// GitHub Enterprise logins are looked up under the host of the API URL
+	for apiURL, host := range map[string]string{
+		"https://api.github.com":           "github.com",
+		"https://ghe.example.com/api/v3":   "ghe.example.com",
+		"https://api.octocorp.ghe.com":     "octocorp.ghe.com",
+		"https://ghe.example.com:8443/api": "ghe.example.com:8443",
+	} {
+		if got := apiHost(apiURL); got != host {
+			t.Errorf("apiHost(%q) = %q, want %q", apiURL, got, host)
+		}
+	}
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("PATH", t.TempDir()) // No gh, so the hosts file answers
+	if token, err := resolveToken("ghe.example.com"); err != nil || token != "gho_enterprise" {
+		t.Errorf("Expected the enterprise token from hosts.yml, got %q, %v", token, err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "from-github-token")
+	t.Setenv("GH_TOKEN", "from-gh-token")
+	if token, err := resolveToken("github.com"); err != nil || token != "from-gh-token" {
+		t.Errorf("Expected GH_TOKEN first, got %q, %v", token, err)
+	}
+	t.Setenv("GH_TOKEN", "")
+	if token, _ := resolveToken("github.com"); token != "from-github-token" {
+		t.Errorf("Expected GITHUB_TOKEN, got %q", token)
+	}
+}
+
+// TestRepoFromRemote tests finding the repository of SSH and HTTPS remotes
+func TestRepoFromRemote(t *testing.T) {
+	for _, remote := range []string{
+		"git@github.com:coollabsio/jean-tui.git",
+		"https://github.com/coollabsio/jean-tui.git\n",
+		"https://github.com/coollabsio/jean-tui",
+		"ssh://git@github.com/coollabsio/jean-tui.git",
+	} {
+		if repo, err := repoFromRemote(remote); err != nil || repo != "coollabsio/jean-tui" {
+			t.Errorf("repoFromRemote(%q) = %q, %v", remote, repo, err)
+		}
+	}
+	if _, err := repoFromRemote("jean"); err == nil {
+		t.Error("Expected an error for a remote without owner")
+	}
+
+	m := &Manager{apiURL: "https://ghe.example.com/api/v3"}
+	if url := m.graphQLURL(); url != "https://ghe.example.com/api/graphql" {
+		t.Errorf("Unexpected GitHub Enterprise GraphQL URL %q", url)
+	}
+}
@@ -0,0 +1,155 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// codeOwnersDirs are the directories GitHub looks for a CODEOWNERS file in, in order
+// Only the first file found is used
+var codeOwnersDirs = []string{".github", "", "docs"}
+
+// codeOwnersRule is a line of a CODEOWNERS file
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []string // "@user", "@org/team" or an email address, empty = no owners
+}
+
+// CodeOwners is a parsed CODEOWNERS file
+type CodeOwners struct {
+	Path  string // Path relative to the worktree, e.g. ".github/CODEOWNERS"
+	rules []codeOwnersRule
+}
+
+// CodeOwnerSignOff is an owner whose review a change needs, with the files it owns
+type CodeOwnerSignOff struct {
+	Owner string
+	Files []string
+}
+
+// LoadCodeOwners reads the CODEOWNERS file of a worktree, nil when there is none
+func LoadCodeOwners(worktreePath string) *CodeOwners {
+	for _, dir := range codeOwnersDirs {
+		path := filepath.Join(dir, "CODEOWNERS")
+		data, err := os.ReadFile(filepath.Join(worktreePath, path))
+		if err != nil {
+			continue
+		}
+		return ParseCodeOwners(path, string(data))
+	}
+	return nil
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file, skipping invalid patterns
+func ParseCodeOwners(path, content string) *CodeOwners {
+	codeOwners := &CodeOwners{Path: path}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		pattern, err := regexp.Compile(codeOwnersPattern(fields[0]))
+		if err != nil {
+			continue
+		}
+		rule := codeOwnersRule{pattern: pattern}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.owners = append(rule.owners, owner)
+		}
+		codeOwners.rules = append(codeOwners.rules, rule)
+	}
+	return codeOwners
+}
+
+// codeOwnersPattern converts a CODEOWNERS pattern, which follows the gitignore rules, to a
+// regular expression matching file paths relative to the repository root
+func codeOwnersPattern(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, `\`) // "\#file" escapes a leading #
+
+	// Patterns with a slash before their end are relative to the root, others match at any depth
+	prefix := "(?:.*/)?"
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		prefix = ""
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// A directory pattern owns everything below it, other patterns match files or directories
+	// A wildcard in the last segment only matches its own level: "docs/*" doesn't own "docs/a/b.md"
+	suffix := "(?:/.*)?"
+	if strings.HasSuffix(pattern, "/") {
+		pattern = strings.TrimSuffix(pattern, "/")
+		suffix = "/.*"
+	} else if strings.Contains(pattern[strings.LastIndex(pattern, "/")+1:], "*") {
+		suffix = ""
+	}
+
+	var re strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	return "^" + prefix + re.String() + suffix + "$"
+}
+
+// OwnersOf returns the owners of a file: those of the last matching rule
+func (c *CodeOwners) OwnersOf(file string) []string {
+	file = strings.TrimPrefix(filepath.ToSlash(file), "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(file) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// SignOffs returns the owners whose review the changed files need, owners of the most files first
+func (c *CodeOwners) SignOffs(files []string) []CodeOwnerSignOff {
+	byOwner := map[string][]string{}
+	for _, file := range files {
+		for _, owner := range c.OwnersOf(file) {
+			byOwner[owner] = append(byOwner[owner], file)
+		}
+	}
+
+	signOffs := make([]CodeOwnerSignOff, 0, len(byOwner))
+	for owner, owned := range byOwner {
+		signOffs = append(signOffs, CodeOwnerSignOff{Owner: owner, Files: owned})
+	}
+	sort.Slice(signOffs, func(i, j int) bool {
+		if len(signOffs[i].Files) != len(signOffs[j].Files) {
+			return len(signOffs[i].Files) > len(signOffs[j].Files)
+		}
+		return signOffs[i].Owner < signOffs[j].Owner
+	})
+	return signOffs
+}
+
+// SuggestedReviewers returns the owners of the sign-offs that can be requested as reviewers,
+// written like the reviewers of PRMetadata ("alice", "org/team"). Email owners are left out
+func SuggestedReviewers(signOffs []CodeOwnerSignOff) []string {
+	var reviewers []string
+	for _, signOff := range signOffs {
+		if strings.HasPrefix(signOff.Owner, "@") {
+			reviewers = append(reviewers, strings.TrimPrefix(signOff.Owner, "@"))
+		}
+	}
+	return reviewers
+}
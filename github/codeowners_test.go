@@ -0,0 +1,81 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestCodeOwners tests matching files against CODEOWNERS patterns, the last match winning
+func TestCodeOwners(t *testing.T) {
+	codeOwners := ParseCodeOwners("CODEOWNERS", `# Default owners
+*                 @org/core
+*.md              docs@example.com
+/api/             @org/backend @alice  # API team
+web/**/*.tsx      @org/frontend
+\#notes           @bob
+docs/*            @org/docs
+/vendor/
+`)
+
+	tests := []struct {
+		file   string
+		owners []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"README.md", []string{"docs@example.com"}},
+		{"guide/setup.md", []string{"docs@example.com"}},
+		{"api/server.go", []string{"@org/backend", "@alice"}},
+		{"api/v2/routes.go", []string{"@org/backend", "@alice"}},
+		{"cmd/api/main.go", []string{"@org/core"}},
+		{"web/app.tsx", []string{"@org/frontend"}},
+		{"web/pages/home/index.tsx", []string{"@org/frontend"}},
+		{"web/app.ts", []string{"@org/core"}},
+		{"#notes", []string{"@bob"}},
+		{"docs/intro.md", []string{"@org/docs"}},
+		{"docs/a/b.md", []string{"docs@example.com"}},
+		{"docs/a/b.go", []string{"@org/core"}},
+		{"vendor/lib/lib.go", nil},
+	}
+	for _, tt := range tests {
+		if got := codeOwners.OwnersOf(tt.file); !reflect.DeepEqual(got, tt.owners) {
+			t.Errorf("OwnersOf(%q) = %v, want %v", tt.file, got, tt.owners)
+		}
+	}
+
+	signOffs := codeOwners.SignOffs([]string{"api/server.go", "api/db.go", "README.md", "main.go", "vendor/x.go"})
+	want := []CodeOwnerSignOff{
+		{Owner: "@alice", Files: []string{"api/server.go", "api/db.go"}},
+		{Owner: "@org/backend", Files: []string{"api/server.go", "api/db.go"}},
+		{Owner: "@org/core", Files: []string{"main.go"}},
+		{Owner: "docs@example.com", Files: []string{"README.md"}},
+	}
+	if !reflect.DeepEqual(signOffs, want) {
+		t.Fatalf("SignOffs() = %+v, want %+v", signOffs, want)
+	}
+	if got := SuggestedReviewers(signOffs); !reflect.DeepEqual(got, []string{"alice", "org/backend", "org/core"}) {
+		t.Errorf("SuggestedReviewers() = %v", got)
+	}
+}
+
+// TestLoadCodeOwners tests that the first CODEOWNERS file GitHub would use is loaded
+func TestLoadCodeOwners(t *testing.T) {
+	root := t.TempDir()
+	if codeOwners := LoadCodeOwners(root); codeOwners != nil {
+		t.Errorf("Expected no CODEOWNERS, got %+v", codeOwners)
+	}
+
+	for _, path := range []string{"CODEOWNERS", filepath.Join(".github", "CODEOWNERS")} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, path), []byte("* @"+filepath.Dir(path)+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	codeOwners := LoadCodeOwners(root)
+	if codeOwners == nil || codeOwners.Path != filepath.Join(".github", "CODEOWNERS") || codeOwners.OwnersOf("x")[0] != "@.github" {
+		t.Errorf("Expected .github/CODEOWNERS to win, got %+v", codeOwners)
+	}
+}
@@ -0,0 +1,169 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IssueFilters are the quick filters of the issue picker, in the order they are cycled
+var IssueFilters = []string{"assigned", "open", "created", "all"}
+
+// Issue is a GitHub issue a worktree can be created from
+type Issue struct {
+	Number int
+	Title  string
+	Body   string
+	URL    string
+	State  string // "open" or "closed"
+	Author string
+	Labels []string
+}
+
+// IssueSearch describes a page of an issue search
+type IssueSearch struct {
+	Text    string // Free text, may contain GitHub search qualifiers like milestone:v1
+	Filter  string // One of IssueFilters, "" = assigned
+	Label   string // Only issues with this label, "" = any
+	PerPage int    // Page size, 0 = 30
+	After   string // Cursor of the previous page, "" = first page
+}
+
+// IssuePage is one page of issue search results
+type IssuePage struct {
+	Issues    []Issue
+	Total     int    // Number of matching issues across all pages
+	HasMore   bool   // Whether another page can be loaded
+	EndCursor string // Cursor to pass as IssueSearch.After for the next page
+}
+
+// issueSearchQuery searches issues with cursor pagination
+const issueSearchQuery = `query($query: String!, $first: Int!, $after: String) {
+  search(query: $query, type: ISSUE, first: $first, after: $after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on Issue { number title body url state author { login } labels(first: 10) { nodes { name } } }
+    }
+  }
+}`
+
+// Query builds the GitHub search query of the search for a repository ("owner/name")
+func (s IssueSearch) Query(repo string) string {
+	parts := []string{"repo:" + repo, "is:issue"}
+	switch s.Filter {
+	case "", "assigned":
+		parts = append(parts, "is:open", "assignee:@me")
+	case "open":
+		parts = append(parts, "is:open")
+	case "created":
+		parts = append(parts, "is:open", "author:@me")
+	}
+	if s.Label != "" {
+		parts = append(parts, fmt.Sprintf("label:%q", s.Label))
+	}
+	if text := strings.TrimSpace(s.Text); text != "" {
+		// "#123" finds the issue by number
+		parts = append(parts, strings.TrimPrefix(text, "#"))
+	}
+	return strings.Join(append(parts, "sort:updated-desc"), " ")
+}
+
+// SearchIssues gets a page of the repository's issues matching a search, most recently updated first
+func (m *Manager) SearchIssues(worktreePath string, search IssueSearch) (*IssuePage, error) {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	perPage := search.PerPage
+	if perPage <= 0 {
+		perPage = 30
+	}
+	variables := map[string]interface{}{"query": search.Query(repo), "first": perPage}
+	if search.After != "" {
+		variables["after"] = search.After
+	}
+	output, err := m.graphQL(issueSearchQuery, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	return parseIssueSearch(output)
+}
+
+// parseIssueSearch parses the response of issueSearchQuery
+func parseIssueSearch(data []byte) (*IssuePage, error) {
+	if err := graphQLError(data); err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	var response struct {
+		Data struct {
+			Search struct {
+				IssueCount int `json:"issueCount"`
+				PageInfo   struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					Number int    `json:"number"`
+					Title  string `json:"title"`
+					Body   string `json:"body"`
+					URL    string `json:"url"`
+					State  string `json:"state"`
+					Author struct {
+						Login string `json:"login"`
+					} `json:"author"`
+					Labels struct {
+						Nodes []struct {
+							Name string `json:"name"`
+						} `json:"nodes"`
+					} `json:"labels"`
+				} `json:"nodes"`
+			} `json:"search"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse issue search: %w", err)
+	}
+
+	search := response.Data.Search
+	page := &IssuePage{
+		Issues:    make([]Issue, 0, len(search.Nodes)),
+		Total:     search.IssueCount,
+		HasMore:   search.PageInfo.HasNextPage,
+		EndCursor: search.PageInfo.EndCursor,
+	}
+	for _, node := range search.Nodes {
+		issue := Issue{
+			Number: node.Number,
+			Title:  node.Title,
+			Body:   strings.ReplaceAll(node.Body, "\r\n", "\n"),
+			URL:    node.URL,
+			State:  strings.ToLower(node.State),
+			Author: node.Author.Login,
+		}
+		for _, label := range node.Labels.Nodes {
+			issue.Labels = append(issue.Labels, label.Name)
+		}
+		page.Issues = append(page.Issues, issue)
+	}
+	return page, nil
+}
+
+// closingKeyword matches GitHub's keywords that close an issue when the PR merges, e.g. "Fixes #12"
+var closingKeyword = regexp.MustCompile(`(?i)\b(close[sd]?|fix(e[sd])?|resolve[sd]?):?\s+#(\d+)\b`)
+
+// AddClosingReference appends "Closes #N" to a PR body, unless the body already closes the issue
+func AddClosingReference(body string, number int) string {
+	for _, match := range closingKeyword.FindAllStringSubmatch(body, -1) {
+		if match[3] == fmt.Sprint(number) {
+			return body
+		}
+	}
+	reference := fmt.Sprintf("Closes #%d", number)
+	if strings.TrimSpace(body) == "" {
+		return reference
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + reference
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestIssueSearchQuery tests building GitHub search queries from the issue picker filters
+func TestIssueSearchQuery(t *testing.T) {
+	tests := []struct {
+		search IssueSearch
+		want   string
+	}{
+		{IssueSearch{}, "repo:o/r is:issue is:open assignee:@me sort:updated-desc"},
+		{IssueSearch{Filter: "open", Label: "good first issue"}, `repo:o/r is:issue is:open label:"good first issue" sort:updated-desc`},
+		{IssueSearch{Filter: "created", Text: "crash"}, "repo:o/r is:issue is:open author:@me crash sort:updated-desc"},
+		{IssueSearch{Filter: "all", Text: " #42 "}, "repo:o/r is:issue 42 sort:updated-desc"},
+	}
+	for _, tt := range tests {
+		if got := tt.search.Query("o/r"); got != tt.want {
+			t.Errorf("Query(%+v) = %q, want %q", tt.search, got, tt.want)
+		}
+	}
+}
+
+// TestParseIssueSearch tests parsing a page of issue search results
+func TestParseIssueSearch(t *testing.T) {
+	response := `{"data":{"search":{"issueCount":41,"pageInfo":{"hasNextPage":true,"endCursor":"Y3Vyc29yOjMw"},"nodes":[
+		{"number":12,"title":"Login times out","body":"Steps:\r\n1. Log in","url":"https://github.com/o/r/issues/12","state":"OPEN","author":{"login":"alice"},"labels":{"nodes":[{"name":"bug"},{"name":"auth"}]}}
+	]}}}`
+
+	page, err := parseIssueSearch([]byte(response))
+	if err != nil {
+		t.Fatalf("parseIssueSearch failed: %v", err)
+	}
+	if page.Total != 41 || !page.HasMore || page.EndCursor != "Y3Vyc29yOjMw" || len(page.Issues) != 1 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	want := Issue{
+		Number: 12,
+		Title:  "Login times out",
+		Body:   "Steps:\n1. Log in",
+		URL:    "https://github.com/o/r/issues/12",
+		State:  "open",
+		Author: "alice",
+		Labels: []string{"bug", "auth"},
+	}
+	if !reflect.DeepEqual(page.Issues[0], want) {
+		t.Errorf("Expected %+v, got %+v", want, page.Issues[0])
+	}
+
+	if _, err := parseIssueSearch([]byte(`{"errors":[{"message":"Bad credentials"}]}`)); err == nil {
+		t.Error("Expected GraphQL errors to be returned")
+	}
+}
+
+// TestAddClosingReference tests linking a PR to the issue it closes
+func TestAddClosingReference(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"", "Closes #12"},
+		{"## Summary\nFixes login\n", "## Summary\nFixes login\n\nCloses #12"},
+		{"Resolves: #12", "Resolves: #12"},
+		{"fixed #12 and #13", "fixed #12 and #13"},
+		{"Closes #123", "Closes #123\n\nCloses #12"},
+		{"See #12", "See #12\n\nCloses #12"},
+	}
+	for _, tt := range tests {
+		if got := AddClosingReference(tt.body, 12); got != tt.want {
+			t.Errorf("AddClosingReference(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// PRMetadata holds the reviewers, assignees, labels and milestone set on a new pull request
+type PRMetadata struct {
+	Reviewers []string // User logins, or "org/team" for teams
+	Assignees []string
+	Labels    []string
+	Milestone int // Milestone number, 0 = none
+}
+
+// IsEmpty reports whether there is nothing to set
+func (p PRMetadata) IsEmpty() bool {
+	return len(p.Reviewers) == 0 && len(p.Assignees) == 0 && len(p.Labels) == 0 && p.Milestone == 0
+}
+
+// Milestone is an open milestone of the repository
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// RepoMetadata holds the values PR metadata is completed from
+type RepoMetadata struct {
+	Viewer     string   // Login of the token's user, who can't review their own PR
+	Users      []string // Users that can be assigned or asked for review
+	Labels     []string
+	Milestones []Milestone
+}
+
+// repoMetadataQuery fetches the viewer and the assignable users, labels and open milestones of a repository
+const repoMetadataQuery = `query($owner: String!, $name: String!) {
+  viewer { login }
+  repository(owner: $owner, name: $name) {
+    assignableUsers(first: 100) { nodes { login } }
+    labels(first: 100, orderBy: {field: NAME, direction: ASC}) { nodes { name } }
+    milestones(first: 50, states: OPEN, orderBy: {field: DUE_DATE, direction: ASC}) { nodes { number title } }
+  }
+}`
+
+// GetRepoMetadata gets the users, labels and open milestones of the worktree's repository
+func (m *Manager) GetRepoMetadata(worktreePath string) (*RepoMetadata, error) {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	output, err := m.graphQL(repoMetadataQuery, map[string]interface{}{"owner": owner, "name": name})
+	if err == nil {
+		err = graphQLError(output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository metadata: %w", err)
+	}
+	return parseRepoMetadata(output)
+}
+
+// parseRepoMetadata parses the response of repoMetadataQuery
+func parseRepoMetadata(data []byte) (*RepoMetadata, error) {
+	var response struct {
+		Data struct {
+			Viewer struct {
+				Login string `json:"login"`
+			} `json:"viewer"`
+			Repository struct {
+				AssignableUsers struct {
+					Nodes []struct {
+						Login string `json:"login"`
+					} `json:"nodes"`
+				} `json:"assignableUsers"`
+				Labels struct {
+					Nodes []struct {
+						Name string `json:"name"`
+					} `json:"nodes"`
+				} `json:"labels"`
+				Milestones struct {
+					Nodes []Milestone `json:"nodes"`
+				} `json:"milestones"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse repository metadata: %w", err)
+	}
+
+	repo := response.Data.Repository
+	metadata := &RepoMetadata{Viewer: response.Data.Viewer.Login, Milestones: repo.Milestones.Nodes}
+	for _, user := range repo.AssignableUsers.Nodes {
+		metadata.Users = append(metadata.Users, user.Login)
+	}
+	for _, label := range repo.Labels.Nodes {
+		metadata.Labels = append(metadata.Labels, label.Name)
+	}
+	return metadata, nil
+}
+
+// SetPRMetadata requests reviews and sets the assignees, labels and milestone of a pull request
+// Reviewers written "org/team" are requested as teams
+func (m *Manager) SetPRMetadata(prURL string, metadata PRMetadata) error {
+	owner, name, number, err := parsePRURL(prURL)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	if len(metadata.Reviewers) > 0 {
+		users, teams := []string{}, []string{}
+		for _, reviewer := range metadata.Reviewers {
+			if _, team, isTeam := strings.Cut(strings.TrimPrefix(reviewer, "@"), "/"); isTeam {
+				teams = append(teams, team)
+			} else {
+				users = append(users, strings.TrimPrefix(reviewer, "@"))
+			}
+		}
+		payload := map[string]interface{}{"reviewers": users, "team_reviewers": teams}
+		path := fmt.Sprintf("/repos/%s/%s/pulls/%d/requested_reviewers", owner, name, number)
+		if err := m.rest(http.MethodPost, path, payload, nil); err != nil {
+			failures = append(failures, "reviewers: "+err.Error())
+		}
+	}
+
+	// Assignees, labels and milestones belong to the PR's issue
+	issue := map[string]interface{}{}
+	if len(metadata.Assignees) > 0 {
+		issue["assignees"] = metadata.Assignees
+	}
+	if len(metadata.Labels) > 0 {
+		issue["labels"] = metadata.Labels
+	}
+	if metadata.Milestone != 0 {
+		issue["milestone"] = metadata.Milestone
+	}
+	if len(issue) > 0 {
+		if err := m.rest(http.MethodPatch, fmt.Sprintf("/repos/%s/%s/issues/%d", owner, name, number), issue, nil); err != nil {
+			failures = append(failures, "assignees, labels and milestone: "+err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to set PR metadata: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseRepoMetadata tests reading the users, labels and milestones of a repository
+func TestParseRepoMetadata(t *testing.T) {
+	data := `{"data":{"viewer":{"login":"carol"},"repository":{
+		"assignableUsers":{"nodes":[{"login":"alice"},{"login":"bob"}]},
+		"labels":{"nodes":[{"name":"bug"},{"name":"good first issue"}]},
+		"milestones":{"nodes":[{"number":3,"title":"v1.2"}]}}}}`
+
+	metadata, err := parseRepoMetadata([]byte(data))
+	if err != nil {
+		t.Fatalf("parseRepoMetadata failed: %v", err)
+	}
+	if !reflect.DeepEqual(metadata.Users, []string{"alice", "bob"}) || !reflect.DeepEqual(metadata.Labels, []string{"bug", "good first issue"}) {
+		t.Errorf("Unexpected users or labels: %+v", metadata)
+	}
+	if metadata.Viewer != "carol" || len(metadata.Milestones) != 1 || metadata.Milestones[0] != (Milestone{Number: 3, Title: "v1.2"}) {
+		t.Errorf("Unexpected viewer or milestones: %+v", metadata)
+	}
+}
+
+// TestSetPRMetadata tests requesting user and team reviews and setting the PR's issue fields
+func TestSetPRMetadata(t *testing.T) {
+	m, requests := newFakeGitHub(t, map[string]string{
+		"POST /repos/o/r/pulls/7/requested_reviewers": `201 {}`,
+		"PATCH /repos/o/r/issues/7":                   `{}`,
+	})
+	url := "https://github.com/o/r/pull/7"
+
+	if err := m.SetPRMetadata(url, PRMetadata{}); err != nil || len(*requests) != 0 {
+		t.Errorf("Expected no requests for empty metadata, got %v, %+v", err, *requests)
+	}
+
+	metadata := PRMetadata{Reviewers: []string{"@alice", "o/backend"}, Assignees: []string{"bob"}, Labels: []string{"bug"}, Milestone: 3}
+	if err := m.SetPRMetadata(url, metadata); err != nil {
+		t.Fatalf("SetPRMetadata failed: %v", err)
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %+v", *requests)
+	}
+	review := (*requests)[0].Body
+	if !reflect.DeepEqual(review["reviewers"], []interface{}{"alice"}) || !reflect.DeepEqual(review["team_reviewers"], []interface{}{"backend"}) {
+		t.Errorf("Unexpected review request: %v", review)
+	}
+	issue := (*requests)[1]
+	if issue.Method != http.MethodPatch || issue.Body["milestone"] != float64(3) || !reflect.DeepEqual(issue.Body["labels"], []interface{}{"bug"}) {
+		t.Errorf("Unexpected issue update: %+v", issue)
+	}
+
+	// Failures are reported together, one request failing doesn't skip the other
+	err := m.SetPRMetadata("https://github.com/o/r/pull/8", PRMetadata{Reviewers: []string{"alice"}, Labels: []string{"bug"}})
+	if err == nil || !strings.Contains(err.Error(), "reviewers: Not Found") || !strings.Contains(err.Error(), "labels and milestone: Not Found") {
+		t.Errorf("Expected both failures reported, got %v", err)
+	}
+}
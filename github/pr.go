@@ -3,12 +3,28 @@ package github
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
 	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-// Manager handles GitHub operations using gh CLI
-type Manager struct{}
+// Manager handles GitHub operations through the GitHub REST and GraphQL APIs
+type Manager struct {
+	apiURL     string // REST API root, GraphQL is derived from it
+	httpClient *http.Client
+
+	tokenMu sync.Mutex
+	token   string
+
+	reposMu sync.Mutex
+	repos   map[string]string // Repository PRs and issues live in, by origin repository (see upstreamRepo)
+}
 
 // PRInfo holds information about a pull request
 type PRInfo struct {
@@ -16,253 +32,303 @@ type PRInfo struct {
 	Title       string `json:"title"`
 	HeadRefName string `json:"headRefName"`
 	URL         string `json:"url"`
-	Status      string `json:"state"` // "OPEN", "MERGED", or "CLOSED"
+	Status      string `json:"state"` // "open", "merged", or "closed"
+	IsDraft     bool   `json:"isDraft"`
 	Author      struct {
 		Login string `json:"login"`
 	} `json:"author"`
 }
 
 // NewManager creates a new GitHub manager
+// The token is looked up on the first request, see resolveToken
 func NewManager() *Manager {
-	return &Manager{}
-}
-
-// IsGhInstalled checks if gh CLI is installed
-func (m *Manager) IsGhInstalled() bool {
-	cmd := exec.Command("gh", "--version")
-	return cmd.Run() == nil
+	apiURL := strings.TrimSuffix(os.Getenv("GITHUB_API_URL"), "/")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	return &Manager{
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
 }
 
-// IsAuthenticated checks if user is authenticated with gh
+// IsAuthenticated checks if a GitHub token is available
 func (m *Manager) IsAuthenticated() (bool, error) {
-	cmd := exec.Command("gh", "auth", "status")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// If output contains "not logged into", user is not authenticated
-		if strings.Contains(string(output), "not logged into") {
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to check auth status: %s", string(output))
+	if _, err := m.authToken(); err != nil {
+		return false, nil
 	}
 	return true, nil
 }
 
+// remotePattern matches the owner and name at the end of SSH and HTTPS remote URLs
+var remotePattern = regexp.MustCompile(`[:/]([^/:]+)/([^/]+?)(?:\.git)?/?$`)
+
+// repoFromRemote extracts "owner/name" from a git remote URL
+func repoFromRemote(remoteURL string) (string, error) {
+	match := remotePattern.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if match == nil {
+		return "", fmt.Errorf("not a GitHub remote: %s", remoteURL)
+	}
+	return match[1] + "/" + match[2], nil
+}
+
 // CreatePR creates a pull request (draft or ready for review)
+// From a fork, the PR is opened on the parent repository with the fork's branch as head
 func (m *Manager) CreatePR(worktreePath, branch, baseBranch, title, description string, isDraft bool) (string, error) {
-	// Check if gh is installed
-	if !m.IsGhInstalled() {
-		return "", fmt.Errorf("gh CLI is not installed. Install it from https://cli.github.com")
+	origin, err := m.originRepo(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	repo := m.upstreamRepo(origin)
+	head := branch
+	if repo != origin {
+		owner, _, _ := strings.Cut(origin, "/")
+		head = owner + ":" + branch
 	}
 
-	// Check if authenticated
-	authenticated, err := m.IsAuthenticated()
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	payload := map[string]interface{}{
+		"base":  baseBranch,
+		"head":  head,
+		"title": title,
+		"body":  description,
+		"draft": isDraft,
+	}
+	if err := m.rest(http.MethodPost, "/repos/"+repo+"/pulls", payload, &created); err != nil {
+		return "", fmt.Errorf("failed to create PR: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// GetRepoName gets the "owner/name" of the repository PRs, issues and CI runs live in: the
+// worktree's origin remote, or the repository it was forked from (like gh does)
+func (m *Manager) GetRepoName(worktreePath string) (string, error) {
+	origin, err := m.originRepo(worktreePath)
 	if err != nil {
 		return "", err
 	}
-	if !authenticated {
-		return "", fmt.Errorf("not authenticated with GitHub. Run 'gh auth login' to authenticate")
+	return m.upstreamRepo(origin), nil
+}
+
+// originRepo gets the "owner/name" of the worktree's origin remote
+func (m *Manager) originRepo(worktreePath string) (string, error) {
+	output, err := exec.Command("git", "-C", worktreePath, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repo name: %w", err)
 	}
+	return repoFromRemote(string(output))
+}
 
-	// Create PR with title and description
-	args := []string{
-		"pr", "create",
-		"--base", baseBranch,
-		"--head", branch,
-		"--title", title,
-		"--body", description,
+// upstreamRepo returns the parent of a forked repository, or the repository itself
+// The answer is cached per manager, a failed lookup falls back to the repository and is retried
+func (m *Manager) upstreamRepo(repo string) string {
+	m.reposMu.Lock()
+	upstream, ok := m.repos[repo]
+	m.reposMu.Unlock()
+	if ok {
+		return upstream
 	}
 
-	// Add draft flag if requested
-	if isDraft {
-		args = append(args, "--draft")
+	var response struct {
+		Fork   bool `json:"fork"`
+		Parent *struct {
+			FullName string `json:"full_name"`
+		} `json:"parent"`
+	}
+	if err := m.rest(http.MethodGet, "/repos/"+repo, nil, &response); err != nil {
+		return repo
+	}
+	upstream = repo
+	if response.Fork && response.Parent != nil && response.Parent.FullName != "" {
+		upstream = response.Parent.FullName
 	}
 
-	cmd := exec.Command("gh", args...)
-	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("failed to create PR: %s", string(output))
+	m.reposMu.Lock()
+	if m.repos == nil {
+		m.repos = make(map[string]string)
 	}
+	m.repos[repo] = upstream
+	m.reposMu.Unlock()
+	return upstream
+}
 
-	// Extract PR URL from output
-	prURL := strings.TrimSpace(string(output))
-	return prURL, nil
+// restPR is the part of the REST pull request object jean uses
+type restPR struct {
+	NodeID string `json:"node_id"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
 }
 
-// GetRepoName gets the repository name from gh CLI
-func (m *Manager) GetRepoName(worktreePath string) (string, error) {
-	cmd := exec.Command("gh", "repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
-	cmd.Dir = worktreePath
-	output, err := cmd.Output()
+// getPR gets a pull request by URL from the REST API
+func (m *Manager) getPR(prURL string) (*restPR, error) {
+	owner, name, number, err := parsePRURL(prURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to get repo name: %w", err)
+		return nil, err
+	}
+	var pr restPR
+	if err := m.rest(http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, name, number), nil, &pr); err != nil {
+		return nil, err
 	}
-	return strings.TrimSpace(string(output)), nil
+	return &pr, nil
 }
 
-// GetPRStatus gets the current status of a pull request
+// GetPRStatus gets the current status of a pull request: "open", "merged" or "closed"
 func (m *Manager) GetPRStatus(prURL string) (string, error) {
-	cmd := exec.Command("gh", "pr", "view", prURL, "--json", "state", "--jq", ".state")
-	output, err := cmd.CombinedOutput()
+	pr, err := m.getPR(prURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to get PR status: %s", string(output))
+		return "", fmt.Errorf("failed to get PR status: %w", err)
 	}
-
-	// The output will be one of: OPEN, MERGED, CLOSED
-	status := strings.ToLower(strings.TrimSpace(string(output)))
-	return status, nil
+	if pr.Merged {
+		return "merged", nil
+	}
+	return pr.State, nil
 }
 
+// prForBranchQuery finds the most recent pull request of a branch, whatever its state
+const prForBranchQuery = `query($owner: String!, $name: String!, $branch: String!) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(headRefName: $branch, first: 1, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { number title headRefName url state isDraft author { login } }
+    }
+  }
+}`
+
 // GetPRForBranch gets the PR details for a given branch (if it exists)
 func (m *Manager) GetPRForBranch(worktreePath, branch string) (*PRInfo, error) {
-	// Search for PR on this branch with full details (including closed/merged)
-	cmd := exec.Command("gh", "pr", "list",
-		"--head", branch,
-		"--state", "all",
-		"--json", "number,title,headRefName,url,state,author",
-		"--jq", ".[0]")
-	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
+	repo, err := m.GetRepoName(worktreePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search for PR: %s", string(output))
+		return nil, err
 	}
-
-	outputStr := strings.TrimSpace(string(output))
-	if outputStr == "" || outputStr == "null" {
-		return nil, nil // No PR found
+	owner, name, _ := strings.Cut(repo, "/")
+	output, err := m.graphQL(prForBranchQuery, map[string]interface{}{"owner": owner, "name": name, "branch": branch})
+	if err == nil {
+		err = graphQLError(output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for PR: %w", err)
 	}
 
-	// Parse the JSON response
-	var prInfo PRInfo
-	if err := json.Unmarshal([]byte(outputStr), &prInfo); err != nil {
+	var response struct {
+		Data struct {
+			Repository struct {
+				PullRequests struct {
+					Nodes []PRInfo `json:"nodes"`
+				} `json:"pullRequests"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse PR info: %w", err)
 	}
+	nodes := response.Data.Repository.PullRequests.Nodes
+	if len(nodes) == 0 {
+		return nil, nil // No PR found
+	}
+	pr := nodes[0]
+	pr.Status = strings.ToLower(pr.Status)
+	return &pr, nil
+}
 
-	return &prInfo, nil
+// resolvePR finds the repository and number of a PR given by URL, number or head branch
+func (m *Manager) resolvePR(worktreePath, prIdentifier string) (string, int, error) {
+	if prURLPattern.MatchString(prIdentifier) {
+		owner, name, number, err := parsePRURL(prIdentifier)
+		return owner + "/" + name, number, err
+	}
+	if number, err := strconv.Atoi(strings.TrimPrefix(prIdentifier, "#")); err == nil {
+		repo, err := m.GetRepoName(worktreePath)
+		return repo, number, err
+	}
+	pr, err := m.GetPRForBranch(worktreePath, prIdentifier)
+	if err != nil {
+		return "", 0, err
+	}
+	if pr == nil {
+		return "", 0, fmt.Errorf("no PR found for branch %s", prIdentifier)
+	}
+	return m.resolvePR(worktreePath, pr.URL)
 }
 
 // UpdatePR updates the title and/or description of an existing PR
+// The PR is identified by its URL, number or head branch
 func (m *Manager) UpdatePR(worktreePath, prIdentifier, title, description string) error {
-	args := []string{"pr", "edit", prIdentifier}
+	repo, number, err := m.resolvePR(worktreePath, prIdentifier)
+	if err != nil {
+		return fmt.Errorf("failed to update PR: %w", err)
+	}
 
+	payload := map[string]interface{}{}
 	if title != "" {
-		args = append(args, "--title", title)
+		payload["title"] = title
 	}
-
 	if description != "" {
-		args = append(args, "--body", description)
+		payload["body"] = description
 	}
-
-	cmd := exec.Command("gh", args...)
-	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to update PR: %s", string(output))
+	if err := m.rest(http.MethodPatch, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), payload, nil); err != nil {
+		return fmt.Errorf("failed to update PR: %w", err)
 	}
-
 	return nil
 }
 
-// MarkPRReady converts a draft PR to ready for review
-func (m *Manager) MarkPRReady(worktreePath, prURL string) error {
-	// Check if gh is installed
-	if !m.IsGhInstalled() {
-		return fmt.Errorf("gh CLI is not installed. Install it from https://cli.github.com")
+// SetPRBase changes the branch a pull request merges into
+func (m *Manager) SetPRBase(prURL, baseBranch string) error {
+	owner, name, number, err := parsePRURL(prURL)
+	if err == nil {
+		err = m.rest(http.MethodPatch, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, name, number), map[string]interface{}{"base": baseBranch}, nil)
 	}
-
-	// Check if authenticated
-	authenticated, err := m.IsAuthenticated()
 	if err != nil {
-		return err
-	}
-	if !authenticated {
-		return fmt.Errorf("not authenticated with GitHub. Run 'gh auth login' to authenticate")
+		return fmt.Errorf("failed to change PR base: %w", err)
 	}
+	return nil
+}
 
-	// Mark PR as ready (remove draft status)
-	cmd := exec.Command("gh", "pr", "ready", prURL)
-	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
+// MarkPRReady converts a draft PR to ready for review
+func (m *Manager) MarkPRReady(worktreePath, prURL string) error {
+	// Only the GraphQL API can take a PR out of draft, it needs the PR's node ID
+	pr, err := m.getPR(prURL)
 	if err != nil {
-		return fmt.Errorf("failed to mark PR as ready: %s", string(output))
+		return fmt.Errorf("failed to mark PR as ready: %w", err)
 	}
-
-	return nil
+	const mutation = `mutation($id: ID!) { markPullRequestReadyForReview(input: {pullRequestId: $id}) { pullRequest { id } } }`
+	return m.runMutation("mark PR as ready", mutation, map[string]interface{}{"id": pr.NodeID})
 }
 
 // MergePR merges a pull request using the specified merge method
 // mergeMethod should be one of: "squash", "merge", "rebase"
 func (m *Manager) MergePR(worktreePath, prURL, mergeMethod string) error {
-	// Check if gh is installed
-	if !m.IsGhInstalled() {
-		return fmt.Errorf("gh CLI is not installed. Install it from https://cli.github.com")
-	}
-
-	// Check if authenticated
-	authenticated, err := m.IsAuthenticated()
-	if err != nil {
-		return err
-	}
-	if !authenticated {
-		return fmt.Errorf("not authenticated with GitHub. Run 'gh auth login' to authenticate")
-	}
-
 	// Validate merge method
 	validMethods := map[string]bool{
-		"squash":  true,
-		"merge":   true,
-		"rebase":  true,
+		"squash": true,
+		"merge":  true,
+		"rebase": true,
 	}
 	if !validMethods[mergeMethod] {
 		return fmt.Errorf("invalid merge method: %s. Must be one of: squash, merge, rebase", mergeMethod)
 	}
 
-	// Merge the PR with the specified method
-	args := []string{"pr", "merge", prURL, "--" + mergeMethod}
-	cmd := exec.Command("gh", args...)
-	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
+	owner, name, number, err := parsePRURL(prURL)
 	if err != nil {
-		return fmt.Errorf("failed to merge PR: %s", string(output))
+		return fmt.Errorf("failed to merge PR: %w", err)
+	}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/merge", owner, name, number)
+	if err := m.rest(http.MethodPut, path, map[string]interface{}{"merge_method": mergeMethod}, nil); err != nil {
+		return fmt.Errorf("failed to merge PR: %w", err)
 	}
-
 	return nil
 }
 
-// ListPRs lists all open pull requests for the repository
-func (m *Manager) ListPRs(worktreePath string) ([]PRInfo, error) {
-	// Check if gh is installed
-	if !m.IsGhInstalled() {
-		err := fmt.Errorf("gh CLI is not installed. Install it from https://cli.github.com")
-		return nil, err
-	}
-
-	// Check if authenticated
-	authenticated, err := m.IsAuthenticated()
-	if err != nil {
-		return nil, err
+// OpenInBrowser opens a pull request (or any URL) in the default browser
+func OpenInBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
 	}
-	if !authenticated {
-		err := fmt.Errorf("not authenticated with GitHub. Run 'gh auth login' to authenticate")
-		return nil, err
-	}
-
-	// List open PRs in JSON format (only 5 latest to avoid cluttering the screen)
-	cmd := exec.Command("gh", "pr", "list",
-		"--state", "open",
-		"--json", "number,title,headRefName,url,author",
-		"--limit", "5")
-	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list PRs: %s", string(output))
-	}
-
-	// Parse JSON response
-	var prs []PRInfo
-	if err := json.Unmarshal(output, &prs); err != nil {
-		return nil, fmt.Errorf("failed to parse PR list: %v", err)
-	}
-
-	return prs, nil
+	return cmd.Start()
 }
@@ -0,0 +1,188 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReviewThread is a review comment thread on a line of a pull request
+type ReviewThread struct {
+	ID         string
+	Path       string
+	Line       int // Line the thread is attached to, the original line when outdated
+	StartLine  int // First line of a multi-line comment, 0 = single line
+	IsResolved bool
+	IsOutdated bool   // The code changed since the comment was made
+	DiffHunk   string // Diff context of the first comment, ending at the commented line
+	Comments   []ReviewComment
+}
+
+// ReviewComment is a comment in a review thread
+type ReviewComment struct {
+	Author    string
+	Body      string
+	URL       string
+	CreatedAt time.Time
+}
+
+// Location returns the file and line(s) of the thread, e.g. "main.go:12-15"
+func (t ReviewThread) Location() string {
+	switch {
+	case t.Line == 0:
+		return t.Path
+	case t.StartLine > 0 && t.StartLine != t.Line:
+		return fmt.Sprintf("%s:%d-%d", t.Path, t.StartLine, t.Line)
+	}
+	return fmt.Sprintf("%s:%d", t.Path, t.Line)
+}
+
+// reviewThreadsQuery fetches the review threads of a pull request with their comments
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          id
+          isResolved
+          isOutdated
+          path
+          line
+          originalLine
+          startLine
+          originalStartLine
+          comments(first: 50) {
+            nodes { author { login } body url createdAt diffHunk }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// GetReviewThreads gets the review threads of a pull request, oldest first
+func (m *Manager) GetReviewThreads(prURL string) ([]ReviewThread, error) {
+	owner, name, number, err := parsePRURL(prURL)
+	if err != nil {
+		return nil, err
+	}
+	output, err := m.graphQL(reviewThreadsQuery, map[string]interface{}{"owner": owner, "name": name, "number": number})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review threads: %w", err)
+	}
+	return parseReviewThreads(output)
+}
+
+// parseReviewThreads parses the response of reviewThreadsQuery
+func parseReviewThreads(data []byte) ([]ReviewThread, error) {
+	if err := graphQLError(data); err != nil {
+		return nil, fmt.Errorf("failed to get review threads: %w", err)
+	}
+	var response struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID                string `json:"id"`
+							IsResolved        bool   `json:"isResolved"`
+							IsOutdated        bool   `json:"isOutdated"`
+							Path              string `json:"path"`
+							Line              int    `json:"line"`
+							OriginalLine      int    `json:"originalLine"`
+							StartLine         int    `json:"startLine"`
+							OriginalStartLine int    `json:"originalStartLine"`
+							Comments          struct {
+								Nodes []struct {
+									Author struct {
+										Login string `json:"login"`
+									} `json:"author"`
+									Body      string    `json:"body"`
+									URL       string    `json:"url"`
+									CreatedAt time.Time `json:"createdAt"`
+									DiffHunk  string    `json:"diffHunk"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse review threads: %w", err)
+	}
+	pr := response.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request not found")
+	}
+
+	threads := make([]ReviewThread, 0, len(pr.ReviewThreads.Nodes))
+	for _, node := range pr.ReviewThreads.Nodes {
+		thread := ReviewThread{
+			ID:         node.ID,
+			Path:       node.Path,
+			Line:       node.Line,
+			StartLine:  node.StartLine,
+			IsResolved: node.IsResolved,
+			IsOutdated: node.IsOutdated,
+		}
+		// Outdated threads have no current line, point at the line that was commented on
+		if thread.Line == 0 {
+			thread.Line = node.OriginalLine
+			thread.StartLine = node.OriginalStartLine
+		}
+		for i, comment := range node.Comments.Nodes {
+			if i == 0 {
+				thread.DiffHunk = comment.DiffHunk
+			}
+			author := comment.Author.Login
+			if author == "" {
+				author = "ghost" // Deleted accounts
+			}
+			thread.Comments = append(thread.Comments, ReviewComment{
+				Author:    author,
+				Body:      comment.Body,
+				URL:       comment.URL,
+				CreatedAt: comment.CreatedAt,
+			})
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+// ReplyToReviewThread adds a reply to a review thread
+func (m *Manager) ReplyToReviewThread(threadID, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("reply cannot be empty")
+	}
+	const mutation = `mutation($threadId: ID!, $body: String!) {
+  addPullRequestReviewThreadReply(input: {pullRequestReviewThreadId: $threadId, body: $body}) { comment { id } }
+}`
+	return m.runMutation("reply", mutation, map[string]interface{}{"threadId": threadID, "body": body})
+}
+
+// ResolveReviewThread marks a review thread as resolved, or unresolved when resolved is false
+func (m *Manager) ResolveReviewThread(threadID string, resolved bool) error {
+	mutation := `mutation($threadId: ID!) { resolveReviewThread(input: {threadId: $threadId}) { thread { id } } }`
+	action := "resolve thread"
+	if !resolved {
+		mutation = `mutation($threadId: ID!) { unresolveReviewThread(input: {threadId: $threadId}) { thread { id } } }`
+		action = "unresolve thread"
+	}
+	return m.runMutation(action, mutation, map[string]interface{}{"threadId": threadID})
+}
+
+// runMutation runs a GraphQL mutation, reporting errors returned in the response
+func (m *Manager) runMutation(action, mutation string, variables map[string]interface{}) error {
+	output, err := m.graphQL(mutation, variables)
+	if err == nil {
+		err = graphQLError(output)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"testing"
+)
+
+// TestParseReviewThreads tests parsing review threads, including outdated ones
+func TestParseReviewThreads(t *testing.T) {
+	response := `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[
+		{"id":"T1","isResolved":false,"isOutdated":false,"path":"main.go","line":15,"originalLine":12,"startLine":12,"originalStartLine":10,
+		 "comments":{"nodes":[
+			{"author":{"login":"alice"},"body":"Handle the error","url":"https://github.com/o/r/pull/7#discussion_r1","createdAt":"2026-10-01T10:00:00Z","diffHunk":"@@ -1,3 +1,4 @@\n+x, _ := f()"},
+			{"author":null,"body":"Agreed","url":"u2","createdAt":"2026-10-01T11:00:00Z","diffHunk":"ignored"}]}},
+		{"id":"T2","isResolved":true,"isOutdated":true,"path":"README.md","line":null,"originalLine":3,"startLine":null,"originalStartLine":null,
+		 "comments":{"nodes":[{"author":{"login":"bob"},"body":"Typo","url":"u3","createdAt":"2026-10-02T10:00:00Z","diffHunk":"@@"}]}}
+	]}}}}}`
+
+	threads, err := parseReviewThreads([]byte(response))
+	if err != nil {
+		t.Fatalf("parseReviewThreads failed: %v", err)
+	}
+	if len(threads) != 2 {
+		t.Fatalf("Expected 2 threads, got %d", len(threads))
+	}
+
+	first := threads[0]
+	if first.ID != "T1" || first.Location() != "main.go:12-15" || first.DiffHunk != "@@ -1,3 +1,4 @@\n+x, _ := f()" {
+		t.Errorf("Unexpected first thread: %+v", first)
+	}
+	if len(first.Comments) != 2 || first.Comments[0].Author != "alice" || first.Comments[1].Author != "ghost" {
+		t.Errorf("Unexpected comments: %+v", first.Comments)
+	}
+
+	// Outdated threads point at the line that was commented on
+	if second := threads[1]; !second.IsResolved || !second.IsOutdated || second.Location() != "README.md:3" {
+		t.Errorf("Unexpected outdated thread: %+v (%s)", second, second.Location())
+	}
+
+	if _, err := parseReviewThreads([]byte(`{"errors":[{"message":"Resource not accessible"}]}`)); err == nil {
+		t.Error("Expected GraphQL errors to be returned")
+	}
+}
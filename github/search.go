@@ -0,0 +1,122 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PRFilters are the quick filters of the PR browser, in the order they are cycled
+var PRFilters = []string{"open", "mine", "review-requested", "draft", "closed", "merged", "all"}
+
+// PRSearch describes a page of a pull request search
+type PRSearch struct {
+	Text    string // Free text, may contain GitHub search qualifiers like label:bug
+	Filter  string // One of PRFilters, "" = open
+	Label   string // Only PRs with this label, "" = any
+	PerPage int    // Page size, 0 = 30
+	After   string // Cursor of the previous page, "" = first page
+}
+
+// PRPage is one page of pull request search results
+type PRPage struct {
+	PRs       []PRInfo
+	Total     int    // Number of matching PRs across all pages
+	HasMore   bool   // Whether another page can be loaded
+	EndCursor string // Cursor to pass as PRSearch.After for the next page
+}
+
+// prSearchQuery searches pull requests with cursor pagination
+const prSearchQuery = `query($query: String!, $first: Int!, $after: String) {
+  search(query: $query, type: ISSUE, first: $first, after: $after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest { number title headRefName url state isDraft author { login } }
+    }
+  }
+}`
+
+// Query builds the GitHub search query of the search for a repository ("owner/name")
+func (s PRSearch) Query(repo string) string {
+	parts := []string{"repo:" + repo, "is:pr"}
+	switch s.Filter {
+	case "", "open":
+		parts = append(parts, "is:open")
+	case "mine":
+		parts = append(parts, "is:open", "author:@me")
+	case "review-requested":
+		parts = append(parts, "is:open", "review-requested:@me")
+	case "draft":
+		parts = append(parts, "is:open", "draft:true")
+	case "closed":
+		parts = append(parts, "is:closed", "is:unmerged")
+	case "merged":
+		parts = append(parts, "is:merged")
+	}
+	if s.Label != "" {
+		parts = append(parts, fmt.Sprintf("label:%q", s.Label))
+	}
+	if text := strings.TrimSpace(s.Text); text != "" {
+		// "#123" finds the PR by number
+		parts = append(parts, strings.TrimPrefix(text, "#"))
+	}
+	return strings.Join(append(parts, "sort:updated-desc"), " ")
+}
+
+// SearchPRs gets a page of the repository's pull requests matching a search, most recently updated first
+func (m *Manager) SearchPRs(worktreePath string, search PRSearch) (*PRPage, error) {
+	repo, err := m.GetRepoName(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	perPage := search.PerPage
+	if perPage <= 0 {
+		perPage = 30
+	}
+	variables := map[string]interface{}{"query": search.Query(repo), "first": perPage}
+	if search.After != "" {
+		variables["after"] = search.After
+	}
+	output, err := m.graphQL(prSearchQuery, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search PRs: %w", err)
+	}
+	return parsePRSearch(output)
+}
+
+// parsePRSearch parses the response of prSearchQuery
+func parsePRSearch(data []byte) (*PRPage, error) {
+	if err := graphQLError(data); err != nil {
+		return nil, fmt.Errorf("failed to search PRs: %w", err)
+	}
+	var response struct {
+		Data struct {
+			Search struct {
+				IssueCount int `json:"issueCount"`
+				PageInfo   struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []PRInfo `json:"nodes"`
+			} `json:"search"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse PR search: %w", err)
+	}
+
+	search := response.Data.Search
+	page := &PRPage{
+		PRs:       make([]PRInfo, 0, len(search.Nodes)),
+		Total:     search.IssueCount,
+		HasMore:   search.PageInfo.HasNextPage,
+		EndCursor: search.PageInfo.EndCursor,
+	}
+	for _, pr := range search.Nodes {
+		pr.Status = strings.ToLower(pr.Status)
+		page.PRs = append(page.PRs, pr)
+	}
+	return page, nil
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"testing"
+)
+
+// TestPRSearchQuery tests building GitHub search queries from the PR browser filters
+func TestPRSearchQuery(t *testing.T) {
+	tests := []struct {
+		search PRSearch
+		want   string
+	}{
+		{PRSearch{}, "repo:o/r is:pr is:open sort:updated-desc"},
+		{PRSearch{Filter: "mine", Text: "login"}, "repo:o/r is:pr is:open author:@me login sort:updated-desc"},
+		{PRSearch{Filter: "review-requested"}, "repo:o/r is:pr is:open review-requested:@me sort:updated-desc"},
+		{PRSearch{Filter: "draft", Text: "label:bug"}, "repo:o/r is:pr is:open draft:true label:bug sort:updated-desc"},
+		{PRSearch{Filter: "open", Label: "good first issue"}, `repo:o/r is:pr is:open label:"good first issue" sort:updated-desc`},
+		{PRSearch{Filter: "closed"}, "repo:o/r is:pr is:closed is:unmerged sort:updated-desc"},
+		{PRSearch{Filter: "merged", Text: " #42 "}, "repo:o/r is:pr is:merged 42 sort:updated-desc"},
+		{PRSearch{Filter: "all"}, "repo:o/r is:pr sort:updated-desc"},
+	}
+	for _, tt := range tests {
+		if got := tt.search.Query("o/r"); got != tt.want {
+			t.Errorf("Query(%+v) = %q, want %q", tt.search, got, tt.want)
+		}
+	}
+}
+
+// TestParsePRSearch tests parsing a page of search results
+func TestParsePRSearch(t *testing.T) {
+	response := `{"data":{"search":{"issueCount":73,"pageInfo":{"hasNextPage":true,"endCursor":"Y3Vyc29yOjMw"},"nodes":[
+		{"number":12,"title":"Add login","headRefName":"feature/login","url":"https://github.com/o/r/pull/12","state":"OPEN","isDraft":true,"author":{"login":"alice"}},
+		{"number":9,"title":"Fix crash","headRefName":"fix/crash","url":"https://github.com/o/r/pull/9","state":"MERGED","isDraft":false,"author":{"login":"bob"}}
+	]}}}`
+
+	page, err := parsePRSearch([]byte(response))
+	if err != nil {
+		t.Fatalf("parsePRSearch failed: %v", err)
+	}
+	if page.Total != 73 || !page.HasMore || page.EndCursor != "Y3Vyc29yOjMw" || len(page.PRs) != 2 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	first := page.PRs[0]
+	if first.Number != 12 || first.HeadRefName != "feature/login" || first.Status != "open" || !first.IsDraft || first.Author.Login != "alice" {
+		t.Errorf("Unexpected PR: %+v", first)
+	}
+	if page.PRs[1].Status != "merged" {
+		t.Errorf("Expected lower-case state, got %q", page.PRs[1].Status)
+	}
+
+	if _, err := parsePRSearch([]byte(`{"errors":[{"message":"Bad credentials"}]}`)); err == nil {
+		t.Error("Expected GraphQL errors to be returned")
+	}
+}
@@ -0,0 +1,253 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PRDetails holds the review, CI and merge state of a pull request
+type PRDetails struct {
+	State             string   // "open", "merged" or "closed"
+	Draft             bool     // Whether the PR is a draft
+	Checks            string   // CI check rollup: "passing", "failing", "pending", "" = no checks
+	ReviewDecision    string   // "approved", "changes_requested", "review_required", "" = no review needed
+	Reviewers         []string // Requested reviewers (users or teams) who haven't reviewed yet
+	Mergeable         string   // "mergeable", "conflicting", "" = not computed yet
+	UnresolvedThreads int      // Review threads not marked as resolved
+}
+
+// prDetailsFields are the pull request fields shown as PR badges
+const prDetailsFields = `state
+      isDraft
+      reviewDecision
+      mergeable
+      reviewRequests(first: 20) {
+        nodes {
+          requestedReviewer {
+            ... on User { login }
+            ... on Team { slug }
+          }
+        }
+      }
+      commits(last: 1) {
+        nodes { commit { statusCheckRollup { state } } }
+      }
+      reviewThreads(first: 100) {
+        nodes { isResolved }
+      }`
+
+// prDetailsQuery fetches everything shown as PR badges in one request
+const prDetailsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      ` + prDetailsFields + `
+    }
+  }
+}`
+
+// prDetailsBatchSize is how many PRs GetPRDetailsBatch fetches per request
+const prDetailsBatchSize = 25
+
+// prURLPattern matches pull request URLs on any host, GitHub Enterprise serves them from its own
+var prURLPattern = regexp.MustCompile(`https?://[^/\s]+/([^/\s]+)/([^/\s]+)/pull/(\d+)`)
+
+// parsePRURL extracts the owner, repository name and number from a pull request URL
+func parsePRURL(prURL string) (string, string, int, error) {
+	match := prURLPattern.FindStringSubmatch(prURL)
+	if match == nil {
+		return "", "", 0, fmt.Errorf("not a GitHub pull request URL: %s", prURL)
+	}
+	number, _ := strconv.Atoi(match[3])
+	return match[1], match[2], number, nil
+}
+
+// GetPRDetails gets the review, CI and merge state of a pull request
+func (m *Manager) GetPRDetails(prURL string) (*PRDetails, error) {
+	owner, name, number, err := parsePRURL(prURL)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := m.graphQL(prDetailsQuery, map[string]interface{}{"owner": owner, "name": name, "number": number})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PR details: %w", err)
+	}
+	return parsePRDetails(output)
+}
+
+// prDetailsNode is a pull request in the response of prDetailsQuery
+type prDetailsNode struct {
+	State          string `json:"state"`
+	IsDraft        bool   `json:"isDraft"`
+	ReviewDecision string `json:"reviewDecision"`
+	Mergeable      string `json:"mergeable"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	ReviewThreads struct {
+		Nodes []struct {
+			IsResolved bool `json:"isResolved"`
+		} `json:"nodes"`
+	} `json:"reviewThreads"`
+}
+
+// parsePRDetails parses the response of prDetailsQuery
+func parsePRDetails(data []byte) (*PRDetails, error) {
+	if err := graphQLError(data); err != nil {
+		return nil, fmt.Errorf("failed to get PR details: %w", err)
+	}
+	var response struct {
+		Data struct {
+			Repository struct {
+				PullRequest *prDetailsNode `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse PR details: %w", err)
+	}
+	pr := response.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request not found")
+	}
+	return pr.details(), nil
+}
+
+// details turns the GraphQL pull request into badges data
+func (pr *prDetailsNode) details() *PRDetails {
+	details := &PRDetails{
+		State:          strings.ToLower(pr.State),
+		Draft:          pr.IsDraft,
+		ReviewDecision: strings.ToLower(pr.ReviewDecision),
+	}
+
+	switch pr.Mergeable {
+	case "MERGEABLE":
+		details.Mergeable = "mergeable"
+	case "CONFLICTING":
+		details.Mergeable = "conflicting"
+	}
+
+	for _, node := range pr.ReviewRequests.Nodes {
+		if reviewer := node.RequestedReviewer.Login; reviewer != "" {
+			details.Reviewers = append(details.Reviewers, reviewer)
+		} else if team := node.RequestedReviewer.Slug; team != "" {
+			details.Reviewers = append(details.Reviewers, team)
+		}
+	}
+
+	if nodes := pr.Commits.Nodes; len(nodes) > 0 && nodes[0].Commit.StatusCheckRollup != nil {
+		details.Checks = checksState(nodes[0].Commit.StatusCheckRollup.State)
+	}
+
+	for _, thread := range pr.ReviewThreads.Nodes {
+		if !thread.IsResolved {
+			details.UnresolvedThreads++
+		}
+	}
+	return details
+}
+
+// GetPRDetailsBatch gets the badges data of many pull requests with one request per
+// prDetailsBatchSize PRs. PRs that can't be fetched (not on GitHub, deleted, no access)
+// are missing from the result, an error is only returned when nothing could be fetched
+func (m *Manager) GetPRDetailsBatch(prURLs []string) (map[string]*PRDetails, error) {
+	result := make(map[string]*PRDetails)
+	var lastErr error
+	for start := 0; start < len(prURLs); start += prDetailsBatchSize {
+		end := min(start+prDetailsBatchSize, len(prURLs))
+		query, variables, aliases := prDetailsBatchQuery(prURLs[start:end])
+		if len(aliases) == 0 {
+			continue
+		}
+		output, err := m.graphQL(query, variables)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		found, err := parsePRDetailsBatch(output, aliases)
+		if err != nil {
+			lastErr = err
+		}
+		for url, details := range found {
+			result[url] = details
+		}
+	}
+	if len(result) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to get PR details: %w", lastErr)
+	}
+	return result, nil
+}
+
+// prDetailsBatchQuery builds one query fetching several PRs under the aliases pr0, pr1...
+// Returns the aliases with the URL each one fetches, URLs that aren't PRs are left out
+func prDetailsBatchQuery(prURLs []string) (string, map[string]interface{}, map[string]string) {
+	var params, fields []string
+	variables := make(map[string]interface{})
+	aliases := make(map[string]string)
+	for i, prURL := range prURLs {
+		owner, name, number, err := parsePRURL(prURL)
+		if err != nil {
+			continue
+		}
+		alias := fmt.Sprintf("pr%d", i)
+		aliases[alias] = prURL
+		variables[fmt.Sprintf("o%d", i)] = owner
+		variables[fmt.Sprintf("n%d", i)] = name
+		variables[fmt.Sprintf("p%d", i)] = number
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!, $p%d: Int!", i, i, i))
+		fields = append(fields, fmt.Sprintf("%s: repository(owner: $o%d, name: $n%d) { pullRequest(number: $p%d) { %s } }", alias, i, i, i, prDetailsFields))
+	}
+	query := fmt.Sprintf("query(%s) {\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n"))
+	return query, variables, aliases
+}
+
+// parsePRDetailsBatch parses the response of prDetailsBatchQuery into details by PR URL
+func parsePRDetailsBatch(data []byte, aliases map[string]string) (map[string]*PRDetails, error) {
+	var response struct {
+		Data map[string]*struct {
+			PullRequest *prDetailsNode `json:"pullRequest"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse PR details: %w", err)
+	}
+	result := make(map[string]*PRDetails)
+	for alias, repo := range response.Data {
+		if url, ok := aliases[alias]; ok && repo != nil && repo.PullRequest != nil {
+			result[url] = repo.PullRequest.details()
+		}
+	}
+	// Errors come with partial data, e.g. one repository that can't be resolved
+	return result, graphQLError(data)
+}
+
+// checksState maps a GitHub status check rollup state to "passing", "failing" or "pending"
+func checksState(rollup string) string {
+	switch rollup {
+	case "SUCCESS":
+		return "passing"
+	case "FAILURE", "ERROR":
+		return "failing"
+	case "PENDING", "EXPECTED":
+		return "pending"
+	}
+	return ""
+}
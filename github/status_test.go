@@ -0,0 +1,61 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParsePRURL tests extracting the repository and number from PR URLs
+func TestParsePRURL(t *testing.T) {
+	owner, name, number, err := parsePRURL("https://github.com/coollabsio/jean-tui/pull/42/files")
+	if err != nil || owner != "coollabsio" || name != "jean-tui" || number != 42 {
+		t.Errorf("Unexpected result: %s %s %d %v", owner, name, number, err)
+	}
+	owner, name, number, err = parsePRURL("https://github.example.com/team/app/pull/7")
+	if err != nil || owner != "team" || name != "app" || number != 7 {
+		t.Errorf("Expected GitHub Enterprise URLs to parse, got %s %s %d %v", owner, name, number, err)
+	}
+	if _, _, _, err := parsePRURL("https://gitlab.com/o/r/-/merge_requests/1"); err == nil {
+		t.Error("Expected an error for a non-GitHub URL")
+	}
+}
+
+// TestParsePRDetails tests turning the GraphQL response into PR badges data
+func TestParsePRDetails(t *testing.T) {
+	response := `{"data":{"repository":{"pullRequest":{
+		"state":"OPEN","isDraft":true,"reviewDecision":"CHANGES_REQUESTED","mergeable":"CONFLICTING",
+		"reviewRequests":{"nodes":[{"requestedReviewer":{"login":"alice"}},{"requestedReviewer":{"slug":"backend"}}]},
+		"commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"FAILURE"}}}]},
+		"reviewThreads":{"nodes":[{"isResolved":false},{"isResolved":true},{"isResolved":false}]}
+	}}}}`
+
+	details, err := parsePRDetails([]byte(response))
+	if err != nil {
+		t.Fatalf("parsePRDetails failed: %v", err)
+	}
+	want := &PRDetails{
+		State:             "open",
+		Draft:             true,
+		Checks:            "failing",
+		ReviewDecision:    "changes_requested",
+		Reviewers:         []string{"alice", "backend"},
+		Mergeable:         "conflicting",
+		UnresolvedThreads: 2,
+	}
+	if !reflect.DeepEqual(details, want) {
+		t.Errorf("Expected %+v, got %+v", want, details)
+	}
+
+	// No checks, no review requirement and mergeability still being computed
+	details, err = parsePRDetails([]byte(`{"data":{"repository":{"pullRequest":{"state":"MERGED","reviewDecision":null,"mergeable":"UNKNOWN","commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]}}}}}`))
+	if err != nil {
+		t.Fatalf("parsePRDetails failed: %v", err)
+	}
+	if details.State != "merged" || details.Checks != "" || details.ReviewDecision != "" || details.Mergeable != "" {
+		t.Errorf("Expected empty badges, got %+v", details)
+	}
+
+	if _, err := parsePRDetails([]byte(`{"data":{"repository":{"pullRequest":null}},"errors":[{"message":"Could not resolve"}]}`)); err == nil {
+		t.Error("Expected GraphQL errors to be returned")
+	}
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PRTemplate is a pull request template of the repository
+type PRTemplate struct {
+	Name string // File name, e.g. "pull_request_template.md" or "bug_fix.md"
+	Body string
+}
+
+// prTemplateDirs are the directories GitHub looks for pull request templates in, in order
+var prTemplateDirs = []string{".github", "", "docs"}
+
+// isPRTemplateName reports whether a file name is a pull request template
+// GitHub matches the name case-insensitively, with or without a .md or .txt extension
+func isPRTemplateName(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range []string{"", ".md", ".txt"} {
+		if name == "pull_request_template"+ext {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadPRTemplates returns the pull request templates of a worktree: the single template
+// (pull_request_template.md in .github, the root or docs) first, then the templates of the
+// PULL_REQUEST_TEMPLATE directories sorted by name. Empty templates are skipped
+func LoadPRTemplates(worktreePath string) []PRTemplate {
+	var single, multiple []PRTemplate
+	for _, dir := range prTemplateDirs {
+		entries, err := os.ReadDir(filepath.Join(worktreePath, dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !isPRTemplateName(entry.Name()) {
+				continue
+			}
+			path := filepath.Join(worktreePath, dir, entry.Name())
+			if !entry.IsDir() {
+				single = appendPRTemplate(single, path)
+				continue
+			}
+
+			files, err := os.ReadDir(path)
+			if err != nil {
+				continue
+			}
+			var found []PRTemplate
+			for _, file := range files {
+				ext := strings.ToLower(filepath.Ext(file.Name()))
+				if !file.IsDir() && (ext == ".md" || ext == ".txt") {
+					found = appendPRTemplate(found, filepath.Join(path, file.Name()))
+				}
+			}
+			sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+			multiple = append(multiple, found...)
+		}
+	}
+	return append(single, multiple...)
+}
+
+// appendPRTemplate reads a template file and appends it unless it is empty or unreadable
+func appendPRTemplate(templates []PRTemplate, path string) []PRTemplate {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return templates
+	}
+	body := strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if body == "" {
+		return templates
+	}
+	return append(templates, PRTemplate{Name: filepath.Base(path), Body: body})
+}
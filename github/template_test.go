@@ -0,0 +1,46 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadPRTemplates tests finding the single template and the template directories
+func TestLoadPRTemplates(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if templates := LoadPRTemplates(root); len(templates) != 0 {
+		t.Errorf("Expected no templates, got %+v", templates)
+	}
+
+	write(".github/PULL_REQUEST_TEMPLATE/feature.md", "## Feature\r\n- [ ] Docs\r\n")
+	write(".github/PULL_REQUEST_TEMPLATE/bug_fix.md", "## Bug\n")
+	write(".github/PULL_REQUEST_TEMPLATE/notes.yml", "ignored: true\n")
+	write("docs/Pull_Request_Template.md", "## Summary\n")
+	write("pull_request_template.txt", "   \n")
+
+	templates := LoadPRTemplates(root)
+	want := []PRTemplate{
+		{Name: "Pull_Request_Template.md", Body: "## Summary"},
+		{Name: "bug_fix.md", Body: "## Bug"},
+		{Name: "feature.md", Body: "## Feature\n- [ ] Docs"},
+	}
+	if len(templates) != len(want) {
+		t.Fatalf("Expected %d templates, got %+v", len(want), templates)
+	}
+	for i := range want {
+		if templates[i] != want[i] {
+			t.Errorf("Template %d: expected %+v, got %+v", i, want[i], templates[i])
+		}
+	}
+}
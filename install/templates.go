@@ -41,8 +41,10 @@ jean() {
         if [ "$debug_enabled" = "true" ]; then
         echo "DEBUG wrapper: switch file exists and has content" >> "$debug_log"
         fi
-        # Read the switch info: path|branch|auto-claude|target-window|script-command|claude-session-name|is-claude-initialized
-        local switch_info=$(cat "$temp_file")
+        # Read the switch info: path|branch|auto-claude|target-window|script-command|claude-session-name|is-claude-initialized|agent-window|agent-command
+        # The second line holds the multiplexer (tmux or zellij)
+        local switch_info=$(head -n 1 "$temp_file")
+        local multiplexer=$(sed -n 2p "$temp_file")
         if [ "$debug_enabled" = "true" ]; then
         echo "DEBUG wrapper: switch_info=$switch_info" >> "$debug_log"
         fi
@@ -52,10 +54,48 @@ jean() {
         fi
 
         # Parse the info (using worktree_path instead of path to avoid PATH conflict)
-        IFS='|' read -r worktree_path branch auto_claude target_window script_command claude_session_name is_claude_initialized <<< "$switch_info"
+        # agent_command is last and keeps any "|" it contains
+        IFS='|' read -r worktree_path branch auto_claude target_window script_command claude_session_name is_claude_initialized agent_window agent_command <<< "$switch_info"
+
+        # Older jean versions don't send the agent: fall back to claude
+        if [ -z "$agent_window" ]; then
+            agent_window="claude"
+            agent_command=""
+            if command -v claude >/dev/null 2>&1; then
+                agent_command="claude --add-dir \"$worktree_path\" --permission-mode plan"
+                if [ "$is_claude_initialized" = "true" ]; then
+                    # Try with --continue first, fallback to fresh start if it fails
+                    agent_command="claude --add-dir \"$worktree_path\" --continue --permission-mode plan || $agent_command"
+                fi
+            fi
+        fi
 
         # Check if we got valid data (has at least two pipes)
         if [[ "$switch_info" == *"|"*"|"* ]]; then
+            # Zellij sessions are built by jean, the wrapper only attaches to them
+            if [ "$multiplexer" = "zellij" ]; then
+                if ! command -v zellij >/dev/null 2>&1; then
+                    # No zellij, just cd
+                    cd "$worktree_path" || return
+                    echo "Switched to worktree: $branch (no zellij)"
+                    return
+                fi
+                if [ -n "$ZELLIJ" ]; then
+                    # Zellij can't switch sessions from the command line inside a session
+                    cd "$worktree_path" || return
+                    echo "Switched to worktree: $branch"
+                    echo "Note: Already in zellij. Session: $claude_session_name would be available outside zellij."
+                    return
+                fi
+                local tab_name="terminal"
+                if [ "$target_window" = "claude" ]; then
+                    tab_name="$agent_window"
+                fi
+                zellij --session "$claude_session_name" action go-to-tab-name "$tab_name" 2>/dev/null
+                zellij attach --create "$claude_session_name" options --default-cwd "$worktree_path"
+                continue
+            fi
+
             # Check if tmux is available
             if ! command -v tmux >/dev/null 2>&1; then
                 # No tmux, just cd
@@ -102,17 +142,12 @@ jean() {
                 if ! tmux list-windows -t "$session_name" -F "#{window_index}:#{window_name}" | grep -q "^${window_index}:"; then
                     # Target window doesn't exist, create it
                     if [ "$target_window" = "claude" ]; then
-                        # Create claude window with claude command
-                        if command -v claude >/dev/null 2>&1; then
-                            if [ "$is_claude_initialized" = "true" ]; then
-                                # Try with --continue first, fallback to fresh start if it fails
-                                tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude" "claude --add-dir \"$worktree_path\" --continue --permission-mode plan || claude --add-dir \"$worktree_path\" --permission-mode plan"
-                            else
-                                tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude" "claude --add-dir \"$worktree_path\" --permission-mode plan"
-                            fi
+                        # Create agent window with the agent command
+                        if [ -n "$agent_command" ]; then
+                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window" "$agent_command"
                         else
-                            # Fallback to shell if claude not available
-                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude"
+                            # Fallback to shell if the agent is not available
+                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window"
                         fi
                     else
                         # Create terminal window
@@ -130,18 +165,13 @@ jean() {
                 # Window 1: terminal (always created) - base-index 1 makes first window = 1
                 tmux new-session -d -s "$session_name" -c "$worktree_path" -n "terminal"
 
-                # Window 2: claude (if auto-claude is true)
+                # Window 2: agent (if auto-claude is true)
                 if [ "$auto_claude" = "true" ]; then
-                    if command -v claude >/dev/null 2>&1; then
-                        if [ "$is_claude_initialized" = "true" ]; then
-                            # Try with --continue first, fallback to fresh start if it fails
-                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude" "claude --add-dir \"$worktree_path\" --continue --permission-mode plan || claude --add-dir \"$worktree_path\" --permission-mode plan"
-                        else
-                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude" "claude --add-dir \"$worktree_path\" --permission-mode plan"
-                        fi
+                    if [ -n "$agent_command" ]; then
+                        tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window" "$agent_command"
                     else
                         # Fallback: create window with shell
-                        tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude"
+                        tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window"
                     fi
                 fi
 
@@ -192,12 +222,19 @@ function jean
 
         # Check if switch info was written
         if test -f "$temp_file" -a -s "$temp_file"
-            # Read the switch info: path|branch|auto-claude|target-window|script-command|claude-session-name|is-claude-initialized
-            set switch_info (cat $temp_file)
+            # Read the switch info: path|branch|auto-claude|target-window|script-command|claude-session-name|is-claude-initialized|agent-window|agent-command
+            # The second line holds the multiplexer (tmux or zellij)
+            set lines (cat $temp_file)
             rm $temp_file
+            set switch_info $lines[1]
+            set multiplexer ""
+            if test (count $lines) -ge 2
+                set multiplexer $lines[2]
+            end
 
             # Parse the info (using worktree_path instead of path to avoid PATH conflict)
-            set parts (string split '|' $switch_info)
+            # Split at most 8 times so agent-command keeps any "|" it contains
+            set parts (string split -m 8 '|' $switch_info)
 
             # Check if we got valid data (has at least 3 parts)
             if test (count $parts) -ge 3
@@ -216,6 +253,48 @@ function jean
                 if test (count $parts) -ge 7
                     set is_claude_initialized $parts[7]
                 end
+                set agent_window ""
+                set agent_command ""
+                if test (count $parts) -ge 9
+                    set agent_window $parts[8]
+                    set agent_command $parts[9]
+                end
+
+                # Older jean versions don't send the agent: fall back to claude
+                if test -z "$agent_window"
+                    set agent_window "claude"
+                    if command -v claude &> /dev/null
+                        set agent_command "claude --add-dir \"$worktree_path\" --permission-mode plan"
+                        if test "$is_claude_initialized" = "true"
+                            # Try with --continue first, fallback to fresh start if it fails
+                            set agent_command "claude --add-dir \"$worktree_path\" --continue --permission-mode plan; or $agent_command"
+                        end
+                    end
+                end
+
+                # Zellij sessions are built by jean, the wrapper only attaches to them
+                if test "$multiplexer" = "zellij"
+                    if not command -v zellij &> /dev/null
+                        # No zellij, just cd
+                        cd $worktree_path
+                        echo "Switched to worktree: $branch (no zellij)"
+                        return
+                    end
+                    if test -n "$ZELLIJ"
+                        # Zellij can't switch sessions from the command line inside a session
+                        cd $worktree_path
+                        echo "Switched to worktree: $branch"
+                        echo "Note: Already in zellij. Session: $claude_session_name would be available outside zellij."
+                        return
+                    end
+                    set tab_name "terminal"
+                    if test "$target_window" = "claude"
+                        set tab_name $agent_window
+                    end
+                    zellij --session "$claude_session_name" action go-to-tab-name "$tab_name" 2>/dev/null
+                    zellij attach --create "$claude_session_name" options --default-cwd "$worktree_path"
+                    continue
+                end
 
                 # Check if tmux is available
                 if not command -v tmux &> /dev/null
@@ -259,17 +338,12 @@ function jean
                     if test $window_exists -eq 0
                         # Target window doesn't exist, create it
                         if test "$target_window" = "claude"
-                            # Create claude window
-                            if command -v claude &> /dev/null
-                                set claude_args "--add-dir \"$worktree_path\" --permission-mode plan"
-                                if test "$is_claude_initialized" = "true"
-                                    # Try with --continue first, fallback to fresh start if it fails
-                                    set claude_args "--add-dir \"$worktree_path\" --continue --permission-mode plan; or claude --add-dir \"$worktree_path\" --permission-mode plan"
-                                end
-                                tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude" "claude $claude_args"
+                            # Create agent window
+                            if test -n "$agent_command"
+                                tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window" "$agent_command"
                             else
                                 # Fallback to shell
-                                tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude"
+                                tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window"
                             end
                         else
                             # Create terminal window
@@ -284,18 +358,13 @@ function jean
                     # Window 1: terminal (always created) - base-index 1 makes first window = 1
                     tmux new-session -d -s "$session_name" -c "$worktree_path" -n "terminal"
 
-                    # Window 2: claude (if auto-claude is true)
+                    # Window 2: agent (if auto-claude is true)
                     if test "$auto_claude" = "true"
-                        if command -v claude &> /dev/null
-                            set claude_args "--add-dir \"$worktree_path\" --permission-mode plan"
-                            if test "$is_claude_initialized" = "true"
-                                # Try with --continue first, fallback to fresh start if it fails
-                                set claude_args "--add-dir \"$worktree_path\" --continue --permission-mode plan; or claude --add-dir \"$worktree_path\" --permission-mode plan"
-                            end
-                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude" "claude $claude_args"
+                        if test -n "$agent_command"
+                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window" "$agent_command"
                         else
                             # Fallback: create window with shell
-                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "claude"
+                            tmux new-window -t "$session_name:2" -c "$worktree_path" -n "$agent_window"
                         end
                     end
 
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,9 +13,12 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/gc"
+	"github.com/coollabsio/jean-tui/git"
 	"github.com/coollabsio/jean-tui/install"
 	"github.com/coollabsio/jean-tui/internal/update"
 	"github.com/coollabsio/jean-tui/internal/version"
+	"github.com/coollabsio/jean-tui/session"
 	"github.com/coollabsio/jean-tui/tui"
 )
 
@@ -57,7 +61,7 @@ func main() {
 	shouldCheckInit := true
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
-		case "init", "version", "help":
+		case "init", "version", "help", "agent-state", "agent", "gc":
 			shouldCheckInit = false
 		}
 	}
@@ -78,6 +82,15 @@ func main() {
 		case "update":
 			handleUpdate()
 			return
+		case "agent-state":
+			handleAgentState()
+			return
+		case "agent":
+			handleAgent()
+			return
+		case "gc":
+			handleGC()
+			return
 		case "version":
 			fmt.Printf("jean version %s\n", version.CliVersion)
 			os.Exit(0)
@@ -130,7 +143,8 @@ func main() {
 	if m, ok := finalModel.(tui.Model); ok {
 		switchInfo := m.GetSwitchInfo()
 		if switchInfo.Path != "" {
-			// Format: path|branch|auto-claude|target-window|script-command|session-name|is-claude-initialized
+			// Format: path|branch|auto-claude|target-window|script-command|session-name|is-claude-initialized|agent-window|agent-command
+			// The agent command is last because it may contain "|" (resume fallback uses "||")
 			autoCl := "false"
 			if switchInfo.AutoClaude {
 				autoCl = "true"
@@ -143,7 +157,11 @@ func main() {
 			if switchInfo.IsClaudeInitialized {
 				isInitialized = "true"
 			}
-			switchData := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", switchInfo.Path, switchInfo.Branch, autoCl, targetWindow, switchInfo.ScriptCommand, switchInfo.SessionName, isInitialized)
+			switchData := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s", switchInfo.Path, switchInfo.Branch, autoCl, targetWindow, switchInfo.ScriptCommand, switchInfo.SessionName, isInitialized, switchInfo.AgentWindow, switchInfo.AgentCommand)
+			// The multiplexer goes on a second line, which older wrappers ignore
+			if switchInfo.Multiplexer != "" {
+				switchData += "\n" + switchInfo.Multiplexer
+			}
 
 			// Debug: log what we're writing
 			debugLog(fmt.Sprintf("DEBUG main: switchInfo={Path:%q Branch:%q AutoClaude:%v TargetWindow:%q SessionName:%q}", switchInfo.Path, switchInfo.Branch, switchInfo.AutoClaude, switchInfo.TargetWindow, switchInfo.SessionName))
@@ -334,6 +352,193 @@ func handleInit() {
 	}
 }
 
+// handleAgentState records the state of the AI agent running in a worktree
+// Called by agent hooks: "jean agent-state --hook" (Claude Code hook JSON on stdin)
+// or "jean agent-state <working|waiting|idle|errored> [message]" for other agents
+func handleAgentState() {
+	stateCmd := flag.NewFlagSet("agent-state", flag.ExitOnError)
+	hookFlag := stateCmd.Bool("hook", false, "Read Claude Code hook input from stdin")
+	pathFlag := stateCmd.String("path", ".", "Worktree path")
+
+	stateCmd.Parse(os.Args[2:])
+
+	path := *pathFlag
+	var state session.AgentState
+
+	if *hookFlag {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return
+		}
+		hookState, cwd, err := session.AgentStateFromHook(data)
+		if err != nil {
+			// Never fail the agent because of jean, unknown events are ignored
+			debugLog(fmt.Sprintf("agent-state hook ignored: %v", err))
+			return
+		}
+		state = hookState
+		if cwd != "" && path == "." {
+			path = cwd
+		}
+	} else {
+		args := stateCmd.Args()
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Usage: jean agent-state <working|waiting|idle|errored> [message]\n")
+			os.Exit(1)
+		}
+		state = session.AgentState{State: args[0], Message: strings.Join(args[1:], " ")}
+	}
+
+	if err := session.WriteAgentState(path, state); err != nil {
+		if *hookFlag {
+			debugLog(fmt.Sprintf("agent-state hook failed: %v", err))
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleAgent handles the agent subcommands
+// "jean agent send <branch> <prompt>" types a prompt into the agent window of a worktree's session
+func handleAgent() {
+	if len(os.Args) < 3 || os.Args[2] != "send" {
+		fmt.Fprintf(os.Stderr, "Usage: jean agent send [-path <repo>] <branch> <prompt>\n")
+		os.Exit(1)
+	}
+
+	sendCmd := flag.NewFlagSet("agent send", flag.ExitOnError)
+	pathFlag := sendCmd.String("path", ".", "Path to git repository")
+	sendCmd.Parse(os.Args[3:])
+
+	args := sendCmd.Args()
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: jean agent send [-path <repo>] <branch> <prompt>\n")
+		os.Exit(1)
+	}
+	branch := args[0]
+
+	// Read the prompt from stdin when it is omitted or "-" (e.g. long prompts from a file)
+	prompt := strings.Join(args[1:], " ")
+	if prompt == "" || prompt == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read prompt: %v\n", err)
+			os.Exit(1)
+		}
+		prompt = string(data)
+	}
+
+	gitManager := git.NewManager(*pathFlag)
+	repoPath, err := gitManager.GetRepoRoot()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	worktrees, err := gitManager.List("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			found = true
+			break
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "Error: no worktree for branch '%s'\n", branch)
+		os.Exit(1)
+	}
+
+	sessionManager := session.NewManager()
+	if cfg, err := config.NewManager(); err == nil {
+		sessionManager.SetAgent(tui.RepoAgent(cfg, repoPath))
+		sessionManager.SetMultiplexer(session.NewMultiplexer(cfg.GetMultiplexer()))
+	}
+
+	sessionName := sessionManager.SanitizeName(filepath.Base(repoPath), branch)
+	if err := sessionManager.SendPrompt(sessionName, prompt); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Prompt sent to %s\n", branch)
+}
+
+// handleGC finds orphan sessions, prunable worktrees, merged branches and stale config
+// entries, lists them and removes them after confirmation
+func handleGC() {
+	gcCmd := flag.NewFlagSet("gc", flag.ExitOnError)
+	pathFlag := gcCmd.String("path", ".", "Path to git repository")
+	dryRunFlag := gcCmd.Bool("dry-run", false, "Only list what would be cleaned")
+	yesFlag := gcCmd.Bool("yes", false, "Clean without asking for confirmation")
+	gcCmd.Parse(os.Args[2:])
+
+	gitManager := git.NewManager(*pathFlag)
+	repoPath, err := gitManager.GetRepoRoot()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	gitManager = git.NewManager(repoPath)
+
+	collector := &gc.Collector{
+		RepoPath: repoPath,
+		Git:      gitManager,
+		Sessions: session.NewManager(),
+	}
+	if cfg, err := config.NewManager(); err == nil {
+		collector.Config = cfg
+		collector.BaseBranch = cfg.GetBaseBranch(repoPath)
+		collector.Sessions.SetMultiplexer(session.NewMultiplexer(cfg.GetMultiplexer()))
+	}
+	if collector.BaseBranch == "" {
+		collector.BaseBranch, _ = gitManager.GetDefaultBranch()
+	}
+
+	items, err := collector.Find()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing to clean up")
+		return
+	}
+
+	fmt.Printf("Found %d item(s) to clean up in %s:\n", len(items), repoPath)
+	for _, item := range items {
+		fmt.Printf("  %-9s %s (%s)\n", item.Kind, item.Name, item.Reason)
+	}
+
+	if *dryRunFlag {
+		return
+	}
+	if !*yesFlag {
+		fmt.Print("\nClean up these items? [y/N] ")
+		var answer string
+		fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" && answer != "yes" {
+			fmt.Println("Aborted")
+			return
+		}
+	}
+
+	failed := 0
+	for _, result := range collector.Clean(items) {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "  ✗ %s %s: %v\n", result.Item.Kind, result.Item.Name, result.Err)
+		}
+	}
+	fmt.Printf("Cleaned %d of %d item(s)\n", len(items)-failed, len(items))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
 // GetRCFileForShell is exported from install package wrapper
 func getRCFileForShell(shell install.Shell, homeDir string) string {
 	switch shell {
@@ -362,6 +567,9 @@ USAGE:
 COMMANDS:
     init            Install or manage jean shell integration
     update          Update jean to the latest version
+    agent-state     Record the AI agent state of a worktree (used by agent hooks)
+    agent send      Send a prompt to a worktree's agent session: jean agent send <branch> <prompt>
+    gc              Clean up orphan sessions, pruned worktrees, merged branches and stale config
     help            Show this help message
     version         Print version and exit
 
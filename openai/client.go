@@ -46,6 +46,11 @@ type PRContent struct {
 	Description string `json:"description"`
 }
 
+// ConflictResolution represents an AI-suggested conflict resolution
+type ConflictResolution struct {
+	Resolution string `json:"resolution"`
+}
+
 // NewClient creates a new OpenAI-compatible API client
 func NewClient(apiKey, baseURL, model string) (*Client, error) {
 	if apiKey == "" {
@@ -150,6 +155,12 @@ func (c *Client) GenerateBranchName(diff, customPrompt string) (string, error) {
 
 // GeneratePRContent generates a PR title and description from a git diff
 func (c *Client) GeneratePRContent(diff, customPrompt string) (title, description string, err error) {
+	return c.GeneratePRContentFromTemplate(diff, "", customPrompt)
+}
+
+// GeneratePRContentFromTemplate generates a PR title and a description that fills in the
+// repository's pull request template (no template = the prompt's own format)
+func (c *Client) GeneratePRContentFromTemplate(diff, template, customPrompt string) (title, description string, err error) {
 	// Limit diff to reasonable size
 	if len(diff) > 5000 {
 		diff = diff[:5000]
@@ -160,7 +171,11 @@ func (c *Client) GeneratePRContent(diff, customPrompt string) (title, descriptio
 	if prompt == "" {
 		prompt = DefaultPRPrompt
 	}
-	// Replace {diff} placeholder with actual diff
+	if template = strings.TrimSpace(template); template != "" && !strings.Contains(prompt, "{template}") {
+		prompt += PRTemplatePrompt
+	}
+	// Replace {template} and {diff} placeholders with the actual template and diff
+	prompt = strings.ReplaceAll(prompt, "{template}", template)
 	prompt = strings.ReplaceAll(prompt, "{diff}", diff)
 
 	response, err := c.callAPI(prompt)
@@ -186,6 +201,40 @@ func (c *Client) GeneratePRContent(diff, customPrompt string) (title, descriptio
 	return content.Title, content.Description, nil
 }
 
+// SuggestConflictResolution asks the AI for a resolution of a single conflict hunk
+func (c *Client) SuggestConflictResolution(file, ours, base, theirs string) (string, error) {
+	// Limit each side to reasonable size
+	limit := func(s string) string {
+		if len(s) > 3000 {
+			return s[:3000]
+		}
+		return s
+	}
+
+	prompt := DefaultConflictPrompt
+	prompt = strings.ReplaceAll(prompt, "{file}", file)
+	prompt = strings.ReplaceAll(prompt, "{ours}", limit(ours))
+	prompt = strings.ReplaceAll(prompt, "{base}", limit(base))
+	prompt = strings.ReplaceAll(prompt, "{theirs}", limit(theirs))
+
+	response, err := c.callAPI(prompt)
+	if err != nil {
+		return "", WrapError("suggest conflict resolution", err)
+	}
+
+	// Parse JSON response (keeps leading whitespace of the resolved code intact)
+	var content ConflictResolution
+	if err := json.Unmarshal([]byte(response), &content); err != nil {
+		return "", fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	if strings.Contains(content.Resolution, "<<<<<<<") || strings.Contains(content.Resolution, ">>>>>>>") {
+		return "", fmt.Errorf("AI suggestion still contains conflict markers")
+	}
+
+	return content.Resolution, nil
+}
+
 // TestConnection makes a simple API call to verify credentials work
 func (c *Client) TestConnection() error {
 	// Make a simple test request
@@ -535,6 +535,44 @@ func TestGeneratePRContent_Success(t *testing.T) {
 	}
 }
 
+// TestGeneratePRContentFromTemplate tests that the repository's PR template is sent to the AI
+func TestGeneratePRContentFromTemplate(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompts = append(prompts, req.Messages[len(req.Messages)-1].Content)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newChatResponse(`{"title": "Add login", "description": "## Summary\nAdds login\n\n- [x] Tests"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-key", server.URL, "gpt-4")
+	template := "## Summary\n<!-- What does this change? -->\n\n- [ ] Tests"
+	if _, desc, err := client.GeneratePRContentFromTemplate("+ login", template, ""); err != nil || !strings.HasPrefix(desc, "## Summary") {
+		t.Fatalf("GeneratePRContentFromTemplate() = %q, %v", desc, err)
+	}
+	if !strings.Contains(prompts[0], "Pull request template:\n"+template) || !strings.Contains(prompts[0], "+ login") {
+		t.Errorf("Expected the template and diff in the prompt, got:\n%s", prompts[0])
+	}
+
+	// Custom prompts can place the template themselves
+	if _, _, err := client.GeneratePRContentFromTemplate("+ login", template, "Fill {template} for {diff}"); err != nil {
+		t.Fatalf("GeneratePRContentFromTemplate() error = %v", err)
+	}
+	if prompts[1] != "Fill "+template+" for + login" {
+		t.Errorf("Unexpected custom prompt %q", prompts[1])
+	}
+
+	// Without a template the prompt is unchanged
+	if _, _, err := client.GeneratePRContent("+ login", ""); err != nil {
+		t.Fatalf("GeneratePRContent() error = %v", err)
+	}
+	if strings.Contains(prompts[2], "Pull request template") {
+		t.Error("Expected no template section without a template")
+	}
+}
+
 // TestGeneratePRContent_InvalidJSON tests error on malformed JSON response
 func TestGeneratePRContent_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -622,6 +660,59 @@ func TestGeneratePRContent_MarkdownCleanup(t *testing.T) {
 	}
 }
 
+// TestSuggestConflictResolution tests parsing of AI conflict resolutions
+func TestSuggestConflictResolution(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiResponse string
+		expected    string
+		expectErr   bool
+	}{
+		{
+			name:        "Preserves indentation",
+			apiResponse: `{"resolution": "    return a + b\n"}`,
+			expected:    "    return a + b\n",
+		},
+		{
+			name:        "Rejects conflict markers",
+			apiResponse: `{"resolution": "<<<<<<< HEAD\na\n=======\nb\n>>>>>>> main\n"}`,
+			expectErr:   true,
+		},
+		{
+			name:        "Invalid JSON",
+			apiResponse: "not json",
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(newChatResponse(tt.apiResponse))
+			}))
+			defer server.Close()
+
+			client, _ := NewClient("test-key", server.URL, "gpt-4")
+			resolution, err := client.SuggestConflictResolution("main.go", "a\n", "", "b\n")
+
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("SuggestConflictResolution() expected error, got %q", resolution)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SuggestConflictResolution() error = %v", err)
+			}
+			if resolution != tt.expected {
+				t.Errorf("SuggestConflictResolution() = %q, want %q", resolution, tt.expected)
+			}
+		})
+	}
+}
+
 // TestTestConnection_Success tests successful connection test
 func TestTestConnection_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -80,6 +80,34 @@ Example JSON Response:
 
 Git diff:
 {diff}`
+
+	// PRTemplatePrompt is appended to the PR prompt when the repository has a pull request template
+	// The {template} placeholder will be replaced with the template, prompts can also place it themselves
+	PRTemplatePrompt = `
+
+The repository has a pull request template. Write the description by filling in this template
+instead of using the format above: keep its headings, checklists and order, replace HTML comments
+and placeholder text with content about these changes, and leave checkboxes you can't decide unchecked.
+
+Pull request template:
+{template}`
+
+	// DefaultConflictPrompt suggests a resolution for a single merge conflict hunk
+	// Placeholders: {file}, {ours}, {base}, {theirs}
+	DefaultConflictPrompt = `Resolve this git merge conflict in {file}.
+
+Current branch version (ours):
+{ours}
+Common ancestor version (base, may be empty):
+{base}
+Incoming branch version (theirs):
+{theirs}
+Combine the intent of both sides. Keep the original indentation and code style.
+
+Return ONLY valid JSON in this format (no markdown, no extra text):
+{"resolution": "..."}
+
+The resolution replaces the whole conflicted region and must not contain conflict markers.`
 )
 
 // GetDefaultCommitPrompt returns the default commit message prompt
@@ -96,3 +124,8 @@ func GetDefaultBranchNamePrompt() string {
 func GetDefaultPRPrompt() string {
 	return DefaultPRPrompt
 }
+
+// GetDefaultConflictPrompt returns the default conflict resolution prompt
+func GetDefaultConflictPrompt() string {
+	return DefaultConflictPrompt
+}
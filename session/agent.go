@@ -0,0 +1,163 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Agent describes the terminal AI coding agent started in the agent window (window 2)
+type Agent struct {
+	Name            string   // Agent name, also used as the tmux window name (e.g. "claude")
+	Command         string   // Executable to run (e.g. "claude", "codex", "aider")
+	Args            string   // Argument template, supports {worktree}, {branch} and {repo}
+	ResumeFlag      string   // Flag that resumes the previous conversation, "" = agent can't resume
+	PermissionFlag  string   // Flag used to pass the permission mode (e.g. "--permission-mode")
+	PermissionMode  string   // Permission mode value, "" = don't pass the permission flag
+	PromptArg       string   // Argument template giving a fresh agent its first prompt, {prompt} is replaced, "" = the agent takes none
+	WaitingPatterns []string // Screen patterns meaning the agent waits for input, empty = built-in Claude detection
+}
+
+// DefaultAgent returns the Claude Code agent used when no agent is configured
+func DefaultAgent() Agent {
+	return Agent{
+		Name:           "claude",
+		Command:        "claude",
+		Args:           "--add-dir {worktree}",
+		ResumeFlag:     "--continue",
+		PermissionFlag: "--permission-mode",
+		PermissionMode: "plan",
+		PromptArg:      "{prompt}",
+	}
+}
+
+// WindowName returns the tmux window name for the agent
+// Characters tmux parses in targets (':' and '.') are replaced, profiles edited by hand may contain them
+func (a Agent) WindowName() string {
+	name := "claude"
+	if a.Name != "" {
+		name = a.Name
+	} else if fields := strings.Fields(a.Command); len(fields) > 0 {
+		name = filepath.Base(fields[0])
+	}
+	return windowNameReplacer.Replace(name)
+}
+
+// windowNameReplacer replaces the characters tmux treats as target separators
+var windowNameReplacer = strings.NewReplacer(":", "-", ".", "-", " ", "-")
+
+// IsAvailable checks if the agent executable is available in PATH
+func (a Agent) IsAvailable() bool {
+	fields := strings.Fields(a.Command)
+	if len(fields) == 0 {
+		return false
+	}
+	_, err := exec.LookPath(fields[0])
+	return err == nil
+}
+
+// BuildCommand builds the shell command starting the agent in a worktree
+// When resume is true the agent's resume flag is added to continue its last conversation
+func (a Agent) BuildCommand(worktreePath, branch, repoName string, resume bool) string {
+	replacer := strings.NewReplacer(
+		"{worktree}", shellQuote(worktreePath),
+		"{branch}", shellQuote(branch),
+		"{repo}", shellQuote(repoName),
+	)
+
+	parts := []string{a.Command}
+	if args := replacer.Replace(a.Args); strings.TrimSpace(args) != "" {
+		parts = append(parts, args)
+	}
+	if resume && a.ResumeFlag != "" {
+		parts = append(parts, a.ResumeFlag)
+	}
+	if a.PermissionFlag != "" && a.PermissionMode != "" {
+		parts = append(parts, a.PermissionFlag, a.PermissionMode)
+	}
+	return strings.Join(parts, " ")
+}
+
+// BuildCommandWithPrompt builds the shell command starting a fresh agent with the content of
+// promptFile as its first prompt. The shell reads the file, so the command stays on one line
+// whatever the prompt contains. Agents without a prompt argument start without it
+func (a Agent) BuildCommandWithPrompt(worktreePath, branch, repoName, promptFile string) string {
+	command := a.BuildCommand(worktreePath, branch, repoName, false)
+	if a.PromptArg == "" {
+		return command
+	}
+	prompt := `"$(cat ` + shellQuote(promptFile) + `)"`
+	return command + " " + strings.ReplaceAll(a.PromptArg, "{prompt}", prompt)
+}
+
+// initialPromptFile is the file in a worktree's git directory holding the first prompt of its agent
+const initialPromptFile = "jean-initial-prompt.md"
+
+// WriteInitialPrompt stores the first prompt of a worktree's agent (e.g. the issue it was created
+// for). It is given to the agent until the agent was started once in the worktree
+func WriteInitialPrompt(worktreePath, prompt string) error {
+	path, err := worktreeGitFile(worktreePath, initialPromptFile)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(prompt), 0644); err != nil {
+		return fmt.Errorf("failed to write initial prompt: %w", err)
+	}
+	return nil
+}
+
+// initialPromptPath returns the initial prompt file of a worktree, "" if it has none
+func initialPromptPath(worktreePath string) string {
+	path, err := worktreeGitFile(worktreePath, initialPromptFile)
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// SendPrompt types a prompt into the agent window of a session and submits it
+// Multi-line prompts arrive as a single message
+func (m *Manager) SendPrompt(sessionName, prompt string) error {
+	if strings.TrimSpace(prompt) == "" {
+		return fmt.Errorf("prompt cannot be empty")
+	}
+	if !m.SessionExists(sessionName) {
+		return fmt.Errorf("session %s is not running", sessionName)
+	}
+
+	windowName := m.agent.WindowName()
+	if !m.mux.HasWindow(sessionName, windowName) {
+		return fmt.Errorf("session %s has no %s window", sessionName, windowName)
+	}
+	return m.mux.SendText(sessionName, windowName, prompt)
+}
+
+// RestartAgent replaces the agent window of a running session with a fresh one
+// The agent resumes its previous conversation when isInitialized is true
+func (m *Manager) RestartAgent(sessionName, path string, isInitialized bool, layout *Layout) error {
+	if !m.mux.SessionExists(sessionName) {
+		return fmt.Errorf("session %s not found", sessionName)
+	}
+	if m.mux.HasWindow(sessionName, m.agent.WindowName()) {
+		if err := m.mux.KillWindow(sessionName, m.agent.WindowName()); err != nil {
+			return err
+		}
+	}
+	return m.mux.AddWindow(sessionName, path, m.agentWindow(path, isInitialized, layout))
+}
+
+var shellSafe = regexp.MustCompile(`^[a-zA-Z0-9_./:@%+=,-]+$`)
+
+// shellQuote quotes a value for use in a shell command if it contains special characters
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
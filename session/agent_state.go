@@ -0,0 +1,320 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Agent states written by agent hooks
+const (
+	AgentWorking = "working" // Agent is processing a prompt or running tools
+	AgentWaiting = "waiting" // Agent needs the user (permission prompt or question)
+	AgentIdle    = "idle"    // Agent finished its turn or the session ended
+	AgentErrored = "errored" // Agent stopped with an error
+)
+
+// agentStateFile is the state file name inside the worktree's git directory
+// Keeping it in the git directory means it never shows up as an uncommitted change
+const agentStateFile = "jean-agent-state.json"
+
+// agentHookCommand is the jean subcommand installed as an agent hook
+const agentHookCommand = "agent-state --hook"
+
+// AgentState is the last state reported by the agent running in a worktree
+type AgentState struct {
+	State     string    `json:"state"`
+	Message   string    `json:"message,omitempty"` // Last prompt, notification or error message
+	Event     string    `json:"event,omitempty"`   // Hook event that produced the state
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsValidAgentState reports whether s is one of the known agent states
+func IsValidAgentState(s string) bool {
+	switch s {
+	case AgentWorking, AgentWaiting, AgentIdle, AgentErrored:
+		return true
+	}
+	return false
+}
+
+// AgentStatePath returns the state file path for a worktree (inside its git directory)
+func AgentStatePath(worktreePath string) (string, error) {
+	return worktreeGitFile(worktreePath, agentStateFile)
+}
+
+// worktreeGitFile returns the path of a file in a worktree's git directory, which never shows up as a change
+func worktreeGitFile(worktreePath, name string) (string, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--absolute-git-dir")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git worktree: %s", worktreePath)
+	}
+	return filepath.Join(strings.TrimSpace(string(output)), name), nil
+}
+
+// WriteAgentState records the agent state of a worktree
+func WriteAgentState(worktreePath string, state AgentState) error {
+	if !IsValidAgentState(state.State) {
+		return fmt.Errorf("invalid agent state '%s': must be working, waiting, idle or errored", state.State)
+	}
+
+	path, err := AgentStatePath(worktreePath)
+	if err != nil {
+		return err
+	}
+
+	if state.UpdatedAt.IsZero() {
+		state.UpdatedAt = time.Now()
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so the TUI never reads a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write agent state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadAgentState returns the last recorded agent state of a worktree
+// Returns nil if no state was recorded (no hooks installed or agent never ran)
+func ReadAgentState(worktreePath string) (*AgentState, error) {
+	path, err := AgentStatePath(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state AgentState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse agent state: %w", err)
+	}
+	return &state, nil
+}
+
+// hookInput is the JSON Claude Code passes to hooks on stdin (only the fields jean uses)
+type hookInput struct {
+	HookEventName string `json:"hook_event_name"`
+	Cwd           string `json:"cwd"`
+	Message       string `json:"message"`   // Notification
+	Prompt        string `json:"prompt"`    // UserPromptSubmit
+	ToolName      string `json:"tool_name"` // PreToolUse
+	Error         string `json:"error"`     // StopFailure
+}
+
+// AgentStateFromHook converts Claude Code hook input to an agent state
+// Returns the state and the working directory the agent runs in
+func AgentStateFromHook(data []byte) (AgentState, string, error) {
+	var input hookInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return AgentState{}, "", fmt.Errorf("failed to parse hook input: %w", err)
+	}
+
+	state := AgentState{Event: input.HookEventName, UpdatedAt: time.Now()}
+	switch input.HookEventName {
+	case "UserPromptSubmit":
+		state.State = AgentWorking
+		state.Message = input.Prompt
+	case "PreToolUse":
+		state.State = AgentWorking
+		if input.ToolName != "" {
+			state.Message = "Running " + input.ToolName
+		}
+	case "Notification":
+		state.State = AgentWaiting
+		state.Message = input.Message
+	case "Stop":
+		state.State = AgentIdle
+		state.Message = "Finished"
+	case "StopFailure":
+		state.State = AgentErrored
+		state.Message = input.Error
+		if state.Message == "" {
+			state.Message = "Stopped with an error"
+		}
+	case "SessionEnd":
+		state.State = AgentIdle
+		state.Message = "Session ended"
+	default:
+		return AgentState{}, "", fmt.Errorf("unsupported hook event '%s'", input.HookEventName)
+	}
+
+	// Keep the state file small, prompts can be long
+	if runes := []rune(state.Message); len(runes) > 200 {
+		state.Message = string(runes[:200]) + "…"
+	}
+	return state, input.Cwd, nil
+}
+
+// claudeHookEvents are the Claude Code hook events jean listens to
+var claudeHookEvents = []string{"UserPromptSubmit", "PreToolUse", "Notification", "Stop", "StopFailure", "SessionEnd"}
+
+// claudeSettingsPath returns the path of the user's Claude Code settings file
+func claudeSettingsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".claude", "settings.json"), nil
+}
+
+// loadClaudeSettings reads the Claude Code settings, keeping unknown keys intact
+func loadClaudeSettings(path string) (map[string]interface{}, error) {
+	settings := map[string]interface{}{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// saveClaudeSettings writes the Claude Code settings file
+func saveClaudeSettings(path string, settings map[string]interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// isJeanHookGroup reports whether a hook matcher group was installed by jean
+func isJeanHookGroup(group interface{}) bool {
+	groupMap, ok := group.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	hooks, _ := groupMap["hooks"].([]interface{})
+	for _, hook := range hooks {
+		if hookMap, ok := hook.(map[string]interface{}); ok {
+			if command, _ := hookMap["command"].(string); strings.HasSuffix(command, agentHookCommand) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasAgentStateHooks checks if jean's agent state hooks are installed in ~/.claude/settings.json
+func (m *Manager) HasAgentStateHooks() (bool, error) {
+	path, err := claudeSettingsPath()
+	if err != nil {
+		return false, err
+	}
+	settings, err := loadClaudeSettings(path)
+	if err != nil {
+		return false, err
+	}
+
+	hooks, _ := settings["hooks"].(map[string]interface{})
+	for _, groups := range hooks {
+		list, _ := groups.([]interface{})
+		for _, group := range list {
+			if isJeanHookGroup(group) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// InstallAgentStateHooks adds hooks to ~/.claude/settings.json that report agent state to jean
+// jeanPath is the jean executable the hooks call; existing jean hooks are replaced
+func (m *Manager) InstallAgentStateHooks(jeanPath string) error {
+	if err := m.RemoveAgentStateHooks(); err != nil {
+		return err
+	}
+
+	path, err := claudeSettingsPath()
+	if err != nil {
+		return err
+	}
+	settings, err := loadClaudeSettings(path)
+	if err != nil {
+		return err
+	}
+
+	hooks, _ := settings["hooks"].(map[string]interface{})
+	if hooks == nil {
+		hooks = map[string]interface{}{}
+	}
+
+	command := fmt.Sprintf("%s %s", shellQuote(jeanPath), agentHookCommand)
+	for _, event := range claudeHookEvents {
+		groups, _ := hooks[event].([]interface{})
+		hooks[event] = append(groups, map[string]interface{}{
+			"hooks": []interface{}{
+				map[string]interface{}{"type": "command", "command": command},
+			},
+		})
+	}
+	settings["hooks"] = hooks
+
+	return saveClaudeSettings(path, settings)
+}
+
+// RemoveAgentStateHooks removes jean's hooks from ~/.claude/settings.json, keeping all other hooks
+func (m *Manager) RemoveAgentStateHooks() error {
+	path, err := claudeSettingsPath()
+	if err != nil {
+		return err
+	}
+	settings, err := loadClaudeSettings(path)
+	if err != nil {
+		return err
+	}
+
+	hooks, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	changed := false
+	for event, groups := range hooks {
+		list, _ := groups.([]interface{})
+		var kept []interface{}
+		for _, group := range list {
+			if isJeanHookGroup(group) {
+				changed = true
+				continue
+			}
+			kept = append(kept, group)
+		}
+		if len(kept) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = kept
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if len(hooks) == 0 {
+		delete(settings, "hooks")
+	}
+
+	return saveClaudeSettings(path, settings)
+}
@@ -0,0 +1,138 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAgentStateFromHook tests mapping Claude Code hook events to agent states
+func TestAgentStateFromHook(t *testing.T) {
+	tests := []struct {
+		input   string
+		state   string
+		message string
+	}{
+		{`{"hook_event_name":"UserPromptSubmit","cwd":"/w","prompt":"fix the tests"}`, AgentWorking, "fix the tests"},
+		{`{"hook_event_name":"PreToolUse","cwd":"/w","tool_name":"Bash"}`, AgentWorking, "Running Bash"},
+		{`{"hook_event_name":"Notification","cwd":"/w","message":"Claude needs your permission to use Bash"}`, AgentWaiting, "Claude needs your permission to use Bash"},
+		{`{"hook_event_name":"Stop","cwd":"/w"}`, AgentIdle, "Finished"},
+		{`{"hook_event_name":"StopFailure","cwd":"/w","error":"rate_limit"}`, AgentErrored, "rate_limit"},
+		{`{"hook_event_name":"StopFailure","cwd":"/w"}`, AgentErrored, "Stopped with an error"},
+		{`{"hook_event_name":"SessionEnd","cwd":"/w"}`, AgentIdle, "Session ended"},
+	}
+
+	for _, tt := range tests {
+		state, cwd, err := AgentStateFromHook([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tt.input, err)
+		}
+		if state.State != tt.state || state.Message != tt.message || cwd != "/w" {
+			t.Errorf("For %s got state %q message %q cwd %q", tt.input, state.State, state.Message, cwd)
+		}
+	}
+
+	if _, _, err := AgentStateFromHook([]byte(`{"hook_event_name":"PostToolUse"}`)); err == nil {
+		t.Error("Expected unsupported event to fail")
+	}
+
+	long, _, _ := AgentStateFromHook([]byte(`{"hook_event_name":"UserPromptSubmit","prompt":"` + strings.Repeat("é", 300) + `"}`))
+	if len([]rune(long.Message)) != 201 {
+		t.Errorf("Expected long prompt to be truncated, got %d runes", len([]rune(long.Message)))
+	}
+}
+
+// TestWriteReadAgentState tests the state file round trip inside a worktree's git directory
+func TestWriteReadAgentState(t *testing.T) {
+	dir := t.TempDir()
+	if output, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+		t.Skipf("git not available: %s", output)
+	}
+
+	state, err := ReadAgentState(dir)
+	if err != nil || state != nil {
+		t.Fatalf("Expected no state before the first write, got %v, %v", state, err)
+	}
+
+	if err := WriteAgentState(dir, AgentState{State: "busy"}); err == nil {
+		t.Error("Expected invalid state to be rejected")
+	}
+
+	if err := WriteAgentState(dir, AgentState{State: AgentWaiting, Message: "Pick an option"}); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	state, err = ReadAgentState(dir)
+	if err != nil || state == nil {
+		t.Fatalf("Failed to read state: %v", err)
+	}
+	if state.State != AgentWaiting || state.Message != "Pick an option" || state.UpdatedAt.IsZero() {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+
+	// The state file must not show up as a change in the worktree
+	output, _ := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if strings.TrimSpace(string(output)) != "" {
+		t.Errorf("Expected clean worktree, got %q", output)
+	}
+}
+
+// TestInstallRemoveAgentStateHooks tests editing ~/.claude/settings.json without losing user settings
+func TestInstallRemoveAgentStateHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	userSettings := `{"model":"opus","hooks":{"Stop":[{"hooks":[{"type":"command","command":"notify-send done"}]}]}}`
+	if err := os.WriteFile(settingsPath, []byte(userSettings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager()
+	if installed, _ := m.HasAgentStateHooks(); installed {
+		t.Fatal("Expected hooks not to be installed")
+	}
+
+	// Installing twice must not duplicate the hooks
+	for i := 0; i < 2; i++ {
+		if err := m.InstallAgentStateHooks("/usr/local/bin/jean"); err != nil {
+			t.Fatalf("Failed to install hooks: %v", err)
+		}
+	}
+	if installed, _ := m.HasAgentStateHooks(); !installed {
+		t.Fatal("Expected hooks to be installed")
+	}
+
+	settings, err := loadClaudeSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks := settings["hooks"].(map[string]interface{})
+	if stop := hooks["Stop"].([]interface{}); len(stop) != 2 {
+		t.Errorf("Expected user and jean Stop hooks, got %d", len(stop))
+	}
+	if notification := hooks["Notification"].([]interface{}); len(notification) != 1 {
+		t.Errorf("Expected one Notification hook, got %d", len(notification))
+	}
+
+	if err := m.RemoveAgentStateHooks(); err != nil {
+		t.Fatalf("Failed to remove hooks: %v", err)
+	}
+	if installed, _ := m.HasAgentStateHooks(); installed {
+		t.Error("Expected hooks to be removed")
+	}
+
+	settings, _ = loadClaudeSettings(settingsPath)
+	if settings["model"] != "opus" {
+		t.Error("Expected unrelated settings to be kept")
+	}
+	hooks = settings["hooks"].(map[string]interface{})
+	if len(hooks) != 1 || len(hooks["Stop"].([]interface{})) != 1 {
+		t.Errorf("Expected only the user's Stop hook to remain, got %v", hooks)
+	}
+}
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestAgentBuildCommand tests building agent commands from templates
+func TestAgentBuildCommand(t *testing.T) {
+	claude := DefaultAgent()
+	if got := claude.BuildCommand("/repo/.workspaces/x", "x", "repo", false); got != "claude --add-dir /repo/.workspaces/x --permission-mode plan" {
+		t.Errorf("Unexpected default command: %q", got)
+	}
+
+	expected := "claude --add-dir /w --continue --permission-mode plan"
+	if got := claude.BuildCommand("/w", "x", "repo", true); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	codex := Agent{
+		Name:    "codex",
+		Command: "codex",
+		Args:    "--cd {worktree} --title {repo}/{branch}",
+	}
+	expected = "codex --cd '/my repo/w' --title app/feature/login"
+	if got := codex.BuildCommand("/my repo/w", "feature/login", "app", true); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if codex.WindowName() != "codex" {
+		t.Errorf("Expected window name codex, got %q", codex.WindowName())
+	}
+
+	// tmux target separators never reach the window name
+	if name := (Agent{Name: "my:codex.v2"}).WindowName(); name != "my-codex-v2" {
+		t.Errorf("Expected window name my-codex-v2, got %q", name)
+	}
+	if name := (Agent{Command: "/usr/bin/python3.12 agent.py"}).WindowName(); name != "python3-12" {
+		t.Errorf("Expected window name python3-12, got %q", name)
+	}
+}
+
+// TestInitialPrompt tests starting a fresh agent with the prompt stored in its worktree
+func TestInitialPrompt(t *testing.T) {
+	claude := DefaultAgent()
+	expected := `claude --add-dir /w --permission-mode plan "$(cat '/w/.git/jean initial.md')"`
+	if got := claude.BuildCommandWithPrompt("/w", "x", "repo", "/w/.git/jean initial.md"); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	codex := Agent{Command: "codex", Args: "--cd {worktree}"}
+	if got := codex.BuildCommandWithPrompt("/w", "x", "repo", "/p"); got != "codex --cd /w" {
+		t.Errorf("Expected agent without prompt argument to start without prompt, got %q", got)
+	}
+
+	dir := t.TempDir()
+	if output, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+		t.Skipf("git not available: %s", output)
+	}
+	if initialPromptPath(dir) != "" {
+		t.Error("Expected no initial prompt before writing one")
+	}
+	if err := WriteInitialPrompt(dir, "Work on GitHub issue #12: Crash\n\nIt crashes"); err != nil {
+		t.Fatalf("Failed to write initial prompt: %v", err)
+	}
+	path := initialPromptPath(dir)
+	if path == "" || !strings.Contains(path, ".git") {
+		t.Errorf("Expected initial prompt in the git directory, got %q", path)
+	}
+
+	output, _ := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if strings.TrimSpace(string(output)) != "" {
+		t.Errorf("Expected clean worktree, got %q", output)
+	}
+}
+
+// TestMatchesWaitingPattern tests agent specific waiting detection
+func TestMatchesWaitingPattern(t *testing.T) {
+	d := NewAgentStatusDetector(Agent{WaitingPatterns: []string{"> Ask anything"}})
+
+	if !d.matchesWaitingPattern("working...\ndone\n\n> Ask anything\n\n") {
+		t.Error("Expected prompt at the bottom of the screen to match")
+	}
+
+	scrolled := "> Ask anything\n1\n2\n3\n4\n5\n6\n"
+	if d.matchesWaitingPattern(scrolled) {
+		t.Error("Expected prompt that scrolled away not to match")
+	}
+}
+
+// TestSendPromptValidation tests that prompts are only sent to running sessions
+func TestSendPromptValidation(t *testing.T) {
+	m := NewManager()
+	if err := m.SendPrompt("jean-test-send", "  \n"); err == nil {
+		t.Error("Expected error for empty prompt")
+	}
+	if err := m.SendPrompt("jean-test-send-missing-session", "fix the tests"); err == nil {
+		t.Error("Expected error for a session that is not running")
+	}
+}
+
+// TestLastLines tests trimming captured pane output for the preview
+func TestLastLines(t *testing.T) {
+	output := "one\n\x1b[32mtwo\x1b[0m\nthree\n\x1b[0m\n   \n\n"
+	got := lastLines(output, 2)
+	if len(got) != 2 || got[0] != "\x1b[32mtwo\x1b[0m" || got[1] != "three" {
+		t.Errorf("Unexpected lines: %q", got)
+	}
+	if got := lastLines(output, 10); len(got) != 3 {
+		t.Errorf("Expected all 3 non-blank lines, got %q", got)
+	}
+	if got := lastLines("\n\n", 5); len(got) != 0 {
+		t.Errorf("Expected no lines for blank output, got %q", got)
+	}
+}
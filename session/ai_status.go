@@ -1,21 +1,36 @@
 package session
 
 import (
-	"bytes"
-	"os/exec"
 	"strings"
 )
 
-// AIStatusDetector detects the status of AI sessions (Claude)
-type AIStatusDetector struct{}
+// AIStatusDetector detects the status of AI sessions (Claude or a configured agent)
+type AIStatusDetector struct {
+	waitingPatterns []string    // Agent specific patterns, empty = built-in Claude detection
+	mux             Multiplexer // Multiplexer hosting the sessions
+	windowName      string      // Window the agent runs in
+}
 
-// NewAIStatusDetector creates a new AI status detector
+// NewAIStatusDetector creates a new AI status detector for Claude sessions in tmux
 func NewAIStatusDetector() *AIStatusDetector {
-	return &AIStatusDetector{}
+	return NewAgentStatusDetector(DefaultAgent())
+}
+
+// NewAgentStatusDetector creates a status detector for the given agent running in tmux
+// Agents without waiting patterns use the built-in Claude detection
+func NewAgentStatusDetector(agent Agent) *AIStatusDetector {
+	return &AIStatusDetector{waitingPatterns: agent.WaitingPatterns, mux: &tmuxMultiplexer{}, windowName: agent.WindowName()}
+}
+
+// StatusDetector returns a status detector for the manager's agent and multiplexer
+func (m *Manager) StatusDetector() *AIStatusDetector {
+	d := NewAgentStatusDetector(m.agent)
+	d.mux = m.mux
+	return d
 }
 
 // DetectAISessionState checks if an AI session (Claude) is waiting for input
-// It does this by examining the tmux session contents for Claude-specific prompts
+// It does this by examining the agent window's contents for Claude-specific prompts
 func (d *AIStatusDetector) DetectAISessionState(sessionName string) bool {
 	if sessionName == "" {
 		return false
@@ -32,40 +47,49 @@ func (d *AIStatusDetector) DetectAISessionState(sessionName string) bool {
 		return false
 	}
 
+	// Configured agents declare their own prompt patterns
+	if len(d.waitingPatterns) > 0 {
+		return d.matchesWaitingPattern(output)
+	}
+
 	// Look for Claude-specific indicators that it's waiting for input
 	return d.isClaudeWaiting(output)
 }
 
-// sessionExists checks if a tmux session exists
+// sessionExists checks if a session exists
 func (d *AIStatusDetector) sessionExists(sessionName string) bool {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-	output, err := cmd.Output()
+	return d.mux.SessionExists(sessionName)
+}
+
+// captureSessionOutput captures the current screen of the session's agent window without colors
+func (d *AIStatusDetector) captureSessionOutput(sessionName string) (string, error) {
+	output, err := d.mux.Capture(sessionName, d.windowName)
 	if err != nil {
-		return false
+		return "", err
 	}
+	return ansiSequence.ReplaceAllString(output, ""), nil
+}
 
-	sessions := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, session := range sessions {
-		if session == sessionName {
-			return true
+// matchesWaitingPattern checks if the last lines of the output contain one of the agent's waiting patterns
+// Only the bottom of the screen is checked so old prompts in the scrollback don't count
+func (d *AIStatusDetector) matchesWaitingPattern(output string) bool {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
 		}
 	}
-
-	return false
-}
-
-// captureSessionOutput captures the current output of a tmux session
-func (d *AIStatusDetector) captureSessionOutput(sessionName string) (string, error) {
-	// Use tmux capture-pane to get the current screen content
-	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p")
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
-		return "", err
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
 	}
 
-	return stdout.String(), nil
+	tail := strings.Join(lines, "\n")
+	for _, pattern := range d.waitingPatterns {
+		if pattern != "" && strings.Contains(tail, pattern) {
+			return true
+		}
+	}
+	return false
 }
 
 // isClaudeWaiting checks if the output contains indicators that Claude is waiting for input
@@ -0,0 +1,264 @@
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Layout describes extra tmux windows and panes to build when a session is created
+// Windows named "terminal" or "claude" add panes to the built-in terminal and agent windows,
+// any other name creates a new window after them
+type Layout struct {
+	Windows []LayoutWindow
+}
+
+// LayoutWindow is a named tmux window made of one or more panes
+type LayoutWindow struct {
+	Name        string       // Window name (e.g. "dev")
+	Arrangement string       // Optional tmux layout applied after splitting (e.g. "tiled", "main-vertical")
+	Panes       []LayoutPane // Panes in creation order, the first pane is the window itself
+}
+
+// LayoutPane is a single pane of a layout window
+type LayoutPane struct {
+	Command string // Command typed into the pane (e.g. "npm run dev"), empty = plain shell
+	Split   string // "horizontal" (side by side) or "vertical" (stacked), defaults to vertical
+	Size    string // Optional pane size as percentage or lines (e.g. "30%", "10")
+}
+
+// firstLayoutWindowIndex is the index of the first window created from a layout
+// Windows 1 (terminal) and 2 (claude) are reserved so the shell wrapper can always find them
+const firstLayoutWindowIndex = 3
+
+// IsEmpty reports whether the layout defines no windows
+func (l *Layout) IsEmpty() bool {
+	return l == nil || len(l.Windows) == 0
+}
+
+// findWindow returns the layout window with the given name
+func (l *Layout) findWindow(name string) *LayoutWindow {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Windows {
+		if l.Windows[i].Name == name {
+			return &l.Windows[i]
+		}
+	}
+	return nil
+}
+
+// windowIndex returns the tmux window index a layout window is created at
+func (l *Layout) windowIndex(name string) string {
+	switch name {
+	case "terminal":
+		return "1"
+	case "claude":
+		return "2"
+	}
+
+	index := firstLayoutWindowIndex
+	for _, w := range l.Windows {
+		if w.Name == "terminal" || w.Name == "claude" {
+			continue
+		}
+		if w.Name == name {
+			break
+		}
+		index++
+	}
+	return strconv.Itoa(index)
+}
+
+// splitArgs builds the tmux split-window arguments for a pane
+func splitArgs(target, path string, pane LayoutPane) []string {
+	args := []string{"split-window", "-t", target, "-c", path}
+	if pane.Split == "horizontal" {
+		args = append(args, "-h")
+	} else {
+		args = append(args, "-v")
+	}
+	if pane.Size != "" {
+		args = append(args, "-l", pane.Size)
+	}
+	// Print the new pane id so commands can be sent to it
+	return append(args, "-P", "-F", "#{pane_id}")
+}
+
+// EnsureSession creates a detached session with the terminal window, the claude window
+// (if autoStartClaude is true) and the windows of the layout
+// An existing session only gets its missing agent window (if autoStartClaude is true)
+func (m *Manager) EnsureSession(sessionName, path string, autoStartClaude, isInitialized bool, layout *Layout) error {
+	if !m.SessionExists(sessionName) {
+		return m.mux.Create(sessionName, path, m.sessionWindows(path, autoStartClaude, isInitialized, layout))
+	}
+
+	if autoStartClaude && !m.mux.HasWindow(sessionName, m.agent.WindowName()) {
+		return m.mux.AddWindow(sessionName, path, m.agentWindow(path, isInitialized, layout))
+	}
+	return nil
+}
+
+// sessionWindows lists the windows of a new session: terminal, agent (if autoStartClaude is true)
+// and the windows of the layout
+// Layout windows named "terminal" or "claude" add panes next to the built-in window's own pane
+func (m *Manager) sessionWindows(path string, autoStartClaude, isInitialized bool, layout *Layout) []Window {
+	terminal := Window{Name: "terminal", Index: 1, Panes: []LayoutPane{{}}}
+	if w := layout.findWindow("terminal"); w != nil {
+		terminal.Panes = append(terminal.Panes, w.Panes...)
+		terminal.Arrangement = w.Arrangement
+	}
+	windows := []Window{terminal}
+
+	if autoStartClaude {
+		windows = append(windows, m.agentWindow(path, isInitialized, layout))
+	} else if w := layout.findWindow("claude"); w != nil && len(w.Panes) > 0 {
+		// Without the agent the layout's claude window is a plain window
+		windows = append(windows, Window{Name: m.agent.WindowName(), Index: 2, Panes: w.Panes, Arrangement: w.Arrangement})
+	}
+
+	if layout != nil {
+		for _, w := range layout.Windows {
+			if w.Name == "" || w.Name == "terminal" || w.Name == "claude" {
+				continue
+			}
+			index, _ := strconv.Atoi(layout.windowIndex(w.Name))
+			window := Window{Name: w.Name, Index: index, Panes: w.Panes, Arrangement: w.Arrangement}
+			if len(window.Panes) == 0 {
+				window.Panes = []LayoutPane{{}}
+			}
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// agentWindow returns window 2 running the agent, or a shell if the agent is not installed
+func (m *Manager) agentWindow(path string, isInitialized bool, layout *Layout) Window {
+	window := Window{
+		Name:    m.agent.WindowName(),
+		Index:   2,
+		Program: m.buildAgentCommand(path, isInitialized),
+		Panes:   []LayoutPane{{}},
+	}
+	if w := layout.findWindow("claude"); w != nil {
+		window.Panes = append(window.Panes, w.Panes...)
+		window.Arrangement = w.Arrangement
+	}
+	return window
+}
+
+// ApplyLayout creates the windows and panes of a layout in an existing session
+// Windows that already exist only get their missing panes (tmux only), so it is safe to call more than once
+func (m *Manager) ApplyLayout(sessionName, path string, layout *Layout) error {
+	if layout.IsEmpty() {
+		return nil
+	}
+
+	for _, window := range layout.Windows {
+		if err := m.applyLayoutWindow(sessionName, path, layout, window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLayoutWindow materialises a single layout window
+func (m *Manager) applyLayoutWindow(sessionName, path string, layout *Layout, window LayoutWindow) error {
+	if window.Name == "" {
+		return fmt.Errorf("layout window without a name")
+	}
+
+	// The "claude" layout window is the agent window, whatever the agent is called
+	name := window.Name
+	if name == "claude" {
+		name = m.agent.WindowName()
+	}
+
+	// Other multiplexers create a window with all its panes at once, panes can't be added later
+	if !m.isTmux() {
+		if m.mux.HasWindow(sessionName, name) {
+			return nil
+		}
+		index, _ := strconv.Atoi(layout.windowIndex(window.Name))
+		return m.mux.AddWindow(sessionName, path, Window{Name: name, Index: index, Panes: window.Panes, Arrangement: window.Arrangement})
+	}
+
+	// Address the window by exact name once it exists, indexes depend on the user's base-index
+	target := sessionName + ":=" + name
+	panes := window.Panes
+
+	existingPanes, exists := windowPaneCount(sessionName, name)
+	if !exists {
+		index := sessionName + ":" + layout.windowIndex(window.Name)
+		cmd := exec.Command("tmux", "new-window", "-d", "-t", index, "-c", path, "-n", name, "-P", "-F", "#{pane_id}")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to create window %s: %s", window.Name, string(output))
+		}
+		if len(panes) > 0 {
+			sendCommand(strings.TrimSpace(string(output)), panes[0].Command)
+			panes = panes[1:]
+		}
+	} else {
+		// Built-in windows keep their own first pane; layout panes are added next to it.
+		// Panes that were already created on a previous run are skipped
+		if window.Name != "terminal" && window.Name != "claude" && len(panes) > 0 {
+			panes = panes[1:]
+		}
+		skip := min(existingPanes-1, len(panes))
+		panes = panes[skip:]
+	}
+
+	return splitPanes(target, path, window.Name, panes, window.Arrangement)
+}
+
+// splitPanes splits the given panes off a tmux window, arranges them and focuses the first pane
+func splitPanes(target, path, windowName string, panes []LayoutPane, arrangement string) error {
+	for _, pane := range panes {
+		cmd := exec.Command("tmux", splitArgs(target, path, pane)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to split window %s: %s", windowName, string(output))
+		}
+		sendCommand(strings.TrimSpace(string(output)), pane.Command)
+	}
+
+	if arrangement != "" {
+		_ = exec.Command("tmux", "select-layout", "-t", target, arrangement).Run()
+	}
+
+	// Focus the first pane so attaching lands on the main pane
+	_ = exec.Command("tmux", "select-pane", "-t", target+".{top-left}").Run()
+	return nil
+}
+
+// sendCommand types a command into a tmux pane
+// The command runs inside the pane's shell so the pane stays open when it exits
+func sendCommand(paneID, command string) {
+	if paneID == "" || command == "" {
+		return
+	}
+	_ = exec.Command("tmux", "send-keys", "-t", paneID, command, "Enter").Run()
+}
+
+// windowPaneCount returns the number of panes of a named tmux window and whether the window exists
+func windowPaneCount(sessionName, windowName string) (int, bool) {
+	cmd := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_name}:#{window_panes}")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, false
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		sep := strings.LastIndex(line, ":")
+		if sep == -1 || line[:sep] != windowName {
+			continue
+		}
+		count, _ := strconv.Atoi(line[sep+1:])
+		return count, true
+	}
+	return 0, false
+}
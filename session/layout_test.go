@@ -0,0 +1,82 @@
+package session
+
+import (
+	"testing"
+)
+
+// TestLayoutWindowIndex tests that built-in windows keep their index and layout windows follow them in order
+func TestLayoutWindowIndex(t *testing.T) {
+	layout := &Layout{Windows: []LayoutWindow{
+		{Name: "dev"},
+		{Name: "claude"},
+		{Name: "logs"},
+		{Name: "terminal"},
+		{Name: "db"},
+	}}
+
+	tests := map[string]string{
+		"terminal": "1",
+		"claude":   "2",
+		"dev":      "3",
+		"logs":     "4",
+		"db":       "5",
+	}
+	for name, expected := range tests {
+		if index := layout.windowIndex(name); index != expected {
+			t.Errorf("windowIndex(%q) = %s, expected %s", name, index, expected)
+		}
+	}
+}
+
+// TestApplyLayout_SkipsExistingWindows tests that other multiplexers only get the missing layout windows
+func TestApplyLayout_SkipsExistingWindows(t *testing.T) {
+	mux := &fakeMultiplexer{sessions: map[string][]Window{
+		"jean-app-a": {{Name: "terminal", Index: 1}, {Name: "dev", Index: 3}},
+	}}
+	m := NewManager()
+	m.SetMultiplexer(mux)
+	layout := &Layout{Windows: []LayoutWindow{
+		{Name: "dev", Panes: []LayoutPane{{Command: "echo dev"}}},
+		{Name: "logs", Panes: []LayoutPane{{Command: "tail -f log"}, {Split: "horizontal"}}},
+	}}
+
+	if err := m.ApplyLayout("jean-app-a", "/w", layout); err != nil {
+		t.Fatalf("ApplyLayout failed: %v", err)
+	}
+	windows := mux.sessions["jean-app-a"]
+	if len(windows) != 3 {
+		t.Fatalf("Expected only logs to be added, got %+v", windows)
+	}
+	if logs := windows[2]; logs.Name != "logs" || logs.Index != 4 || len(logs.Panes) != 2 {
+		t.Errorf("Unexpected logs window %+v", logs)
+	}
+}
+
+// TestApplyLayout_SkipsExistingPanes tests that applying a layout twice in tmux doesn't duplicate panes
+func TestApplyLayout_SkipsExistingPanes(t *testing.T) {
+	isolatedTmux(t)
+	worktree := t.TempDir()
+
+	m := NewManager()
+	m.SetAgent(Agent{Name: "codex", Command: "jean-test-missing-agent"})
+	layout := &Layout{Windows: []LayoutWindow{
+		{Name: "dev", Panes: []LayoutPane{{Command: "echo dev"}, {Split: "horizontal"}, {Split: "vertical"}}},
+		{Name: "terminal", Panes: []LayoutPane{{Split: "horizontal"}}},
+	}}
+
+	if err := m.EnsureSession("jean-app-layout", worktree, true, false, layout); err != nil {
+		t.Fatalf("EnsureSession failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := m.ApplyLayout("jean-app-layout", worktree, layout); err != nil {
+			t.Fatalf("ApplyLayout failed: %v", err)
+		}
+	}
+
+	if panes, _ := windowPaneCount("jean-app-layout", "dev"); panes != 3 {
+		t.Errorf("Expected 3 panes in dev, got %d", panes)
+	}
+	if panes, _ := windowPaneCount("jean-app-layout", "terminal"); panes != 2 {
+		t.Errorf("Expected 2 panes in terminal, got %d", panes)
+	}
+}
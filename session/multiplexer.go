@@ -0,0 +1,66 @@
+package session
+
+// Supported terminal multiplexers
+const (
+	MultiplexerTmux   = "tmux"
+	MultiplexerZellij = "zellij"
+)
+
+// Multiplexer is a terminal multiplexer hosting jean sessions
+// Windows are tmux windows or Zellij tabs, addressed by name
+type Multiplexer interface {
+	// Name returns the multiplexer name (MultiplexerTmux or MultiplexerZellij)
+	Name() string
+	// IsAvailable reports whether the multiplexer is installed
+	IsAvailable() bool
+	// SessionExists reports whether a session is running
+	SessionExists(sessionName string) bool
+	// HasWindow reports whether a running session has a window with the given name
+	HasWindow(sessionName, windowName string) bool
+	// Create starts a detached session with the given windows, the first window is focused
+	Create(sessionName, path string, windows []Window) error
+	// AddWindow adds a window to a running session without focusing it
+	AddWindow(sessionName, path string, window Window) error
+	// Attach attaches the terminal to a session, focusing windowName first ("" = keep the focused window)
+	Attach(sessionName, windowName string) error
+	// List returns all running sessions
+	List() ([]Session, error)
+	// Windows returns the windows of a running session in order
+	Windows(sessionName string) ([]WindowInfo, error)
+	// Kill terminates a session and all its windows
+	Kill(sessionName string) error
+	// KillWindow closes a window and the programs running in it
+	KillWindow(sessionName, windowName string) error
+	// Rename renames a running session
+	Rename(oldName, newName string) error
+	// Capture returns the screen of a window with its ANSI colors
+	Capture(sessionName, windowName string) (string, error)
+	// SendText pastes text into a window and submits it with Enter
+	SendText(sessionName, windowName, text string) error
+}
+
+// Window describes a window to create in a session
+type Window struct {
+	Name        string
+	Index       int          // tmux window index (1 = terminal, 2 = agent, 3+ = layout), 0 = next free index
+	Program     string       // Command run instead of a shell in the first pane, the window closes when it exits
+	Panes       []LayoutPane // The first entry is the window's own pane, the others are split off in order
+	Arrangement string       // Optional tmux layout applied after splitting (ignored by Zellij)
+}
+
+// WindowInfo describes a window of a running session
+type WindowInfo struct {
+	Index   int
+	Name    string
+	Command string // Program running in the window's active pane, "" if unknown (Zellij)
+	PID     int    // PID of the active pane's process (usually the shell), 0 if unknown (Zellij)
+	Active  bool   // Whether the window is the session's current window
+}
+
+// NewMultiplexer returns the multiplexer with the given name, tmux for unknown names
+func NewMultiplexer(name string) Multiplexer {
+	if name == MultiplexerZellij {
+		return &zellijMultiplexer{}
+	}
+	return &tmuxMultiplexer{}
+}
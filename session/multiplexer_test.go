@@ -0,0 +1,289 @@
+package session
+
+import (
+	"testing"
+)
+
+// fakeMultiplexer records created sessions and windows instead of running a multiplexer
+type fakeMultiplexer struct {
+	sessions map[string][]Window
+	listed   []Session
+	screens  map[string]string // Captured screen by "session:window"
+	attached string            // "session:window" of the last attach
+}
+
+func (f *fakeMultiplexer) Name() string      { return "fake" }
+func (f *fakeMultiplexer) IsAvailable() bool { return true }
+func (f *fakeMultiplexer) SessionExists(sessionName string) bool {
+	_, ok := f.sessions[sessionName]
+	return ok
+}
+func (f *fakeMultiplexer) HasWindow(sessionName, windowName string) bool {
+	for _, w := range f.sessions[sessionName] {
+		if w.Name == windowName {
+			return true
+		}
+	}
+	return false
+}
+func (f *fakeMultiplexer) Create(sessionName, path string, windows []Window) error {
+	f.sessions[sessionName] = windows
+	return nil
+}
+func (f *fakeMultiplexer) AddWindow(sessionName, path string, window Window) error {
+	f.sessions[sessionName] = append(f.sessions[sessionName], window)
+	return nil
+}
+func (f *fakeMultiplexer) Attach(sessionName, windowName string) error {
+	f.attached = sessionName + ":" + windowName
+	return nil
+}
+func (f *fakeMultiplexer) List() ([]Session, error) { return f.listed, nil }
+func (f *fakeMultiplexer) Kill(sessionName string) error {
+	delete(f.sessions, sessionName)
+	return nil
+}
+func (f *fakeMultiplexer) KillWindow(sessionName, windowName string) error {
+	windows := f.sessions[sessionName][:0]
+	for _, w := range f.sessions[sessionName] {
+		if w.Name != windowName {
+			windows = append(windows, w)
+		}
+	}
+	f.sessions[sessionName] = windows
+	return nil
+}
+func (f *fakeMultiplexer) Windows(sessionName string) ([]WindowInfo, error) {
+	var windows []WindowInfo
+	for _, w := range f.sessions[sessionName] {
+		windows = append(windows, WindowInfo{Index: w.Index, Name: w.Name})
+	}
+	return windows, nil
+}
+func (f *fakeMultiplexer) Rename(oldName, newName string) error { return nil }
+func (f *fakeMultiplexer) Capture(sessionName, windowName string) (string, error) {
+	return f.screens[sessionName+":"+windowName], nil
+}
+func (f *fakeMultiplexer) SendText(sessionName, windowName, text string) error { return nil }
+
+// TestEnsureSession_BuildsWindowsThroughMultiplexer tests the windows of new sessions and
+// adding the agent window to an existing session
+func TestEnsureSession_BuildsWindowsThroughMultiplexer(t *testing.T) {
+	mux := &fakeMultiplexer{sessions: map[string][]Window{}}
+	m := NewManager()
+	m.SetAgent(Agent{Name: "codex", Command: "jean-test-missing-agent"})
+	m.SetMultiplexer(mux)
+
+	layout := &Layout{Windows: []LayoutWindow{
+		{Name: "terminal", Panes: []LayoutPane{{Command: "htop", Split: "horizontal"}}},
+		{Name: "dev", Panes: []LayoutPane{{Command: "npm run dev"}, {Command: "npm test"}}},
+	}}
+	if err := m.EnsureSession("jean-app-a", "/w", false, false, layout); err != nil {
+		t.Fatalf("EnsureSession failed: %v", err)
+	}
+
+	windows := mux.sessions["jean-app-a"]
+	if len(windows) != 2 || windows[0].Name != "terminal" || windows[1].Name != "dev" || windows[1].Index != 3 {
+		t.Fatalf("Unexpected windows: %+v", windows)
+	}
+	if len(windows[0].Panes) != 2 || windows[0].Panes[1].Command != "htop" {
+		t.Errorf("Expected the terminal to keep its own pane plus the layout pane, got %+v", windows[0].Panes)
+	}
+	if len(windows[1].Panes) != 2 || windows[1].Panes[0].Command != "npm run dev" {
+		t.Errorf("Expected layout panes for dev, got %+v", windows[1].Panes)
+	}
+
+	// Opening the agent later adds its window to the running session
+	if err := m.EnsureSession("jean-app-a", "/w", true, false, layout); err != nil {
+		t.Fatalf("EnsureSession failed: %v", err)
+	}
+	windows = mux.sessions["jean-app-a"]
+	if len(windows) != 3 || windows[2].Name != "codex" || windows[2].Index != 2 {
+		t.Errorf("Expected the agent window to be added, got %+v", windows)
+	}
+}
+
+// TestAttachToWindow_ThroughMultiplexer tests that attaching creates missing windows, including
+// layout windows with their panes, through the multiplexer
+func TestAttachToWindow_ThroughMultiplexer(t *testing.T) {
+	mux := &fakeMultiplexer{sessions: map[string][]Window{"jean-app-a": {{Name: "terminal", Index: 1}}}}
+	m := NewManager()
+	m.SetMultiplexer(mux)
+	layout := &Layout{Windows: []LayoutWindow{
+		{Name: "dev", Panes: []LayoutPane{{Command: "npm run dev"}, {Command: "npm test"}}},
+	}}
+
+	if err := m.AttachToWindow("jean-app-a", "/w", false, "dev", layout); err != nil {
+		t.Fatalf("AttachToWindow failed: %v", err)
+	}
+	windows := mux.sessions["jean-app-a"]
+	if len(windows) != 2 || windows[1].Name != "dev" || windows[1].Index != 3 || len(windows[1].Panes) != 2 {
+		t.Fatalf("Expected the dev window with its panes, got %+v", windows)
+	}
+	if mux.attached != "jean-app-a:dev" {
+		t.Errorf("Expected to attach to dev, got %q", mux.attached)
+	}
+
+	// Existing windows are attached to as they are
+	if err := m.AttachToWindow("jean-app-a", "/w", false, "dev", layout); err != nil || len(mux.sessions["jean-app-a"]) != 2 {
+		t.Errorf("Expected dev not to be created twice, got %+v (%v)", mux.sessions["jean-app-a"], err)
+	}
+	if err := m.NewWindowAndAttach("jean-app-a", "/w"); err == nil {
+		t.Error("Expected unnamed windows to be refused outside tmux")
+	}
+}
+
+// TestStatusDetector_CapturesAgentWindow tests detecting a waiting agent from the screen of its
+// window as captured by the multiplexer
+func TestStatusDetector_CapturesAgentWindow(t *testing.T) {
+	mux := &fakeMultiplexer{
+		sessions: map[string][]Window{"jean-app-a": {{Name: "terminal"}, {Name: "opencode"}}},
+		screens:  map[string]string{"jean-app-a:opencode": "working...\n\x1b[1m> Ask\x1b[0m anything\n"},
+	}
+	m := NewManager()
+	m.SetAgent(Agent{Name: "opencode", Command: "opencode", WaitingPatterns: []string{"> Ask anything"}})
+	m.SetMultiplexer(mux)
+
+	if !m.StatusDetector().DetectAISessionState("jean-app-a") {
+		t.Error("Expected the agent to be waiting")
+	}
+	if m.StatusDetector().DetectAISessionState("jean-app-missing") {
+		t.Error("Expected no state for a missing session")
+	}
+}
+
+// TestList_FiltersByRepository tests filtering sessions by path, or by name when the path is unknown
+func TestList_FiltersByRepository(t *testing.T) {
+	m := NewManager()
+	m.SetMultiplexer(&fakeMultiplexer{listed: []Session{
+		{Name: "jean-app-main", Path: "/code/app"},
+		{Name: "jean-app-moved", Path: "/elsewhere/app"},
+		{Name: "jean-app-login"},   // Zellij, no path
+		{Name: "jean-other-login"}, // Zellij, other repository
+		{Name: "notes", Path: "/code/app"},
+		{Name: "jean-app-v2-main", Path: "/code/app-v2"}, // Sibling directory sharing the prefix
+	}})
+
+	sessions, err := m.List("/code/app")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, sess := range sessions {
+		names = append(names, sess.Name)
+	}
+	if len(names) != 2 || names[0] != "jean-app-main" || names[1] != "jean-app-login" {
+		t.Errorf("Expected jean-app-main and jean-app-login, got %v", names)
+	}
+
+	if all, _ := m.List(""); len(all) != 5 {
+		t.Errorf("Expected all 5 jean sessions without a repository, got %d", len(all))
+	}
+}
+
+// TestKillWorktreeSessions tests killing a worktree's session together with its terminal session
+func TestKillWorktreeSessions(t *testing.T) {
+	mux := &fakeMultiplexer{sessions: map[string][]Window{
+		"jean-app-a":          {{Name: "terminal"}},
+		"jean-app-a-terminal": {{Name: "terminal"}},
+		"jean-app-b":          {{Name: "terminal"}},
+	}}
+	m := NewManager()
+	m.SetMultiplexer(mux)
+
+	if err := m.KillWorktreeSessions("jean-app-a"); err != nil {
+		t.Fatalf("KillWorktreeSessions failed: %v", err)
+	}
+	if len(mux.sessions) != 1 || !m.SessionExists("jean-app-b") {
+		t.Errorf("Expected only jean-app-b to remain, got %v", mux.sessions)
+	}
+	if err := m.KillWorktreeSessions("jean-app-b"); err != nil {
+		t.Errorf("Expected a missing terminal session to be skipped, got %v", err)
+	}
+}
+
+// TestKillWindowAndRestartAgent tests closing single windows and replacing the agent window
+func TestKillWindowAndRestartAgent(t *testing.T) {
+	mux := &fakeMultiplexer{sessions: map[string][]Window{}}
+	m := NewManager()
+	m.SetAgent(Agent{Name: "codex", Command: "jean-test-missing-agent"})
+	m.SetMultiplexer(mux)
+
+	if err := m.RestartAgent("jean-app-a", "/w", false, nil); err == nil {
+		t.Error("Expected an error restarting the agent of a missing session")
+	}
+	if err := m.EnsureSession("jean-app-a", "/w", false, false, nil); err != nil {
+		t.Fatalf("EnsureSession failed: %v", err)
+	}
+
+	// The agent window is added when missing, and replaced when running
+	for i := 0; i < 2; i++ {
+		if err := m.RestartAgent("jean-app-a", "/w", true, nil); err != nil {
+			t.Fatalf("RestartAgent failed: %v", err)
+		}
+	}
+	windows, _ := m.Windows("jean-app-a")
+	if len(windows) != 2 || windows[1].Name != "codex" {
+		t.Fatalf("Expected terminal and one agent window, got %+v", windows)
+	}
+
+	if err := m.KillWindow("jean-app-a", "codex"); err != nil {
+		t.Fatalf("KillWindow failed: %v", err)
+	}
+	if err := m.KillWindow("jean-app-a", "terminal"); err == nil {
+		t.Error("Expected killing the last window to be refused")
+	}
+}
+
+// TestParseTmuxWindows tests parsing list-windows output
+func TestParseTmuxWindows(t *testing.T) {
+	windows := parseTmuxWindows("1|0|zsh|4242|terminal\n2|1|node|4250|claude\n3|0|npm|4300|dev|server\n")
+	if len(windows) != 3 {
+		t.Fatalf("Expected 3 windows, got %+v", windows)
+	}
+	if windows[1] != (WindowInfo{Index: 2, Name: "claude", Command: "node", PID: 4250, Active: true}) {
+		t.Errorf("Unexpected agent window: %+v", windows[1])
+	}
+	if windows[2].Name != "dev|server" {
+		t.Errorf("Expected window names to keep '|', got %q", windows[2].Name)
+	}
+}
+
+// TestTmuxEnsureSession tests creating a tmux session with layout panes and the agent window
+func TestTmuxEnsureSession(t *testing.T) {
+	isolatedTmux(t)
+	worktree := t.TempDir()
+
+	m := NewManager()
+	m.SetAgent(Agent{Name: "codex", Command: "jean-test-missing-agent"})
+	layout := &Layout{Windows: []LayoutWindow{
+		{Name: "dev", Panes: []LayoutPane{{Command: "echo dev"}, {Split: "horizontal"}}},
+	}}
+
+	if err := m.EnsureSession("jean-app-tmux", worktree, true, false, layout); err != nil {
+		t.Fatalf("EnsureSession failed: %v", err)
+	}
+	// The terminal window gets tmux's base-index, 0 without a user config
+	if windows := tmuxWindows(t, "jean-app-tmux"); windows != "0:terminal 2:codex 3:dev" {
+		t.Errorf("Unexpected windows %q", windows)
+	}
+	if panes, _ := windowPaneCount("jean-app-tmux", "dev"); panes != 2 {
+		t.Errorf("Expected 2 panes in dev, got %d", panes)
+	}
+
+	sessions, err := m.List(worktree)
+	if err != nil || len(sessions) != 1 || sessions[0].Path != worktree {
+		t.Errorf("Expected the session in the worktree, got %+v (%v)", sessions, err)
+	}
+
+	windows, err := m.Windows("jean-app-tmux")
+	if err != nil || len(windows) != 3 || windows[0].PID == 0 || windows[0].Command == "" {
+		t.Errorf("Expected windows with their pane program and PID, got %+v (%v)", windows, err)
+	}
+	if err := m.KillWindow("jean-app-tmux", "dev"); err != nil {
+		t.Fatalf("KillWindow failed: %v", err)
+	}
+	if windows := tmuxWindows(t, "jean-app-tmux"); windows != "0:terminal 2:codex" {
+		t.Errorf("Expected dev to be killed, got %q", windows)
+	}
+}
@@ -0,0 +1,178 @@
+package session
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notification methods
+const (
+	NotifyBell    = "bell"        // Terminal bell (BEL)
+	NotifyOSC9    = "osc9"        // OSC 9 desktop notification (iTerm2, WezTerm, Windows Terminal, kitty)
+	NotifyOSC777  = "osc777"      // OSC 777 desktop notification (rxvt, foot, Ghostty, VTE terminals)
+	NotifyDesktop = "notify-send" // Desktop notification through notify-send (Linux) or osascript (macOS)
+	NotifyCommand = "command"     // User defined shell command
+)
+
+// AgentNotification describes an agent that needs the user's attention
+type AgentNotification struct {
+	Title   string
+	Message string
+	Repo    string
+	Branch  string
+	Key     string // Rate limiting key, usually the worktree path
+}
+
+// Notifier sends notifications when agent sessions need input
+// It is safe for concurrent use and rate limits notifications per key
+type Notifier struct {
+	Methods   []string      // Notification methods used, in order
+	Command   string        // Shell command for NotifyCommand, supports {title}, {message}, {repo} and {branch}
+	RateLimit time.Duration // Minimum time between notifications with the same key
+
+	out      io.Writer // Terminal escape sequences are written here
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewNotifier creates a notifier writing terminal notifications to stderr
+func NewNotifier(methods []string, command string, rateLimit time.Duration) *Notifier {
+	return &Notifier{
+		Methods:   methods,
+		Command:   command,
+		RateLimit: rateLimit,
+		out:       os.Stderr,
+		lastSent:  make(map[string]time.Time),
+	}
+}
+
+// allow reports whether a notification with the given key may be sent now, and records it if so
+func (n *Notifier) allow(key string, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if last, ok := n.lastSent[key]; ok && now.Sub(last) < n.RateLimit {
+		return false
+	}
+	n.lastSent[key] = now
+	return true
+}
+
+// Notify sends a notification through every configured method
+// Returns false if the notification was dropped by rate limiting
+// Errors of individual methods are collected, the other methods are still tried
+func (n *Notifier) Notify(notification AgentNotification) (bool, error) {
+	if !n.allow(notification.Key, time.Now()) {
+		return false, nil
+	}
+	return true, n.sendAll(n.Methods, notification)
+}
+
+// Prepare rate limits a notification like Notify but leaves its delivery to the caller
+// The escape sequences of the terminal methods are returned for the caller to write to the terminal
+// itself, deliver sends the notification through the other methods and may block
+// Returns false if the notification was dropped by rate limiting
+func (n *Notifier) Prepare(notification AgentNotification) (sequence string, deliver func() error, ok bool) {
+	if !n.allow(notification.Key, time.Now()) {
+		return "", nil, false
+	}
+
+	title := sanitizeNotificationText(notification.Title)
+	message := sanitizeNotificationText(notification.Message)
+	var other []string
+	for _, method := range n.Methods {
+		if s := terminalSequence(method, title, message); s != "" {
+			sequence += s
+			continue
+		}
+		other = append(other, method)
+	}
+	return sequence, func() error { return n.sendAll(other, notification) }, true
+}
+
+// sendAll sends a notification through the given methods, collecting their errors
+func (n *Notifier) sendAll(methods []string, notification AgentNotification) error {
+	var errs []string
+	for _, method := range methods {
+		if err := n.send(method, notification); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", method, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notification failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// send delivers a notification through a single method
+func (n *Notifier) send(method string, notification AgentNotification) error {
+	title := sanitizeNotificationText(notification.Title)
+	message := sanitizeNotificationText(notification.Message)
+
+	switch method {
+	case NotifyBell, NotifyOSC9, NotifyOSC777:
+		// A single write, so the sequence isn't interleaved with other output
+		_, err := io.WriteString(n.out, terminalSequence(method, title, message))
+		return err
+	case NotifyDesktop:
+		if runtime.GOOS == "darwin" {
+			script := fmt.Sprintf("display notification %q with title %q", message, title)
+			return exec.Command("osascript", "-e", script).Run()
+		}
+		return exec.Command("notify-send", "--app-name=jean", title, message).Run()
+	case NotifyCommand:
+		if strings.TrimSpace(n.Command) == "" {
+			return fmt.Errorf("no command configured")
+		}
+		replacer := strings.NewReplacer(
+			"{title}", shellQuote(notification.Title),
+			"{message}", shellQuote(notification.Message),
+			"{repo}", shellQuote(notification.Repo),
+			"{branch}", shellQuote(notification.Branch),
+		)
+		output, err := exec.Command("sh", "-c", replacer.Replace(n.Command)).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown notification method")
+	}
+}
+
+// terminalSequence returns the escape sequence of a terminal notification method, "" for other methods
+// Inside tmux the sequence is wrapped in a passthrough so it reaches the outer terminal
+func terminalSequence(method, title, message string) string {
+	var sequence string
+	switch method {
+	case NotifyBell:
+		return "\a"
+	case NotifyOSC9:
+		sequence = fmt.Sprintf("\x1b]9;%s: %s\a", title, message)
+	case NotifyOSC777:
+		sequence = fmt.Sprintf("\x1b]777;notify;%s;%s\a", title, message)
+	default:
+		return ""
+	}
+	if os.Getenv("TMUX") != "" {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return sequence
+}
+
+// sanitizeNotificationText removes control characters and separators that would break escape sequences
+func sanitizeNotificationText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.ReplaceAll(s, ";", ",")
+}
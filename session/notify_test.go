@@ -0,0 +1,111 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNotifierTerminalSequences tests the bell and OSC notification escape sequences
+func TestNotifierTerminalSequences(t *testing.T) {
+	t.Setenv("TMUX", "")
+	var out bytes.Buffer
+	n := NewNotifier([]string{NotifyBell, NotifyOSC9, NotifyOSC777}, "", time.Minute)
+	n.out = &out
+
+	sent, err := n.Notify(AgentNotification{Title: "app/login", Message: "Allow Bash; rm?\n", Key: "/w"})
+	if err != nil || !sent {
+		t.Fatalf("Expected notification to be sent, got %v, %v", sent, err)
+	}
+
+	expected := "\a" + "\x1b]9;app/login: Allow Bash, rm? \a" + "\x1b]777;notify;app/login;Allow Bash, rm? \a"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	// Inside tmux OSC sequences are wrapped in a passthrough
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	out.Reset()
+	if err := n.send(NotifyOSC9, AgentNotification{Title: "t", Message: "m"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "\x1bPtmux;\x1b\x1b]9;t: m\a\x1b\\" {
+		t.Errorf("Unexpected tmux passthrough: %q", out.String())
+	}
+}
+
+// TestNotifierPrepare tests that prepared notifications leave the terminal output to the caller
+func TestNotifierPrepare(t *testing.T) {
+	t.Setenv("TMUX", "")
+	output := filepath.Join(t.TempDir(), "out")
+	var out bytes.Buffer
+	n := NewNotifier([]string{NotifyBell, NotifyCommand, NotifyOSC9}, "printf '%s' {message} > "+output, time.Minute)
+	n.out = &out
+
+	sequence, deliver, ok := n.Prepare(AgentNotification{Title: "app/x", Message: "done", Key: "/w"})
+	if !ok {
+		t.Fatal("Expected notification to be prepared")
+	}
+	if sequence != "\a\x1b]9;app/x: done\a" {
+		t.Errorf("Unexpected terminal sequence %q", sequence)
+	}
+	if err := deliver(); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "done" {
+		t.Errorf("Expected the command to run, got %q", data)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing written to the terminal, got %q", out.String())
+	}
+
+	if _, _, ok := n.Prepare(AgentNotification{Key: "/w"}); ok {
+		t.Error("Expected the second notification to be rate limited")
+	}
+}
+
+// TestNotifierRateLimit tests that notifications are rate limited per key
+func TestNotifierRateLimit(t *testing.T) {
+	var out bytes.Buffer
+	n := NewNotifier([]string{NotifyBell}, "", time.Minute)
+	n.out = &out
+
+	if sent, _ := n.Notify(AgentNotification{Key: "/a"}); !sent {
+		t.Error("Expected first notification to be sent")
+	}
+	if sent, _ := n.Notify(AgentNotification{Key: "/a"}); sent {
+		t.Error("Expected second notification for the same worktree to be rate limited")
+	}
+	if sent, _ := n.Notify(AgentNotification{Key: "/b"}); !sent {
+		t.Error("Expected notification for another worktree to be sent")
+	}
+	if !n.allow("/a", time.Now().Add(2*time.Minute)) {
+		t.Error("Expected notification to be allowed after the rate limit")
+	}
+}
+
+// TestNotifierCommand tests the user command method with quoted placeholders
+func TestNotifierCommand(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out")
+	n := NewNotifier([]string{NotifyCommand}, "printf '%s|%s|%s|%s' {title} {message} {repo} {branch} > "+output, time.Minute)
+
+	if _, err := n.Notify(AgentNotification{Title: "app/x", Message: "it's done", Repo: "app", Branch: "x", Key: "/w"}); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "app/x|it's done|app|x" {
+		t.Errorf("Unexpected command output: %q", data)
+	}
+
+	n = NewNotifier([]string{NotifyCommand, NotifyBell}, "exit 3", time.Minute)
+	n.out = &bytes.Buffer{}
+	if _, err := n.Notify(AgentNotification{Key: "/w"}); err == nil || !strings.Contains(err.Error(), "command") {
+		t.Errorf("Expected command error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procRoot is the proc filesystem read for process statistics (replaced in tests)
+var procRoot = "/proc"
+
+// clockTicks is the kernel's USER_HZ, the unit of CPU times in /proc/<pid>/stat
+const clockTicks = 100
+
+// ProcessStats is the resource usage of a process and all its descendants
+type ProcessStats struct {
+	PID       int
+	Processes int           // Number of processes in the tree
+	CPUTime   time.Duration // User + system CPU time consumed so far
+	MemoryKB  int64         // Resident memory
+}
+
+// procStat is the part of /proc/<pid>/stat jean uses
+type procStat struct {
+	ppid     int
+	cpuTicks uint64
+	rssPages int64
+}
+
+// ProcessTable is a snapshot of the processes in /proc, read once to look up several process trees
+type ProcessTable struct {
+	stats    map[int]procStat
+	children map[int][]int
+}
+
+// ReadProcessTable reads every process in /proc
+// Returns an error where /proc isn't available (macOS)
+func ReadProcessTable() (*ProcessTable, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, fmt.Errorf("process information not available: %w", err)
+	}
+
+	table := &ProcessTable{stats: make(map[int]procStat), children: make(map[int][]int)}
+	for _, entry := range entries {
+		id, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// Processes can exit while we read, skip them
+		stat, err := readProcStat(id)
+		if err != nil {
+			continue
+		}
+		table.stats[id] = stat
+		table.children[stat.ppid] = append(table.children[stat.ppid], id)
+	}
+	return table, nil
+}
+
+// Stats sums the resource usage of a process tree
+// Returns an error if the process wasn't running when the table was read
+func (t *ProcessTable) Stats(pid int) (ProcessStats, error) {
+	if _, ok := t.stats[pid]; !ok {
+		return ProcessStats{}, fmt.Errorf("process %d not found", pid)
+	}
+
+	result := ProcessStats{PID: pid}
+	pageKB := int64(os.Getpagesize() / 1024)
+	queue := []int{pid}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		stat := t.stats[id]
+		result.Processes++
+		result.CPUTime += time.Duration(stat.cpuTicks) * time.Second / clockTicks
+		result.MemoryKB += stat.rssPages * pageKB
+		queue = append(queue, t.children[id]...)
+	}
+	return result, nil
+}
+
+// ReadProcessStats sums the resource usage of a process tree from /proc
+// Returns an error where /proc isn't available (macOS) or the process is gone
+// Reads all of /proc, use ReadProcessTable to look up several processes
+func ReadProcessStats(pid int) (ProcessStats, error) {
+	table, err := ReadProcessTable()
+	if err != nil {
+		return ProcessStats{}, err
+	}
+	return table.Stats(pid)
+}
+
+// readProcStat parses /proc/<pid>/stat
+// The command name is in parentheses and may contain spaces, so fields are counted after the last ')'
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return procStat{}, fmt.Errorf("malformed stat for process %d", pid)
+	}
+	// Fields after the name start at field 3 (state): ppid is 4, utime 14, stime 15, rss 24
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("malformed stat for process %d", pid)
+	}
+
+	ppid, _ := strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rss, _ := strconv.ParseInt(fields[21], 10, 64)
+	return procStat{ppid: ppid, cpuTicks: utime + stime, rssPages: rss}, nil
+}
+
+// CPUPercent returns the CPU usage between two samples of the same process tree (100 = one core)
+func CPUPercent(previous, current ProcessStats, elapsed time.Duration) float64 {
+	if elapsed <= 0 || previous.PID != current.PID || current.CPUTime < previous.CPUTime {
+		return 0
+	}
+	return float64(current.CPUTime-previous.CPUTime) / float64(elapsed) * 100
+}
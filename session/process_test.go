@@ -0,0 +1,89 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeProcStat writes a fake /proc/<pid>/stat with the given parent, CPU ticks and resident pages
+func writeProcStat(t *testing.T, root, pid, name, ppid, utime, stime, rss string) {
+	t.Helper()
+	dir := filepath.Join(root, pid)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+	// pid (comm) state ppid pgrp session tty tpgid flags minflt cminflt majflt cmajflt utime stime
+	// cutime cstime priority nice threads itrealvalue starttime vsize rss
+	stat := pid + " (" + name + ") S " + ppid + " 1 1 0 -1 0 0 0 0 0 " + utime + " " + stime + " 0 0 20 0 1 0 100 0 " + rss + " 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644); err != nil {
+		t.Fatalf("Failed to write stat: %v", err)
+	}
+}
+
+// TestReadProcessStats tests summing the usage of a process tree
+func TestReadProcessStats(t *testing.T) {
+	root := t.TempDir()
+	previous := procRoot
+	procRoot = root
+	t.Cleanup(func() { procRoot = previous })
+
+	writeProcStat(t, root, "100", "zsh", "1", "10", "5", "100")
+	writeProcStat(t, root, "200", "node (agent) worker", "100", "150", "50", "1000") // Name with spaces and parens
+	writeProcStat(t, root, "300", "rg", "200", "40", "0", "10")
+	writeProcStat(t, root, "400", "other", "1", "999", "999", "999")
+	if err := os.MkdirAll(filepath.Join(root, "self"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := ReadProcessStats(100)
+	if err != nil {
+		t.Fatalf("ReadProcessStats failed: %v", err)
+	}
+	if stats.Processes != 3 {
+		t.Errorf("Expected 3 processes in the tree, got %d", stats.Processes)
+	}
+	if stats.CPUTime != 2550*time.Millisecond {
+		t.Errorf("Expected 2.55s of CPU time, got %v", stats.CPUTime)
+	}
+	if want := int64(1110 * os.Getpagesize() / 1024); stats.MemoryKB != want {
+		t.Errorf("Expected %d KB, got %d", want, stats.MemoryKB)
+	}
+
+	if _, err := ReadProcessStats(999); err == nil {
+		t.Error("Expected an error for a missing process")
+	}
+
+	// A table answers for several trees from a single read of /proc
+	table, err := ReadProcessTable()
+	if err != nil {
+		t.Fatalf("ReadProcessTable failed: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "400")); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err := table.Stats(200); err != nil || stats.Processes != 2 {
+		t.Errorf("Expected 2 processes under 200, got %+v (%v)", stats, err)
+	}
+	if stats, err := table.Stats(400); err != nil || stats.CPUTime != 19980*time.Millisecond {
+		t.Errorf("Expected 400 from the snapshot, got %+v (%v)", stats, err)
+	}
+}
+
+// TestCPUPercent tests the CPU usage between two samples
+func TestCPUPercent(t *testing.T) {
+	previous := ProcessStats{PID: 1, CPUTime: time.Second}
+	current := ProcessStats{PID: 1, CPUTime: 1500 * time.Millisecond}
+
+	if got := CPUPercent(previous, current, 2*time.Second); got != 25 {
+		t.Errorf("Expected 25%%, got %v", got)
+	}
+	// A new process with the same PID, or no elapsed time, has no usage yet
+	if got := CPUPercent(current, previous, time.Second); got != 0 {
+		t.Errorf("Expected 0%% when CPU time went backwards, got %v", got)
+	}
+	if got := CPUPercent(previous, current, 0); got != 0 {
+		t.Errorf("Expected 0%% without elapsed time, got %v", got)
+	}
+}
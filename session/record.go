@@ -0,0 +1,261 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SessionRecord is the last known shape of a jean session
+// Records survive tmux server restarts so sessions can be recreated afterwards
+type SessionRecord struct {
+	Name      string         `json:"name"`
+	Path      string         `json:"path"`            // Working directory of the session (the worktree)
+	Windows   []WindowRecord `json:"windows"`         // Windows in index order
+	Agent     string         `json:"agent,omitempty"` // Name of the agent window, "" = no agent was running
+	Server    string         `json:"server"`          // tmux server instance the session was last seen on
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// WindowRecord is a recorded tmux window
+type WindowRecord struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Path  string `json:"path"` // Current directory of the window's active pane
+}
+
+// sessionRecordsFile is the file holding session records, next to jean's config
+const sessionRecordsFile = "sessions.json"
+
+// restorePlaceholderWindow is the temporary first window of a session being restored
+const restorePlaceholderWindow = "jean-restore"
+
+// sessionRecordsPath returns the path of the session records file
+func sessionRecordsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "jean", sessionRecordsFile), nil
+}
+
+// loadSessionRecords reads the recorded sessions, keyed by session name
+func loadSessionRecords() (map[string]*SessionRecord, error) {
+	records := map[string]*SessionRecord{}
+	path, err := sessionRecordsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse session records: %w", err)
+	}
+	return records, nil
+}
+
+// saveSessionRecords writes the recorded sessions
+func saveSessionRecords(records map[string]*SessionRecord) error {
+	path, err := sessionRecordsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// liveSessions lists the running jean sessions with their windows
+// Returns the server instance id (pid and start time), or "" if no tmux server is running
+func (m *Manager) liveSessions() (string, map[string]*SessionRecord) {
+	cmd := exec.Command("tmux", "list-windows", "-a", "-F",
+		"#{pid}-#{start_time}|#{session_name}|#{session_path}|#{window_index}|#{window_name}|#{pane_current_path}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", nil
+	}
+
+	server := ""
+	sessions := map[string]*SessionRecord{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.SplitN(line, "|", 6)
+		if len(parts) < 6 {
+			continue
+		}
+		server = parts[0]
+		if !strings.HasPrefix(parts[1], sessionPrefix) {
+			continue
+		}
+
+		record, ok := sessions[parts[1]]
+		if !ok {
+			record = &SessionRecord{Name: parts[1], Path: parts[2], Server: parts[0]}
+			sessions[parts[1]] = record
+		}
+		index, _ := strconv.Atoi(parts[3])
+		record.Windows = append(record.Windows, WindowRecord{Index: index, Name: parts[4], Path: parts[5]})
+	}
+
+	agentWindow := m.agent.WindowName()
+	for _, record := range sessions {
+		sort.Slice(record.Windows, func(i, j int) bool { return record.Windows[i].Index < record.Windows[j].Index })
+		for _, window := range record.Windows {
+			if window.Name == agentWindow {
+				record.Agent = agentWindow
+			}
+		}
+	}
+	return server, sessions
+}
+
+// RecordSessions saves the shape of the running jean sessions so they can be restored
+// Sessions closed while the same tmux server keeps running are forgotten; sessions that
+// disappeared with their server (reboot, crash) are kept until they are restored
+// Only tmux sessions are recorded, Zellij resurrects its sessions itself
+func (m *Manager) RecordSessions() error {
+	if !m.isTmux() {
+		return nil
+	}
+
+	server, live := m.liveSessions()
+	if server == "" {
+		// No tmux server, nothing changed since the last record
+		return nil
+	}
+
+	records, err := loadSessionRecords()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for name, record := range records {
+		if _, running := live[name]; !running && record.Server == server {
+			delete(records, name)
+			changed = true
+		}
+	}
+	for name, record := range live {
+		if previous, ok := records[name]; ok {
+			record.UpdatedAt = previous.UpdatedAt
+			if reflect.DeepEqual(previous, record) {
+				continue
+			}
+		}
+		record.UpdatedAt = time.Now()
+		records[name] = record
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return saveSessionRecords(records)
+}
+
+// RestorableSessions returns the recorded sessions of a repository that are not running
+// Sessions whose worktree no longer exists are left out
+func (m *Manager) RestorableSessions(repoPath string) ([]SessionRecord, error) {
+	if !m.isTmux() {
+		return nil, nil
+	}
+
+	records, err := loadSessionRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	var restorable []SessionRecord
+	for _, record := range records {
+		if repoPath != "" && !InRepository(record.Path, repoPath) {
+			continue
+		}
+		if _, err := os.Stat(record.Path); err != nil {
+			continue
+		}
+		if m.SessionExists(record.Name) {
+			continue
+		}
+		restorable = append(restorable, *record)
+	}
+	sort.Slice(restorable, func(i, j int) bool { return restorable[i].Name < restorable[j].Name })
+	return restorable, nil
+}
+
+// ForgetSession removes the record of a session so it is not offered for restore
+func (m *Manager) ForgetSession(sessionName string) error {
+	records, err := loadSessionRecords()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[sessionName]; !ok {
+		return nil
+	}
+	delete(records, sessionName)
+	return saveSessionRecords(records)
+}
+
+// RestoreSession recreates a recorded session with its windows at their recorded indexes
+// The agent window is started with the agent's resume flag and the layout panes are added again
+func (m *Manager) RestoreSession(record SessionRecord, layout *Layout) error {
+	if !m.isTmux() {
+		return fmt.Errorf("sessions can only be restored with tmux")
+	}
+	if m.SessionExists(record.Name) {
+		return nil
+	}
+	if len(record.Windows) == 0 {
+		return fmt.Errorf("session %s has no recorded windows", record.Name)
+	}
+
+	// The placeholder keeps the session alive while the recorded windows replace it
+	cmd := exec.Command("tmux", "new-session", "-d", "-s", record.Name, "-c", record.Path, "-n", restorePlaceholderWindow)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create session: %s", strings.TrimSpace(string(output)))
+	}
+
+	for _, window := range record.Windows {
+		path := window.Path
+		if _, err := os.Stat(path); err != nil {
+			path = record.Path
+		}
+
+		// -k replaces the placeholder if it sits at the same index
+		args := []string{"new-window", "-d", "-k", "-t", fmt.Sprintf("%s:%d", record.Name, window.Index), "-c", path}
+		if record.Agent != "" && window.Name == record.Agent {
+			args = append(args, "-n", m.agent.WindowName())
+			if command := m.buildAgentCommand(record.Path, true); command != "" {
+				args = append(args, command)
+			}
+		} else {
+			args = append(args, "-n", window.Name)
+		}
+
+		if output, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to restore window %s: %s", window.Name, strings.TrimSpace(string(output)))
+		}
+	}
+
+	// Recorded windows at other indexes leave the placeholder behind
+	_ = exec.Command("tmux", "kill-window", "-t", record.Name+":="+restorePlaceholderWindow).Run()
+
+	return m.ApplyLayout(record.Name, record.Path, layout)
+}
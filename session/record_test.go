@@ -0,0 +1,95 @@
+package session
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// isolatedTmux points tmux (and the session records) at a private server and home for the test
+func isolatedTmux(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+}
+
+// tmuxWindows lists the windows of a session as "index:name"
+func tmuxWindows(t *testing.T, sessionName string) string {
+	t.Helper()
+	output, err := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_index}:#{window_name}").Output()
+	if err != nil {
+		t.Fatalf("Failed to list windows of %s: %v", sessionName, err)
+	}
+	return strings.Join(strings.Fields(string(output)), " ")
+}
+
+// TestRecordAndRestoreSessions tests recording sessions and restoring them after the tmux server dies
+func TestRecordAndRestoreSessions(t *testing.T) {
+	isolatedTmux(t)
+	worktree := t.TempDir()
+
+	m := NewManager()
+	m.SetAgent(Agent{Name: "claude", Command: "cat"})
+
+	run := func(args ...string) {
+		if output, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+			t.Fatalf("tmux %v failed: %s", args, output)
+		}
+	}
+	run("new-session", "-d", "-s", "jean-app-login", "-c", worktree, "-n", "terminal")
+	run("new-window", "-t", "jean-app-login:2", "-c", worktree, "-n", "claude", "cat")
+	run("new-window", "-t", "jean-app-login:5", "-c", worktree, "-n", "dev")
+	run("new-session", "-d", "-s", "jean-app-closed", "-c", worktree, "-n", "terminal")
+	run("new-session", "-d", "-s", "personal", "-c", worktree)
+	before := tmuxWindows(t, "jean-app-login")
+
+	if err := m.RecordSessions(); err != nil {
+		t.Fatalf("Failed to record sessions: %v", err)
+	}
+
+	// A session closed while the server keeps running is forgotten
+	run("kill-session", "-t", "jean-app-closed")
+	if err := m.RecordSessions(); err != nil {
+		t.Fatalf("Failed to record sessions: %v", err)
+	}
+
+	// Sessions that vanish with the server are kept
+	run("kill-server")
+	if err := m.RecordSessions(); err != nil {
+		t.Fatalf("Failed to record without a server: %v", err)
+	}
+
+	// A sibling directory sharing the path prefix is another repository
+	if restorable, _ := m.RestorableSessions(worktree[:len(worktree)-1]); len(restorable) != 0 {
+		t.Errorf("Expected no restorable sessions for a path prefix, got %+v", restorable)
+	}
+
+	restorable, err := m.RestorableSessions(worktree)
+	if err != nil {
+		t.Fatalf("Failed to list restorable sessions: %v", err)
+	}
+	if len(restorable) != 1 || restorable[0].Name != "jean-app-login" || restorable[0].Agent != "claude" {
+		t.Fatalf("Expected only jean-app-login to be restorable, got %+v", restorable)
+	}
+
+	layout := &Layout{Windows: []LayoutWindow{{Name: "dev", Panes: []LayoutPane{{}, {Split: "horizontal"}}}}}
+	if err := m.RestoreSession(restorable[0], layout); err != nil {
+		t.Fatalf("Failed to restore session: %v", err)
+	}
+	if after := tmuxWindows(t, "jean-app-login"); after != before {
+		t.Errorf("Expected windows %q after restore, got %q", before, after)
+	}
+	if panes, _ := windowPaneCount("jean-app-login", "dev"); panes != 2 {
+		t.Errorf("Expected layout panes to be added to dev, got %d panes", panes)
+	}
+
+	// Running sessions are not offered again
+	if restorable, _ := m.RestorableSessions(worktree); len(restorable) != 0 {
+		t.Errorf("Expected no restorable sessions after restore, got %+v", restorable)
+	}
+}
@@ -13,22 +13,73 @@ import (
 
 const sessionPrefix = "jean-"
 
-// Session represents a tmux session
+// Session represents a jean session in the terminal multiplexer
 type Session struct {
 	Name         string
 	Branch       string
-	Path         string // Working directory of the session
+	Path         string // Working directory of the session, "" if the multiplexer doesn't report it (Zellij)
 	Active       bool
 	Windows      int
 	LastActivity time.Time
 }
 
-// Manager handles tmux session operations
-type Manager struct{}
+// Manager handles jean session operations
+type Manager struct {
+	agent Agent       // AI coding agent started in the agent window
+	mux   Multiplexer // Terminal multiplexer hosting the sessions
+}
 
-// NewManager creates a new session manager
+// NewManager creates a new session manager using tmux
 func NewManager() *Manager {
-	return &Manager{}
+	return &Manager{agent: DefaultAgent(), mux: &tmuxMultiplexer{}}
+}
+
+// SetMultiplexer sets the terminal multiplexer hosting the sessions
+// A nil multiplexer resets to tmux
+func (m *Manager) SetMultiplexer(mux Multiplexer) {
+	if mux == nil {
+		mux = &tmuxMultiplexer{}
+	}
+	m.mux = mux
+}
+
+// Multiplexer returns the terminal multiplexer hosting the sessions
+func (m *Manager) Multiplexer() Multiplexer {
+	return m.mux
+}
+
+// isTmux reports whether sessions are hosted by tmux (restore records and layout updates are tmux only)
+func (m *Manager) isTmux() bool {
+	return m.mux.Name() == MultiplexerTmux
+}
+
+// SetAgent sets the AI coding agent started in the agent window
+// An agent without a command resets to the default (claude)
+func (m *Manager) SetAgent(agent Agent) {
+	if strings.TrimSpace(agent.Command) == "" {
+		agent = DefaultAgent()
+	}
+	m.agent = agent
+}
+
+// Agent returns the AI coding agent started in the agent window
+func (m *Manager) Agent() Agent {
+	return m.agent
+}
+
+// AgentCommand returns the shell command starting the agent in a worktree
+// A fresh agent starts with the worktree's initial prompt, if it has one (see WriteInitialPrompt)
+// Returns "" if the agent executable is not installed (the window falls back to a shell)
+func (m *Manager) AgentCommand(worktreePath, branch, repoName string, isInitialized bool) string {
+	if !m.agent.IsAvailable() {
+		return ""
+	}
+	if !isInitialized && m.agent.PromptArg != "" {
+		if promptFile := initialPromptPath(worktreePath); promptFile != "" {
+			return m.agent.BuildCommandWithPrompt(worktreePath, branch, repoName, promptFile)
+		}
+	}
+	return m.agent.BuildCommand(worktreePath, branch, repoName, isInitialized)
 }
 
 // SanitizeBranchName sanitizes a branch name for use as a git branch (without prefix)
@@ -63,135 +114,95 @@ func (m *Manager) SanitizeName(repoName, branch string) string {
 	return sessionPrefix + sanitizedBranch
 }
 
-// SessionExists checks if a tmux session with the given name exists
+// SessionExists checks if a session with the given name exists
 func (m *Manager) SessionExists(sessionName string) bool {
-	cmd := exec.Command("tmux", "has-session", "-t", sessionName)
-	err := cmd.Run()
-	return err == nil
-}
-
-// isClaudeAvailable checks if the claude command is available in PATH
-func (m *Manager) isClaudeAvailable() bool {
-	cmd := exec.Command("sh", "-c", "command -v claude")
-	err := cmd.Run()
-	return err == nil
+	return m.mux.SessionExists(sessionName)
 }
 
-// buildClaudeCommand constructs the proper claude command with flags
-// path is the worktree path to add with --add-dir
-// isInitialized determines whether to use --continue flag
-func (m *Manager) buildClaudeCommand(path string, isInitialized bool) string {
-	if isInitialized {
-		return fmt.Sprintf("claude --add-dir %s --continue --permission-mode plan", path)
+// buildAgentCommand constructs the agent command for a worktree path
+// Branch and repository name for the {branch} and {repo} templates are read from git
+// isInitialized determines whether to use the resume flag
+func (m *Manager) buildAgentCommand(path string, isInitialized bool) string {
+	var branch, repoName string
+	if strings.Contains(m.agent.Args, "{branch}") || strings.Contains(m.agent.Args, "{repo}") {
+		if output, err := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD").Output(); err == nil {
+			branch = strings.TrimSpace(string(output))
+		}
+		if output, err := exec.Command("git", "-C", path, "rev-parse", "--path-format=absolute", "--git-common-dir").Output(); err == nil {
+			repoName = filepath.Base(filepath.Dir(strings.TrimSpace(string(output))))
+		}
 	}
-	return fmt.Sprintf("claude --add-dir %s --permission-mode plan", path)
+	return m.AgentCommand(path, branch, repoName, isInitialized)
 }
 
 // createOrAttach creates a new session or attaches to existing one
 // targetWindow specifies which window to attach to: "terminal" (window 0) or "claude" (window 1)
 // Always creates both windows when creating a new session
 // Deprecated: Use session switching via the TUI instead
-func (m *Manager) createOrAttach(path, branch, repoName string, autoStartClaude bool, targetWindow string) error {
+func (m *Manager) createOrAttach(path, branch, repoName string, autoStartClaude bool, targetWindow string, layout *Layout) error {
 	sessionName := m.SanitizeName(repoName, branch)
 
 	if m.SessionExists(sessionName) {
 		// Session exists - ensure target window exists, create if missing
-		return m.AttachToWindow(sessionName, path, autoStartClaude, targetWindow)
+		return m.AttachToWindow(sessionName, path, autoStartClaude, targetWindow, layout)
 	}
 
 	// Create new session with both windows
-	return m.Create(sessionName, path, autoStartClaude, targetWindow)
+	return m.Create(sessionName, path, autoStartClaude, targetWindow, layout)
 }
 
-// Create creates a new tmux session with both windows
+// Create creates a new tmux session with both windows and the windows of the layout
 // Window 1: terminal (shell) - created automatically by new-session with base-index 1
 // Window 2: claude (if autoStartClaude is true)
-func (m *Manager) Create(sessionName, path string, autoStartClaude bool, targetWindow string) error {
-	// Create detached session with window 1 (terminal) - base-index 1 makes first window = 1
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", path, "-n", "terminal")
-	if err := cmd.Run(); err != nil {
+// Window 3+: layout windows (layout may be nil)
+func (m *Manager) Create(sessionName, path string, autoStartClaude bool, targetWindow string, layout *Layout) error {
+	// Use --permission-mode plan (shell wrapper handles --continue for initialized sessions)
+	if err := m.EnsureSession(sessionName, path, autoStartClaude, false, layout); err != nil {
 		return err
 	}
 
-	// Create window 2 (claude) if autoStartClaude is true
-	if autoStartClaude {
-		if m.isClaudeAvailable() {
-			// Claude is available - create window with proper flags
-			// Use --permission-mode plan (shell wrapper handles --continue for initialized sessions)
-			claudeCmd := m.buildClaudeCommand(path, false)
-			cmd = exec.Command("tmux", "new-window", "-t", sessionName+":2", "-c", path, "-n", "claude", claudeCmd)
-		} else {
-			// Claude not available - create shell window as fallback
-			cmd = exec.Command("tmux", "new-window", "-t", sessionName+":2", "-c", path, "-n", "claude")
-		}
-
-		if err := cmd.Run(); err != nil {
-			// Window creation failed, but session exists, so we continue
-			// The user can manually create the window later
-		}
-	}
-
 	// Attach to the target window
-	return m.AttachToWindow(sessionName, path, autoStartClaude, targetWindow)
+	return m.AttachToWindow(sessionName, path, autoStartClaude, targetWindow, layout)
 }
 
 // AttachToWindow attaches to a specific window in a session
-// Creates the window if it doesn't exist
-func (m *Manager) AttachToWindow(sessionName, path string, autoStartClaude bool, targetWindow string) error {
+// targetWindow is "terminal", "claude" or the name of a layout window
+// Creates the window (including its layout panes) if it doesn't exist
+func (m *Manager) AttachToWindow(sessionName, path string, autoStartClaude bool, targetWindow string, layout *Layout) error {
 	var windowIndex string
 	var windowName string
 	var windowCommand string
 
-	if targetWindow == "claude" {
+	isLayoutWindow := targetWindow != "terminal" && targetWindow != "claude" && layout.findWindow(targetWindow) != nil
+	if isLayoutWindow {
+		// Layout windows are created with their panes by applyLayoutWindow below
+		windowIndex = layout.windowIndex(targetWindow)
+		windowName = targetWindow
+	} else if targetWindow == "claude" {
 		windowIndex = "2"
-		windowName = "claude"
-		// Use proper claude command with flags, or fallback to shell
-		if m.isClaudeAvailable() {
-			windowCommand = m.buildClaudeCommand(path, false)
-		} else {
-			windowCommand = "" // Fallback to shell
-		}
+		windowName = m.agent.WindowName()
+		// Use the agent command with flags, or fallback to shell if the agent isn't installed
+		windowCommand = m.buildAgentCommand(path, false)
 	} else {
 		windowIndex = "1"
 		windowName = "terminal"
 		windowCommand = "" // Will use shell
 	}
 
-	// Check if the target window exists
-	checkCmd := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_index}:#{window_name}")
-	output, err := checkCmd.Output()
-	if err == nil {
-		// Parse the windows to check if target window exists
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-		windowExists := false
-		for _, line := range lines {
-			if strings.Contains(line, windowIndex+":"+windowName) {
-				windowExists = true
-				break
-			}
-		}
+	// Create the window if it doesn't exist
+	if !isLayoutWindow && !m.mux.HasWindow(sessionName, windowName) {
+		index, _ := strconv.Atoi(windowIndex)
+		// Ignore errors, window might be created concurrently
+		_ = m.mux.AddWindow(sessionName, path, Window{Name: windowName, Index: index, Program: windowCommand})
+	}
 
-		// If window doesn't exist, create it
-		if !windowExists {
-			if windowCommand != "" {
-				// Create window with specific command
-				cmd := exec.Command("tmux", "new-window", "-t", sessionName+":"+windowIndex, "-c", path, "-n", windowName, windowCommand)
-				cmd.Run() // Ignore errors, window might be created concurrently
-			} else {
-				// Create shell window
-				cmd := exec.Command("tmux", "new-window", "-t", sessionName+":"+windowIndex, "-c", path, "-n", windowName)
-				cmd.Run() // Ignore errors
-			}
-		}
+	// Add the layout panes of the target window (missing panes only)
+	if window := layout.findWindow(targetWindow); window != nil {
+		_ = m.applyLayoutWindow(sessionName, path, layout, *window)
 	}
 
 	// Attach to the target window
-	cmd := exec.Command("tmux", "attach-session", "-t", sessionName+":"+windowIndex)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
+	return m.mux.Attach(sessionName, windowName)
 }
 
 const jeanTmuxConfigMarker = "# === JEAN_TMUX_CONFIG_START_DO_NOT_MODIFY_THIS_LINE ==="
@@ -352,19 +363,41 @@ func (m *Manager) RemoveJeanTmuxConfig() error {
 	return nil
 }
 
-// Attach attaches to an existing tmux session
+// Attach attaches to an existing session
 func (m *Manager) Attach(sessionName string) error {
-	cmd := exec.Command("tmux", "attach-session", "-t", sessionName)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// Runs until the user detaches
+	return m.mux.Attach(sessionName, "")
+}
 
-	// Run and wait for the command to complete (user detaches from tmux)
-	return cmd.Run()
+// AttachWindow attaches to an existing session, switching to the given window first
+func (m *Manager) AttachWindow(sessionName, windowName string) error {
+	// Runs until the user detaches
+	return m.mux.Attach(sessionName, windowName)
+}
+
+// Windows returns the windows of a running session
+func (m *Manager) Windows(sessionName string) ([]WindowInfo, error) {
+	return m.mux.Windows(sessionName)
+}
+
+// KillWindow closes a single window of a session, the session keeps running
+func (m *Manager) KillWindow(sessionName, windowName string) error {
+	windows, err := m.mux.Windows(sessionName)
+	if err != nil {
+		return err
+	}
+	// Closing the last window would end the session, that's what Kill is for
+	if len(windows) == 1 && windows[0].Name == windowName {
+		return fmt.Errorf("%s is the last window of the session, kill the session instead", windowName)
+	}
+	return m.mux.KillWindow(sessionName, windowName)
 }
 
 // NewWindowAndAttach creates a new window in existing session and attaches to it
 func (m *Manager) NewWindowAndAttach(sessionName, path string) error {
+	if !m.isTmux() {
+		return fmt.Errorf("opening an unnamed window is not supported with %s", m.mux.Name())
+	}
 	// Create a new window in the existing session with the specified path
 	// and attach to the session
 	cmd := exec.Command("tmux", "new-window", "-t", sessionName, "-c", path)
@@ -376,9 +409,202 @@ func (m *Manager) NewWindowAndAttach(sessionName, path string) error {
 	return m.Attach(sessionName)
 }
 
-// List returns all jean tmux sessions, optionally filtered by repository path
+// List returns all jean sessions, optionally filtered by repository path
 // If repoPath is empty string, returns all jean sessions
+// Sessions without a known path (Zellij) are matched by the repository name in the session name
 func (m *Manager) List(repoPath string) ([]Session, error) {
+	all, err := m.mux.List()
+	if err != nil {
+		return nil, err
+	}
+
+	repoPrefix := ""
+	if repoPath != "" {
+		repoPrefix = m.SanitizeName(filepath.Base(repoPath), "")
+	}
+
+	var sessions []Session
+	for _, sess := range all {
+		if !strings.HasPrefix(sess.Name, sessionPrefix) {
+			continue
+		}
+		if repoPath != "" {
+			if sess.Path != "" && !InRepository(sess.Path, repoPath) {
+				continue
+			}
+			if sess.Path == "" && !strings.HasPrefix(sess.Name, repoPrefix) {
+				continue
+			}
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions, nil
+}
+
+// InRepository reports whether path is the repository root or inside it
+// A bare prefix check would also match siblings, /code/app-v2 for /code/app
+func InRepository(path, repoPath string) bool {
+	path = filepath.Clean(path)
+	repoPath = filepath.Clean(repoPath)
+	return path == repoPath || strings.HasPrefix(path, repoPath+string(filepath.Separator))
+}
+
+// Kill terminates a session and all its windows
+func (m *Manager) Kill(sessionName string) error {
+	return m.mux.Kill(sessionName)
+}
+
+// KillWorktreeSessions terminates the session of a worktree and its "-terminal" companion
+// session, which would otherwise keep running in the deleted directory. Missing sessions are skipped
+func (m *Manager) KillWorktreeSessions(sessionName string) error {
+	for _, name := range []string{sessionName, sessionName + "-terminal"} {
+		if !m.SessionExists(name) {
+			continue
+		}
+		if err := m.Kill(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameSession renames an existing session
+// Returns nil if session doesn't exist (no error)
+func (m *Manager) RenameSession(oldName, newName string) error {
+	// Check if session exists
+	if !m.SessionExists(oldName) {
+		// Session doesn't exist, nothing to do
+		return nil
+	}
+	return m.mux.Rename(oldName, newName)
+}
+
+// IsTmuxAvailable checks if tmux is installed
+func (m *Manager) IsTmuxAvailable() bool {
+	cmd := exec.Command("tmux", "-V")
+	err := cmd.Run()
+	return err == nil
+}
+
+// CaptureWindow returns the last lines shown in a session window
+// Colors are kept as ANSI escape sequences; trailing blank lines are dropped so the
+// result ends with the latest output
+func (m *Manager) CaptureWindow(sessionName, windowName string, lines int) ([]string, error) {
+	output, err := m.mux.Capture(sessionName, windowName)
+	if err != nil {
+		return nil, err
+	}
+	return lastLines(output, lines), nil
+}
+
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;:?]*[a-zA-Z]`)
+
+// lastLines returns up to n lines from the end of the output, ignoring trailing blank lines
+func lastLines(output string, n int) []string {
+	all := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	end := len(all)
+	for end > 0 && strings.TrimSpace(ansiSequence.ReplaceAllString(all[end-1], "")) == "" {
+		end--
+	}
+	return all[max(0, end-n):end]
+}
+
+// tmuxMultiplexer hosts sessions in tmux
+type tmuxMultiplexer struct{}
+
+// Name returns "tmux"
+func (t *tmuxMultiplexer) Name() string {
+	return MultiplexerTmux
+}
+
+// IsAvailable checks if tmux is installed
+func (t *tmuxMultiplexer) IsAvailable() bool {
+	return exec.Command("tmux", "-V").Run() == nil
+}
+
+// SessionExists checks if a tmux session with the given name exists
+func (t *tmuxMultiplexer) SessionExists(sessionName string) bool {
+	cmd := exec.Command("tmux", "has-session", "-t", sessionName)
+	err := cmd.Run()
+	return err == nil
+}
+
+// HasWindow checks if a tmux session has a window with the given name
+func (t *tmuxMultiplexer) HasWindow(sessionName, windowName string) bool {
+	_, exists := windowPaneCount(sessionName, windowName)
+	return exists
+}
+
+// Create creates a detached tmux session, the first window is created by new-session
+func (t *tmuxMultiplexer) Create(sessionName, path string, windows []Window) error {
+	if len(windows) == 0 {
+		return fmt.Errorf("session %s has no windows", sessionName)
+	}
+
+	first := windows[0]
+	args := []string{"new-session", "-d", "-s", sessionName, "-c", path, "-n", first.Name, "-P", "-F", "#{pane_id}"}
+	if first.Program != "" {
+		args = append(args, first.Program)
+	}
+	output, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create session: %s", strings.TrimSpace(string(output)))
+	}
+	if err := buildWindow(sessionName, path, first, strings.TrimSpace(string(output))); err != nil {
+		return err
+	}
+
+	for _, window := range windows[1:] {
+		if err := t.AddWindow(sessionName, path, window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddWindow creates a window at its index (or the next free index) without selecting it
+func (t *tmuxMultiplexer) AddWindow(sessionName, path string, window Window) error {
+	target := sessionName + ":"
+	if window.Index > 0 {
+		target += strconv.Itoa(window.Index)
+	}
+	args := []string{"new-window", "-d", "-t", target, "-c", path, "-n", window.Name, "-P", "-F", "#{pane_id}"}
+	if window.Program != "" {
+		args = append(args, window.Program)
+	}
+	output, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create window %s: %s", window.Name, strings.TrimSpace(string(output)))
+	}
+	return buildWindow(sessionName, path, window, strings.TrimSpace(string(output)))
+}
+
+// buildWindow types the first pane's command and splits off the other panes of a new window
+func buildWindow(sessionName, path string, window Window, paneID string) error {
+	if len(window.Panes) == 0 {
+		return nil
+	}
+	sendCommand(paneID, window.Panes[0].Command)
+	return splitPanes(sessionName+":="+window.Name, path, window.Name, window.Panes[1:], window.Arrangement)
+}
+
+// Attach attaches to a tmux session, selecting the window first if given
+func (t *tmuxMultiplexer) Attach(sessionName, windowName string) error {
+	target := sessionName
+	if windowName != "" {
+		target = sessionName + ":=" + windowName
+	}
+	cmd := exec.Command("tmux", "attach-session", "-t", target)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Run and wait for the command to complete (user detaches from tmux)
+	return cmd.Run()
+}
+
+// List returns all tmux sessions
+func (t *tmuxMultiplexer) List() ([]Session, error) {
 	// List all sessions with format: name:windows:attached:activity:path
 	// activity is the maximum window_activity timestamp in the session
 	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}:#{session_windows}:#{session_attached}:#{session_activity}:#{session_path}")
@@ -392,24 +618,12 @@ func (m *Manager) List(repoPath string) ([]Session, error) {
 	var sessions []Session
 
 	for _, line := range lines {
-		if !strings.HasPrefix(line, sessionPrefix) {
-			continue
-		}
-
-		parts := strings.Split(line, ":")
+		parts := strings.SplitN(line, ":", 5)
 		if len(parts) < 5 {
 			continue
 		}
 
 		name := parts[0]
-		sessionPath := parts[4]
-
-		// Filter by repository path if provided
-		if repoPath != "" && !strings.HasPrefix(sessionPath, repoPath) {
-			continue
-		}
-
-		branch := strings.TrimPrefix(name, sessionPrefix)
 		active := parts[2] == "1"
 
 		// Parse window count
@@ -426,8 +640,8 @@ func (m *Manager) List(repoPath string) ([]Session, error) {
 
 		sessions = append(sessions, Session{
 			Name:         name,
-			Branch:       branch,
-			Path:         sessionPath,
+			Branch:       strings.TrimPrefix(name, sessionPrefix),
+			Path:         parts[4],
 			Active:       active,
 			Windows:      windows,
 			LastActivity: lastActivity,
@@ -437,29 +651,91 @@ func (m *Manager) List(repoPath string) ([]Session, error) {
 	return sessions, nil
 }
 
+// Windows returns the windows of a tmux session with the program and PID of their active pane
+func (t *tmuxMultiplexer) Windows(sessionName string) ([]WindowInfo, error) {
+	// Format: index|active|command|pid|name (name last, it may contain '|')
+	cmd := exec.Command("tmux", "list-windows", "-t", sessionName, "-F", "#{window_index}|#{window_active}|#{pane_current_command}|#{pane_pid}|#{window_name}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("session %s not found", sessionName)
+	}
+	return parseTmuxWindows(string(output)), nil
+}
+
+// parseTmuxWindows parses the output of list-windows in the format used by Windows
+func parseTmuxWindows(output string) []WindowInfo {
+	var windows []WindowInfo
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		parts := strings.SplitN(line, "|", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		index, _ := strconv.Atoi(parts[0])
+		pid, _ := strconv.Atoi(parts[3])
+		windows = append(windows, WindowInfo{
+			Index:   index,
+			Name:    parts[4],
+			Command: parts[2],
+			PID:     pid,
+			Active:  parts[1] == "1",
+		})
+	}
+	return windows
+}
+
 // Kill terminates a tmux session and all its windows
-func (m *Manager) Kill(sessionName string) error {
+func (t *tmuxMultiplexer) Kill(sessionName string) error {
 	// tmux kill-session handles killing all windows in the session efficiently
 	cmd := exec.Command("tmux", "kill-session", "-t", sessionName)
 	return cmd.Run()
 }
 
-// RenameSession renames an existing tmux session
-// Returns nil if session doesn't exist (no error)
-func (m *Manager) RenameSession(oldName, newName string) error {
-	// Check if session exists
-	if !m.SessionExists(oldName) {
-		// Session doesn't exist, nothing to do
-		return nil
+// KillWindow kills a tmux window and its panes
+func (t *tmuxMultiplexer) KillWindow(sessionName, windowName string) error {
+	if output, err := exec.Command("tmux", "kill-window", "-t", sessionName+":="+windowName).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to kill window %s: %s", windowName, strings.TrimSpace(string(output)))
 	}
+	return nil
+}
 
+// Rename renames a tmux session
+func (t *tmuxMultiplexer) Rename(oldName, newName string) error {
 	cmd := exec.Command("tmux", "rename-session", "-t", oldName, newName)
 	return cmd.Run()
 }
 
-// IsTmuxAvailable checks if tmux is installed
-func (m *Manager) IsTmuxAvailable() bool {
-	cmd := exec.Command("tmux", "-V")
-	err := cmd.Run()
-	return err == nil
+// Capture returns the screen of the active pane of a window
+func (t *tmuxMultiplexer) Capture(sessionName, windowName string) (string, error) {
+	cmd := exec.Command("tmux", "capture-pane", "-p", "-e", "-t", sessionName+":="+windowName)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("window %s not found in session %s", windowName, sessionName)
+	}
+	return string(output), nil
+}
+
+// promptBuffer is the tmux paste buffer used to send prompts
+const promptBuffer = "jean-prompt"
+
+// SendText pastes text into the active pane of a window as a bracketed paste, then presses Enter
+// Bracketed paste makes multi-line text arrive as a single message
+func (t *tmuxMultiplexer) SendText(sessionName, windowName, text string) error {
+	target := sessionName + ":=" + windowName
+
+	load := exec.Command("tmux", "load-buffer", "-b", promptBuffer, "-")
+	load.Stdin = strings.NewReader(text)
+	if output, err := load.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to load prompt: %s", strings.TrimSpace(string(output)))
+	}
+
+	if output, err := exec.Command("tmux", "paste-buffer", "-p", "-d", "-b", promptBuffer, "-t", target).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to paste prompt: %s", strings.TrimSpace(string(output)))
+	}
+
+	// Give the program a moment to process the paste, an immediate Enter can be swallowed by it
+	time.Sleep(100 * time.Millisecond)
+	if output, err := exec.Command("tmux", "send-keys", "-t", target, "Enter").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to submit prompt: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
 }
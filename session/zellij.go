@@ -0,0 +1,381 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zellijMultiplexer hosts sessions in Zellij, driven through its CLI and generated layout files
+// Windows are Zellij tabs. Zellij doesn't report the directory or activity of a session, and only
+// the focused pane of a session can be captured
+type zellijMultiplexer struct{}
+
+// zellijTabTemplate keeps Zellij's tab bar and status bar around the panes of every tab
+const zellijTabTemplate = `    default_tab_template {
+        pane size=1 borderless=true {
+            plugin location="zellij:tab-bar"
+        }
+        children
+        pane size=2 borderless=true {
+            plugin location="zellij:status-bar"
+        }
+    }
+`
+
+// Name returns "zellij"
+func (z *zellijMultiplexer) Name() string {
+	return MultiplexerZellij
+}
+
+// IsAvailable checks if zellij is installed
+func (z *zellijMultiplexer) IsAvailable() bool {
+	return exec.Command("zellij", "--version").Run() == nil
+}
+
+// zellijAction runs a zellij action in a session
+func zellijAction(sessionName string, args ...string) error {
+	cmd := exec.Command("zellij", append([]string{"--session", sessionName, "action"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("zellij %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SessionExists checks if a running (not exited) Zellij session has the given name
+func (z *zellijMultiplexer) SessionExists(sessionName string) bool {
+	sessions, _ := z.List()
+	for _, sess := range sessions {
+		if sess.Name == sessionName {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWindow checks if a Zellij session has a tab with the given name
+func (z *zellijMultiplexer) HasWindow(sessionName, windowName string) bool {
+	windows, err := z.Windows(sessionName)
+	if err != nil {
+		return false
+	}
+	for _, window := range windows {
+		if window.Name == windowName {
+			return true
+		}
+	}
+	return false
+}
+
+// Windows returns the tabs of a Zellij session, numbered from 1
+// Zellij doesn't report the programs running in a tab, so only names are known
+func (z *zellijMultiplexer) Windows(sessionName string) ([]WindowInfo, error) {
+	output, err := exec.Command("zellij", "--session", sessionName, "action", "query-tab-names").Output()
+	if err != nil {
+		return nil, fmt.Errorf("session %s not found", sessionName)
+	}
+	var windows []WindowInfo
+	for _, name := range strings.Split(string(output), "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			windows = append(windows, WindowInfo{Index: len(windows) + 1, Name: name})
+		}
+	}
+	return windows, nil
+}
+
+// Create starts a background Zellij session from a layout with one tab per window
+func (z *zellijMultiplexer) Create(sessionName, path string, windows []Window) error {
+	if len(windows) == 0 {
+		return fmt.Errorf("session %s has no windows", sessionName)
+	}
+
+	layoutPath, err := writeZellijLayout(sessionName, zellijSessionLayout(path, windows))
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("zellij", "attach", "--create-background", sessionName,
+		"options", "--default-layout", layoutPath, "--default-cwd", path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create session: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// AddWindow opens a new tab from a layout, then returns to the previously focused tab
+func (z *zellijMultiplexer) AddWindow(sessionName, path string, window Window) error {
+	layoutPath, err := writeZellijLayout(sessionName+"-"+window.Name, zellijTabLayout(window))
+	if err != nil {
+		return err
+	}
+	if err := zellijAction(sessionName, "new-tab", "--layout", layoutPath, "--cwd", path, "--name", window.Name); err != nil {
+		return fmt.Errorf("failed to create window %s: %w", window.Name, err)
+	}
+	_ = zellijAction(sessionName, "go-to-previous-tab")
+	return nil
+}
+
+// Attach attaches to a Zellij session, switching to the tab first if given
+func (z *zellijMultiplexer) Attach(sessionName, windowName string) error {
+	if windowName != "" {
+		_ = zellijAction(sessionName, "go-to-tab-name", windowName)
+	}
+	cmd := exec.Command("zellij", "attach", sessionName)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Run and wait for the command to complete (user detaches from zellij)
+	return cmd.Run()
+}
+
+// List returns the running Zellij sessions, exited sessions kept for resurrection are left out
+func (z *zellijMultiplexer) List() ([]Session, error) {
+	output, err := exec.Command("zellij", "list-sessions", "--no-formatting").Output()
+	if err != nil {
+		// No sessions exist
+		return []Session{}, nil
+	}
+	return parseZellijSessions(string(output)), nil
+}
+
+// parseZellijSessions parses the output of 'zellij list-sessions --no-formatting'
+// e.g. "jean-app-main [Created 5m 3s ago] (current)"
+func parseZellijSessions(output string) []Session {
+	sessions := []Session{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.Contains(line, "EXITED") {
+			continue
+		}
+		sessions = append(sessions, Session{
+			Name:   fields[0],
+			Branch: strings.TrimPrefix(fields[0], sessionPrefix),
+			Active: strings.Contains(line, "(current)"),
+		})
+	}
+	return sessions
+}
+
+// Kill terminates a Zellij session and deletes it so it isn't resurrected
+func (z *zellijMultiplexer) Kill(sessionName string) error {
+	return exec.Command("zellij", "delete-session", "--force", sessionName).Run()
+}
+
+// KillWindow closes a Zellij tab, focusing it first since close-tab acts on the focused tab
+func (z *zellijMultiplexer) KillWindow(sessionName, windowName string) error {
+	// go-to-tab-name ignores unknown names, which would close whatever tab is focused
+	if !z.HasWindow(sessionName, windowName) {
+		return fmt.Errorf("window %s not found in session %s", windowName, sessionName)
+	}
+	if err := zellijAction(sessionName, "go-to-tab-name", windowName); err != nil {
+		return err
+	}
+	if err := zellijAction(sessionName, "close-tab"); err != nil {
+		return fmt.Errorf("failed to kill window %s: %w", windowName, err)
+	}
+	return nil
+}
+
+// Rename renames a Zellij session
+func (z *zellijMultiplexer) Rename(oldName, newName string) error {
+	return zellijAction(oldName, "rename-session", newName)
+}
+
+// zellijFocusedTab matches the focused tab in the output of 'zellij action dump-layout'
+var zellijFocusedTab = regexp.MustCompile(`(?m)^\s*tab name="((?:[^"\\]|\\.)*)"[^\n{]*\bfocus=true`)
+
+// Capture returns the screen of the focused pane, if the focused tab is the requested window
+// Zellij can only dump the focused pane; switching tabs would disturb attached clients
+func (z *zellijMultiplexer) Capture(sessionName, windowName string) (string, error) {
+	focused, err := z.focusedTab(sessionName)
+	if err != nil {
+		return "", err
+	}
+	if focused != windowName {
+		return "", fmt.Errorf("window %s is not focused in session %s", windowName, sessionName)
+	}
+
+	file, err := os.CreateTemp("", "jean-zellij-screen-*")
+	if err != nil {
+		return "", err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	if err := zellijAction(sessionName, "dump-screen", file.Name()); err != nil {
+		return "", err
+	}
+	output, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// focusedTab returns the name of the focused tab of a Zellij session
+func (z *zellijMultiplexer) focusedTab(sessionName string) (string, error) {
+	layout, err := exec.Command("zellij", "--session", sessionName, "action", "dump-layout").Output()
+	if err != nil {
+		return "", fmt.Errorf("session %s not found", sessionName)
+	}
+	match := zellijFocusedTab.FindSubmatch(layout)
+	if match == nil {
+		return "", fmt.Errorf("no focused window in session %s", sessionName)
+	}
+	return string(match[1]), nil
+}
+
+// SendText types the text into a tab as a bracketed paste, then presses Enter
+// Zellij only types into the focused tab, so the tab is focused for the time of the paste and the
+// previously focused tab is focused again afterwards
+func (z *zellijMultiplexer) SendText(sessionName, windowName, text string) error {
+	// go-to-tab-name ignores unknown names, which would type into whatever tab is focused
+	if !z.HasWindow(sessionName, windowName) {
+		return fmt.Errorf("window %s not found in session %s", windowName, sessionName)
+	}
+	previous, _ := z.focusedTab(sessionName)
+	if err := zellijAction(sessionName, "go-to-tab-name", windowName); err != nil {
+		return err
+	}
+	if previous != "" && previous != windowName {
+		defer func() { _ = zellijAction(sessionName, "go-to-tab-name", previous) }()
+	}
+	if err := zellijAction(sessionName, "write-chars", "\x1b[200~"+text+"\x1b[201~"); err != nil {
+		return fmt.Errorf("failed to paste prompt: %w", err)
+	}
+
+	// Give the program a moment to process the paste, an immediate Enter can be swallowed by it
+	time.Sleep(100 * time.Millisecond)
+	if err := zellijAction(sessionName, "write", "13"); err != nil {
+		return fmt.Errorf("failed to submit prompt: %w", err)
+	}
+	return nil
+}
+
+// writeZellijLayout writes a generated layout file and returns its path
+// Files are kept (and overwritten next time) because Zellij may read them after the command returns
+func writeZellijLayout(name, content string) (string, error) {
+	dir := filepath.Join(os.TempDir(), "jean-zellij")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+".kdl")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write zellij layout: %w", err)
+	}
+	return path, nil
+}
+
+// zellijSessionLayout builds the layout of a new session, one tab per window with the first one focused
+func zellijSessionLayout(path string, windows []Window) string {
+	var b strings.Builder
+	b.WriteString("layout {\n")
+	fmt.Fprintf(&b, "    cwd %s\n", kdlString(path))
+	b.WriteString(zellijTabTemplate)
+	for i, window := range windows {
+		focus := ""
+		if i == 0 {
+			focus = " focus=true"
+		}
+		fmt.Fprintf(&b, "    tab name=%s%s {\n", kdlString(window.Name), focus)
+		writeZellijPanes(&b, zellijPanes(window), "", 2)
+		b.WriteString("    }\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// zellijTabLayout builds the layout of a single tab opened with 'zellij action new-tab'
+// The tab and status bars are explicit panes, default_tab_template doesn't apply to new-tab layouts
+func zellijTabLayout(window Window) string {
+	var b strings.Builder
+	b.WriteString("layout {\n")
+	b.WriteString("    pane size=1 borderless=true {\n        plugin location=\"zellij:tab-bar\"\n    }\n")
+	writeZellijPanes(&b, zellijPanes(window), "", 1)
+	b.WriteString("    pane size=2 borderless=true {\n        plugin location=\"zellij:status-bar\"\n    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// zellijPanes returns the panes of a window with the window's program as the first pane's command
+// Commands that would be typed into a tmux pane run in a shell that stays open when they exit
+func zellijPanes(window Window) []zellijPane {
+	panes := window.Panes
+	if len(panes) == 0 {
+		panes = []LayoutPane{{}}
+	}
+
+	result := make([]zellijPane, 0, len(panes))
+	for i, pane := range panes {
+		p := zellijPane{split: pane.Split, size: pane.Size}
+		switch {
+		case i == 0 && window.Program != "":
+			p.command = window.Program
+		case pane.Command != "":
+			p.command = pane.Command + `; exec "${SHELL:-sh}"`
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// zellijPane is a pane of a generated layout
+type zellijPane struct {
+	command string // Shell command run in the pane, "" = plain shell
+	split   string // How the pane was split off the previous one ("horizontal" = side by side)
+	size    string
+}
+
+// writeZellijPanes writes panes split off one after another, like tmux does
+// Each split divides the remaining space, so the panes nest: [first, [second, [third...]]]
+func writeZellijPanes(b *strings.Builder, panes []zellijPane, size string, depth int) {
+	indent := strings.Repeat("    ", depth)
+	attrs := zellijSizeAttr(size)
+
+	if len(panes) == 1 {
+		if panes[0].command == "" {
+			fmt.Fprintf(b, "%spane%s\n", indent, attrs)
+			return
+		}
+		fmt.Fprintf(b, "%spane%s command=\"sh\" {\n", indent, attrs)
+		fmt.Fprintf(b, "%s    args \"-c\" %s\n", indent, kdlString(panes[0].command))
+		fmt.Fprintf(b, "%s}\n", indent)
+		return
+	}
+
+	// tmux "horizontal" splits place panes side by side, which Zellij calls a vertical split
+	direction := "horizontal"
+	if panes[1].split == "horizontal" {
+		direction = "vertical"
+	}
+	fmt.Fprintf(b, "%spane%s split_direction=\"%s\" {\n", indent, attrs, direction)
+	writeZellijPanes(b, panes[:1], "", depth+1)
+	writeZellijPanes(b, panes[1:], panes[1].size, depth+1)
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// zellijSizeAttr converts a tmux pane size ("30%" or a number of lines) to a Zellij size attribute
+func zellijSizeAttr(size string) string {
+	size = strings.TrimSpace(size)
+	if strings.HasSuffix(size, "%") {
+		if _, err := strconv.Atoi(strings.TrimSuffix(size, "%")); err == nil {
+			return fmt.Sprintf(" size=%q", size)
+		}
+		return ""
+	}
+	if _, err := strconv.Atoi(size); err == nil {
+		return " size=" + size
+	}
+	return ""
+}
+
+// kdlString quotes a string for a KDL document
+func kdlString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(s) + `"`
+}
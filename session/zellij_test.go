@@ -0,0 +1,75 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseZellijSessions tests parsing 'zellij list-sessions --no-formatting'
+func TestParseZellijSessions(t *testing.T) {
+	output := "jean-app-main [Created 5m 3s ago] (current)\n" +
+		"jean-app-old [Created 2days ago] (EXITED - attach to resurrect)\n" +
+		"notes [Created 1h ago]\n\n"
+
+	sessions := parseZellijSessions(output)
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 running sessions, got %+v", sessions)
+	}
+	if sessions[0].Name != "jean-app-main" || sessions[0].Branch != "app-main" || !sessions[0].Active {
+		t.Errorf("Unexpected first session: %+v", sessions[0])
+	}
+	if sessions[1].Name != "notes" || sessions[1].Active || sessions[1].Path != "" {
+		t.Errorf("Unexpected second session: %+v", sessions[1])
+	}
+}
+
+// TestZellijSessionLayout tests generating a Zellij layout from session windows
+func TestZellijSessionLayout(t *testing.T) {
+	windows := []Window{
+		{Name: "terminal", Panes: []LayoutPane{{}}},
+		{Name: "claude", Program: `claude --add-dir "/w" || claude`, Panes: []LayoutPane{{}}},
+		{Name: "dev", Panes: []LayoutPane{
+			{Command: "npm run dev"},
+			{Command: "npm test -- --watch", Split: "horizontal", Size: "30%"},
+			{Split: "vertical", Size: "10"},
+		}},
+	}
+
+	layout := zellijSessionLayout("/my repo/w", windows)
+	expected := []string{
+		`cwd "/my repo/w"`,
+		`plugin location="zellij:tab-bar"`,
+		`tab name="terminal" focus=true {`,
+		`tab name="claude" {`,
+		`args "-c" "claude --add-dir \"/w\" || claude"`,
+		`tab name="dev" {`,
+		`pane split_direction="vertical" {`,
+		`args "-c" "npm run dev; exec \"${SHELL:-sh}\""`,
+		`pane size="30%" split_direction="horizontal" {`,
+		`pane size=10` + "\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(layout, want) {
+			t.Errorf("Expected layout to contain %q, got:\n%s", want, layout)
+		}
+	}
+	if strings.Count(layout, "{") != strings.Count(layout, "}") {
+		t.Errorf("Unbalanced braces in layout:\n%s", layout)
+	}
+
+	tab := zellijTabLayout(windows[1])
+	if !strings.Contains(tab, `plugin location="zellij:status-bar"`) || !strings.Contains(tab, `command="sh"`) || strings.Contains(tab, "tab name") {
+		t.Errorf("Unexpected tab layout:\n%s", tab)
+	}
+}
+
+// TestZellijFocusedTab tests finding the focused tab in 'zellij action dump-layout' output
+func TestZellijFocusedTab(t *testing.T) {
+	layout := "layout {\n    tab name=\"terminal\" hide_floating_panes=true {\n        pane\n    }\n" +
+		"    tab name=\"claude\" focus=true hide_floating_panes=true {\n        pane command=\"sh\"\n    }\n}\n"
+
+	match := zellijFocusedTab.FindStringSubmatch(layout)
+	if match == nil || match[1] != "claude" {
+		t.Errorf("Expected focused tab claude, got %v", match)
+	}
+}
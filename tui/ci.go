@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coollabsio/jean-tui/git"
+	"github.com/coollabsio/jean-tui/github"
+)
+
+// ciRunLimit is the number of workflow runs listed in the CI modal
+const ciRunLimit = 10
+
+// ciPollInterval is how often the CI modal refreshes while runs are queued or running
+const ciPollInterval = 10 * time.Second
+
+// ciPromptLogLines is the number of log lines up to the failure sent to the agent
+const ciPromptLogLines = 60
+
+// ciRunsLoadedMsg carries the workflow runs of a worktree's branch
+type ciRunsLoadedMsg struct {
+	worktreePath string
+	runs         []github.WorkflowRun
+	err          error
+}
+
+// ciJobsLoadedMsg carries the jobs of a workflow run
+type ciJobsLoadedMsg struct {
+	runID int64
+	jobs  []github.WorkflowJob
+	err   error
+}
+
+// ciLogLoadedMsg carries the cleaned log of a job, shown in the log view or sent to the agent
+// The worktree and run are those of the request, the modal may show others by the time it arrives
+type ciLogLoadedMsg struct {
+	worktree git.Worktree
+	run      github.WorkflowRun
+	job      github.WorkflowJob
+	lines    []string
+	forAgent bool
+	err      error
+}
+
+// ciRerunMsg reports re-running the failed jobs of a run
+type ciRerunMsg struct {
+	runID int64
+	err   error
+}
+
+// ciPollTickMsg refreshes the CI modal, ticks scheduled before the latest one are ignored
+type ciPollTickMsg struct {
+	seq int
+}
+
+// openCIModal opens the CI modal on the workflow runs of a worktree's branch
+func (m Model) openCIModal(wt git.Worktree) (Model, tea.Cmd) {
+	m.modal = ciModal
+	m.ciWorktree = wt
+	m.ciRuns = nil
+	m.ciJobs = nil
+	m.ciJobsRunID = 0
+	m.ciErr = nil
+	m.ciRunIndex = 0
+	m.ciJobIndex = 0
+	m.ciFocusJobs = false
+	m.ciLogJob = nil
+	m.ciLoading = true
+	m.ciPollSeq++
+	return m, m.loadCIRuns(wt)
+}
+
+// loadCIRuns loads the latest workflow runs of a worktree's branch
+func (m Model) loadCIRuns(wt git.Worktree) tea.Cmd {
+	return func() tea.Msg {
+		runs, err := m.githubManager.GetWorkflowRuns(wt.Path, wt.Branch, ciRunLimit)
+		return ciRunsLoadedMsg{worktreePath: wt.Path, runs: runs, err: err}
+	}
+}
+
+// loadCIJobs loads the jobs of a workflow run
+func (m Model) loadCIJobs(runID int64) tea.Cmd {
+	worktreePath := m.ciWorktree.Path
+	return func() tea.Msg {
+		jobs, err := m.githubManager.GetRunJobs(worktreePath, runID)
+		return ciJobsLoadedMsg{runID: runID, jobs: jobs, err: err}
+	}
+}
+
+// loadCILog loads the log of a job
+func (m Model) loadCILog(job github.WorkflowJob, forAgent bool) tea.Cmd {
+	msg := ciLogLoadedMsg{worktree: m.ciWorktree, job: job, forAgent: forAgent}
+	if run := m.selectedCIRun(); run != nil {
+		msg.run = *run
+	}
+	return func() tea.Msg {
+		log, err := m.githubManager.GetJobLog(msg.worktree.Path, job.ID)
+		if err != nil {
+			if job.State() == "running" || job.State() == "queued" {
+				err = fmt.Errorf("the log is available once the job finishes")
+			}
+			msg.err = err
+			return msg
+		}
+		msg.lines = github.CleanJobLog(log)
+		return msg
+	}
+}
+
+// rerunFailedCIJobs re-runs the failed jobs of a workflow run
+func (m Model) rerunFailedCIJobs(runID int64) tea.Cmd {
+	worktreePath := m.ciWorktree.Path
+	return func() tea.Msg {
+		return ciRerunMsg{runID: runID, err: m.githubManager.RerunFailedJobs(worktreePath, runID)}
+	}
+}
+
+// scheduleCIPoll schedules the next refresh of the CI modal
+func (m Model) scheduleCIPoll() tea.Cmd {
+	seq := m.ciPollSeq
+	return tea.Tick(ciPollInterval, func(t time.Time) tea.Msg {
+		return ciPollTickMsg{seq: seq}
+	})
+}
+
+// ciActive reports whether a listed run is still queued or running
+func (m Model) ciActive() bool {
+	for _, run := range m.ciRuns {
+		if state := run.State(); state == "running" || state == "queued" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCIRuns shows the loaded runs and loads the jobs of the selected one
+func (m Model) applyCIRuns(msg ciRunsLoadedMsg) (Model, tea.Cmd) {
+	if m.modal != ciModal || msg.worktreePath != m.ciWorktree.Path {
+		return m, nil
+	}
+	m.ciLoading = false
+	m.ciErr = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	// Keep the selected run selected when newer runs come in
+	m.ciRuns = msg.runs
+	for i, run := range m.ciRuns {
+		if run.ID == m.ciJobsRunID {
+			m.ciRunIndex = i
+		}
+	}
+	if m.ciRunIndex >= len(m.ciRuns) {
+		m.ciRunIndex = max(0, len(m.ciRuns)-1)
+	}
+
+	var cmds []tea.Cmd
+	if run := m.selectedCIRun(); run != nil {
+		if run.ID != m.ciJobsRunID {
+			m.ciJobs = nil
+			m.ciJobIndex = 0
+		}
+		m.ciJobsRunID = run.ID
+		cmds = append(cmds, m.loadCIJobs(run.ID))
+	}
+	if m.ciActive() {
+		// Only the latest scheduled refresh counts
+		m.ciPollSeq++
+		cmds = append(cmds, m.scheduleCIPoll())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// applyCIJobs shows the loaded jobs of the selected run, loading the log shown in the log view
+// once its job finished
+func (m Model) applyCIJobs(msg ciJobsLoadedMsg) (Model, tea.Cmd) {
+	if m.modal != ciModal || msg.runID != m.ciJobsRunID {
+		return m, nil
+	}
+	if msg.err != nil {
+		return m, m.showErrorNotification(msg.err.Error(), 4*time.Second)
+	}
+	m.ciJobs = msg.jobs
+	if m.ciJobIndex >= len(m.ciJobs) {
+		m.ciJobIndex = max(0, len(m.ciJobs)-1)
+	}
+	if m.ciLogJob == nil || m.ciLogLines != nil {
+		return m, nil
+	}
+	for _, job := range m.ciJobs {
+		if job.ID == m.ciLogJob.ID && job.Status == "completed" {
+			m.ciLogJob = &job
+			m.ciLogErr = nil
+			return m, m.loadCILog(job, false)
+		}
+	}
+	return m, nil
+}
+
+// openCILog opens the log view on a job
+func (m Model) openCILog(job github.WorkflowJob) (Model, tea.Cmd) {
+	m.ciLogJob = &job
+	m.ciLogLines = nil
+	m.ciLogErr = nil
+	m.ciLogScroll = 0
+	return m, m.loadCILog(job, false)
+}
+
+// applyCILog shows a loaded log in the log view, or sends the failure it explains to the agent
+func (m Model) applyCILog(msg ciLogLoadedMsg) (Model, tea.Cmd) {
+	if msg.forAgent {
+		if msg.err != nil {
+			return m, m.showErrorNotification(msg.err.Error(), 4*time.Second)
+		}
+		return m.sendCIFailure(msg.worktree, msg.run, msg.job, msg.lines)
+	}
+	if m.modal != ciModal || m.ciLogJob == nil || m.ciLogJob.ID != msg.job.ID {
+		return m, nil
+	}
+	m.ciLogLines = msg.lines
+	m.ciLogErr = msg.err
+	m.ciLogScroll = 0
+	return m, nil
+}
+
+// selectCIRun selects another run and loads its jobs
+func (m Model) selectCIRun(index int) (Model, tea.Cmd) {
+	if index < 0 || index >= len(m.ciRuns) || index == m.ciRunIndex {
+		return m, nil
+	}
+	m.ciRunIndex = index
+	m.ciJobs = nil
+	m.ciJobIndex = 0
+	m.ciJobsRunID = m.ciRuns[index].ID
+	return m, m.loadCIJobs(m.ciJobsRunID)
+}
+
+// selectedCIRun returns the run selected in the CI modal
+func (m Model) selectedCIRun() *github.WorkflowRun {
+	if m.ciRunIndex >= 0 && m.ciRunIndex < len(m.ciRuns) {
+		return &m.ciRuns[m.ciRunIndex]
+	}
+	return nil
+}
+
+// targetCIJob returns the job the log and agent actions apply to: the job shown in the log
+// view, the selected job when the job list is focused, or else the first failed job
+func (m Model) targetCIJob() *github.WorkflowJob {
+	if m.ciLogJob != nil {
+		return m.ciLogJob
+	}
+	if m.ciFocusJobs && m.ciJobIndex < len(m.ciJobs) {
+		return &m.ciJobs[m.ciJobIndex]
+	}
+	for i := range m.ciJobs {
+		if m.ciJobs[i].State() == "failure" {
+			return &m.ciJobs[i]
+		}
+	}
+	return nil
+}
+
+// ciFailurePrompt turns a failed job and the end of its log into a task for the agent
+func ciFailurePrompt(branch string, run github.WorkflowRun, job github.WorkflowJob, lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CI failed on branch %s: workflow %q, job %q", branch, run.Name, job.Name)
+	if step := job.FailedStep(); step != "" {
+		fmt.Fprintf(&b, " at step %q", step)
+	}
+	b.WriteString(". Find the cause and fix it.\n")
+	if job.URL != "" {
+		b.WriteString(job.URL + "\n")
+	}
+	if excerpt := github.FailureExcerpt(lines, ciPromptLogLines); len(excerpt) > 0 {
+		b.WriteString("\nEnd of the job log:\n```\n" + strings.Join(excerpt, "\n") + "\n```")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// sendCIFailure sends a failed job's log to the agent of the worktree whose run failed
+func (m Model) sendCIFailure(wt git.Worktree, run github.WorkflowRun, job github.WorkflowJob, lines []string) (Model, tea.Cmd) {
+	if !m.sessionRunning(wt.ClaudeSessionName) {
+		return m, m.showWarningNotification("No running session for " + wt.Branch + ", open it with Enter first")
+	}
+	cmd := m.showInfoNotification("Sending CI failure to the agent...")
+	return m, tea.Batch(cmd, m.sendPrompt(ciFailurePrompt(wt.Branch, run, job, lines), []git.Worktree{wt}))
+}
+
+// formatCIDuration formats the duration of a run or job, e.g. "4m30s"
+func formatCIDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return d.String()
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mergedPR is a worktree whose PR was merged on GitHub
+type mergedPR struct {
+	branch string
+	path   string
+	url    string
+	number int
+}
+
+// mergedCleanupMsg reports the cleanup of worktrees whose PR merged
+type mergedCleanupMsg struct {
+	cleaned    []string // Branches whose worktree, sessions and branches were removed
+	skipped    []string // Branches left alone because of uncommitted changes
+	unmerged   []string // Branches left alone because they have commits the merged PR doesn't
+	keptBranch []string // Branches git refused to delete (git branch -d) once their worktree was removed
+	failures   []string // "branch: error" for cleanups that failed
+	pulledBase bool     // Whether the base branch was pulled in the main repository
+	restack    []string // Branches stacked on a cleaned branch, to restack with 'U'
+}
+
+// mergedPRCleanupLabel describes a merged PR cleanup policy for the settings
+func mergedPRCleanupLabel(policy string) string {
+	switch policy {
+	case "auto":
+		return "Automatic"
+	case "off":
+		return "Off"
+	}
+	return "Ask"
+}
+
+// mergedPRFor returns the merged PR of a workspace worktree, nil for the main repository and
+// the base branch which are never cleaned up
+func (m Model) mergedPRFor(branch, prURL string, number int) *mergedPR {
+	if branch == "" || branch == m.baseBranch {
+		return nil
+	}
+	for _, wt := range m.worktrees {
+		if wt.Branch == branch && strings.Contains(wt.Path, ".workspaces") {
+			return &mergedPR{branch: branch, path: wt.Path, url: prURL, number: number}
+		}
+	}
+	return nil
+}
+
+// offerMergedCleanup applies the repository's merged PR policy: clean the worktrees up right
+// away ("auto"), queue them for the post-merge cleanup modal ("ask") or do nothing ("off")
+func (m Model) offerMergedCleanup(merged []mergedPR) (Model, tea.Cmd) {
+	policy := "ask"
+	if m.configManager != nil {
+		policy = m.configManager.GetMergedPRCleanup(m.repoPath)
+	}
+
+	switch policy {
+	case "off":
+		return m, nil
+	case "auto":
+		if len(merged) == 0 {
+			return m, nil
+		}
+		cmd := m.showInfoNotification(fmt.Sprintf("Cleaning up %d merged worktree%s...", len(merged), pluralize(len(merged))))
+		return m, tea.Batch(cmd, m.cleanupMergedWorktrees(merged))
+	}
+
+	for _, pr := range merged {
+		queued := m.postMergePR != nil && m.postMergePR.url == pr.url
+		for _, q := range m.mergedCleanupQueue {
+			queued = queued || q.url == pr.url
+		}
+		if !queued {
+			m.mergedCleanupQueue = append(m.mergedCleanupQueue, pr)
+		}
+	}
+	return m.nextMergedCleanup(), nil
+}
+
+// nextMergedCleanup opens the post-merge cleanup modal for the next queued merged PR, unless
+// another modal is open (the queue is picked up again after the next refresh)
+func (m Model) nextMergedCleanup() Model {
+	if m.modal != noModal || len(m.mergedCleanupQueue) == 0 {
+		return m
+	}
+	pr := m.mergedCleanupQueue[0]
+	m.mergedCleanupQueue = m.mergedCleanupQueue[1:]
+	m.postMergePR = &pr
+	m.localMergeBranch = pr.branch
+	m.localMergeWorktree = pr.path
+	m.localMergeTarget = m.baseBranchFor(pr.branch)
+	m.postMergeDeleteIndex = 0
+	m.modal = postMergeCleanupModal
+	return m
+}
+
+// verifyMerged checks that the local and remote heads of a merged PR's branch are contained in
+// the merged PR head or the base branch, so cleaning the branch up loses no commits pushed or
+// made after the merge
+func (m Model) verifyMerged(pr mergedPR, baseBranch string) error {
+	var targets []string
+	if pr.number > 0 {
+		if head, err := m.gitManager.FetchOriginRef(pr.path, fmt.Sprintf("refs/pull/%d/head", pr.number)); err == nil && head != "" {
+			targets = append(targets, head)
+		}
+	}
+	if base, err := m.gitManager.FetchOriginRef(pr.path, "refs/heads/"+strings.TrimPrefix(baseBranch, "origin/")); err == nil && base != "" {
+		targets = append(targets, base)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("could not fetch the merged PR or %s", baseBranch)
+	}
+
+	heads := []string{"refs/heads/" + pr.branch}
+	remoteHead, err := m.gitManager.FetchOriginRef(pr.path, "refs/heads/"+pr.branch)
+	if err != nil {
+		return err
+	}
+	if remoteHead != "" {
+		heads = append(heads, remoteHead)
+	}
+
+	for _, head := range heads {
+		merged := false
+		for _, target := range targets {
+			if ok, err := m.gitManager.IsAncestor(pr.path, head, target); err == nil && ok {
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			return fmt.Errorf("%s has commits that are not in the merged PR", head)
+		}
+	}
+	return nil
+}
+
+// cleanupMergedWorktrees kills the sessions, removes the worktree and deletes the local and remote
+// branch of each merged PR, then pulls the base branch in the main repository
+// Worktrees with uncommitted changes, or with commits the merged PR doesn't have, are skipped
+func (m Model) cleanupMergedWorktrees(merged []mergedPR) tea.Cmd {
+	baseBranch := m.baseBranch
+	return func() tea.Msg {
+		result := mergedCleanupMsg{}
+		for _, pr := range merged {
+			if dirty, err := m.gitManager.HasUncommittedChanges(pr.path); err != nil || dirty {
+				result.skipped = append(result.skipped, pr.branch)
+				continue
+			}
+
+			if err := m.verifyMerged(pr, baseBranch); err != nil {
+				m.debugLog(fmt.Sprintf("Merged cleanup: keeping %s: %v", pr.branch, err))
+				result.unmerged = append(result.unmerged, pr.branch)
+				continue
+			}
+
+			// Branches stacked on this one move to its parent when its config is cleaned up
+			children := stackChildren(m.configManager.GetStacks(m.repoPath), pr.branch)
+
+			if err := m.gitManager.RemoveWorktree(pr.path, false); err != nil {
+				result.failures = append(result.failures, fmt.Sprintf("%s: %v", pr.branch, err))
+				continue
+			}
+			// Squash-merged branches whose remote branch is gone look unmerged to git, keep them
+			if err := m.gitManager.DeleteMergedBranch(pr.branch); err != nil {
+				m.debugLog(fmt.Sprintf("Merged cleanup: keeping branch %s: %v", pr.branch, err))
+				result.keptBranch = append(result.keptBranch, pr.branch)
+			}
+			_ = m.configManager.CleanupBranch(m.repoPath, pr.branch) // Ignore error, not critical
+			_ = m.sessionManager.KillWorktreeSessions(m.sessionManager.SanitizeName(filepath.Base(m.repoPath), pr.branch))
+
+			// GitHub may have deleted the head branch already
+			if err := m.gitManager.DeleteRemoteBranch(m.repoPath, pr.branch); err != nil && !strings.Contains(err.Error(), "remote ref does not exist") {
+				m.debugLog(fmt.Sprintf("Merged cleanup: failed to delete remote branch %s: %v", pr.branch, err))
+			}
+
+			result.cleaned = append(result.cleaned, pr.branch)
+			result.restack = append(result.restack, children...)
+		}
+
+		// Bring the merged changes into the main repository when it's on the base branch
+		if len(result.cleaned) > 0 {
+			current, err := m.gitManager.GetCurrentBranch()
+			if err == nil && current == baseBranch {
+				if dirty, err := m.gitManager.HasUncommittedChanges(m.repoPath); err == nil && !dirty {
+					if err := m.gitManager.PullBranchInPath(m.repoPath, baseBranch); err != nil {
+						m.debugLog(fmt.Sprintf("Merged cleanup: failed to pull %s: %v", baseBranch, err))
+					} else {
+						result.pulledBase = true
+					}
+				}
+			}
+		}
+		return result
+	}
+}
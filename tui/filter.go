@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/git"
+)
+
+// worktreeSortOrders lists the selectable worktree list orders (cycled with 'O')
+var worktreeSortOrders = []string{"recent", "name", "status", "pr"}
+
+// worktreeSortLabel returns a human readable name for a sort order
+func worktreeSortLabel(order string) string {
+	switch order {
+	case "name":
+		return "name"
+	case "status":
+		return "ahead/behind"
+	case "pr":
+		return "PR status"
+	}
+	return "recently modified"
+}
+
+// worktreeFilter is a parsed main list filter query
+// Free text is fuzzy matched against branch, PR titles and beads issue titles,
+// "is:" tokens (is:dirty, is:behind, is:pr, is:waiting, is:attention) restrict by status
+type worktreeFilter struct {
+	terms     []string
+	dirty     bool
+	behind    bool
+	hasPR     bool
+	aiWaiting bool
+	attention bool
+}
+
+// parseWorktreeFilter parses a filter query such as "auth is:dirty"
+func parseWorktreeFilter(query string) worktreeFilter {
+	var f worktreeFilter
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		switch field {
+		case "is:dirty":
+			f.dirty = true
+		case "is:behind":
+			f.behind = true
+		case "is:pr", "has:pr":
+			f.hasPR = true
+		case "is:waiting", "is:ai":
+			f.aiWaiting = true
+		case "is:attention":
+			f.attention = true
+		default:
+			f.terms = append(f.terms, field)
+		}
+	}
+	return f
+}
+
+// isEmpty reports whether the filter matches every worktree
+func (f worktreeFilter) isEmpty() bool {
+	return len(f.terms) == 0 && !f.dirty && !f.behind && !f.hasPR && !f.aiWaiting && !f.attention
+}
+
+// matches reports whether a worktree passes the filter
+// Every term has to fuzzy match at least one of the searchable fields
+func (f worktreeFilter) matches(wt git.Worktree) bool {
+	prs, _ := wt.PRs.([]config.PRInfo)
+
+	if f.dirty && !wt.HasUncommitted {
+		return false
+	}
+	if f.behind && wt.BehindCount == 0 {
+		return false
+	}
+	if f.hasPR && len(prs) == 0 {
+		return false
+	}
+	if f.aiWaiting && !wt.AIWaiting {
+		return false
+	}
+	if f.attention && (len(prs) == 0 || !prNeedsAttention(prs[len(prs)-1])) {
+		return false
+	}
+
+	fields := []string{wt.Branch}
+	for _, pr := range prs {
+		fields = append(fields, pr.Title)
+	}
+	fields = append(fields, wt.IssueTitles...)
+
+	for _, term := range f.terms {
+		found := false
+		for _, field := range fields {
+			if fuzzyMatch(term, field) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyMatch reports whether all characters of pattern appear in text in order (case-insensitive)
+func fuzzyMatch(pattern, text string) bool {
+	pattern = strings.ToLower(pattern)
+	text = strings.ToLower(text)
+
+	p := []rune(pattern)
+	i := 0
+	for _, r := range text {
+		if i < len(p) && r == p[i] {
+			i++
+		}
+	}
+	return i == len(p)
+}
+
+// prNeedsAttention reports whether an open PR has failing checks, requested changes,
+// merge conflicts or unresolved review threads
+func prNeedsAttention(pr config.PRInfo) bool {
+	if !isOpenPR(pr) {
+		return false
+	}
+	return pr.Checks == "failing" || pr.ReviewDecision == "changes_requested" || pr.Mergeable == "conflicting" || pr.UnresolvedThreads > 0
+}
+
+// prStatusRank orders worktrees by their latest PR (open first, no PR last)
+func prStatusRank(wt git.Worktree) int {
+	prs, _ := wt.PRs.([]config.PRInfo)
+	if len(prs) == 0 {
+		return 4
+	}
+	switch prs[len(prs)-1].Status {
+	case "open":
+		return 0
+	case "draft":
+		return 1
+	case "merged":
+		return 2
+	}
+	return 3
+}
+
+// sortWorktreeSlice sorts worktrees by the given order, keeping the root worktree first
+// Ties are broken by last modified time (most recent first)
+func sortWorktreeSlice(worktrees []git.Worktree, order string) {
+	sort.SliceStable(worktrees, func(i, j int) bool {
+		a, b := worktrees[i], worktrees[j]
+
+		// Root worktree always comes first
+		if a.IsCurrent != b.IsCurrent {
+			return a.IsCurrent
+		}
+
+		switch order {
+		case "name":
+			return strings.ToLower(a.Branch) < strings.ToLower(b.Branch)
+		case "status":
+			if a.BehindCount != b.BehindCount {
+				return a.BehindCount > b.BehindCount
+			}
+			if a.AheadCount != b.AheadCount {
+				return a.AheadCount > b.AheadCount
+			}
+		case "pr":
+			if ra, rb := prStatusRank(a), prStatusRank(b); ra != rb {
+				return ra < rb
+			}
+		}
+
+		// Otherwise, sort by last modified time (most recent first)
+		return a.LastModified.After(b.LastModified)
+	})
+}
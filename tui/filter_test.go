@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/git"
+)
+
+// TestFuzzyMatch tests case-insensitive subsequence matching
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"auth", "feature/auth-login", true},
+		{"fal", "feature/auth-login", true},
+		{"FAL", "feature/auth-login", true},
+		{"lga", "feature/auth-login", false},
+		{"", "anything", true},
+	}
+
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestWorktreeFilter_Matches tests text and status filters
+func TestWorktreeFilter_Matches(t *testing.T) {
+	wt := git.Worktree{
+		Branch:         "fix-login",
+		HasUncommitted: true,
+		PRs:            []config.PRInfo{{Title: "Fix session timeout", Status: "open"}},
+		IssueTitles:    []string{"Users get logged out"},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"login", true},
+		{"timeout", true},    // PR title
+		{"logged out", true}, // beads issue title (both terms)
+		{"is:dirty", true},
+		{"is:pr login", true},
+		{"is:behind", false},
+		{"is:waiting", false},
+		{"is:attention", false},
+		{"payments", false},
+	}
+
+	for _, tt := range tests {
+		if got := parseWorktreeFilter(tt.query).matches(wt); got != tt.want {
+			t.Errorf("filter %q matches = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestWorktreeFilter_Attention tests filtering worktrees whose latest PR needs attention
+func TestWorktreeFilter_Attention(t *testing.T) {
+	filter := parseWorktreeFilter("is:attention")
+	tests := []struct {
+		pr   config.PRInfo
+		want bool
+	}{
+		{config.PRInfo{Status: "open", PRDetails: config.PRDetails{Checks: "failing"}}, true},
+		{config.PRInfo{Status: "open", PRDetails: config.PRDetails{ReviewDecision: "changes_requested"}}, true},
+		{config.PRInfo{Status: "draft", PRDetails: config.PRDetails{Mergeable: "conflicting"}}, true},
+		{config.PRInfo{Status: "open", PRDetails: config.PRDetails{UnresolvedThreads: 1}}, true},
+		{config.PRInfo{Status: "open", PRDetails: config.PRDetails{Checks: "passing", ReviewDecision: "approved"}}, false},
+		{config.PRInfo{Status: "merged", PRDetails: config.PRDetails{Checks: "failing"}}, false},
+	}
+	for _, tt := range tests {
+		wt := git.Worktree{Branch: "feature", PRs: []config.PRInfo{tt.pr}}
+		if got := filter.matches(wt); got != tt.want {
+			t.Errorf("is:attention matches %+v = %v, want %v", tt.pr, got, tt.want)
+		}
+	}
+}
+
+// TestMoveSelection_SkipsFilteredWorktrees tests navigation over a filtered list
+func TestMoveSelection_SkipsFilteredWorktrees(t *testing.T) {
+	m := setupTestModel()
+	m.worktrees = []git.Worktree{
+		{Branch: "main", IsCurrent: true},
+		{Branch: "api-docs"},
+		{Branch: "ui-theme"},
+		{Branch: "api-client"},
+	}
+	m.worktreeFilterInput.SetValue("api")
+	m.ensureSelectionVisible()
+
+	if m.selectedIndex != 1 {
+		t.Fatalf("Expected selection on first match (1), got %d", m.selectedIndex)
+	}
+	if !m.moveSelection(1) || m.selectedIndex != 3 {
+		t.Errorf("Expected down to skip filtered worktree and select 3, got %d", m.selectedIndex)
+	}
+	if m.moveSelection(1) {
+		t.Error("Expected no movement past the last visible worktree")
+	}
+}
+
+// TestSelectedWorktree_HiddenByFilter tests that a worktree the filter hides is never acted on
+// and that status updates keep the cursor on a visible worktree
+func TestSelectedWorktree_HiddenByFilter(t *testing.T) {
+	m := setupTestModel()
+	m.worktrees = []git.Worktree{
+		{Branch: "main", Path: "/repo", IsCurrent: true},
+		{Branch: "api", Path: "/repo/.workspaces/api", HasUncommitted: true},
+		{Branch: "ui", Path: "/repo/.workspaces/ui", HasUncommitted: true},
+	}
+	m.selectedIndex = 1
+	m.worktreeFilterInput.SetValue("is:dirty")
+	if wt := m.selectedWorktree(); wt == nil || wt.Branch != "api" {
+		t.Fatalf("Expected api selected, got %+v", wt)
+	}
+
+	// api gets committed: it no longer passes the filter, the cursor moves on to ui
+	resultModel, _ := m.Update(worktreeStatusUpdatedMsg{index: 1, path: "/repo/.workspaces/api"})
+	m = resultModel.(Model)
+	if wt := m.selectedWorktree(); wt == nil || wt.Branch != "ui" {
+		t.Errorf("Expected the cursor to move to ui, got %+v", wt)
+	}
+
+	m.selectedIndex = 1
+	if wt := m.selectedWorktree(); wt != nil {
+		t.Errorf("Expected no selection on a filtered out worktree, got %s", wt.Branch)
+	}
+}
+
+// TestSortWorktreeSlice tests the selectable sort orders
+func TestSortWorktreeSlice(t *testing.T) {
+	now := time.Now()
+	worktrees := []git.Worktree{
+		{Branch: "zeta", LastModified: now, BehindCount: 1},
+		{Branch: "main", IsCurrent: true},
+		{Branch: "alpha", LastModified: now.Add(-time.Hour), BehindCount: 5, PRs: []config.PRInfo{{Status: "open"}}},
+	}
+
+	sortWorktreeSlice(worktrees, "name")
+	if worktrees[0].Branch != "main" || worktrees[1].Branch != "alpha" {
+		t.Errorf("Expected root first then alpha, got %s, %s", worktrees[0].Branch, worktrees[1].Branch)
+	}
+
+	sortWorktreeSlice(worktrees, "recent")
+	if worktrees[1].Branch != "zeta" {
+		t.Errorf("Expected most recent (zeta) after root, got %s", worktrees[1].Branch)
+	}
+
+	sortWorktreeSlice(worktrees, "status")
+	if worktrees[1].Branch != "alpha" {
+		t.Errorf("Expected most behind (alpha) after root, got %s", worktrees[1].Branch)
+	}
+
+	sortWorktreeSlice(worktrees, "recent")
+	sortWorktreeSlice(worktrees, "pr")
+	if worktrees[1].Branch != "alpha" {
+		t.Errorf("Expected worktree with open PR (alpha) after root, got %s", worktrees[1].Branch)
+	}
+}
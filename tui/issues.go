@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/github"
+	"github.com/coollabsio/jean-tui/openai"
+	"github.com/coollabsio/jean-tui/session"
+)
+
+// issuesLoadedMsg carries a page of an issue search
+type issuesLoadedMsg struct {
+	search github.IssueSearch
+	page   *github.IssuePage
+	err    error
+}
+
+// issueSearchDebounceMsg fires after a keystroke in the issue picker's search
+type issueSearchDebounceMsg struct {
+	seq int
+}
+
+// issueBranchNamedMsg carries the AI generated branch name of a worktree created from an issue
+type issueBranchNamedMsg struct {
+	issue  github.Issue
+	branch string
+	err    error
+}
+
+// maxIssueBranchLength keeps branch names derived from long issue titles readable
+const maxIssueBranchLength = 50
+
+// openIssuePicker opens the issue picker on the issues assigned to the user
+func (m Model) openIssuePicker() (Model, tea.Cmd) {
+	m.modal = issueListModal
+	m.stackParent = ""
+	m.issueFilter = 0
+	m.issueLabel = ""
+	m.issueSearchInput.SetValue("")
+	m.issueSearchInput.Focus()
+	m.issues = nil
+	m.issueLoadingError = ""
+	m, cmd := m.searchIssues()
+	// Labels to filter by come with the repository metadata
+	return m, tea.Batch(cmd, m.loadRepoMetadata(m.repoPath))
+}
+
+// currentIssueSearch returns the first page of the search shown in the issue picker
+func (m Model) currentIssueSearch() github.IssueSearch {
+	return github.IssueSearch{
+		Text:   strings.TrimSpace(m.issueSearchInput.Value()),
+		Filter: github.IssueFilters[m.issueFilter],
+		Label:  m.issueLabel,
+	}
+}
+
+// sameIssueSearch reports whether two searches list the same issues, whatever their page
+func sameIssueSearch(a, b github.IssueSearch) bool {
+	return a.Text == b.Text && a.Filter == b.Filter && a.Label == b.Label
+}
+
+// searchIssues loads the first page of the current search
+func (m Model) searchIssues() (Model, tea.Cmd) {
+	m.issueListIndex = 0
+	m.issueLoadingError = ""
+	m.issueLoading = true
+	return m, m.loadIssues(m.currentIssueSearch())
+}
+
+// loadMoreIssues loads the next page of the current search, if there is one
+func (m Model) loadMoreIssues() (Model, tea.Cmd) {
+	if m.issueLoading || !m.issueHasMore {
+		return m, nil
+	}
+	search := m.currentIssueSearch()
+	search.After = m.issueCursor
+	m.issueLoading = true
+	return m, m.loadIssues(search)
+}
+
+func (m Model) loadIssues(search github.IssueSearch) tea.Cmd {
+	return func() tea.Msg {
+		m.debugLog(fmt.Sprintf("loadIssues() called - searching issues (filter=%q, label=%q, text=%q, after=%q)", search.Filter, search.Label, search.Text, search.After))
+		page, err := m.githubManager.SearchIssues(m.repoPath, search)
+		return issuesLoadedMsg{search: search, page: page, err: err}
+	}
+}
+
+// applyIssues shows a loaded page of issues, dropping pages of a search the user has moved on from
+func (m Model) applyIssues(msg issuesLoadedMsg) (Model, tea.Cmd) {
+	if m.modal != issueListModal || !sameIssueSearch(msg.search, m.currentIssueSearch()) {
+		return m, nil
+	}
+	m.issueLoading = false
+	if msg.err != nil {
+		m.issueLoadingError = msg.err.Error()
+		return m, m.showErrorNotification("Failed to load issues: "+msg.err.Error(), 4*time.Second)
+	}
+	if msg.search.After == "" {
+		m.issues = nil
+		m.issueListIndex = 0
+	}
+	m.issues = append(m.issues, msg.page.Issues...)
+	m.issueTotal = msg.page.Total
+	m.issueHasMore = msg.page.HasMore
+	m.issueCursor = msg.page.EndCursor
+	m.issueLoadingError = ""
+	return m, nil
+}
+
+// cycleIssueLabel filters the issue picker by the next label of the repository, then by none
+func (m Model) cycleIssueLabel() (Model, tea.Cmd) {
+	label, ok := m.nextRepoLabel(m.issueLabel)
+	if !ok {
+		return m, m.showInfoNotification("No labels to filter by")
+	}
+	m.issueLabel = label
+	return m.searchIssues()
+}
+
+// nextRepoLabel returns the repository label after current, "" after the last one. It reports
+// false when the repository metadata has no labels.
+func (m Model) nextRepoLabel(current string) (string, bool) {
+	if m.prRepoMetadata == nil || len(m.prRepoMetadata.Labels) == 0 {
+		return "", false
+	}
+	labels := m.prRepoMetadata.Labels
+	for i, label := range labels {
+		if label == current {
+			if i+1 < len(labels) {
+				return labels[i+1], true
+			}
+			return "", true
+		}
+	}
+	return labels[0], true
+}
+
+// issueBranchName derives a branch name from an issue's number and title, e.g. "42-fix-login-crash"
+func (m Model) issueBranchName(issue github.Issue, title string) string {
+	name := m.sessionManager.SanitizeBranchName(strings.ToLower(fmt.Sprintf("%d-%s", issue.Number, title)))
+	if len(name) > maxIssueBranchLength {
+		name = strings.TrimRight(name[:maxIssueBranchLength], "-_")
+	}
+	return name
+}
+
+// issuePrompt is the first prompt of the agent working on an issue
+func issuePrompt(issue github.Issue) string {
+	prompt := fmt.Sprintf("Work on GitHub issue #%d: %s\n%s", issue.Number, issue.Title, issue.URL)
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		prompt += "\n\n" + body
+	}
+	return prompt
+}
+
+// startIssueWorktree creates a worktree for an issue, named by the AI branch namer when enabled
+func (m Model) startIssueWorktree(issue github.Issue) (Model, tea.Cmd) {
+	m.modal = noModal
+	m.issueSearchInput.Blur()
+	if m.configManager != nil && m.configManager.HasActiveAIProvider(m.repoPath) && m.configManager.GetAIBranchNameEnabled() {
+		cmd := m.showInfoNotification(fmt.Sprintf("🤖 Naming branch for issue #%d...", issue.Number))
+		return m, tea.Batch(cmd, m.generateIssueBranchName(issue))
+	}
+	return m.createIssueWorktree(issue, m.issueBranchName(issue, issue.Title))
+}
+
+// generateIssueBranchName asks the AI provider (or its fallback) for a branch name describing an issue
+func (m Model) generateIssueBranchName(issue github.Issue) tea.Cmd {
+	return func() tea.Msg {
+		text := fmt.Sprintf("GitHub issue #%d: %s\n\n%s", issue.Number, issue.Title, issue.Body)
+		customPrompt := m.configManager.GetBranchNamePrompt()
+
+		var profiles []*config.AIProviderProfile
+		if profile := m.configManager.GetActiveProviderProfile(m.repoPath); profile != nil {
+			profiles = append(profiles, profile)
+		}
+		if fallback := m.configManager.GetFallbackProviderProfile(m.repoPath); fallback != nil {
+			profiles = append(profiles, fallback)
+		}
+
+		err := fmt.Errorf("AI provider not configured. Please configure an AI provider in settings")
+		for _, profile := range profiles {
+			var client *openai.Client
+			if client, err = openai.NewClient(profile.APIKey, profile.BaseURL, profile.Model); err != nil {
+				continue
+			}
+			var name string
+			if name, err = client.GenerateBranchName(text, customPrompt); err == nil {
+				return issueBranchNamedMsg{issue: issue, branch: name}
+			}
+		}
+		return issueBranchNamedMsg{issue: issue, err: err}
+	}
+}
+
+// createIssueWorktree creates a worktree on a new branch for an issue, linking the branch to the
+// issue and seeding its agent with the issue
+func (m Model) createIssueWorktree(issue github.Issue, branch string) (Model, tea.Cmd) {
+	if branch == "" {
+		return m, m.showWarningNotification("Issue title contains no valid branch characters")
+	}
+	path, err := m.gitManager.GetDefaultPath(branch)
+	if err != nil {
+		return m, m.showWarningNotification("Failed to generate workspace path")
+	}
+
+	m.stackParent = ""
+	create := m.createWorktreeWithSession(path, branch, true)
+	cmd := m.showInfoNotification(fmt.Sprintf("Creating worktree for issue #%d: %s\n  Path: %s", issue.Number, branch, path))
+	return m, tea.Batch(cmd, func() tea.Msg {
+		msg := create()
+		created, ok := msg.(worktreeCreatedWithSessionMsg)
+		if !ok || (created.err != nil && !strings.Contains(created.err.Error(), "setup script failed")) {
+			return msg
+		}
+		if m.configManager != nil {
+			info := &config.IssueInfo{Number: issue.Number, Title: issue.Title, URL: issue.URL}
+			if err := m.configManager.SetBranchIssue(m.repoPath, branch, info); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to link %s to issue #%d: %v", branch, issue.Number, err))
+			}
+		}
+		if err := session.WriteInitialPrompt(path, issuePrompt(issue)); err != nil {
+			m.debugLog(fmt.Sprintf("Failed to write initial prompt of %s: %v", branch, err))
+		}
+		return msg
+	})
+}
+
+// branchIssue returns the issue a branch was created for, nil if none
+func (m Model) branchIssue(branch string) *config.IssueInfo {
+	if m.configManager == nil {
+		return nil
+	}
+	return m.configManager.GetBranchIssue(m.repoPath, branch)
+}
+
+// withClosingReference makes a PR body close the issue its branch was created for
+func (m Model) withClosingReference(branch, body string) string {
+	if issue := m.branchIssue(branch); issue != nil {
+		return github.AddClosingReference(body, issue.Number)
+	}
+	return body
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
@@ -13,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/coollabsio/jean-tui/beads"
 	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/gc"
 	"github.com/coollabsio/jean-tui/git"
 	"github.com/coollabsio/jean-tui/github"
 	"github.com/coollabsio/jean-tui/internal/version"
@@ -30,6 +31,9 @@ type SwitchInfo struct {
 	ScriptCommand        string // If set, run this script command instead of shell/Claude
 	SessionName          string // Custom name for Claude session (for --session flag)
 	IsClaudeInitialized  bool   // Whether this Claude session has been initialized before
+	AgentWindow          string // Tmux window name of the AI coding agent (e.g. "claude")
+	AgentCommand         string // Command starting the agent, "" = plain shell (agent not installed)
+	Multiplexer          string // Terminal multiplexer hosting the session ("tmux" or "zellij")
 }
 
 type modalType int
@@ -66,6 +70,15 @@ const (
 	hookEditModal
 	configScopeSelectModal
 	configEditorModal
+	conflictResolutionModal
+	stashModal
+	refreshSummaryModal
+	bulkActionModal
+	sendPromptModal
+	gcModal
+	prReviewModal
+	issueListModal
+	ciModal
 )
 
 // NotificationType defines the type of notification
@@ -120,6 +133,34 @@ type Model struct {
 	// Branch status tracking
 	lastCreatedBranch string // Last created branch name (for auto-selection after creation)
 	lastRenamedBranch string // Last renamed branch name (for auto-selection after rename)
+	stackParent       string // Branch the worktree being created is stacked on, "" = base branch
+
+	// Worktree list filter and sort state
+	worktreeFilterInput  textinput.Model // Filter query for the main list (fuzzy text + is: tokens)
+	worktreeFilterActive bool            // Whether the filter input is focused
+	worktreeSort         string          // Sort order of the main list ("recent", "name", "status", "pr")
+	agentHooksInstalled  bool            // Whether agent state hooks are installed (disables pane scraping)
+
+	// Pane preview in the details panel
+	previewMode   string   // Window shown in the preview: "agent", "terminal" or "off"
+	previewPath   string   // Worktree path the preview lines belong to
+	previewWindow string   // Window the preview lines were captured from
+	previewLines  []string // Last lines of the window, with ANSI colors
+
+	// Windows of the session selected in the session list
+	sessionWindows      []sessionWindow
+	sessionWindowsOf    string                 // Session the windows belong to
+	sessionWindowIndex  int                    // Selected window
+	sessionWindowSample map[int]processSample // Previous resource sample per pane PID, for CPU usage
+
+	// Session restore after tmux server restarts
+	restorableSessions []session.SessionRecord // Recorded sessions of this repository that are not running
+	restoreOffered     bool                    // Whether the restore hint was already shown
+
+	// Agent notifications
+	notifier         *session.Notifier // Sends notifications when an agent starts waiting for input
+	agentsWaiting    map[string]bool   // worktree path -> whether its agent waited at the last activity check
+	terminalSequence string            // Notification escape sequences written with the next frame
 
 	// Activity tracking
 	lastActivityCheck     time.Time
@@ -134,8 +175,15 @@ type Model struct {
 	sessionNameInput       textinput.Model // Session name input for new worktree
 	commitSubjectInput     textinput.Model // Subject line for commit message
 	prTitleInput           textinput.Model // PR title input
-	prDescriptionInput     textinput.Model // PR description input
-	prModalFocused         int             // Which field in PR modal is focused (0=title, 1=description, 2=create, 3=cancel)
+	prDescriptionInput     textarea.Model  // PR description input, seeded from the repository's PR template
+	prModalFocused         int             // Which field in PR modal is focused (0=title, 1=description, 2=reviewers, 3=assignees, 4=labels, 5=milestone, 6=create, 7=cancel)
+	prMetadataInputs       [4]textinput.Model // Reviewers, assignees, labels and milestone of the new PR (comma-separated)
+	prCompletionIndex      int             // Highlighted completion of the focused metadata field
+	prTemplates            []github.PRTemplate // PR templates of the worktree
+	prTemplateIndex        int             // Template the description was seeded from
+	prRepoMetadata         *github.RepoMetadata // Users, labels and milestones to complete from, reloaded when the PR modal or issue picker opens
+	prCodeOwners           []github.CodeOwnerSignOff // Code owners whose review the PR's changes need
+	prCodeOwnersPath       string          // CODEOWNERS file prCodeOwners come from
 	prModalWorktreePath    string          // Worktree path for PR being created
 	prModalBranch          string          // Branch for PR being created
 	branchIndex            int
@@ -234,6 +282,42 @@ type Model struct {
 	prSearchInput  textinput.Model      // Search input for PR filtering
 	prLoadingError string               // Error message when loading PRs
 
+	// PR browser state (worktree from PR), searched on GitHub page by page
+	prFilter      int                       // Index in github.PRFilters
+	prLabel       string                    // Label the PRs are filtered by, "" = any
+	prLoading     bool                      // Whether a search or next page is being fetched
+	prSearchSeq   int                       // Incremented on every keystroke to debounce searches
+	prSearchCache map[string]*prSearchCache // Loaded pages by search, see prSearchKey
+
+	// Issue picker state (worktree from issue), searched on GitHub page by page
+	issueSearchInput  textinput.Model // Search input of the issue picker
+	issues            []github.Issue  // Issues loaded so far for the current search
+	issueListIndex    int             // Selected issue index
+	issueFilter       int             // Index in github.IssueFilters
+	issueLabel        string          // Label the issues are filtered by, "" = any
+	issueLoading      bool            // Whether a search or next page is being fetched
+	issueLoadingError string          // Error message when loading issues
+	issueSearchSeq    int             // Incremented on every keystroke to debounce searches
+	issueHasMore      bool            // Whether another page can be loaded
+	issueCursor       string          // Cursor of the next page
+	issueTotal        int             // Number of issues matching the search
+
+	// CI modal state (workflow runs of a worktree's branch)
+	ciWorktree  git.Worktree         // Worktree whose branch the runs belong to, its agent gets failures sent as prompts
+	ciRuns      []github.WorkflowRun // Latest runs, newest first
+	ciJobs      []github.WorkflowJob // Jobs of the selected run
+	ciJobsRunID int64                // Run the jobs are loaded for
+	ciLoading   bool
+	ciErr       error
+	ciRunIndex  int                 // Selected run
+	ciJobIndex  int                 // Selected job
+	ciFocusJobs bool                // Whether up/down move through the jobs instead of the runs
+	ciPollSeq   int                 // Incremented whenever a refresh is scheduled, only the latest tick refreshes
+	ciLogJob    *github.WorkflowJob // Job shown in the log view, nil = run list
+	ciLogLines  []string            // Cleaned log of ciLogJob
+	ciLogErr    error
+	ciLogScroll int // Lines scrolled up from the end of the log
+
 	// Local merge modal state
 	localMergeBranch     string // Branch being merged (worktree branch)
 	localMergeTarget     string // Target branch (base branch)
@@ -242,6 +326,8 @@ type Model struct {
 	localMergeBehind     int    // Number of commits behind
 	localMergeFocused    int    // Which button is focused (0=confirm, 1=cancel)
 	postMergeDeleteIndex int    // Selected option in post-merge cleanup (0=delete, 1=keep)
+	postMergePR          *mergedPR  // PR merged on GitHub shown in the post-merge cleanup, nil = after a local merge
+	mergedCleanupQueue   []mergedPR // PRs merged on GitHub waiting for the post-merge cleanup
 
 	// PR state settings modal state
 	prStateSettingsCursor int // Selected PR state (0=draft, 1=ready for review)
@@ -289,6 +375,81 @@ type Model struct {
 	configEditorPath     string               // Path to config file being edited
 	configEditorInProgress bool               // Whether config editor is currently running
 	configEditorStatus   string               // Status message for config editor (error/success)
+
+	// Conflict resolution modal state
+	conflictPath           string            // Path (worktree or main repo) with the in-progress merge/rebase
+	conflictOperation      string            // Operation in progress ("merge" or "rebase")
+	conflictFiles          []string          // Files with unresolved conflicts
+	conflictFileIndex      int               // Selected file in the conflict list
+	conflictFile           *git.ConflictFile // File being resolved hunk by hunk (nil = file list view)
+	conflictHunkIndex      int               // Selected hunk in the current file
+	conflictFromLocalMerge bool              // Whether conflicts came from a local merge (show cleanup modal after continuing)
+	conflictStatus         string            // Status message for conflict modal (error/success)
+	generatingConflictFix  bool              // Whether we're currently asking the AI for a resolution
+
+	// Stash modal state
+	stashes           []git.Stash     // All stashes of the repository
+	stashIndex        int             // Selected stash in the visible list
+	stashWorktreePath string          // Worktree the stash modal was opened for
+	stashBranch       string          // Branch of that worktree (used to filter stashes)
+	stashShowAll      bool            // Show stashes from all branches instead of only stashBranch
+	stashMessageInput textinput.Model // Message input for a new stash
+	stashInputActive  bool            // Whether the stash message input is focused
+	stashConfirmDrop  bool            // Whether the next 'd' confirms dropping the selected stash
+	stashStatus       string          // Status message for stash modal (error/success)
+
+	// Refresh summary modal state
+	refreshResults      []refreshResult // Per-worktree outcomes of the last refresh
+	refreshSummaryIndex int             // Selected row in the refresh summary modal
+
+	// Multi-select and bulk action state
+	markedWorktrees map[string]bool // Worktree paths marked with space for bulk actions
+	bulkActionIndex int             // Selected action in the bulk action menu
+	bulkAction      string          // Chosen bulk action ("delete", "pull", "push", "kill")
+	bulkStage       int             // 0=choose action, 1=confirm, 2=running, 3=results
+	bulkForce       bool            // Force-delete marked worktrees with uncommitted changes
+	bulkResults     []bulkResult    // Per-worktree results of the last bulk action
+
+	// Send prompt modal state
+	promptInput   textarea.Model // Prompt typed into the agent windows
+	promptTargets []git.Worktree // Worktrees the prompt is sent to (marked worktrees or the selected one)
+
+	// Garbage collection modal state
+	gcStage   int         // 0=scanning, 1=confirm, 2=cleaning, 3=results
+	gcItems   []gc.Item   // Garbage found by the dry run
+	gcResults []gc.Result // Per-item results of the cleanup
+	gcErr     error       // Error of the dry run
+
+	// PR review threads modal state
+	reviewPR           config.PRInfo         // PR the threads belong to
+	reviewWorktree     git.Worktree          // Worktree of the PR, its agent gets threads sent as prompts
+	reviewThreads      []github.ReviewThread // All review threads of the PR
+	reviewLoading      bool
+	reviewErr          error
+	reviewIndex        int            // Selected thread among the visible ones
+	reviewShowResolved bool           // Whether resolved threads are listed
+	reviewReplying     bool           // Whether the reply editor is open
+	reviewReplyInput   textarea.Model // Reply to the selected thread
+}
+
+// bulkActions lists the actions available for marked worktrees
+var bulkActions = []struct {
+	id          string
+	name        string
+	description string
+}{
+	{"delete", "Delete worktrees", "Remove worktrees, delete their branches and kill their sessions"},
+	{"pull", "Pull from base branch", "Merge the base branch into each worktree"},
+	{"push", "Push to remote", "Push each branch to origin"},
+	{"kill", "Kill sessions", "Kill the tmux session of each worktree"},
+}
+
+// bulkResult is the outcome of a bulk action for a single worktree
+type bulkResult struct {
+	branch  string
+	path    string
+	skipped string // Reason the worktree was skipped ("" if the action ran)
+	err     error
 }
 
 // NewModel creates a new TUI model
@@ -324,10 +485,21 @@ func NewModel(repoPath string, autoClaude bool) Model {
 	prTitleInput.CharLimit = 72
 	prTitleInput.Width = 70
 
-	prDescriptionInput := textinput.New()
-	prDescriptionInput.Placeholder = "PR description (optional, explain what and why)"
-	prDescriptionInput.CharLimit = 500
-	prDescriptionInput.Width = 70
+	prDescriptionInput := textarea.New()
+	prDescriptionInput.Placeholder = "PR description (optional, explain what and why, alt+enter for a new line)"
+	prDescriptionInput.CharLimit = 20000
+	prDescriptionInput.SetWidth(70)
+	prDescriptionInput.SetHeight(6)
+	prDescriptionInput.ShowLineNumbers = false
+	prDescriptionInput.KeyMap.InsertNewline.SetKeys("alt+enter", "ctrl+j")
+
+	var prMetadataInputs [4]textinput.Model
+	for i, placeholder := range []string{"alice, org/team", "alice", "bug, enhancement", "v1.0"} {
+		prMetadataInputs[i] = textinput.New()
+		prMetadataInputs[i].Placeholder = placeholder
+		prMetadataInputs[i].CharLimit = 500
+		prMetadataInputs[i].Width = 58
+	}
 
 	aiAPIKeyInput := textinput.New()
 	aiAPIKeyInput.Placeholder = "sk-or-..."
@@ -340,6 +512,11 @@ func NewModel(repoPath string, autoClaude bool) Model {
 	prSearchInput.CharLimit = 100
 	prSearchInput.Width = 50
 
+	issueSearchInput := textinput.New()
+	issueSearchInput.Placeholder = "Search issues by #number, title or qualifiers like milestone:v1..."
+	issueSearchInput.CharLimit = 100
+	issueSearchInput.Width = 50
+
 	// Initialize AI prompt textareas (for customizing prompts)
 	aiPromptCommitInput := textarea.New()
 	aiPromptCommitInput.Placeholder = "Commit message prompt (must contain {diff})"
@@ -359,6 +536,22 @@ func NewModel(repoPath string, autoClaude bool) Model {
 	aiPromptPRInput.SetWidth(100)
 	aiPromptPRInput.SetHeight(5)
 
+	promptInput := textarea.New()
+	promptInput.Placeholder = "Prompt for the agent (enter to send, alt+enter for a new line)"
+	promptInput.CharLimit = 4000
+	promptInput.SetWidth(80)
+	promptInput.SetHeight(5)
+	promptInput.ShowLineNumbers = false
+	promptInput.KeyMap.InsertNewline.SetKeys("alt+enter", "ctrl+j")
+
+	reviewReplyInput := textarea.New()
+	reviewReplyInput.Placeholder = "Reply (enter to send, alt+enter for a new line)"
+	reviewReplyInput.CharLimit = 4000
+	reviewReplyInput.SetWidth(80)
+	reviewReplyInput.SetHeight(4)
+	reviewReplyInput.ShowLineNumbers = false
+	reviewReplyInput.KeyMap.InsertNewline.SetKeys("alt+enter", "ctrl+j")
+
 	// Initialize hooks text inputs
 	hookNameInput := textinput.New()
 	hookNameInput.Placeholder = "Hook name (e.g., 'Install dependencies')"
@@ -370,6 +563,17 @@ func NewModel(repoPath string, autoClaude bool) Model {
 	hookCommandInput.CharLimit = 500
 	hookCommandInput.Width = 70
 
+	worktreeFilterInput := textinput.New()
+	worktreeFilterInput.Placeholder = "Filter (is:dirty is:behind is:pr is:waiting is:attention)"
+	worktreeFilterInput.Prompt = "/ "
+	worktreeFilterInput.CharLimit = 100
+	worktreeFilterInput.Width = 40
+
+	stashMessageInput := textinput.New()
+	stashMessageInput.Placeholder = "Stash message (optional)"
+	stashMessageInput.CharLimit = 100
+	stashMessageInput.Width = 50
+
 	// Initialize config manager (ignore errors, will use defaults)
 	configManager, _ := config.NewManager()
 
@@ -421,13 +625,21 @@ func NewModel(repoPath string, autoClaude bool) Model {
 		commitSubjectInput: commitSubjectInput,
 		prTitleInput:       prTitleInput,
 		prDescriptionInput: prDescriptionInput,
+		prMetadataInputs:   prMetadataInputs,
 		aiAPIKeyInput:      aiAPIKeyInput,
 		prSearchInput:      prSearchInput,
+		issueSearchInput:   issueSearchInput,
 		aiPromptCommitInput: aiPromptCommitInput,
 		aiPromptBranchInput: aiPromptBranchInput,
 		aiPromptPRInput:     aiPromptPRInput,
 		hookNameInput:       hookNameInput,
 		hookCommandInput:    hookCommandInput,
+		stashMessageInput:   stashMessageInput,
+		promptInput:         promptInput,
+		reviewReplyInput:    reviewReplyInput,
+		worktreeFilterInput: worktreeFilterInput,
+		worktreeSort:        "recent",
+		previewMode:         "agent",
 		aiModels:            aiModels,
 		autoClaude:         autoClaude,
 		repoPath:           absoluteRepoPath,
@@ -443,7 +655,13 @@ func NewModel(repoPath string, autoClaude bool) Model {
 	if configManager != nil {
 		m.aiCommitEnabled = configManager.GetAICommitEnabled()
 		m.aiBranchNameEnabled = configManager.GetAIBranchNameEnabled()
+		m.worktreeSort = configManager.GetWorktreeSort(absoluteRepoPath)
+		m.applyAgent()
+		m.sessionManager.SetMultiplexer(session.NewMultiplexer(configManager.GetMultiplexer()))
 	}
+	m.agentHooksInstalled, _ = m.sessionManager.HasAgentStateHooks()
+	m.agentsWaiting = make(map[string]bool)
+	m.applyNotificationConfig()
 
 	return m
 }
@@ -482,6 +700,7 @@ func (m Model) Init() tea.Cmd {
 		m.loadSessions(),
 		m.initializeBeads(), // Auto-initialize beads
 		m.scheduleActivityCheck(),
+		m.schedulePanePreview(),
 		m.checkForUpdates(),
 		tea.EnterAltScreen,
 	)
@@ -504,8 +723,13 @@ type (
 	}
 
 	prsLoadedMsg struct {
-		prs []github.PRInfo
-		err error
+		search github.PRSearch
+		page   *github.PRPage
+		err    error
+	}
+
+	prSearchDebounceMsg struct {
+		seq int
 	}
 
 	prDetailsLoadedForBranchMsg struct {
@@ -536,10 +760,13 @@ type (
 
 	worktreeStatusUpdatedMsg struct {
 		index         int   // Index of worktree in list
+		path          string // Worktree path (used if the list was re-sorted meanwhile)
 		hasUncommitted bool
 		aheadCount    int
 		behindCount   int
 		aiWaiting     bool  // Whether Claude is waiting for input
+		agentState    string // Agent state reported by hooks, "" = unknown
+		agentMessage  string // Last message reported by the agent
 		ports         []int // Active ports parsed from config files
 		err           error
 	}
@@ -584,11 +811,13 @@ type (
 		prTitle      string // PR title for storing in config
 		author       string // PR author for storing in config
 		isDraft      bool   // Whether the PR is a draft
+		metadataErr  error  // Failure to set the reviewers, assignees, labels or milestone of the created PR
 	}
 
 	branchPulledMsg struct {
-		err         error
-		hadConflict bool
+		err          error
+		hadConflict  bool
+		worktreePath string // Worktree where the merge happened (used to resolve conflicts)
 	}
 
 	localMergePreparedMsg struct {
@@ -603,26 +832,126 @@ type (
 	localMergeCompletedMsg struct {
 		branch       string // Branch that was merged
 		worktreePath string // Worktree path
+		mergePath    string // Path where the merge happened (main repo)
 		err          error
 		hadConflict  bool   // Whether there was a merge conflict
 	}
 
+	conflictsLoadedMsg struct {
+		path      string
+		operation string   // "merge", "rebase" or "" when nothing is in progress
+		files     []string // Files with unresolved conflicts
+		err       error
+	}
+
+	conflictFileLoadedMsg struct {
+		file *git.ConflictFile
+		err  error
+	}
+
+	conflictSuggestionMsg struct {
+		path       string // File the suggestion is for
+		hunkIndex  int
+		resolution string
+		err        error
+	}
+
+	conflictFileResolvedMsg struct {
+		path string
+		err  error
+	}
+
+	mergeContinuedMsg struct {
+		err error
+	}
+
+	mergeAbortedMsg struct {
+		err error
+	}
+
+	bulkActionCompletedMsg struct {
+		action  string
+		results []bulkResult
+	}
+
+	promptSentMsg struct {
+		results []bulkResult
+	}
+
+	gcScannedMsg struct {
+		items []gc.Item
+		err   error
+	}
+
+	gcCleanedMsg struct {
+		results []gc.Result
+	}
+
+	panePreviewTickMsg struct{}
+
+	panePreviewMsg struct {
+		path   string
+		window string
+		lines  []string
+	}
+
+	sessionWindowsMsg struct {
+		sessionName string
+		windows     []session.WindowInfo
+		stats       map[int]session.ProcessStats // Keyed by pane PID, missing when /proc isn't available
+		sampledAt   time.Time
+		err         error
+	}
+
+	reviewThreadsLoadedMsg struct {
+		prURL   string
+		threads []github.ReviewThread
+		err     error
+	}
+
+	reviewThreadUpdatedMsg struct {
+		prURL  string
+		action string // "reply", "resolve" or "unresolve"
+		err    error
+	}
+
+	sessionWindowActionMsg struct {
+		sessionName string
+		action      string // "kill" or "restart"
+		window      string
+		err         error
+	}
+
 	refreshWithPullMsg struct {
 		err               error
 		fetchedCommits    int             // Total commits fetched from remote
 		updatedBranches   map[string]int  // Branch name -> commits pulled
 		upToDate          bool            // Whether everything was already up to date
 		mergedBaseBranch  bool            // Whether base branch was merged into selected worktree
-		pullErr           error           // Error from pulling the main repo branch (non-blocking)
+		results           []refreshResult // Per-worktree outcome of the pull
+	}
+
+	stashesLoadedMsg struct {
+		stashes []git.Stash
+		err     error
+	}
+
+	stashActionMsg struct {
+		action string // "push", "pop", "apply" or "drop"
+		ref    string
+		err    error
 	}
 
 	activityTickMsg time.Time
 
 	activityCheckedMsg struct {
-		sessions []session.Session
-		err      error
+		sessions    []session.Session
+		agentStates []agentStateUpdate
+		err         error
 	}
 
+	terminalSequenceWrittenMsg struct{}
+
 	commitCreatedMsg struct {
 		err        error
 		commitHash string
@@ -708,7 +1037,8 @@ type (
 	}
 
 	prStatusesRefreshedMsg struct {
-		err error
+		merged []mergedPR // Worktrees whose PR flipped to merged
+		err    error
 	}
 
 	// Push-only messages (without PR creation)
@@ -764,6 +1094,7 @@ func (m Model) loadWorktrees() tea.Cmd {
 					worktrees[i].OpenIssues = summary.OpenCount
 					worktrees[i].ClosedIssues = summary.ClosedCount
 					worktrees[i].HasBeads = summary.TotalCount > 0
+					worktrees[i].IssueTitles = summary.Titles
 				}
 			}
 		}
@@ -798,18 +1129,14 @@ func (m Model) loadWorktreeStatus(index int, worktree git.Worktree) tea.Cmd {
 		aheadCount := 0
 		behindCount := 0
 		if m.baseBranch != "" && !strings.HasPrefix(worktree.Branch, "(detached") {
-			if ahead, behind, err := m.gitManager.GetBranchStatus(worktree.Path, worktree.Branch, m.baseBranch); err == nil {
+			if ahead, behind, err := m.gitManager.GetBranchStatus(worktree.Path, worktree.Branch, m.baseBranchFor(worktree.Branch)); err == nil {
 				aheadCount = ahead
 				behindCount = behind
 			}
 		}
 
 		// Detect AI session status (non-blocking, failures are silent)
-		aiWaiting := false
-		if worktree.ClaudeSessionName != "" {
-			aiDetector := session.NewAIStatusDetector()
-			aiWaiting = aiDetector.DetectAISessionState(worktree.ClaudeSessionName)
-		}
+		aiWaiting, agentState, agentMessage := m.detectAgentState(worktree)
 
 		// Parse ports from config files (non-blocking, failures are silent)
 		ports := []int{}
@@ -820,35 +1147,126 @@ func (m Model) loadWorktreeStatus(index int, worktree git.Worktree) tea.Cmd {
 
 		return worktreeStatusUpdatedMsg{
 			index:          index,
+			path:           worktree.Path,
 			hasUncommitted: hasUncommitted,
 			aheadCount:     aheadCount,
 			behindCount:    behindCount,
 			aiWaiting:      aiWaiting,
+			agentState:     agentState,
+			agentMessage:   agentMessage,
 			ports:          ports,
 			err:            nil,
 		}
 	}
 }
 
+// detectAgentState returns whether the worktree's agent waits for input, plus its state and last message
+// Hook-reported state (see "jean agent-state") is used when available, pane scraping otherwise
+func (m Model) detectAgentState(worktree git.Worktree) (bool, string, string) {
+	if worktree.ClaudeSessionName == "" {
+		return false, "", ""
+	}
+
+	if state, err := session.ReadAgentState(worktree.Path); err == nil && state != nil {
+		// A state left behind by a killed session is stale
+		if !m.sessionManager.SessionExists(worktree.ClaudeSessionName) {
+			return false, "", ""
+		}
+		waiting := state.State == session.AgentWaiting || state.State == session.AgentIdle
+		return waiting, state.State, state.Message
+	}
+
+	// With hooks installed a missing state means the agent hasn't run yet, don't guess from the pane
+	agent := m.sessionManager.Agent()
+	if m.agentHooksInstalled && len(agent.WaitingPatterns) == 0 {
+		return false, "", ""
+	}
+
+	return m.sessionManager.StatusDetector().DetectAISessionState(worktree.ClaudeSessionName), "", ""
+}
+
 func (m Model) loadBranches() tea.Msg {
 	branches, err := m.gitManager.ListBranches()
 	return branchesLoadedMsg{branches: branches, err: err}
 }
 
-func (m Model) loadPRs() tea.Cmd {
+func (m Model) loadPRs(search github.PRSearch) tea.Cmd {
 	return func() tea.Msg {
-		m.debugLog("loadPRs() called - fetching PRs from GitHub for repo: " + m.repoPath)
-		prs, err := m.githubManager.ListPRs(m.repoPath)
+		m.debugLog(fmt.Sprintf("loadPRs() called - searching PRs on GitHub for repo: %s (filter=%q, label=%q, text=%q, after=%q)", m.repoPath, search.Filter, search.Label, search.Text, search.After))
+		page, err := m.githubManager.SearchPRs(m.repoPath, search)
 		if err != nil {
 			m.debugLog("loadPRs() failed with error: " + err.Error())
 		} else {
-			m.debugLog(fmt.Sprintf("loadPRs() succeeded - loaded %d PRs", len(prs)))
-			for i, pr := range prs {
-				m.debugLog(fmt.Sprintf("  PR[%d]: #%d - %s (branch: %s, url: %s)", i, pr.Number, pr.Title, pr.HeadRefName, pr.URL))
-			}
+			m.debugLog(fmt.Sprintf("loadPRs() succeeded - loaded %d of %d PRs", len(page.PRs), page.Total))
 		}
-		return prsLoadedMsg{prs: prs, err: err}
+		return prsLoadedMsg{search: search, page: page, err: err}
+	}
+}
+
+// prSearchCacheTTL is how long searched PR pages are reused before searching GitHub again
+const prSearchCacheTTL = 2 * time.Minute
+
+// prSearchDebounce is how long the PR browser waits after the last keystroke before searching
+const prSearchDebounce = 300 * time.Millisecond
+
+// prSearchCache holds the pages of a PR search loaded so far
+type prSearchCache struct {
+	prs       []github.PRInfo
+	total     int
+	hasMore   bool
+	cursor    string
+	fetchedAt time.Time
+}
+
+// currentPRSearch returns the first page of the search shown in the PR browser
+func (m Model) currentPRSearch() github.PRSearch {
+	return github.PRSearch{
+		Text:   strings.TrimSpace(m.prSearchInput.Value()),
+		Filter: github.PRFilters[m.prFilter],
+		Label:  m.prLabel,
+	}
+}
+
+// prSearchKey identifies a search in the cache, whatever page is requested
+func prSearchKey(search github.PRSearch) string {
+	return search.Filter + "\x00" + search.Label + "\x00" + search.Text
+}
+
+// searchPRs shows the first page of the current search, from the cache when fresh enough
+func (m Model) searchPRs(refresh bool) (Model, tea.Cmd) {
+	search := m.currentPRSearch()
+	m.prListIndex = 0
+	m.prLoadingError = ""
+	if cached := m.prSearchCache[prSearchKey(search)]; cached != nil && !refresh && time.Since(cached.fetchedAt) < prSearchCacheTTL {
+		m.prs = cached.prs
+		m.filteredPRs = cached.prs
+		m.prLoading = false
+		return m, nil
+	}
+	m.prLoading = true
+	return m, m.loadPRs(search)
+}
+
+// loadMorePRs fetches the next page of the current search, if there is one
+func (m Model) loadMorePRs() (Model, tea.Cmd) {
+	search := m.currentPRSearch()
+	cached := m.prSearchCache[prSearchKey(search)]
+	if m.prLoading || cached == nil || !cached.hasMore {
+		return m, nil
 	}
+	search.After = cached.cursor
+	m.prLoading = true
+	return m, m.loadPRs(search)
+}
+
+// cyclePRLabel filters the PR browser by the next label of the repository, then by none
+func (m Model) cyclePRLabel() (Model, tea.Cmd) {
+	label, ok := m.nextRepoLabel(m.prLabel)
+	if !ok {
+		return m, m.showInfoNotification("No labels to filter by")
+	}
+	m.prLabel = label
+	return m.searchPRs(false)
 }
 
 // loadPRDetailsForBranch fetches PR details for a specific branch from GitHub
@@ -904,13 +1322,30 @@ func (m Model) createWorktreeWithSession(path, sessionName string, newBranch boo
 			return worktreeCreatedWithSessionMsg{err: err, path: path, branch: sessionName, sessionName: sessionName}
 		}
 
-		// Use base branch when creating new branch
+		// Use base branch when creating new branch, or the branch it is stacked on
 		baseBranch := ""
 		if newBranch {
 			baseBranch = m.baseBranch
+			if m.stackParent != "" {
+				baseBranch = m.stackParent
+			}
+		}
+
+		// Remember where the parent was so restacking only moves this branch's own commits
+		forkPoint := ""
+		if newBranch && m.stackParent != "" {
+			var err error
+			if forkPoint, err = m.gitManager.ResolveRef(m.stackParent); err != nil {
+				return worktreeCreatedWithSessionMsg{err: err, path: path, branch: sessionName, sessionName: sessionName}
+			}
 		}
 
 		err := m.gitManager.Create(path, sessionName, newBranch, baseBranch)
+		if forkPoint != "" && (err == nil || strings.Contains(err.Error(), "setup script failed")) {
+			if saveErr := m.configManager.SetStackEntry(m.repoPath, sessionName, &config.StackEntry{Parent: m.stackParent, ForkPoint: forkPoint}); saveErr != nil {
+				m.debugLog(fmt.Sprintf("Failed to save stack parent of %s: %v", sessionName, saveErr))
+			}
+		}
 		return worktreeCreatedWithSessionMsg{err: err, path: path, branch: sessionName, sessionName: sessionName}
 	}
 }
@@ -929,10 +1364,10 @@ func (m Model) deleteWorktree(path, branch string, force bool) tea.Cmd {
 			_ = m.configManager.CleanupBranch(m.repoPath, branch) // Ignore error, not critical
 		}
 
-		// Then kill the associated tmux session if it exists
+		// Then kill the associated tmux sessions if they exist
 		repoName := filepath.Base(m.repoPath)
 		sessionName := m.sessionManager.SanitizeName(repoName, branch)
-		_ = m.sessionManager.Kill(sessionName) // Ignore error if session doesn't exist
+		_ = m.sessionManager.KillWorktreeSessions(sessionName) // Ignore error, the worktree is gone already
 
 		return worktreeDeletedMsg{err: nil}
 	}
@@ -994,6 +1429,16 @@ func (m Model) renameBranch(oldName, newName, worktreePath string) tea.Cmd {
 			}
 		}
 
+		// Keep the branch in its stack and linked to its issue under the new name
+		if m.configManager != nil {
+			if err := m.configManager.RenameStackBranch(m.repoPath, oldName, newName); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to rename stack entry of %s: %v", oldName, err))
+			}
+			if err := m.configManager.RenameBranchIssue(m.repoPath, oldName, newName); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to rename issue link of %s: %v", oldName, err))
+			}
+		}
+
 		// Success: branch renamed, directory path unchanged
 		return branchRenamedMsg{
 			oldBranch: oldName,
@@ -1202,7 +1647,7 @@ func (m Model) reloadConfig() tea.Cmd {
 	}
 }
 
-func (m Model) createPR(worktreePath, branch string, optionalTitle string, optionalDescription string) tea.Cmd {
+func (m Model) createPR(worktreePath, branch string, optionalTitle string, optionalDescription string, metadata github.PRMetadata) tea.Cmd {
 	return func() tea.Msg {
 		// Check if it's a GitHub repo
 		isGitHub, err := m.gitManager.IsGitHubRepo()
@@ -1227,6 +1672,10 @@ func (m Model) createPR(worktreePath, branch string, optionalTitle string, optio
 			return prCreatedMsg{err: fmt.Errorf("no commits to create PR"), isDraft: m.prIsDraft}
 		}
 
+		if err := m.pushStackParent(worktreePath, branch); err != nil {
+			return prCreatedMsg{err: err, isDraft: m.prIsDraft}
+		}
+
 		// Check if remote branch exists
 		remoteBranchExists, err := m.gitManager.RemoteBranchExists(worktreePath, branch)
 		if err != nil {
@@ -1262,11 +1711,11 @@ func (m Model) createPR(worktreePath, branch string, optionalTitle string, optio
 			title = strings.Title(title)
 		}
 
-		// Use provided description or default to empty
-		description := optionalDescription
+		// Use provided description or default to empty, closing the issue the branch was created for
+		description := m.withClosingReference(branch, optionalDescription)
 
 		// Create PR (draft or ready for review based on user selection)
-		prURL, err := m.githubManager.CreatePR(worktreePath, branch, m.baseBranch, title, description, m.prIsDraft)
+		prURL, err := m.githubManager.CreatePR(worktreePath, branch, m.baseBranchFor(branch), title, description, m.prIsDraft)
 		if err != nil {
 			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath}
 		}
@@ -1277,7 +1726,13 @@ func (m Model) createPR(worktreePath, branch string, optionalTitle string, optio
 			author = user
 		}
 
-		return prCreatedMsg{prURL: prURL, branch: branch, worktreePath: worktreePath, prTitle: title, author: author, isDraft: m.prIsDraft}
+		// Request reviews and set assignees, labels and milestone on the new PR
+		var metadataErr error
+		if !metadata.IsEmpty() {
+			metadataErr = m.githubManager.SetPRMetadata(prURL, metadata)
+		}
+
+		return prCreatedMsg{prURL: prURL, branch: branch, worktreePath: worktreePath, prTitle: title, author: author, isDraft: m.prIsDraft, metadataErr: metadataErr}
 	}
 }
 
@@ -1306,6 +1761,7 @@ func (m Model) createOrUpdatePR(worktreePath, branch string, title string, descr
 		}
 
 		// If PR exists, update it instead of creating a new one
+		// The description is left as written, the closing reference was added on creation
 		if existingPR != nil {
 			if err := m.githubManager.UpdatePR(worktreePath, branch, title, description); err != nil {
 				return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath, isDraft: m.prIsDraft}
@@ -1314,7 +1770,12 @@ func (m Model) createOrUpdatePR(worktreePath, branch string, title string, descr
 		}
 
 		// PR doesn't exist, create a new one (draft or ready for review based on user selection)
-		prURL, err := m.githubManager.CreatePR(worktreePath, branch, m.baseBranch, title, description, m.prIsDraft)
+		// Close the issue the branch was created for when the PR merges
+		description = m.withClosingReference(branch, description)
+		if err := m.pushStackParent(worktreePath, branch); err != nil {
+			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath, isDraft: m.prIsDraft}
+		}
+		prURL, err := m.githubManager.CreatePR(worktreePath, branch, m.baseBranchFor(branch), title, description, m.prIsDraft)
 		if err != nil {
 			return prCreatedMsg{err: err, branch: branch, worktreePath: worktreePath, isDraft: m.prIsDraft}
 		}
@@ -1637,6 +2098,16 @@ func (m Model) renameBranchForPR(oldName, newName, worktreePath string) tea.Cmd
 			}
 		}
 
+		// Keep the branch in its stack and linked to its issue under the new name
+		if m.configManager != nil {
+			if err := m.configManager.RenameStackBranch(m.repoPath, oldName, newName); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to rename stack entry of %s: %v", oldName, err))
+			}
+			if err := m.configManager.RenameBranchIssue(m.repoPath, oldName, newName); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to rename issue link of %s: %v", oldName, err))
+			}
+		}
+
 		// Step 2: Rename directory if it's a workspace worktree
 		workspacesDir, err := m.gitManager.GetWorkspacesDir()
 		if err == nil && strings.HasPrefix(worktreePath, workspacesDir) {
@@ -1713,16 +2184,24 @@ func (m Model) generatePRContent(worktreePath, branchName, baseBranch string) te
 			}
 		}
 
+		// Follow the template picked in the PR modal, or the repository's first template
+		template := ""
+		if m.modal == prContentModal {
+			template = m.selectedPRTemplate()
+		} else if templates := github.LoadPRTemplates(worktreePath); len(templates) > 0 {
+			template = templates[0].Body
+		}
+
 		// Call AI API to generate title and description
 		customPrompt := m.configManager.GetPRPrompt()
-		title, description, err := client.GeneratePRContent(diff, customPrompt)
+		title, description, err := client.GeneratePRContentFromTemplate(diff, template, customPrompt)
 		if err != nil {
 			// Try fallback provider
 			fallback := m.configManager.GetFallbackProviderProfile(m.repoPath)
 			if fallback != nil {
 				fallbackClient, fallbackErr := openai.NewClient(fallback.APIKey, fallback.BaseURL, fallback.Model)
 				if fallbackErr == nil {
-					title, description, err = fallbackClient.GeneratePRContent(diff, customPrompt)
+					title, description, err = fallbackClient.GeneratePRContentFromTemplate(diff, template, customPrompt)
 					if err == nil {
 						// Successfully used fallback - return result
 						return prContentGeneratedMsg{
@@ -1808,37 +2287,87 @@ func (m Model) testConnection(apiKey, baseURL, model string) tea.Cmd {
 	}
 }
 
-// refreshPRStatuses refreshes the status of all PRs for the selected worktree
+// refreshPRStatuses refreshes the status of all PRs for the selected worktree, and the review,
+// CI and merge state of the open PRs of every worktree
 func (m Model) refreshPRStatuses() tea.Cmd {
+	worktrees := m.worktrees
+	selected := m.selectedIndex
 	return func() tea.Msg {
-		if m.selectedIndex < 0 || m.selectedIndex >= len(m.worktrees) {
-			return prStatusesRefreshedMsg{err: fmt.Errorf("no worktree selected")}
-		}
-
-		worktree := m.worktrees[m.selectedIndex]
-		prs := m.configManager.GetPRs(m.repoPath, worktree.Branch)
-
-		// Update the status of each PR
-		for _, pr := range prs {
-			status, err := m.githubManager.GetPRStatus(pr.URL)
-			if err == nil {
-				_ = m.configManager.UpdatePRStatus(m.repoPath, worktree.Branch, pr.URL, status)
+		// Collect the PRs to refresh, then fetch them all in as few requests as possible
+		branches := make(map[string]string)
+		known := make(map[string]config.PRInfo)
+		var urls []string
+		for i, worktree := range worktrees {
+			for _, pr := range m.configManager.GetPRs(m.repoPath, worktree.Branch) {
+				// Merged and closed PRs don't change, only the selected worktree re-checks them
+				if i != selected && pr.Status != "open" && pr.Status != "draft" && pr.Status != "" {
+					continue
+				}
+				branches[pr.URL] = worktree.Branch
+				known[pr.URL] = pr
+				urls = append(urls, pr.URL)
 			}
 		}
 
-		// Reload worktrees to get updated PR info
-		worktrees, err := m.gitManager.List(m.baseBranch)
+		details, err := m.githubManager.GetPRDetailsBatch(urls)
 		if err != nil {
-			return prStatusesRefreshedMsg{err: err}
+			// Fall back to the plain status of each PR below
+			m.debugLog("refreshPRStatuses: failed to get PR details: " + err.Error())
+		}
+		var merged []mergedPR
+		var statusErr error
+		refreshed := 0
+		for _, url := range urls {
+			state := ""
+			if prDetails, ok := details[url]; ok {
+				m.savePRDetails(branches[url], url, prDetails)
+				state = prDetails.State
+			} else if status, err := m.githubManager.GetPRStatus(url); err == nil {
+				_ = m.configManager.UpdatePRStatus(m.repoPath, branches[url], url, status)
+				state = status
+			} else {
+				statusErr = err
+				continue
+			}
+			refreshed++
+			if previous := known[url].Status; state == "merged" && previous != "merged" && previous != "closed" {
+				if pr := m.mergedPRFor(branches[url], url, known[url].PRNumber); pr != nil {
+					merged = append(merged, *pr)
+				}
+			}
 		}
 
-		// Load PR info into worktrees
-		for i := range worktrees {
-			worktrees[i].PRs = m.configManager.GetPRs(m.repoPath, worktrees[i].Branch)
+		if refreshed == 0 && statusErr != nil {
+			return prStatusesRefreshedMsg{err: statusErr}
 		}
+		return prStatusesRefreshedMsg{merged: merged}
+	}
+}
 
-		return prStatusesRefreshedMsg{err: nil}
+// refreshPR updates the status and badges of a PR in config, falling back to the plain status
+// when the details can't be fetched
+func (m Model) refreshPR(branch string, pr config.PRInfo) {
+	details, err := m.githubManager.GetPRDetails(pr.URL)
+	if err != nil {
+		m.debugLog(fmt.Sprintf("refreshPR: failed to get details of %s: %v", pr.URL, err))
+		if status, err := m.githubManager.GetPRStatus(pr.URL); err == nil {
+			_ = m.configManager.UpdatePRStatus(m.repoPath, branch, pr.URL, status)
+		}
+		return
 	}
+	m.savePRDetails(branch, pr.URL, details)
+}
+
+// savePRDetails stores the state and badges of a PR in config
+func (m Model) savePRDetails(branch, prURL string, details *github.PRDetails) {
+	_ = m.configManager.UpdatePRDetails(m.repoPath, branch, prURL, details.State, config.PRDetails{
+		Draft:             details.Draft,
+		Checks:            details.Checks,
+		ReviewDecision:    details.ReviewDecision,
+		Reviewers:         details.Reviewers,
+		Mergeable:         details.Mergeable,
+		UnresolvedThreads: details.UnresolvedThreads,
+	})
 }
 
 // loadPRDetailsForAllWorktrees loads PR details from GitHub for all worktrees asynchronously
@@ -1875,6 +2404,9 @@ func (m Model) loadPRDetailsForAllWorktrees() tea.Cmd {
 				// Save to config with full PR details
 				if err := m.configManager.AddPR(m.repoPath, wt.Branch, prInfo.URL, prInfo.Number, prInfo.Title, prInfo.Author.Login); err != nil {
 					m.debugLog(fmt.Sprintf("loadPRDetailsForAllWorktrees: failed to save PR to config: %s", err.Error()))
+				} else {
+					// Fill in the real state and the badges of the discovered PR
+					m.refreshPR(wt.Branch, config.PRInfo{URL: prInfo.URL})
 				}
 			} else {
 				m.debugLog(fmt.Sprintf("loadPRDetailsForAllWorktrees: no PR found for branch %s", wt.Branch))
@@ -2018,6 +2550,16 @@ func (m Model) renameBranchForPush(oldName, newName, worktreePath string) tea.Cm
 			}
 		}
 
+		// Keep the branch in its stack and linked to its issue under the new name
+		if m.configManager != nil {
+			if err := m.configManager.RenameStackBranch(m.repoPath, oldName, newName); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to rename stack entry of %s: %v", oldName, err))
+			}
+			if err := m.configManager.RenameBranchIssue(m.repoPath, oldName, newName); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to rename issue link of %s: %v", oldName, err))
+			}
+		}
+
 		// Step 2: Rename directory if it's a workspace worktree
 		newWorktreePath := worktreePath
 		workspacesDir, err := m.gitManager.GetWorkspacesDir()
@@ -2064,10 +2606,16 @@ func (m Model) pushBranch(worktreePath, branch string) tea.Cmd {
 }
 
 // Helper methods
+
+// selectedWorktree returns the worktree under the cursor, nil when the filter hides it so
+// actions never apply to a worktree the user can't see
 func (m Model) selectedWorktree() *git.Worktree {
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.worktrees) {
 		return nil
 	}
+	if filter := parseWorktreeFilter(m.worktreeFilterInput.Value()); !filter.isEmpty() && !filter.matches(m.worktrees[m.selectedIndex]) {
+		return nil
+	}
 	return &m.worktrees[m.selectedIndex]
 }
 
@@ -2100,7 +2648,8 @@ func (m Model) filterBranches(query string) []string {
 }
 
 func (m Model) filterPRs(query string) []github.PRInfo {
-	if query == "" {
+	// The PR browser searches on GitHub, its results already match
+	if query == "" || m.prListCreationMode {
 		return m.prs
 	}
 
@@ -2120,8 +2669,15 @@ func (m Model) filterPRs(query string) []github.PRInfo {
 }
 
 // GetSwitchInfo returns the switch information (for shell integration)
+// The agent window, command and multiplexer are filled in from the session manager
 func (m Model) GetSwitchInfo() SwitchInfo {
-	return m.switchInfo
+	info := m.switchInfo
+	if info.Path != "" && m.sessionManager != nil {
+		info.AgentWindow = m.sessionManager.Agent().WindowName()
+		info.AgentCommand = m.sessionManager.AgentCommand(info.Path, info.Branch, filepath.Base(m.repoPath), info.IsClaudeInitialized)
+		info.Multiplexer = m.sessionManager.Multiplexer().Name()
+	}
+	return info
 }
 
 // GetConfigManager returns the config manager for access from main.go
@@ -2129,6 +2685,65 @@ func (m Model) GetConfigManager() *config.Manager {
 	return m.configManager
 }
 
+// applyAgent configures the session manager with the repository's AI coding agent
+func (m *Model) applyAgent() {
+	if m.configManager == nil || m.sessionManager == nil {
+		return
+	}
+	m.sessionManager.SetAgent(RepoAgent(m.configManager, m.repoPath))
+}
+
+// RepoAgent returns the AI coding agent configured for a repository
+func RepoAgent(configManager *config.Manager, repoPath string) session.Agent {
+	if configManager == nil {
+		return session.DefaultAgent()
+	}
+	return toSessionAgent(configManager.GetAgentName(repoPath), configManager.GetAgent(repoPath))
+}
+
+// toSessionAgent converts an agent profile to the session package type
+// A nil profile is the built-in claude agent
+func toSessionAgent(name string, cfg *config.AgentConfig) session.Agent {
+	if cfg == nil {
+		return session.DefaultAgent()
+	}
+	return session.Agent{
+		Name:            name,
+		Command:         cfg.Command,
+		Args:            cfg.Args,
+		ResumeFlag:      cfg.ResumeFlag,
+		PermissionFlag:  cfg.PermissionFlag,
+		PermissionMode:  cfg.PermissionMode,
+		PromptArg:       cfg.PromptArg,
+		WaitingPatterns: cfg.WaitingPatterns,
+	}
+}
+
+// sessionLayout returns the tmux layout configured for the repository (nil if none)
+func (m Model) sessionLayout() *session.Layout {
+	if m.configManager == nil {
+		return nil
+	}
+	return toSessionLayout(m.configManager.GetTmuxLayout(m.repoPath))
+}
+
+// toSessionLayout converts a configured tmux layout to the session package type
+func toSessionLayout(cfg *config.TmuxLayout) *session.Layout {
+	if cfg == nil {
+		return nil
+	}
+
+	layout := &session.Layout{}
+	for _, w := range cfg.Windows {
+		window := session.LayoutWindow{Name: w.Name, Arrangement: w.Layout}
+		for _, p := range w.Panes {
+			window.Panes = append(window.Panes, session.LayoutPane{Command: p.Command, Split: p.Split, Size: p.Size})
+		}
+		layout.Windows = append(layout.Windows, window)
+	}
+	return layout
+}
+
 // loadSessions loads tmux sessions for the current repository only
 func (m Model) loadSessions() tea.Cmd {
 	return func() tea.Msg {
@@ -2136,12 +2751,188 @@ func (m Model) loadSessions() tea.Cmd {
 		if err != nil {
 			return statusMsg("Failed to load sessions")
 		}
-		return sessionsLoadedMsg{sessions: sessions}
+		// Sessions lost with a previous tmux server can be restored (errors only hide the offer)
+		restorable, _ := m.sessionManager.RestorableSessions(m.repoPath)
+		return sessionsLoadedMsg{sessions: sessions, restorable: restorable}
 	}
 }
 
 type sessionsLoadedMsg struct {
-	sessions []session.Session
+	sessions   []session.Session
+	restorable []session.SessionRecord
+}
+
+// sessionWindow is a window of the selected session with the resource usage of its processes
+type sessionWindow struct {
+	session.WindowInfo
+	stats *session.ProcessStats // nil when unknown (no /proc, Zellij)
+	cpu   float64               // CPU usage in percent since the previous refresh, -1 until sampled twice
+}
+
+// processSample is a resource sample of a window's process tree
+type processSample struct {
+	stats session.ProcessStats
+	at    time.Time
+}
+
+// selectedSession returns the session selected in the session list
+func (m Model) selectedSession() *session.Session {
+	if m.sessionIndex >= 0 && m.sessionIndex < len(m.sessions) {
+		return &m.sessions[m.sessionIndex]
+	}
+	return nil
+}
+
+// selectedSessionWindow returns the window selected in the session list
+func (m Model) selectedSessionWindow() *sessionWindow {
+	if m.sessionWindowIndex >= 0 && m.sessionWindowIndex < len(m.sessionWindows) {
+		return &m.sessionWindows[m.sessionWindowIndex]
+	}
+	return nil
+}
+
+// worktreeForSession returns the worktree a session belongs to, nil if there is none
+func (m Model) worktreeForSession(sessionName string) *git.Worktree {
+	for i := range m.worktrees {
+		if m.worktrees[i].ClaudeSessionName == sessionName {
+			return &m.worktrees[i]
+		}
+	}
+	return nil
+}
+
+// loadSessionWindows lists the windows of a session with the resource usage of their processes
+func (m Model) loadSessionWindows(sessionName string) tea.Cmd {
+	return func() tea.Msg {
+		windows, err := m.sessionManager.Windows(sessionName)
+		if err != nil {
+			return sessionWindowsMsg{sessionName: sessionName, err: err}
+		}
+		// Errors only hide the usage (no /proc on macOS, process just exited)
+		stats := make(map[int]session.ProcessStats)
+		if table, err := session.ReadProcessTable(); err == nil {
+			for _, window := range windows {
+				if window.PID == 0 {
+					continue
+				}
+				if stat, err := table.Stats(window.PID); err == nil {
+					stats[window.PID] = stat
+				}
+			}
+		}
+		return sessionWindowsMsg{sessionName: sessionName, windows: windows, stats: stats, sampledAt: time.Now()}
+	}
+}
+
+// applySessionWindows stores freshly loaded windows, computing CPU usage from the previous samples
+// The selection follows the selected window by name, or starts on the session's current window
+func (m *Model) applySessionWindows(msg sessionWindowsMsg) {
+	selected := ""
+	if w := m.selectedSessionWindow(); w != nil {
+		selected = w.Name
+	}
+
+	samples := make(map[int]processSample, len(msg.stats))
+	windows := make([]sessionWindow, 0, len(msg.windows))
+	m.sessionWindowIndex = 0
+	for i, info := range msg.windows {
+		window := sessionWindow{WindowInfo: info, cpu: -1}
+		if stat, ok := msg.stats[info.PID]; ok {
+			window.stats = &stat
+			if previous, ok := m.sessionWindowSample[info.PID]; ok {
+				window.cpu = session.CPUPercent(previous.stats, stat, msg.sampledAt.Sub(previous.at))
+			}
+			samples[info.PID] = processSample{stats: stat, at: msg.sampledAt}
+		}
+		if info.Name == selected || (selected == "" && info.Active) {
+			m.sessionWindowIndex = i
+		}
+		windows = append(windows, window)
+	}
+	m.sessionWindows = windows
+	m.sessionWindowSample = samples
+}
+
+// selectSessionWindows shows the windows of the selected session, loading them if the selection changed
+func (m *Model) selectSessionWindows() tea.Cmd {
+	sess := m.selectedSession()
+	if sess == nil {
+		m.sessionWindows = nil
+		m.sessionWindowsOf = ""
+		return nil
+	}
+	if sess.Name == m.sessionWindowsOf {
+		return nil
+	}
+	m.sessionWindows = nil
+	m.sessionWindowIndex = 0
+	m.sessionWindowSample = nil
+	m.sessionWindowsOf = sess.Name
+	return m.loadSessionWindows(sess.Name)
+}
+
+// killSessionWindow closes a single window of a session
+func (m Model) killSessionWindow(sessionName, windowName string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.sessionManager.KillWindow(sessionName, windowName)
+		return sessionWindowActionMsg{sessionName: sessionName, action: "kill", window: windowName, err: err}
+	}
+}
+
+// restartSessionAgent replaces the agent window of a session with a freshly started agent
+// The agent resumes its conversation if it was started in the worktree before
+func (m Model) restartSessionAgent(sess session.Session) tea.Cmd {
+	path, branch := sess.Path, ""
+	if wt := m.worktreeForSession(sess.Name); wt != nil {
+		path, branch = wt.Path, wt.Branch
+	}
+	return func() tea.Msg {
+		windowName := m.sessionManager.Agent().WindowName()
+		if path == "" {
+			return sessionWindowActionMsg{sessionName: sess.Name, action: "restart", window: windowName, err: fmt.Errorf("no worktree found for session %s", sess.Name)}
+		}
+		isInitialized := false
+		if m.configManager != nil && branch != "" {
+			isInitialized = m.configManager.IsClaudeInitialized(m.repoPath, branch)
+		}
+		err := m.sessionManager.RestartAgent(sess.Name, path, isInitialized, m.sessionLayout())
+		// The next start resumes the conversation of the restarted agent
+		if err == nil && !isInitialized && m.configManager != nil && branch != "" {
+			_ = m.configManager.SetClaudeInitialized(m.repoPath, branch)
+		}
+		return sessionWindowActionMsg{sessionName: sess.Name, action: "restart", window: windowName, err: err}
+	}
+}
+
+type sessionsRestoredMsg struct {
+	results []bulkResult
+}
+
+// restoreSessions recreates recorded sessions, resuming their agents
+func (m Model) restoreSessions(records []session.SessionRecord) tea.Cmd {
+	worktrees := m.worktrees
+	return func() tea.Msg {
+		layout := m.sessionLayout()
+		results := make([]bulkResult, 0, len(records))
+		for _, record := range records {
+			result := bulkResult{branch: strings.TrimPrefix(record.Name, "jean-"), path: record.Path}
+			for _, wt := range worktrees {
+				if wt.Path == record.Path {
+					result.branch = wt.Branch
+					break
+				}
+			}
+
+			result.err = m.sessionManager.RestoreSession(record, layout)
+			// The agent was started with its resume flag, keep the initialized state in sync
+			if result.err == nil && record.Agent != "" && m.configManager != nil {
+				_ = m.configManager.SetClaudeInitialized(m.repoPath, result.branch)
+			}
+			m.debugLog(fmt.Sprintf("Restore session %s: err=%v", record.Name, result.err))
+			results = append(results, result)
+		}
+		return sessionsRestoredMsg{results: results}
+	}
 }
 
 // pullFromBaseBranch pulls changes from the base branch into the worktree
@@ -2157,7 +2948,7 @@ func (m Model) pullFromBaseBranch(worktreePath, baseBranch string) tea.Cmd {
 		if err != nil {
 			// Check if it's a merge conflict
 			if strings.Contains(err.Error(), "merge conflict") {
-				return branchPulledMsg{err: err, hadConflict: true}
+				return branchPulledMsg{err: err, hadConflict: true, worktreePath: worktreePath}
 			}
 			return branchPulledMsg{err: err, hadConflict: false}
 		}
@@ -2192,7 +2983,7 @@ func (m Model) checkAndPullFromBase(worktreePath, baseBranch string) tea.Cmd {
 		if err != nil {
 			// Check if it's a merge conflict
 			if strings.Contains(err.Error(), "merge conflict") {
-				return branchPulledMsg{err: err, hadConflict: true}
+				return branchPulledMsg{err: err, hadConflict: true, worktreePath: worktreePath}
 			}
 			return branchPulledMsg{err: err, hadConflict: false}
 		}
@@ -2260,6 +3051,7 @@ func (m Model) executeLocalMerge(worktreePath, branch, baseBranch string) tea.Cm
 				return localMergeCompletedMsg{
 					branch:       branch,
 					worktreePath: worktreePath,
+					mergePath:    repoRoot,
 					err:          err,
 					hadConflict:  true,
 				}
@@ -2281,68 +3073,572 @@ func (m Model) executeLocalMerge(worktreePath, branch, baseBranch string) tea.Cm
 	}
 }
 
-// refreshWithPull fetches latest commits from remote and pulls all worktrees
-// Automatically pulls changes into ALL worktrees (main repo + workspace branches)
-// Skips worktrees with uncommitted changes to prevent merge conflicts
-func (m Model) refreshWithPull() tea.Cmd {
+// loadConflicts lists the files with unresolved conflicts in the given path
+func (m Model) loadConflicts(path string) tea.Cmd {
 	return func() tea.Msg {
-		msg := refreshWithPullMsg{
-			updatedBranches: make(map[string]int),
-			upToDate:        true,
+		files, err := m.gitManager.GetConflictedFiles(path)
+		return conflictsLoadedMsg{
+			path:      path,
+			operation: m.gitManager.MergeOperation(path),
+			files:     files,
+			err:       err,
 		}
+	}
+}
 
-		// Fetch all updates from remote first to get latest refs
-		if err := m.gitManager.FetchRemote(); err != nil {
-			return refreshWithPullMsg{err: fmt.Errorf("failed to fetch updates: %w", err)}
-		}
+// loadConflictFile parses the conflict hunks of a single file
+func (m Model) loadConflictFile(path, file string) tea.Cmd {
+	return func() tea.Msg {
+		conflict, err := m.gitManager.LoadConflictFile(path, file)
+		return conflictFileLoadedMsg{file: conflict, err: err}
+	}
+}
+
+// suggestConflictResolution asks the AI provider for a resolution of one hunk
+func (m Model) suggestConflictResolution(file string, hunkIndex int, hunk git.ConflictHunk) tea.Cmd {
+	return func() tea.Msg {
+		// Get active AI provider profile
+		profile := m.configManager.GetActiveProviderProfile(m.repoPath)
+		if profile == nil {
+			return conflictSuggestionMsg{
+				path:      file,
+				hunkIndex: hunkIndex,
+				err:       fmt.Errorf("AI provider not configured. Please configure an AI provider in settings"),
+			}
+		}
+
+		// Create AI client
+		client, err := openai.NewClient(profile.APIKey, profile.BaseURL, profile.Model)
+		if err != nil {
+			return conflictSuggestionMsg{
+				path:      file,
+				hunkIndex: hunkIndex,
+				err:       fmt.Errorf("failed to create AI client: %w", err),
+			}
+		}
+
+		resolution, err := client.SuggestConflictResolution(file, hunk.Ours, hunk.Base, hunk.Theirs)
+		if err != nil {
+			// Try fallback provider
+			fallback := m.configManager.GetFallbackProviderProfile(m.repoPath)
+			if fallback != nil {
+				fallbackClient, fallbackErr := openai.NewClient(fallback.APIKey, fallback.BaseURL, fallback.Model)
+				if fallbackErr == nil {
+					resolution, err = fallbackClient.SuggestConflictResolution(file, hunk.Ours, hunk.Base, hunk.Theirs)
+				}
+			}
+		}
+
+		return conflictSuggestionMsg{
+			path:       file,
+			hunkIndex:  hunkIndex,
+			resolution: resolution,
+			err:        err,
+		}
+	}
+}
+
+// resolveConflictFile writes the chosen hunk resolutions to disk and stages the file
+func (m Model) resolveConflictFile(path string, file *git.ConflictFile) tea.Cmd {
+	return func() tea.Msg {
+		err := m.gitManager.ResolveConflictFile(path, file)
+		return conflictFileResolvedMsg{path: file.Path, err: err}
+	}
+}
+
+// markConflictResolved stages a file that was resolved outside of jean (e.g. in an editor)
+func (m Model) markConflictResolved(path, file string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.gitManager.MarkConflictResolved(path, file)
+		return conflictFileResolvedMsg{path: file, err: err}
+	}
+}
+
+// continueMerge completes the in-progress merge or rebase
+func (m Model) continueMerge(path string) tea.Cmd {
+	return func() tea.Msg {
+		return mergeContinuedMsg{err: m.gitManager.ContinueMerge(path)}
+	}
+}
+
+// abortMerge aborts the in-progress merge or rebase
+func (m Model) abortMerge(path string) tea.Cmd {
+	return func() tea.Msg {
+		return mergeAbortedMsg{err: m.gitManager.AbortConflictedOperation(path)}
+	}
+}
+
+// refreshWorkers bounds how many worktrees are pulled concurrently during refresh
+const refreshWorkers = 4
+
+// refreshOutcome describes what happened to a single worktree during refresh
+type refreshOutcome int
+
+const (
+	refreshUpToDate refreshOutcome = iota
+	refreshUpdated
+	refreshSkippedDirty
+	refreshSkippedNoRemote
+	refreshDiverged
+	refreshFailed
+)
+
+// refreshResult is the outcome of pulling a single worktree during refresh
+type refreshResult struct {
+	branch      string
+	path        string
+	outcome     refreshOutcome
+	commits     int   // Commits pulled (refreshUpdated)
+	ahead       int   // Local commits not on the remote (refreshDiverged)
+	behind      int   // Remote commits not in the local branch (refreshDiverged)
+	autoStashed bool  // Whether local changes were stashed and restored around the pull
+	err         error // Failure reason (refreshFailed), or a non-fatal autostash error
+}
+
+// refreshWithPull fetches latest commits from remote and pulls all worktrees
+// Automatically pulls changes into ALL worktrees (main repo + workspace branches)
+// Dirty worktrees are skipped (or autostashed if enabled) and diverged branches are left alone
+// Pulls run in parallel with at most refreshWorkers at a time
+func (m Model) refreshWithPull() tea.Cmd {
+	return func() tea.Msg {
+		msg := refreshWithPullMsg{
+			updatedBranches: make(map[string]int),
+			upToDate:        true,
+		}
+
+		// Fetch all updates from remote first to get latest refs
+		if err := m.gitManager.FetchRemote(); err != nil {
+			return refreshWithPullMsg{err: fmt.Errorf("failed to fetch updates: %w", err)}
+		}
+
+		autoStash := m.configManager != nil && m.configManager.GetAutoStash(m.repoPath)
+		_, remoteErr := m.gitManager.GetRemoteURL()
+		hasRemote := remoteErr == nil
 
 		// Pull all worktrees (both main repo and workspace branches)
+		var worktrees []git.Worktree
 		for _, wt := range m.worktrees {
-			if wt.Branch == "" {
-				continue // Skip if no branch is checked out
+			if wt.Branch != "" {
+				worktrees = append(worktrees, wt) // Skip if no branch is checked out
 			}
+		}
 
-			// Check if this worktree has uncommitted changes
-			hasUncommitted, _ := m.gitManager.HasUncommittedChanges(wt.Path)
-			if hasUncommitted {
-				continue // Skip pulling if there are uncommitted changes
+		results := make([]refreshResult, len(worktrees))
+		sem := make(chan struct{}, refreshWorkers)
+		var stashMu sync.Mutex // The stash list is shared by all worktrees
+		var wg sync.WaitGroup
+		for i, wt := range worktrees {
+			wg.Add(1)
+			go func(i int, wt git.Worktree) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				results[i] = m.pullWorktreeForRefresh(wt, hasRemote, autoStash, &stashMu)
+			}(i, wt)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			if result.outcome == refreshUpdated {
+				msg.updatedBranches[result.branch] = result.commits
+				msg.upToDate = false
 			}
+		}
+		msg.results = results
 
-			// Pull this worktree's current branch
-			var output string
-			var err error
+		// Worktree list will be reloaded by the Update handler
+		// This recalculates ahead/behind counts based on fetched refs
+		return msg
+	}
+}
 
-			if wt.IsCurrent {
-				// For main repo, use PullCurrentBranchWithOutput
-				output, err = m.gitManager.PullCurrentBranchWithOutput(m.repoPath, wt.Branch)
+// pullWorktreeForRefresh pulls a single worktree's branch and reports the outcome
+func (m Model) pullWorktreeForRefresh(wt git.Worktree, hasRemote, autoStash bool, stashMu *sync.Mutex) refreshResult {
+	result := refreshResult{branch: wt.Branch, path: wt.Path}
+
+	upstream := ""
+	if hasRemote {
+		// Branches that only exist locally have nothing to pull
+		exists, err := m.gitManager.RemoteBranchExists(wt.Path, wt.Branch)
+		if err != nil {
+			result.outcome = refreshFailed
+			result.err = err
+			return result
+		}
+		if !exists {
+			result.outcome = refreshSkippedNoRemote
+			return result
+		}
+
+		// Don't create merge commits for branches that diverged from their upstream
+		upstream = m.gitManager.UpstreamRef(wt.Path, wt.Branch)
+		ahead, behind, err := m.gitManager.GetBranchStatus(wt.Path, wt.Branch, upstream)
+		if err != nil {
+			result.outcome = refreshFailed
+			result.err = err
+			return result
+		}
+		if ahead > 0 && behind > 0 {
+			result.outcome = refreshDiverged
+			result.ahead = ahead
+			result.behind = behind
+			return result
+		}
+		if behind == 0 {
+			result.outcome = refreshUpToDate
+			return result
+		}
+		result.commits = behind
+	}
+
+	// Check if this worktree has uncommitted changes
+	hasUncommitted, _ := m.gitManager.HasUncommittedChanges(wt.Path)
+	stashMessage := ""
+	if hasUncommitted {
+		if !autoStash {
+			result.outcome = refreshSkippedDirty
+			return result
+		}
+
+		// Autostash: stash changes, pull, then pop them back
+		stashMessage = fmt.Sprintf("jean autostash %s %d", wt.Branch, time.Now().UnixNano())
+		stashMu.Lock()
+		err := m.gitManager.PushStash(wt.Path, stashMessage)
+		stashMu.Unlock()
+		if err != nil {
+			result.outcome = refreshFailed
+			result.err = fmt.Errorf("failed to autostash: %w", err)
+			return result
+		}
+	}
+
+	// Pull this worktree's current branch, fast-forward only when it has a remote
+	var output string
+	var err error
+	if upstream != "" {
+		output, err = m.gitManager.FastForwardWithOutput(wt.Path, upstream)
+	} else if wt.IsCurrent {
+		// For main repo, use PullCurrentBranchWithOutput
+		output, err = m.gitManager.PullCurrentBranchWithOutput(m.repoPath, wt.Branch)
+	} else {
+		// For workspace branches, use PullBranchInPathWithOutput
+		output, err = m.gitManager.PullBranchInPathWithOutput(wt.Path, wt.Branch)
+	}
+
+	if stashMessage != "" {
+		// Restore stashed changes even if the pull failed
+		// Look the stash up by message since other workers may have pushed stashes too
+		stashMu.Lock()
+		popErr := fmt.Errorf("autostash entry not found")
+		if stashes, listErr := m.gitManager.ListStashes(wt.Path); listErr == nil {
+			for _, stash := range stashes {
+				if stash.Message == stashMessage {
+					popErr = m.gitManager.PopStash(wt.Path, stash.Ref)
+					break
+				}
+			}
+		}
+		stashMu.Unlock()
+		if popErr != nil {
+			result.err = fmt.Errorf("failed to restore autostash (changes kept in stash): %w", popErr)
+		} else {
+			result.autoStashed = true
+		}
+	}
+
+	if err != nil {
+		result.outcome = refreshFailed
+		result.err = err
+		return result
+	}
+
+	if result.commits > 0 {
+		// The remote commits counted before the fast-forward
+		result.outcome = refreshUpdated
+		return result
+	}
+
+	// Parse the output to extract commit count
+	isUpToDate, commitCount := m.gitManager.ParsePullOutput(output)
+	if !isUpToDate && commitCount > 0 {
+		result.outcome = refreshUpdated
+		result.commits = commitCount
+	}
+	return result
+}
+
+// markedWorktreeList returns the marked worktrees in list order
+func (m Model) markedWorktreeList() []git.Worktree {
+	var marked []git.Worktree
+	for _, wt := range m.worktrees {
+		if m.markedWorktrees[wt.Path] {
+			marked = append(marked, wt)
+		}
+	}
+	return marked
+}
+
+// sessionRunning reports whether a tmux session was running at the last activity check
+func (m Model) sessionRunning(sessionName string) bool {
+	for _, sess := range m.sessions {
+		if sess.Name == sessionName {
+			return true
+		}
+	}
+	return false
+}
+
+// sendPrompt types a prompt into the agent window of every target worktree's session
+func (m Model) sendPrompt(prompt string, worktrees []git.Worktree) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]bulkResult, 0, len(worktrees))
+		for _, wt := range worktrees {
+			result := bulkResult{branch: wt.Branch, path: wt.Path}
+			if !m.sessionManager.SessionExists(wt.ClaudeSessionName) {
+				result.skipped = "no running session"
 			} else {
-				// For workspace branches, use PullBranchInPathWithOutput
-				output, err = m.gitManager.PullBranchInPathWithOutput(wt.Path, wt.Branch)
+				result.err = m.sessionManager.SendPrompt(wt.ClaudeSessionName, prompt)
 			}
+			m.debugLog(fmt.Sprintf("Send prompt to %s: skipped=%q err=%v", wt.Branch, result.skipped, result.err))
+			results = append(results, result)
+		}
+		return promptSentMsg{results: results}
+	}
+}
 
-			if err != nil {
-				// Pull failed for this worktree, but continue with others
-				// Store the first error if no error was already recorded
-				if msg.pullErr == nil {
-					msg.pullErr = fmt.Errorf("failed to pull %s: %w", wt.Branch, err)
-				}
+// visibleReviewThreads returns the review threads listed in the review modal
+// Unresolved threads come first, resolved ones only when shown
+func (m Model) visibleReviewThreads() []github.ReviewThread {
+	var unresolved, resolved []github.ReviewThread
+	for _, thread := range m.reviewThreads {
+		if thread.IsResolved {
+			resolved = append(resolved, thread)
+		} else {
+			unresolved = append(unresolved, thread)
+		}
+	}
+	if !m.reviewShowResolved {
+		return unresolved
+	}
+	return append(unresolved, resolved...)
+}
+
+// selectedReviewThread returns the thread selected in the review modal
+func (m Model) selectedReviewThread() *github.ReviewThread {
+	threads := m.visibleReviewThreads()
+	if m.reviewIndex >= 0 && m.reviewIndex < len(threads) {
+		return &threads[m.reviewIndex]
+	}
+	return nil
+}
+
+// loadReviewThreads loads the review threads of a PR
+func (m Model) loadReviewThreads(prURL string) tea.Cmd {
+	return func() tea.Msg {
+		threads, err := m.githubManager.GetReviewThreads(prURL)
+		return reviewThreadsLoadedMsg{prURL: prURL, threads: threads, err: err}
+	}
+}
+
+// replyToReviewThread posts a reply to a review thread
+func (m Model) replyToReviewThread(prURL, threadID, body string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.githubManager.ReplyToReviewThread(threadID, body)
+		return reviewThreadUpdatedMsg{prURL: prURL, action: "reply", err: err}
+	}
+}
+
+// resolveReviewThread resolves a review thread, or unresolves it when resolved is false
+func (m Model) resolveReviewThread(prURL, threadID string, resolved bool) tea.Cmd {
+	action := "resolve"
+	if !resolved {
+		action = "unresolve"
+	}
+	return func() tea.Msg {
+		err := m.githubManager.ResolveReviewThread(threadID, resolved)
+		return reviewThreadUpdatedMsg{prURL: prURL, action: action, err: err}
+	}
+}
+
+// reviewThreadPromptHunkLines is the number of diff lines included in a review thread prompt
+const reviewThreadPromptHunkLines = 12
+
+// reviewThreadPrompt turns a review thread into a task for the agent
+// The diff context ends at the commented line, so only its last lines are kept
+func reviewThreadPrompt(pr config.PRInfo, thread github.ReviewThread) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address this review comment on PR #%d at %s", pr.PRNumber, thread.Location())
+	if thread.IsOutdated {
+		b.WriteString(" (the code changed since the comment was made)")
+	}
+	b.WriteString(":\n")
+
+	if hunk := strings.TrimRight(thread.DiffHunk, "\n"); hunk != "" {
+		lines := strings.Split(hunk, "\n")
+		if len(lines) > reviewThreadPromptHunkLines {
+			lines = lines[len(lines)-reviewThreadPromptHunkLines:]
+		}
+		b.WriteString("\n```diff\n" + strings.Join(lines, "\n") + "\n```\n")
+	}
+	b.WriteString("\n")
+	for _, comment := range thread.Comments {
+		fmt.Fprintf(&b, "%s: %s\n", comment.Author, strings.TrimSpace(comment.Body))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// gcCollector returns the garbage collector of the current repository
+func (m Model) gcCollector() *gc.Collector {
+	return &gc.Collector{
+		RepoPath:   m.repoPath,
+		BaseBranch: m.baseBranch,
+		Git:        m.gitManager,
+		Sessions:   m.sessionManager,
+		Config:     m.configManager,
+	}
+}
+
+// scanGarbage finds orphan sessions, prunable worktrees, merged branches and stale config (dry run)
+func (m Model) scanGarbage() tea.Cmd {
+	return func() tea.Msg {
+		items, err := m.gcCollector().Find()
+		return gcScannedMsg{items: items, err: err}
+	}
+}
+
+// cleanGarbage removes the items found by scanGarbage
+func (m Model) cleanGarbage(items []gc.Item) tea.Cmd {
+	return func() tea.Msg {
+		results := m.gcCollector().Clean(items)
+		for _, result := range results {
+			m.debugLog(fmt.Sprintf("GC %s %s: err=%v", result.Item.Kind, result.Item.Name, result.Err))
+		}
+		return gcCleanedMsg{results: results}
+	}
+}
+
+// bulkSkipReason returns why a worktree would be skipped by a bulk action ("" if it will be processed)
+func (m Model) bulkSkipReason(action string, wt git.Worktree) string {
+	switch action {
+	case "delete":
+		if wt.IsCurrent {
+			return "main repository can't be deleted"
+		}
+		if wt.HasUncommitted && !m.bulkForce {
+			return "uncommitted changes (press f to force)"
+		}
+	case "pull":
+		if m.baseBranch == "" {
+			return "base branch not set"
+		}
+		if !strings.Contains(wt.Path, ".workspaces") {
+			return "main worktree (use 'git pull' manually)"
+		}
+		if wt.HasUncommitted {
+			return "uncommitted changes"
+		}
+	case "push":
+		if wt.Branch == "" || strings.HasPrefix(wt.Branch, "(detached") {
+			return "no branch checked out"
+		}
+	case "kill":
+		if !m.sessionManager.SessionExists(wt.ClaudeSessionName) {
+			return "no running session"
+		}
+	}
+	return ""
+}
+
+// runBulkAction runs the action for every marked worktree and reports per-item results
+func (m Model) runBulkAction(action string, worktrees []git.Worktree) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]bulkResult, 0, len(worktrees))
+
+		// Fetch once up front instead of per worktree
+		var fetchErr error
+		if action == "pull" {
+			fetchErr = m.gitManager.FetchRemote()
+		}
+
+		for _, wt := range worktrees {
+			result := bulkResult{branch: wt.Branch, path: wt.Path}
+			if reason := m.bulkSkipReason(action, wt); reason != "" {
+				result.skipped = reason
+				results = append(results, result)
 				continue
 			}
 
-			// Parse the output to extract commit count
-			isUpToDate, commitCount := m.gitManager.ParsePullOutput(output)
-			if !isUpToDate && commitCount > 0 {
-				msg.updatedBranches[wt.Branch] = commitCount
-				msg.upToDate = false
+			switch action {
+			case "delete":
+				// Same steps as a single delete: remove, clean up config, kill session
+				result.err = m.gitManager.Remove(wt.Path, m.bulkForce)
+				if result.err == nil {
+					if m.configManager != nil {
+						_ = m.configManager.CleanupBranch(m.repoPath, wt.Branch) // Ignore error, not critical
+					}
+					_ = m.sessionManager.KillWorktreeSessions(wt.ClaudeSessionName) // Ignore error, the worktree is gone already
+				}
+			case "pull":
+				if fetchErr != nil {
+					result.err = fmt.Errorf("failed to fetch: %w", fetchErr)
+				} else {
+					result.err = m.gitManager.MergeBranch(wt.Path, m.baseBranch)
+				}
+			case "push":
+				result.err = m.gitManager.Push(wt.Path, wt.Branch)
+			case "kill":
+				result.err = m.sessionManager.Kill(wt.ClaudeSessionName)
 			}
+
+			m.debugLog(fmt.Sprintf("Bulk %s %s: err=%v", action, wt.Branch, result.err))
+			results = append(results, result)
 		}
 
-		// Worktree list will be reloaded by the Update handler
-		// This recalculates ahead/behind counts based on fetched refs
-		return msg
+		return bulkActionCompletedMsg{action: action, results: results}
+	}
+}
+
+// loadStashes loads the stash list of the repository
+func (m Model) loadStashes(worktreePath string) tea.Cmd {
+	return func() tea.Msg {
+		stashes, err := m.gitManager.ListStashes(worktreePath)
+		return stashesLoadedMsg{stashes: stashes, err: err}
+	}
+}
+
+// runStashAction pushes, pops, applies or drops a stash in the worktree
+func (m Model) runStashAction(worktreePath, action, ref, message string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		switch action {
+		case "push":
+			err = m.gitManager.PushStash(worktreePath, message)
+		case "pop":
+			err = m.gitManager.PopStash(worktreePath, ref)
+		case "apply":
+			err = m.gitManager.ApplyStash(worktreePath, ref)
+		case "drop":
+			err = m.gitManager.DropStash(worktreePath, ref)
+		}
+		return stashActionMsg{action: action, ref: ref, err: err}
 	}
 }
 
+// visibleStashes returns the stashes shown in the stash modal
+// By default only stashes created on the worktree's branch are shown
+func (m Model) visibleStashes() []git.Stash {
+	if m.stashShowAll {
+		return m.stashes
+	}
+	var visible []git.Stash
+	for _, stash := range m.stashes {
+		if stash.Branch == m.stashBranch {
+			visible = append(visible, stash)
+		}
+	}
+	return visible
+}
+
 // scheduleActivityCheck schedules periodic activity checks
 func (m Model) scheduleActivityCheck() tea.Cmd {
 	return tea.Every(2*time.Second, func(t time.Time) tea.Msg {
@@ -2365,14 +3661,179 @@ func (m Model) animateRenameSpinner() tea.Cmd {
 	})
 }
 
+// panePreviewLines is the maximum number of pane lines shown in the details preview
+const panePreviewLines = 15
+
+// previewModes are the preview sources cycled with W
+var previewModes = []string{"agent", "terminal", "off"}
+
+// schedulePanePreview schedules the next refresh of the pane preview
+func (m Model) schedulePanePreview() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return panePreviewTickMsg{}
+	})
+}
+
+// capturePanePreview captures the last lines of the selected worktree's agent or terminal window
+// Falls back to the terminal window when the session has no agent window
+func (m Model) capturePanePreview(wt git.Worktree) tea.Cmd {
+	mode := m.previewMode
+	return func() tea.Msg {
+		window := "terminal"
+		if mode == "agent" {
+			window = m.sessionManager.Agent().WindowName()
+		}
+
+		lines, err := m.sessionManager.CaptureWindow(wt.ClaudeSessionName, window, panePreviewLines)
+		if err != nil && mode == "agent" {
+			window = "terminal"
+			lines, err = m.sessionManager.CaptureWindow(wt.ClaudeSessionName, window, panePreviewLines)
+		}
+		if err != nil {
+			return panePreviewMsg{path: wt.Path}
+		}
+		return panePreviewMsg{path: wt.Path, window: window, lines: lines}
+	}
+}
+
 // checkSessionActivity checks for recent session activity in current repository
+// Also refreshes the agent state of every worktree with a running session
 func (m Model) checkSessionActivity() tea.Cmd {
+	worktrees := m.worktrees
 	return func() tea.Msg {
 		sessions, err := m.sessionManager.List(m.repoPath)
 		if err != nil {
 			return activityCheckedMsg{sessions: []session.Session{}, err: err}
 		}
-		return activityCheckedMsg{sessions: sessions, err: nil}
+
+		// Keep the session records current so sessions can be restored after a tmux restart
+		if err := m.sessionManager.RecordSessions(); err != nil {
+			m.debugLog(fmt.Sprintf("Failed to record sessions: %v", err))
+		}
+
+		running := make(map[string]bool, len(sessions))
+		for _, sess := range sessions {
+			running[sess.Name] = true
+		}
+
+		var agentStates []agentStateUpdate
+		for _, wt := range worktrees {
+			if !running[wt.ClaudeSessionName] {
+				continue
+			}
+			aiWaiting, state, message := m.detectAgentState(wt)
+			agentStates = append(agentStates, agentStateUpdate{
+				path:      wt.Path,
+				branch:    wt.Branch,
+				aiWaiting: aiWaiting,
+				state:     state,
+				message:   message,
+			})
+		}
+		return activityCheckedMsg{sessions: sessions, agentStates: agentStates, err: nil}
+	}
+}
+
+// agentStateUpdate is the agent state of a worktree found by an activity check
+type agentStateUpdate struct {
+	path      string
+	branch    string
+	aiWaiting bool
+	state     string
+	message   string
+}
+
+// applyNotificationConfig (re)creates the agent notifier from the global notification settings
+func (m *Model) applyNotificationConfig() {
+	if m.configManager == nil {
+		return
+	}
+	cfg := m.configManager.GetNotificationConfig()
+	m.notifier = session.NewNotifier(cfg.Methods, cfg.Command, time.Duration(cfg.RateLimitSeconds)*time.Second)
+}
+
+// applyAgentStates updates worktrees with fresh agent states and returns a command notifying
+// about agents that started waiting for input since the previous check
+// The first check after startup only records states, agents that were already waiting don't notify
+func (m *Model) applyAgentStates(updates []agentStateUpdate) tea.Cmd {
+	seen := make(map[string]bool, len(updates))
+	var started []agentStateUpdate
+	for _, update := range updates {
+		seen[update.path] = true
+		for i := range m.worktrees {
+			if m.worktrees[i].Path == update.path {
+				m.worktrees[i].AIWaiting = update.aiWaiting
+				m.worktrees[i].AgentState = update.state
+				m.worktrees[i].AgentMessage = update.message
+				break
+			}
+		}
+
+		wasWaiting, known := m.agentsWaiting[update.path]
+		if known && !wasWaiting && update.aiWaiting {
+			started = append(started, update)
+		}
+		m.agentsWaiting[update.path] = update.aiWaiting
+	}
+
+	// Forget sessions that stopped, a new session starts from a clean slate
+	for path := range m.agentsWaiting {
+		if !seen[path] {
+			delete(m.agentsWaiting, path)
+		}
+	}
+
+	if len(started) == 0 || m.notifier == nil {
+		return nil
+	}
+	if m.configManager != nil && m.configManager.GetNotificationsMuted(m.repoPath) {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for _, update := range started {
+		notification := agentNotification(filepath.Base(m.repoPath), update)
+		cmds = append(cmds, m.showInfoNotification(notification.Title+": "+notification.Message))
+
+		sequence, deliver, ok := m.notifier.Prepare(notification)
+		if !ok {
+			continue
+		}
+		// Only the renderer may write to the terminal, View emits the sequence with the next frame
+		if sequence != "" {
+			if m.terminalSequence == "" {
+				cmds = append(cmds, tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+					return terminalSequenceWrittenMsg{}
+				}))
+			}
+			m.terminalSequence += sequence
+		}
+		cmds = append(cmds, func() tea.Msg {
+			if err := deliver(); err != nil {
+				m.debugLog(fmt.Sprintf("Agent notification failed: %v", err))
+			}
+			return nil
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// agentNotification builds the notification for an agent that started waiting
+func agentNotification(repoName string, update agentStateUpdate) session.AgentNotification {
+	title := fmt.Sprintf("%s/%s", repoName, update.branch)
+	message := "Agent needs input"
+	if update.state == session.AgentIdle {
+		message = "Agent finished"
+	} else if update.message != "" {
+		// Hook notifications say what the agent waits for (e.g. a permission prompt)
+		message = update.message
+	}
+	return session.AgentNotification{
+		Title:   title,
+		Message: message,
+		Repo:    repoName,
+		Branch:  update.branch,
+		Key:     update.path,
 	}
 }
 
@@ -2485,19 +3946,69 @@ func (m *Model) sortWorktrees() {
 		return
 	}
 
-	// Sort: root worktree (IsCurrent=true) always first, then by LastModified (most recent first)
-	sort.SliceStable(m.worktrees, func(i, j int) bool {
-		// Root worktree always comes first
-		if m.worktrees[i].IsCurrent {
-			return true
+	// Keep the cursor on the same worktree after reordering, even one the filter hides
+	selectedPath := ""
+	if m.selectedIndex >= 0 && m.selectedIndex < len(m.worktrees) {
+		selectedPath = m.worktrees[m.selectedIndex].Path
+	}
+
+	// Sort: root worktree (IsCurrent=true) always first, then by the selected sort order
+	sortWorktreeSlice(m.worktrees, m.worktreeSort)
+
+	for i, wt := range m.worktrees {
+		if wt.Path == selectedPath {
+			m.selectedIndex = i
+			break
 		}
-		if m.worktrees[j].IsCurrent {
-			return false
+	}
+}
+
+// visibleWorktreeIndices returns the indices of worktrees that pass the current filter
+func (m Model) visibleWorktreeIndices() []int {
+	filter := parseWorktreeFilter(m.worktreeFilterInput.Value())
+	indices := make([]int, 0, len(m.worktrees))
+	for i, wt := range m.worktrees {
+		if filter.isEmpty() || filter.matches(wt) {
+			indices = append(indices, i)
 		}
+	}
+	return indices
+}
 
-		// Otherwise, sort by last modified time (most recent first)
-		return m.worktrees[i].LastModified.After(m.worktrees[j].LastModified)
-	})
+// ensureSelectionVisible moves the cursor to the first visible worktree if the selected one is filtered out
+func (m *Model) ensureSelectionVisible() {
+	visible := m.visibleWorktreeIndices()
+	if len(visible) == 0 {
+		return
+	}
+	for _, i := range visible {
+		if i == m.selectedIndex {
+			return
+		}
+	}
+	m.selectedIndex = visible[0]
+}
+
+// moveSelection moves the cursor by delta positions within the visible (filtered) worktrees
+func (m *Model) moveSelection(delta int) bool {
+	visible := m.visibleWorktreeIndices()
+	pos := -1
+	for p, i := range visible {
+		if i == m.selectedIndex {
+			pos = p
+			break
+		}
+	}
+
+	next := pos + delta
+	if pos == -1 {
+		next = 0
+	}
+	if next < 0 || next >= len(visible) {
+		return false
+	}
+	m.selectedIndex = visible[next]
+	return true
 }
 
 // markPRReady marks a draft PR as ready for review
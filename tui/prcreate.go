@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coollabsio/jean-tui/git"
+	"github.com/coollabsio/jean-tui/github"
+)
+
+// PR content modal fields after the title and description
+const (
+	prReviewersField = iota
+	prAssigneesField
+	prLabelsField
+	prMilestoneField
+)
+
+// prMetadataLabels are the labels of the PR content modal's metadata fields
+var prMetadataLabels = [4]string{"Reviewers", "Assignees", "Labels", "Milestone"}
+
+// prCreateFocus and prCancelFocus are the PR content modal's buttons
+const (
+	prCreateFocus    = 6
+	prCancelFocus    = 7
+	prModalFocusable = 8
+)
+
+// maxPRCompletions is the number of completions listed under a metadata field
+const maxPRCompletions = 5
+
+// repoMetadataLoadedMsg carries the users, labels and milestones to complete PR metadata from
+type repoMetadataLoadedMsg struct {
+	metadata *github.RepoMetadata
+	err      error
+}
+
+// codeOwnersLoadedMsg carries the code owners whose review the changes of a worktree need
+type codeOwnersLoadedMsg struct {
+	worktreePath string
+	path         string // CODEOWNERS file the owners come from
+	signOffs     []github.CodeOwnerSignOff
+	err          error
+}
+
+// maxCodeOwnersShown is the number of code owners listed in the PR content modal
+const maxCodeOwnersShown = 4
+
+// openPRContentModal opens the PR content modal for manual entry, with the description seeded
+// from the worktree's first PR template and the metadata fields cleared
+func (m Model) openPRContentModal() (Model, tea.Cmd) {
+	m.modal = prContentModal
+	m.prTemplates = github.LoadPRTemplates(m.prModalWorktreePath)
+	m.prTemplateIndex = 0
+	m.prDescriptionInput.SetValue("")
+	if len(m.prTemplates) > 0 {
+		m.prDescriptionInput.SetValue(m.prTemplates[0].Body)
+	}
+	for i := range m.prMetadataInputs {
+		m.prMetadataInputs[i].SetValue("")
+	}
+	m.prCodeOwners = nil
+	m.prCodeOwnersPath = ""
+	m = m.focusPRModalField(0)
+
+	return m, tea.Batch(
+		m.loadCodeOwners(m.prModalWorktreePath, m.baseBranchFor(m.prModalBranch)),
+		m.loadRepoMetadata(m.prModalWorktreePath),
+	)
+}
+
+// loadRepoMetadata reloads the users, labels and milestones to complete from
+// Collaborators and labels change while jean runs, the previous metadata is used until the reload arrives
+func (m Model) loadRepoMetadata(path string) tea.Cmd {
+	if m.githubManager == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		metadata, err := m.githubManager.GetRepoMetadata(path)
+		return repoMetadataLoadedMsg{metadata: metadata, err: err}
+	}
+}
+
+// loadCodeOwners matches the files changed since the base branch against the worktree's
+// CODEOWNERS file
+func (m Model) loadCodeOwners(worktreePath, baseBranch string) tea.Cmd {
+	return func() tea.Msg {
+		codeOwners := github.LoadCodeOwners(worktreePath)
+		if codeOwners == nil {
+			return nil
+		}
+		diff, err := m.gitManager.GetDiffFromBase(worktreePath, baseBranch)
+		if err != nil {
+			return codeOwnersLoadedMsg{worktreePath: worktreePath, err: err}
+		}
+		return codeOwnersLoadedMsg{
+			worktreePath: worktreePath,
+			path:         codeOwners.Path,
+			signOffs:     codeOwners.SignOffs(git.ChangedFiles(diff)),
+		}
+	}
+}
+
+// applyCodeOwners shows the code owners of the PR's changes and suggests them as reviewers,
+// unless reviewers were entered already
+func (m Model) applyCodeOwners(msg codeOwnersLoadedMsg) Model {
+	if m.modal != prContentModal || msg.worktreePath != m.prModalWorktreePath {
+		return m
+	}
+	m.prCodeOwners = msg.signOffs
+	m.prCodeOwnersPath = msg.path
+
+	reviewers := m.prMetadataInputs[prReviewersField]
+	if strings.TrimSpace(reviewers.Value()) == "" {
+		var suggested []string
+		for _, reviewer := range github.SuggestedReviewers(msg.signOffs) {
+			if !m.isPRViewer(reviewer) {
+				suggested = append(suggested, reviewer)
+			}
+		}
+		m.prMetadataInputs[prReviewersField].SetValue(strings.Join(suggested, ", "))
+		m.prMetadataInputs[prReviewersField].CursorEnd()
+	}
+	return m
+}
+
+// isPRViewer reports whether a reviewer is the user creating the PR, who can't review it
+func (m Model) isPRViewer(reviewer string) bool {
+	return m.prRepoMetadata != nil && m.prRepoMetadata.Viewer != "" &&
+		strings.EqualFold(strings.TrimPrefix(reviewer, "@"), m.prRepoMetadata.Viewer)
+}
+
+// codeOwnersSummary lists the owners whose sign-off the PR needs, owners of the most files first
+func (m Model) codeOwnersSummary() string {
+	var owners []string
+	for i, signOff := range m.prCodeOwners {
+		if i == maxCodeOwnersShown {
+			owners = append(owners, fmt.Sprintf("+%d more", len(m.prCodeOwners)-maxCodeOwnersShown))
+			break
+		}
+		owners = append(owners, fmt.Sprintf("%s (%d file%s)", signOff.Owner, len(signOff.Files), pluralize(len(signOff.Files))))
+	}
+	return strings.Join(owners, " • ")
+}
+
+// focusPRModalField focuses a field or button of the PR content modal
+func (m Model) focusPRModalField(focus int) Model {
+	m = m.blurPRModal()
+	m.prModalFocused = focus
+	m.prCompletionIndex = 0
+	switch {
+	case focus == 0:
+		m.prTitleInput.Focus()
+	case focus == 1:
+		m.prDescriptionInput.Focus()
+	case focus >= 2 && focus < prCreateFocus:
+		m.prMetadataInputs[focus-2].Focus()
+	}
+	return m
+}
+
+// blurPRModal blurs every input of the PR content modal
+func (m Model) blurPRModal() Model {
+	m.prTitleInput.Blur()
+	m.prDescriptionInput.Blur()
+	for i := range m.prMetadataInputs {
+		m.prMetadataInputs[i].Blur()
+	}
+	return m
+}
+
+// cyclePRTemplate replaces the description with the next PR template
+func (m Model) cyclePRTemplate() Model {
+	if len(m.prTemplates) == 0 {
+		return m
+	}
+	m.prTemplateIndex = (m.prTemplateIndex + 1) % len(m.prTemplates)
+	m.prDescriptionInput.SetValue(m.prTemplates[m.prTemplateIndex].Body)
+	return m
+}
+
+// selectedPRTemplate returns the body of the PR template the description was seeded from
+func (m Model) selectedPRTemplate() string {
+	if m.prTemplateIndex < len(m.prTemplates) {
+		return m.prTemplates[m.prTemplateIndex].Body
+	}
+	return ""
+}
+
+// splitPRMetadataValues splits a comma-separated metadata field into its trimmed values
+func splitPRMetadataValues(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// prCompletions returns the values matching the last, partially typed value of the focused
+// metadata field, leaving out values already entered
+func (m Model) prCompletions() []string {
+	field := m.prModalFocused - 2
+	if field < prReviewersField || field > prMilestoneField || m.prRepoMetadata == nil {
+		return nil
+	}
+
+	var candidates []string
+	switch field {
+	case prReviewersField, prAssigneesField:
+		candidates = m.prRepoMetadata.Users
+	case prLabelsField:
+		candidates = m.prRepoMetadata.Labels
+	case prMilestoneField:
+		for _, milestone := range m.prRepoMetadata.Milestones {
+			candidates = append(candidates, milestone.Title)
+		}
+	}
+
+	value := m.prMetadataInputs[field].Value()
+	entered := map[string]bool{}
+	parts := strings.Split(value, ",")
+	for _, v := range parts[:len(parts)-1] {
+		entered[strings.ToLower(strings.TrimSpace(v))] = true
+	}
+	partial := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(parts[len(parts)-1]), "@")))
+	if partial == "" {
+		return nil
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if strings.Contains(lower, partial) && lower != partial && !entered[lower] {
+			matches = append(matches, candidate)
+			if len(matches) == maxPRCompletions {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// acceptPRCompletion replaces the partially typed value of the focused metadata field with
+// the highlighted completion
+func (m Model) acceptPRCompletion(completion string) Model {
+	field := m.prModalFocused - 2
+	if field == prMilestoneField {
+		// A PR has a single milestone
+		m.prMetadataInputs[field].SetValue(completion)
+	} else {
+		value := m.prMetadataInputs[field].Value()
+		prefix := ""
+		if i := strings.LastIndex(value, ","); i >= 0 {
+			prefix = value[:i+1] + " "
+		}
+		m.prMetadataInputs[field].SetValue(prefix + completion + ", ")
+	}
+	m.prMetadataInputs[field].CursorEnd()
+	m.prCompletionIndex = 0
+	return m
+}
+
+// prMetadataFromInputs collects the metadata of the modal's fields, resolving the milestone
+// title to its number
+func (m Model) prMetadataFromInputs() (github.PRMetadata, error) {
+	metadata := github.PRMetadata{
+		Assignees: splitPRMetadataValues(m.prMetadataInputs[prAssigneesField].Value()),
+		Labels:    splitPRMetadataValues(m.prMetadataInputs[prLabelsField].Value()),
+	}
+	for _, reviewer := range splitPRMetadataValues(m.prMetadataInputs[prReviewersField].Value()) {
+		// GitHub refuses to request a review from the PR's author
+		if !m.isPRViewer(reviewer) {
+			metadata.Reviewers = append(metadata.Reviewers, reviewer)
+		}
+	}
+
+	title := strings.TrimSpace(m.prMetadataInputs[prMilestoneField].Value())
+	if title == "" {
+		return metadata, nil
+	}
+	if m.prRepoMetadata != nil {
+		for _, milestone := range m.prRepoMetadata.Milestones {
+			if strings.EqualFold(milestone.Title, title) {
+				metadata.Milestone = milestone.Number
+				return metadata, nil
+			}
+		}
+	}
+	return metadata, fmt.Errorf("unknown milestone %q", title)
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coollabsio/jean-tui/config"
+)
+
+// restackStep is a stacked branch to rebase onto its parent
+type restackStep struct {
+	branch    string
+	path      string
+	oldParent string // Parent before restacking
+	parent    string // Parent after restacking, "" = the base branch
+	forkPoint string // Commit of the old parent the branch is based on
+}
+
+// stackRestackedMsg reports the outcome of restacking
+type stackRestackedMsg struct {
+	restacked    []string // Branches rebased onto their parent
+	conflictPath string   // Worktree left with a rebase to resolve
+	warnings     []string // PRs that couldn't be retargeted
+	err          error
+}
+
+// baseBranchFor returns the branch a worktree's branch is based on: the branch it is stacked
+// on, or the repository's base branch
+func (m Model) baseBranchFor(branch string) string {
+	if m.configManager != nil {
+		if entry := m.configManager.GetStackEntry(m.repoPath, branch); entry != nil && entry.Parent != "" {
+			return entry.Parent
+		}
+	}
+	return m.baseBranch
+}
+
+// pushStackParent pushes the branch a stacked branch is based on when GitHub doesn't have it
+// yet, since its PR targets that branch
+func (m Model) pushStackParent(worktreePath, branch string) error {
+	parent := m.baseBranchFor(branch)
+	if parent == m.baseBranch {
+		return nil
+	}
+	exists, err := m.gitManager.RemoteBranchExists(worktreePath, parent)
+	if err != nil {
+		return fmt.Errorf("failed to check parent branch %s: %w", parent, err)
+	}
+	if !exists {
+		if err := m.gitManager.Push(worktreePath, parent); err != nil {
+			return fmt.Errorf("failed to push parent branch %s: %w", parent, err)
+		}
+	}
+	return nil
+}
+
+// stackChildren returns the branches stacked directly on a branch, sorted by name
+func stackChildren(stacks map[string]config.StackEntry, parent string) []string {
+	var children []string
+	for branch, entry := range stacks {
+		if entry.Parent == parent {
+			children = append(children, branch)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+// planRestack returns the branches of the stack containing branch, bottom first, each with
+// the parent it should be rebased onto. Parents that are gone (their PR merged or their
+// worktree deleted) are skipped, so their children move down the stack
+// Returns an error when the stack's parents form a cycle, which a hand-edited config can contain
+func planRestack(stacks map[string]config.StackEntry, gone func(branch string) bool, branch string) ([]restackStep, error) {
+	// Walk down to the lowest stacked branch of the stack
+	root := branch
+	visited := map[string]bool{root: true}
+	for {
+		entry, ok := stacks[root]
+		if !ok || entry.Parent == "" {
+			break
+		}
+		if _, stacked := stacks[entry.Parent]; !stacked {
+			break
+		}
+		if visited[entry.Parent] {
+			return nil, fmt.Errorf("stack of %s loops back to %s, fix the parents in the config", branch, entry.Parent)
+		}
+		visited[entry.Parent] = true
+		root = entry.Parent
+	}
+
+	var steps []restackStep
+	add := func(b string) {
+		if gone(b) {
+			return
+		}
+		entry := stacks[b]
+		parent := entry.Parent
+		for parent != "" && gone(parent) {
+			parent = stacks[parent].Parent
+		}
+		steps = append(steps, restackStep{branch: b, oldParent: entry.Parent, parent: parent, forkPoint: entry.ForkPoint})
+	}
+	if _, ok := stacks[root]; ok {
+		add(root)
+	}
+
+	// Then the branches stacked on it, level by level
+	queue := []string{root}
+	seen := map[string]bool{root: true}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range stackChildren(stacks, current) {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			queue = append(queue, child)
+			add(child)
+		}
+	}
+	return steps, nil
+}
+
+// stackParentGone reports whether a branch no longer anchors a stack: its latest PR is merged
+// or it has no worktree anymore
+func (m Model) stackParentGone(branch string) bool {
+	if m.configManager != nil {
+		if pr := m.configManager.GetLatestPR(m.repoPath, branch); pr != nil && pr.Status == "merged" {
+			return true
+		}
+	}
+	for _, wt := range m.worktrees {
+		if wt.Branch == branch {
+			return false
+		}
+	}
+	return true
+}
+
+// needsRestack returns the stacked branches whose parent is gone
+func (m Model) needsRestack() []string {
+	if m.configManager == nil {
+		return nil
+	}
+	var branches []string
+	for branch, entry := range m.configManager.GetStacks(m.repoPath) {
+		if !m.stackParentGone(branch) && (entry.Parent == "" || m.stackParentGone(entry.Parent)) {
+			branches = append(branches, branch)
+		}
+	}
+	sort.Strings(branches)
+	return branches
+}
+
+// restackWorktrees rebases each branch of the plan onto its parent (or the base branch), moving
+// the PRs of branches whose parent is gone onto their new base
+func (m Model) restackWorktrees(steps []restackStep) tea.Cmd {
+	baseBranch := m.baseBranch
+	return func() tea.Msg {
+		if err := m.gitManager.FetchRemote(); err != nil {
+			return stackRestackedMsg{err: fmt.Errorf("failed to fetch: %w", err)}
+		}
+
+		result := stackRestackedMsg{}
+		for _, step := range steps {
+			// Branches whose parent is gone move onto the base branch as fetched: the local base
+			// branch isn't updated by the fetch and may lack the merged parent
+			target, rebaseTarget := step.parent, step.parent
+			if target == "" {
+				target, rebaseTarget = baseBranch, baseBranch
+				remote := "origin/" + strings.TrimPrefix(baseBranch, "origin/")
+				if _, err := m.gitManager.ResolveRef(remote); err == nil {
+					rebaseTarget = remote
+				}
+			}
+			if op := m.gitManager.MergeOperation(step.path); op != "" {
+				result.err = fmt.Errorf("%s has a %s in progress, finish it with 'C' first", step.branch, op)
+				return result
+			}
+			if dirty, err := m.gitManager.HasUncommittedChanges(step.path); err != nil || dirty {
+				result.err = fmt.Errorf("%s has uncommitted changes, commit or stash them first", step.branch)
+				return result
+			}
+			newForkPoint, err := m.gitManager.ResolveRef(rebaseTarget)
+			if err != nil {
+				result.err = err
+				return result
+			}
+
+			rebaseErr := m.gitManager.RebaseOnto(step.path, rebaseTarget, step.forkPoint)
+			if rebaseErr != nil && !strings.Contains(rebaseErr.Error(), "rebase conflict") {
+				result.err = fmt.Errorf("%s: %w", step.branch, rebaseErr)
+				return result
+			}
+
+			// The branch now sits on the target, even while the conflicts are being resolved
+			var entry *config.StackEntry
+			if step.parent != "" {
+				entry = &config.StackEntry{Parent: step.parent, ForkPoint: newForkPoint}
+			}
+			_ = m.configManager.SetStackEntry(m.repoPath, step.branch, entry)
+
+			if step.parent != step.oldParent {
+				if pr := m.configManager.GetLatestPR(m.repoPath, step.branch); pr != nil && (pr.Status == "open" || pr.Status == "draft") {
+					if err := m.githubManager.SetPRBase(pr.URL, target); err != nil {
+						result.warnings = append(result.warnings, fmt.Sprintf("#%d: %v", pr.PRNumber, err))
+					}
+				}
+			}
+
+			if rebaseErr != nil {
+				result.conflictPath = step.path
+				result.err = fmt.Errorf("%s: %w", step.branch, rebaseErr)
+				return result
+			}
+			result.restacked = append(result.restacked, step.branch)
+		}
+		return result
+	}
+}
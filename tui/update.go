@@ -3,7 +3,6 @@ package tui
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -14,6 +13,7 @@ import (
 	"github.com/coollabsio/jean-tui/git"
 	"github.com/coollabsio/jean-tui/github"
 	"github.com/coollabsio/jean-tui/openai"
+	"github.com/coollabsio/jean-tui/session"
 )
 
 // debugLog writes a message to the debug log file if debug logging is enabled
@@ -130,6 +130,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+			// Keep the cursor on a worktree that passes the current filter
+			m.ensureSelectionVisible()
+
 			// Launch background status loaders for each worktree (non-blocking)
 			// This enables progressive status updates as each worktree's data loads
 			statusLoaders := make([]tea.Cmd, 0, len(m.worktrees))
@@ -147,13 +150,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case worktreeStatusUpdatedMsg:
 		// Update individual worktree with loaded status data (no blocking, progressive update)
-		if msg.index >= 0 && msg.index < len(m.worktrees) {
-			m.worktrees[msg.index].HasUncommitted = msg.hasUncommitted
-			m.worktrees[msg.index].AheadCount = msg.aheadCount
-			m.worktrees[msg.index].BehindCount = msg.behindCount
-			m.worktrees[msg.index].IsOutdated = msg.behindCount > 0
-			m.worktrees[msg.index].AIWaiting = msg.aiWaiting
-			m.worktrees[msg.index].Ports = msg.ports
+		index := msg.index
+		if index < 0 || index >= len(m.worktrees) || m.worktrees[index].Path != msg.path {
+			// The list was re-sorted since the status load started, look the worktree up by path
+			index = -1
+			for i, wt := range m.worktrees {
+				if wt.Path == msg.path {
+					index = i
+					break
+				}
+			}
+		}
+		if index >= 0 {
+			m.worktrees[index].HasUncommitted = msg.hasUncommitted
+			m.worktrees[index].AheadCount = msg.aheadCount
+			m.worktrees[index].BehindCount = msg.behindCount
+			m.worktrees[index].IsOutdated = msg.behindCount > 0
+			m.worktrees[index].AIWaiting = msg.aiWaiting
+			m.worktrees[index].AgentState = msg.agentState
+			m.worktrees[index].AgentMessage = msg.agentMessage
+			m.worktrees[index].Ports = msg.ports
+
+			// Status-based order depends on the data that just loaded
+			if m.worktreeSort == "status" {
+				m.sortWorktrees()
+			}
+			// Filters like is:dirty depend on it too
+			m.ensureSelectionVisible()
 		}
 		return m, nil
 
@@ -185,21 +208,92 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case prsLoadedMsg:
+		// Drop results of a search the user has moved on from
+		if m.modal != prListModal || !m.prListCreationMode || prSearchKey(msg.search) != prSearchKey(m.currentPRSearch()) {
+			m.debugLog("prsLoadedMsg handler: ignoring results of a stale search")
+			return m, nil
+		}
+		m.prLoading = false
 		if msg.err != nil {
 			m.debugLog("prsLoadedMsg handler: ERROR - " + msg.err.Error())
 			m.prLoadingError = msg.err.Error()
 			cmd = m.showErrorNotification("Failed to load PRs: "+msg.err.Error(), 4*time.Second)
 			return m, cmd
 		} else {
-			m.debugLog(fmt.Sprintf("prsLoadedMsg handler: SUCCESS - loaded %d PRs, filtering and preparing modal", len(msg.prs)))
-			m.prs = msg.prs
-			m.filteredPRs = msg.prs
-			m.prListIndex = 0
+			m.debugLog(fmt.Sprintf("prsLoadedMsg handler: SUCCESS - loaded %d PRs (after=%q)", len(msg.page.PRs), msg.search.After))
+			if m.prSearchCache == nil {
+				m.prSearchCache = make(map[string]*prSearchCache)
+			}
+			key := prSearchKey(msg.search)
+			cached := m.prSearchCache[key]
+			if msg.search.After == "" || cached == nil {
+				cached = &prSearchCache{}
+				m.prSearchCache[key] = cached
+				m.prListIndex = 0
+			}
+			cached.prs = append(cached.prs, msg.page.PRs...)
+			cached.total = msg.page.Total
+			cached.hasMore = msg.page.HasMore
+			cached.cursor = msg.page.EndCursor
+			cached.fetchedAt = time.Now()
+
+			m.prs = cached.prs
+			m.filteredPRs = cached.prs
 			m.prLoadingError = ""
-			m.debugLog(fmt.Sprintf("prsLoadedMsg handler: filteredPRs set to %d items, prListIndex=0", len(m.filteredPRs)))
 		}
 		return m, nil
 
+	case prSearchDebounceMsg:
+		// Only search once the user stopped typing
+		if msg.seq != m.prSearchSeq || m.modal != prListModal || !m.prListCreationMode {
+			return m, nil
+		}
+		return m.searchPRs(false)
+
+	case ciRunsLoadedMsg:
+		return m.applyCIRuns(msg)
+
+	case ciJobsLoadedMsg:
+		return m.applyCIJobs(msg)
+
+	case ciLogLoadedMsg:
+		return m.applyCILog(msg)
+
+	case ciRerunMsg:
+		if msg.err != nil {
+			return m, m.showErrorNotification(msg.err.Error(), 4*time.Second)
+		}
+		cmd = m.showSuccessNotification("Re-running failed jobs", 2*time.Second)
+		if m.modal == ciModal {
+			return m, tea.Batch(cmd, m.loadCIRuns(m.ciWorktree))
+		}
+		return m, cmd
+
+	case ciPollTickMsg:
+		// Stop refreshing once the modal closed or was reopened
+		if m.modal != ciModal || msg.seq != m.ciPollSeq {
+			return m, nil
+		}
+		return m, m.loadCIRuns(m.ciWorktree)
+
+	case issuesLoadedMsg:
+		return m.applyIssues(msg)
+
+	case issueSearchDebounceMsg:
+		// Only search once the user stopped typing
+		if msg.seq != m.issueSearchSeq || m.modal != issueListModal {
+			return m, nil
+		}
+		return m.searchIssues()
+
+	case issueBranchNamedMsg:
+		// Fall back to the issue title when the AI can't name the branch
+		if msg.err != nil {
+			m.debugLog(fmt.Sprintf("Failed to generate branch name for issue #%d: %v", msg.issue.Number, msg.err))
+			return m.createIssueWorktree(msg.issue, m.issueBranchName(msg.issue, msg.issue.Title))
+		}
+		return m.createIssueWorktree(msg.issue, m.issueBranchName(msg.issue, msg.branch))
+
 	case prDetailsLoadedForBranchMsg:
 		if msg.err != nil {
 			// Silently ignore errors - PR lookup failure is not critical
@@ -490,8 +584,104 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case sessionsLoadedMsg:
 		m.sessions = msg.sessions
+		m.restorableSessions = msg.restorable
+		var windowsCmd tea.Cmd
+		if m.modal == sessionListModal {
+			// Killed sessions shrink the list, keep the selection on it
+			if m.sessionIndex >= len(m.sessions) {
+				m.sessionIndex = len(m.sessions) - 1
+			}
+			if m.sessionIndex < 0 {
+				m.sessionIndex = 0
+			}
+			windowsCmd = m.selectSessionWindows()
+		}
+		if len(msg.restorable) > 0 && !m.restoreOffered {
+			// Offer once per run, the session list keeps the restore action available
+			m.restoreOffered = true
+			cmd = m.showInfoNotification(fmt.Sprintf("%d session%s lost with the tmux server, press S then r to restore", len(msg.restorable), pluralize(len(msg.restorable))))
+			return m, tea.Batch(cmd, windowsCmd)
+		}
+		return m, windowsCmd
+
+	case sessionWindowsMsg:
+		// Ignore windows of a session that is no longer selected
+		if m.modal != sessionListModal || msg.sessionName != m.sessionWindowsOf {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.sessionWindows = nil
+			return m, nil
+		}
+		m.applySessionWindows(msg)
+		return m, nil
+
+	case reviewThreadsLoadedMsg:
+		// Ignore threads of a PR that is no longer shown
+		if msg.prURL != m.reviewPR.URL {
+			return m, nil
+		}
+		m.reviewLoading = false
+		m.reviewErr = msg.err
+		m.reviewThreads = msg.threads
+		if visible := len(m.visibleReviewThreads()); m.reviewIndex >= visible {
+			m.reviewIndex = max(0, visible-1)
+		}
 		return m, nil
 
+	case reviewThreadUpdatedMsg:
+		if msg.err != nil {
+			return m, m.showErrorNotification(msg.err.Error(), 4*time.Second)
+		}
+		text := "Reply posted"
+		switch msg.action {
+		case "resolve":
+			text = "Thread resolved"
+		case "unresolve":
+			text = "Thread unresolved"
+		}
+		cmd = m.showSuccessNotification(text, 2*time.Second)
+		if m.modal == prReviewModal && msg.prURL == m.reviewPR.URL {
+			return m, tea.Batch(cmd, m.loadReviewThreads(msg.prURL))
+		}
+		return m, cmd
+
+	case sessionWindowActionMsg:
+		if msg.err != nil {
+			return m, m.showErrorNotification(fmt.Sprintf("Failed to %s %s: %v", msg.action, msg.window, msg.err), 4*time.Second)
+		}
+		text := "Window " + msg.window + " killed"
+		if msg.action == "restart" {
+			text = "Agent restarted in " + msg.window
+		}
+		cmd = m.showSuccessNotification(text, 3*time.Second)
+		if m.modal == sessionListModal && msg.sessionName == m.sessionWindowsOf {
+			return m, tea.Batch(cmd, m.loadSessionWindows(msg.sessionName), m.loadSessions())
+		}
+		return m, cmd
+
+	case sessionsRestoredMsg:
+		restored, failed := 0, 0
+		var firstErr error
+		for _, result := range msg.results {
+			if result.err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = result.err
+				}
+			} else {
+				restored++
+			}
+		}
+		statusMsg := fmt.Sprintf("Restored %d session%s", restored, pluralize(restored))
+		if failed > 0 {
+			statusMsg += fmt.Sprintf(", %d failed (%v)", failed, firstErr)
+			cmd = m.showErrorNotification(statusMsg, 5*time.Second)
+		} else {
+			cmd = m.showSuccessNotification(statusMsg, 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadSessions(), m.loadWorktrees())
+
 	case editorOpenedMsg:
 		if msg.err != nil {
 			cmd = m.showErrorNotification("Failed to open editor: " + msg.err.Error(), 4*time.Second)
@@ -532,7 +722,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 						// Trigger PR content regeneration
 						cmd = m.showWarningNotification("PR already exists. Regenerating title and description...")
-						return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.branch, m.baseBranch))
+						return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.branch, m.baseBranchFor(msg.branch)))
 					}
 				}
 			}
@@ -580,12 +770,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				statusMsg = "Draft PR created / updated"
 			}
 			cmd = m.showSuccessNotification(statusMsg + ": " + msg.prURL, 5*time.Second)
+			if msg.metadataErr != nil {
+				// The PR exists, only some of its reviewers, assignees, labels or milestone are missing
+				m.debugLog(fmt.Sprintf("Setting PR metadata failed: %v", msg.metadataErr))
+				cmd = m.showWarningNotification(statusMsg + ", but " + msg.metadataErr.Error())
+			}
 			return m, tea.Batch(
 				cmd,
 				m.loadWorktrees(),
 			)
 		}
 
+	case codeOwnersLoadedMsg:
+		// Suggestions are optional, reviewers can still be typed
+		if msg.err != nil {
+			m.debugLog(fmt.Sprintf("Failed to match CODEOWNERS: %v", msg.err))
+			return m, nil
+		}
+		m = m.applyCodeOwners(msg)
+		return m, nil
+
+	case repoMetadataLoadedMsg:
+		// Completion is optional, the metadata fields still take typed values
+		if msg.err != nil {
+			m.debugLog(fmt.Sprintf("Failed to load repository metadata: %v", msg.err))
+			return m, nil
+		}
+		m.prRepoMetadata = msg.metadata
+		return m, nil
+
 	case prBranchNameGeneratedMsg:
 		// AI branch name generated for PR
 		if msg.err != nil {
@@ -595,9 +808,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			hasAPIKey := m.configManager != nil && m.configManager.HasActiveAIProvider(m.repoPath)
 			aiContentEnabled := m.configManager != nil && m.configManager.GetAICommitEnabled()
 			if hasAPIKey && aiContentEnabled {
-				return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.oldBranchName, m.baseBranch))
+				return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.oldBranchName, m.baseBranchFor(msg.oldBranchName)))
 			}
-			return m, tea.Batch(cmd, m.createPR(msg.worktreePath, msg.oldBranchName, "", ""))
+			return m, tea.Batch(cmd, m.createPR(msg.worktreePath, msg.oldBranchName, "", "", github.PRMetadata{}))
 		}
 
 		// Check if target branch already exists locally
@@ -609,9 +822,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			hasAPIKey := m.configManager != nil && m.configManager.HasActiveAIProvider(m.repoPath)
 			aiContentEnabled := m.configManager != nil && m.configManager.GetAICommitEnabled()
 			if hasAPIKey && aiContentEnabled {
-				return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.oldBranchName, m.baseBranch))
+				return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.oldBranchName, m.baseBranchFor(msg.oldBranchName)))
 			}
-			return m, tea.Batch(cmd, m.createPR(msg.worktreePath, msg.oldBranchName, "", ""))
+			return m, tea.Batch(cmd, m.createPR(msg.worktreePath, msg.oldBranchName, "", "", github.PRMetadata{}))
 		}
 
 		// Store pending rename state
@@ -648,9 +861,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			hasAPIKey := m.configManager != nil && m.configManager.HasActiveAIProvider(m.repoPath)
 			aiContentEnabled := m.configManager != nil && m.configManager.GetAICommitEnabled()
 			if hasAPIKey && aiContentEnabled {
-				return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.oldBranchName, m.baseBranch))
+				return m, tea.Batch(cmd, m.generatePRContent(msg.worktreePath, msg.oldBranchName, m.baseBranchFor(msg.oldBranchName)))
 			}
-			return m, tea.Batch(cmd, m.createPR(msg.worktreePath, msg.oldBranchName, "", ""))
+			return m, tea.Batch(cmd, m.createPR(msg.worktreePath, msg.oldBranchName, "", "", github.PRMetadata{}))
 		}
 
 		cmd = m.showInfoNotification("Renaming branch locally...")
@@ -673,26 +886,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(
 				cmd,
 				m.renameSessionsForBranch(msg.oldBranchName, msg.newBranchName),
-				m.generatePRContent(msg.worktreePath, msg.newBranchName, m.baseBranch),
+				m.generatePRContent(msg.worktreePath, msg.newBranchName, m.baseBranchFor(msg.newBranchName)),
 			)
 		} else {
 			// No AI - open PR content modal for manual entry
-			m.modal = prContentModal
-			m.prModalFocused = 0
 			m.prModalWorktreePath = msg.worktreePath
 			m.prModalBranch = msg.newBranchName
+			m, cmd = m.openPRContentModal()
 
 			// Default title to new branch name
 			defaultTitle := strings.ReplaceAll(msg.newBranchName, "-", " ")
 			defaultTitle = strings.ReplaceAll(defaultTitle, "_", " ")
 			defaultTitle = strings.Title(defaultTitle)
 			m.prTitleInput.SetValue(defaultTitle)
-			m.prTitleInput.Focus()
-			m.prDescriptionInput.SetValue("")
 
 			// Rename tmux sessions
-			cmd = m.renameSessionsForBranch(msg.oldBranchName, msg.newBranchName)
-			return m, cmd
+			return m, tea.Batch(cmd, m.renameSessionsForBranch(msg.oldBranchName, msg.newBranchName))
 		}
 
 	case commitCreatedMsg:
@@ -743,7 +952,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if shouldAIRename {
 					// Start AI rename flow before PR creation
 					cmd = m.showInfoNotification("🤖 Generating semantic branch name...")
-					return m, tea.Batch(cmd, m.generateBranchNameForPR(m.prCreationPending, branch, m.baseBranch))
+					return m, tea.Batch(cmd, m.generateBranchNameForPR(m.prCreationPending, branch, m.baseBranchFor(branch)))
 				} else {
 					// No AI rename needed - use default PR state from config
 					prState := m.configManager.GetPRDefaultState(m.repoPath)
@@ -761,15 +970,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if aiEnabled {
 						// Generate PR content with AI
 						cmd := m.showSuccessNotification("Committed successfully. Generating PR content...", 2*time.Second)
-						return m, tea.Batch(cmd, m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranch))
+						return m, tea.Batch(cmd, m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranchFor(m.prModalBranch)))
 					}
 
 					// No AI - open PR content modal for manual input
-					m.modal = prContentModal
-					m.prModalFocused = 0
-					m.prTitleInput.Focus()
-					m.prDescriptionInput.Blur()
-					return m, m.showSuccessNotification("Committed successfully. Enter PR details:", 2*time.Second)
+					m, cmd = m.openPRContentModal()
+					return m, tea.Batch(cmd, m.showSuccessNotification("Committed successfully. Enter PR details:", 2*time.Second))
 				}
 			}
 
@@ -793,7 +999,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if shouldAIRename {
 					// Start AI rename flow before push
 					notifyCmd := m.showInfoNotification("🤖 Generating semantic branch name...")
-					return m, tea.Batch(notifyCmd, m.generateBranchNameForPush(wt.Path, wt.Branch, m.baseBranch))
+					return m, tea.Batch(notifyCmd, m.generateBranchNameForPush(wt.Path, wt.Branch, m.baseBranchFor(wt.Branch)))
 				} else {
 					// No AI rename needed, go straight to push
 					notifyCmd := m.showInfoNotification("Pushing to remote...")
@@ -827,7 +1033,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if shouldAIRename {
 			// Start AI rename flow before PR creation
 			cmd = m.showInfoNotification("🤖 Generating semantic branch name...")
-			return m, tea.Batch(cmd, m.generateBranchNameForPR(msg.worktreePath, msg.branch, m.baseBranch))
+			return m, tea.Batch(cmd, m.generateBranchNameForPR(msg.worktreePath, msg.branch, m.baseBranchFor(msg.branch)))
 		} else {
 			// No AI rename needed - use default PR state from config
 			prState := m.configManager.GetPRDefaultState(m.repoPath)
@@ -842,15 +1048,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			if aiEnabled {
 				// Generate PR content with AI
-				return m, m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranch)
+				return m, m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranchFor(m.prModalBranch))
 			}
 
 			// No AI - open PR content modal for manual input
-			m.modal = prContentModal
-			m.prModalFocused = 0
-			m.prTitleInput.Focus()
-			m.prDescriptionInput.Blur()
-			return m, nil
+			return m.openPRContentModal()
 		}
 
 	case prContentGeneratedMsg:
@@ -903,9 +1105,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
-		// Not in modal - auto-create or update PR with generated content (for auto-generation flow)
-		cmd = m.showInfoNotification("Creating or updating draft PR...")
-		return m, tea.Batch(cmd, m.createOrUpdatePR(msg.worktreePath, msg.branch, msg.title, msg.description))
+		// Not in modal - open it with the generated content, so reviewers, assignees, labels and
+		// the milestone can be set before the PR is created
+		m.prModalWorktreePath = msg.worktreePath
+		m.prModalBranch = msg.branch
+		m, cmd = m.openPRContentModal()
+		m.prTitleInput.SetValue(msg.title)
+		m.prDescriptionInput.SetValue(msg.description)
+		return m, tea.Batch(cmd, m.showSuccessNotification("PR content generated! Review and press Enter to create", 3*time.Second))
 
 	case pushBranchNameGeneratedMsg:
 		// AI branch name generated for push
@@ -1000,12 +1207,69 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case stackRestackedMsg:
+		if len(msg.warnings) > 0 {
+			m.debugLog("Failed to retarget stacked PRs: " + strings.Join(msg.warnings, "; "))
+		}
+		if msg.conflictPath != "" {
+			// The rebase is left in progress, resolve it like any other conflict
+			m.conflictFromLocalMerge = false
+			cmd = m.showWarningNotification(fmt.Sprintf("Rebase conflict! %s\nOpening conflict resolution...", msg.err.Error()))
+			return m, tea.Batch(cmd, m.loadConflicts(msg.conflictPath))
+		}
+		if msg.err != nil {
+			cmd = m.showErrorNotification("Failed to restack: "+msg.err.Error(), 5*time.Second)
+			return m, tea.Batch(cmd, m.loadWorktrees())
+		}
+		message := fmt.Sprintf("Restacked %d branches. Push them to update their PRs", len(msg.restacked))
+		if len(msg.warnings) > 0 {
+			cmd = m.showWarningNotification(message + "\nSome PRs couldn't be retargeted: " + strings.Join(msg.warnings, ", "))
+		} else {
+			cmd = m.showSuccessNotification(message, 3*time.Second)
+		}
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case mergedCleanupMsg:
+		var parts []string
+		if len(msg.cleaned) > 0 {
+			parts = append(parts, fmt.Sprintf("Cleaned up %s", strings.Join(msg.cleaned, ", ")))
+		}
+		if msg.pulledBase {
+			parts = append(parts, "pulled "+m.baseBranch)
+		}
+		if len(msg.skipped) > 0 {
+			parts = append(parts, fmt.Sprintf("kept %s (uncommitted changes)", strings.Join(msg.skipped, ", ")))
+		}
+		if len(msg.unmerged) > 0 {
+			parts = append(parts, fmt.Sprintf("kept %s (commits not in the merged PR)", strings.Join(msg.unmerged, ", ")))
+		}
+		if len(msg.keptBranch) > 0 {
+			parts = append(parts, fmt.Sprintf("kept branch %s (not fully merged)", strings.Join(msg.keptBranch, ", ")))
+		}
+		if len(msg.restack) > 0 {
+			parts = append(parts, fmt.Sprintf("press 'U' to restack %s", strings.Join(msg.restack, ", ")))
+		}
+		switch {
+		case len(msg.failures) > 0:
+			cmd = m.showErrorNotification("Cleanup failed: "+strings.Join(msg.failures, "; "), 5*time.Second)
+		case len(msg.skipped) > 0 || len(msg.unmerged) > 0 || len(msg.keptBranch) > 0 || len(msg.restack) > 0:
+			cmd = m.showWarningNotification(strings.Join(parts, ", "))
+		default:
+			cmd = m.showSuccessNotification(strings.Join(parts, ", "), 3*time.Second)
+		}
+		if m.selectedIndex >= len(m.worktrees)-len(msg.cleaned) {
+			m.selectedIndex = max(0, len(m.worktrees)-len(msg.cleaned)-1)
+		}
+		m = m.nextMergedCleanup()
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
 	case branchPulledMsg:
 		if msg.err != nil {
 			if msg.hadConflict {
-				// Show error with abort option
-				cmd = m.showWarningNotification("Merge conflict! Run 'git merge --abort' in the worktree to abort.")
-				return m, cmd
+				// Open the conflict resolution modal for the worktree
+				m.conflictFromLocalMerge = false
+				cmd = m.showWarningNotification("Merge conflict! Opening conflict resolution...")
+				return m, tea.Batch(cmd, m.loadConflicts(msg.worktreePath))
 			} else if strings.Contains(msg.err.Error(), "already up-to-date") {
 				// User tried to pull but worktree is already up-to-date (after checking fresh refs)
 				cmd = m.showInfoNotification("Worktree is already up-to-date with base branch")
@@ -1042,10 +1306,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case localMergeCompletedMsg:
 		if msg.err != nil {
 			if msg.hadConflict {
-				// Show error with abort option
-				cmd = m.showWarningNotification("Merge conflict! Resolve conflicts and commit, or run 'git merge --abort' in main repo.")
+				// Open the conflict resolution modal for the main repo
+				m.conflictFromLocalMerge = true
+				cmd = m.showWarningNotification("Merge conflict! Opening conflict resolution...")
 				return m, tea.Batch(
 					cmd,
+					m.loadConflicts(msg.mergePath),
 					m.loadWorktrees(), // Refresh to show updated state
 				)
 			} else {
@@ -1065,6 +1331,262 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update worktree list to show we're now on base branch
 		return m, m.loadWorktrees()
 
+	case conflictsLoadedMsg:
+		if msg.err != nil {
+			cmd = m.showErrorNotification("Failed to load conflicts: " + msg.err.Error(), 5*time.Second)
+			return m, cmd
+		}
+
+		if msg.operation == "" && len(msg.files) == 0 {
+			if m.modal == conflictResolutionModal {
+				m.modal = noModal
+			}
+			cmd = m.showInfoNotification("No merge or rebase in progress")
+			return m, cmd
+		}
+
+		// Only reset selection when the modal is opened fresh
+		if m.modal != conflictResolutionModal {
+			m.conflictStatus = ""
+			m.conflictFileIndex = 0
+		}
+		m.conflictPath = msg.path
+		m.conflictOperation = msg.operation
+		m.conflictFiles = msg.files
+		m.conflictFile = nil
+		m.conflictHunkIndex = 0
+		if m.conflictFileIndex >= len(m.conflictFiles) {
+			m.conflictFileIndex = max(0, len(m.conflictFiles)-1)
+		}
+		m.modal = conflictResolutionModal
+		m.debugLog(fmt.Sprintf("Loaded %d conflicted file(s) in %s (%s)", len(msg.files), msg.path, msg.operation))
+		return m, nil
+
+	case conflictFileLoadedMsg:
+		if msg.err != nil {
+			m.conflictStatus = "Error: " + msg.err.Error()
+			return m, nil
+		}
+
+		if len(msg.file.Hunks) == 0 {
+			m.conflictStatus = fmt.Sprintf("No conflict markers left in %s. Press 'a' to mark it resolved.", msg.file.Path)
+			return m, nil
+		}
+
+		m.conflictFile = msg.file
+		m.conflictHunkIndex = 0
+		m.conflictStatus = ""
+		return m, nil
+
+	case conflictSuggestionMsg:
+		m.generatingConflictFix = false
+
+		// Ignore stale suggestions for a file that is no longer open
+		if m.conflictFile == nil || m.conflictFile.Path != msg.path || msg.hunkIndex >= len(m.conflictFile.Hunks) {
+			return m, nil
+		}
+
+		if msg.err != nil {
+			m.conflictStatus = "AI suggestion failed: " + msg.err.Error()
+			return m, nil
+		}
+
+		m.conflictFile.Hunks[msg.hunkIndex].Choice = git.ConflictCustom
+		m.conflictFile.Hunks[msg.hunkIndex].Resolution = msg.resolution
+		m.conflictStatus = fmt.Sprintf("AI suggestion applied to hunk %d. Review it before saving.", msg.hunkIndex+1)
+		return m, nil
+
+	case conflictFileResolvedMsg:
+		if msg.err != nil {
+			m.conflictStatus = "Error: " + msg.err.Error()
+			return m, nil
+		}
+
+		m.conflictStatus = "✓ Resolved " + msg.path
+		return m, m.loadConflicts(m.conflictPath)
+
+	case mergeContinuedMsg:
+		if msg.err != nil {
+			m.conflictStatus = "Error: " + msg.err.Error()
+			// A rebase can stop again with new conflicts
+			return m, m.loadConflicts(m.conflictPath)
+		}
+
+		m.debugLog(fmt.Sprintf("Conflicted %s completed in %s", m.conflictOperation, m.conflictPath))
+		m.conflictFiles = nil
+		m.conflictFile = nil
+
+		if m.conflictFromLocalMerge {
+			// Finish the local merge flow with the usual cleanup prompt
+			m.conflictFromLocalMerge = false
+			m.postMergeDeleteIndex = 0
+			m.modal = postMergeCleanupModal
+			return m, m.loadWorktrees()
+		}
+
+		m.modal = noModal
+		cmd = m.showSuccessNotification("Conflicts resolved and "+m.conflictOperation+" completed", 3*time.Second)
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case mergeAbortedMsg:
+		if msg.err != nil {
+			m.conflictStatus = "Error: " + msg.err.Error()
+			return m, nil
+		}
+
+		m.modal = noModal
+		m.conflictFiles = nil
+		m.conflictFile = nil
+		m.conflictFromLocalMerge = false
+		cmd = m.showInfoNotification("Aborted " + m.conflictOperation)
+		return m, tea.Batch(cmd, m.loadWorktrees())
+
+	case stashesLoadedMsg:
+		if msg.err != nil {
+			m.stashStatus = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.stashes = msg.stashes
+		if visible := m.visibleStashes(); m.stashIndex >= len(visible) {
+			m.stashIndex = max(0, len(visible)-1)
+		}
+		return m, nil
+
+	case stashActionMsg:
+		if msg.err != nil {
+			m.stashStatus = "Error: " + msg.err.Error()
+			// A conflicting pop/apply still changes the worktree
+			return m, tea.Batch(m.loadStashes(m.stashWorktreePath), m.loadWorktrees())
+		}
+
+		switch msg.action {
+		case "push":
+			m.stashStatus = "✓ Changes stashed"
+		case "pop":
+			m.stashStatus = "✓ Popped " + msg.ref
+		case "apply":
+			m.stashStatus = "✓ Applied " + msg.ref
+		case "drop":
+			m.stashStatus = "✓ Dropped " + msg.ref
+		}
+		m.debugLog(fmt.Sprintf("Stash %s %s in %s", msg.action, msg.ref, m.stashWorktreePath))
+		return m, tea.Batch(m.loadStashes(m.stashWorktreePath), m.loadWorktrees())
+
+	case panePreviewTickMsg:
+		// Refresh the preview of the selected worktree while the main view is visible
+		next := m.schedulePanePreview()
+		if m.modal == sessionListModal && m.sessionWindowsOf != "" {
+			// Keep the process and resource info of the session list current
+			return m, tea.Batch(next, m.loadSessionWindows(m.sessionWindowsOf))
+		}
+		if m.previewMode == "off" || m.modal != noModal {
+			return m, next
+		}
+		wt := m.selectedWorktree()
+		if wt == nil || !m.sessionRunning(wt.ClaudeSessionName) {
+			m.previewLines = nil
+			m.previewPath = ""
+			return m, next
+		}
+		return m, tea.Batch(next, m.capturePanePreview(*wt))
+
+	case panePreviewMsg:
+		// Ignore captures that finished after the selection moved on
+		if wt := m.selectedWorktree(); wt != nil && wt.Path == msg.path {
+			m.previewPath = msg.path
+			m.previewWindow = msg.window
+			m.previewLines = msg.lines
+		}
+		return m, nil
+
+	case promptSentMsg:
+		sent, skipped, failed := 0, 0, 0
+		var firstErr error
+		for _, result := range msg.results {
+			switch {
+			case result.skipped != "":
+				skipped++
+			case result.err != nil:
+				failed++
+				if firstErr == nil {
+					firstErr = result.err
+				}
+			default:
+				sent++
+			}
+		}
+
+		if len(msg.results) == 1 {
+			result := msg.results[0]
+			switch {
+			case result.skipped != "":
+				return m, m.showWarningNotification(fmt.Sprintf("%s: %s", result.branch, result.skipped))
+			case result.err != nil:
+				return m, m.showErrorNotification("Failed to send prompt: "+result.err.Error(), 4*time.Second)
+			}
+			return m, m.showSuccessNotification("Prompt sent to "+result.branch, 2*time.Second)
+		}
+
+		statusMsg := fmt.Sprintf("Prompt sent to %d worktree%s", sent, pluralize(sent))
+		if skipped > 0 {
+			statusMsg += fmt.Sprintf(", %d without a running session", skipped)
+		}
+		if failed > 0 {
+			statusMsg += fmt.Sprintf(", %d failed (%v)", failed, firstErr)
+			return m, m.showErrorNotification(statusMsg, 4*time.Second)
+		}
+		return m, m.showSuccessNotification(statusMsg, 3*time.Second)
+
+	case gcScannedMsg:
+		if m.modal != gcModal {
+			return m, nil
+		}
+		m.gcItems = msg.items
+		m.gcErr = msg.err
+		m.gcStage = 1
+		return m, nil
+
+	case gcCleanedMsg:
+		m.gcResults = msg.results
+		m.gcStage = 3
+		// Deleted branches and killed sessions change both lists
+		return m, tea.Batch(m.loadWorktrees(), m.loadSessions())
+
+	case bulkActionCompletedMsg:
+		m.bulkResults = msg.results
+		m.bulkStage = 3
+
+		succeeded, skipped, failed := 0, 0, 0
+		for _, result := range msg.results {
+			switch {
+			case result.skipped != "":
+				skipped++
+			case result.err != nil:
+				failed++
+			default:
+				succeeded++
+				// Processed worktrees are unmarked; skipped and failed ones stay marked for a retry
+				delete(m.markedWorktrees, result.path)
+			}
+		}
+
+		statusMsg := fmt.Sprintf("Bulk %s: %d succeeded", msg.action, succeeded)
+		if skipped > 0 {
+			statusMsg += fmt.Sprintf(", %d skipped", skipped)
+		}
+		if failed > 0 {
+			statusMsg += fmt.Sprintf(", %d failed", failed)
+			cmd = m.showErrorNotification(statusMsg, 4*time.Second)
+		} else {
+			cmd = m.showSuccessNotification(statusMsg, 3*time.Second)
+		}
+
+		cmds := []tea.Cmd{cmd, m.loadWorktrees()}
+		if msg.action == "delete" || msg.action == "kill" {
+			cmds = append(cmds, m.loadSessions())
+		}
+		return m, tea.Batch(cmds...)
+
 	case refreshWithPullMsg:
 		if msg.err != nil {
 			cmd = m.showErrorNotification("Failed to refresh: " + msg.err.Error(), 5*time.Second)
@@ -1072,18 +1594,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			// Build detailed status message based on what was pulled
 			statusMsg := buildRefreshStatusMessage(msg)
-
-			// If there was an error pulling the main repo branch, append it to the message
-			if msg.pullErr != nil {
-				statusMsg += " (pull error: " + msg.pullErr.Error() + ")"
-				cmd = m.showWarningNotification(statusMsg)
-			} else {
-				cmd = m.showSuccessNotification(statusMsg, 3*time.Second)
-			}
+			m.refreshResults = msg.results
 
 			// Only show notification if not initializing (suppress during startup)
 			if !m.isInitializing {
-				cmd = m.showSuccessNotification(buildRefreshStatusMessage(msg), 3*time.Second)
+				if refreshNeedsAttention(msg.results) {
+					// Show per-worktree outcomes when something was skipped or failed
+					m.refreshSummaryIndex = 0
+					m.modal = refreshSummaryModal
+					cmd = m.showWarningNotification(statusMsg)
+				} else {
+					cmd = m.showSuccessNotification(statusMsg, 3*time.Second)
+				}
 			}
 			// Reload worktree list to show updated status
 			return m, tea.Batch(
@@ -1098,7 +1620,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.loadWorktrees()
 		}
 		// Reload worktrees to show updated PR statuses
-		return m, m.loadWorktrees()
+		var cmds []tea.Cmd
+		if len(msg.merged) > 0 {
+			if stale := m.needsRestack(); len(stale) > 0 {
+				cmds = append(cmds, m.showWarningNotification(fmt.Sprintf("The branch below %s merged. Press 'U' to restack", strings.Join(stale, ", "))))
+			}
+		}
+		// PRs merged on GitHub are cleaned up according to the repository's policy
+		m, cmd = m.offerMergedCleanup(msg.merged)
+		cmds = append(cmds, cmd, m.loadWorktrees())
+		return m, tea.Batch(cmds...)
 
 	case activityTickMsg:
 		// Check if enough time has passed since last activity check
@@ -1110,13 +1641,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.scheduleActivityCheck()
 
 	case activityCheckedMsg:
+		var notifyCmd tea.Cmd
 		if msg.err == nil {
 			// Update sessions with activity information
 			m.sessions = msg.sessions
+			notifyCmd = m.applyAgentStates(msg.agentStates)
+			// Filters like is:waiting depend on the agent states
+			m.ensureSelectionVisible()
 		}
 		// Continue scheduling activity checks
 		cmd = m.scheduleActivityCheck()
-		return m, cmd
+		return m, tea.Batch(cmd, notifyCmd)
+
+	case terminalSequenceWrittenMsg:
+		// The frame carrying the sequence has been rendered, later frames must not repeat it
+		m.terminalSequence = ""
+		return m, nil
 
 	case versionCheckMsg:
 		// Silently handle errors (don't show error notification for version check failures)
@@ -1295,7 +1835,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Start AI rename flow before PR creation
 				cmd := m.showInfoNotification("🤖 Generating semantic branch name...")
 				m.prCreationPending = wt.Path // Set to trigger PR creation after rename
-				return m, tea.Batch(cmd, m.generateBranchNameForPush(wt.Path, wt.Branch, m.baseBranch))
+				return m, tea.Batch(cmd, m.generateBranchNameForPush(wt.Path, wt.Branch, m.baseBranchFor(wt.Branch)))
 			} else {
 				// No AI rename needed - use default PR state from config
 				prState := m.configManager.GetPRDefaultState(m.repoPath)
@@ -1310,15 +1850,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				if aiEnabled {
 					// Generate PR content with AI
-					return m, m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranch)
+					return m, m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranchFor(m.prModalBranch))
 				}
 
 				// No AI - open PR content modal for manual input
-				m.modal = prContentModal
-				m.prModalFocused = 0
-				m.prTitleInput.Focus()
-				m.prDescriptionInput.Blur()
-				return m, nil
+				return m.openPRContentModal()
 			}
 		}
 
@@ -1371,6 +1907,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Execute on-switch hooks before switching (non-blocking)
 			m.gitManager.ExecuteOnSwitchHooks(m.pendingSwitchInfo.Path, m.pendingSwitchInfo.Branch)
 
+			// Build new sessions with the configured tmux layout; the shell wrapper then only attaches
+			// Zellij sessions are always built here, the shell wrapper never creates them
+			layout := m.sessionLayout()
+			mux := m.sessionManager.Multiplexer()
+			if (layout != nil || mux.Name() != session.MultiplexerTmux) && mux.IsAvailable() {
+				info := m.pendingSwitchInfo
+				if err := m.sessionManager.EnsureSession(info.SessionName, info.Path, info.AutoClaude, info.IsClaudeInitialized, layout); err != nil {
+					m.debugLog(fmt.Sprintf("Failed to prepare %s session %s: %v", mux.Name(), info.SessionName, err))
+				}
+			}
+
 			m.switchInfo = *m.pendingSwitchInfo
 			m.pendingSwitchInfo = nil
 			return m, tea.Quit
@@ -1422,9 +1969,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			_ = m.configManager.UpdatePRStatus(m.repoPath, msg.branch, msg.prURL, "merged")
 		}
 
-		// Show success and reload worktrees
+		// Show success and reload worktrees, then offer to clean the worktree up
 		cmd = m.showSuccessNotification("PR merged successfully!", 3*time.Second)
-		return m, tea.Batch(cmd, m.loadWorktrees())
+		var cleanupCmd tea.Cmd
+		number := 0
+		if m.configManager != nil {
+			if latest := m.configManager.GetLatestPR(m.repoPath, msg.branch); latest != nil {
+				number = latest.PRNumber
+			}
+		}
+		if pr := m.mergedPRFor(msg.branch, msg.prURL, number); pr != nil {
+			m, cleanupCmd = m.offerMergedCleanup([]mergedPR{*pr})
+		}
+		return m, tea.Batch(cmd, cleanupCmd, m.loadWorktrees())
 	}
 
 	return m, cmd
@@ -1432,6 +1989,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+
+	// Filter input is focused - keys go to the filter query
+	if m.worktreeFilterActive {
+		return m.handleWorktreeFilterInput(msg)
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		// Clear switch info to prevent shell wrapper from switching directories
@@ -1439,8 +2002,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case "up":
-		if m.selectedIndex > 0 {
-			m.selectedIndex--
+		if m.moveSelection(-1) {
 			// Save the last selected branch
 			if wt := m.selectedWorktree(); wt != nil && m.configManager != nil {
 				_ = m.configManager.SetLastSelectedBranch(m.repoPath, wt.Branch)
@@ -1448,50 +2010,215 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "down":
-		if m.selectedIndex < len(m.worktrees)-1 {
-			m.selectedIndex++
+		if m.moveSelection(1) {
 			// Save the last selected branch
 			if wt := m.selectedWorktree(); wt != nil && m.configManager != nil {
 				_ = m.configManager.SetLastSelectedBranch(m.repoPath, wt.Branch)
 			}
 		}
 
-	case "r":
-		// Refresh: pull latest commits, refresh PR statuses, and load PR details for all worktrees
-		cmd = m.showInfoNotification("Pulling latest commits and refreshing...")
-		return m, tea.Batch(cmd, m.refreshWithPull(), m.refreshPRStatuses(), m.loadPRDetailsForAllWorktrees(), m.checkSessionActivity())
+	case "/":
+		// Filter worktrees (fuzzy text + is:dirty/is:behind/is:pr/is:waiting/is:attention)
+		m.worktreeFilterActive = true
+		return m, m.worktreeFilterInput.Focus()
 
-	case "n":
-		// Open create with custom name modal
-		m.modal = createWithNameModal
-		m.sessionNameInput.SetValue("")  // Start with empty input
-		m.sessionNameInput.Focus()       // Focus the input field
-		m.modalFocused = 0               // Focus on input field
+	case "esc":
+		// Clear an applied filter first, then any marked worktrees
+		if m.worktreeFilterInput.Value() != "" {
+			m.worktreeFilterInput.SetValue("")
+		} else if len(m.markedWorktrees) > 0 {
+			m.markedWorktrees = nil
+		}
 		return m, nil
 
-	case "b":
-		// Open change base branch modal (b for base branch)
-		m.modal = changeBaseBranchModal
-		m.modalFocused = 0
-		m.branchIndex = 0
-		m.searchInput.SetValue("")
-		m.searchInput.Focus()
-		m.filteredBranches = nil
-		// Try to find current base branch in the list
-		return m, m.loadBranches
+	case " ":
+		// Toggle mark on the selected worktree for bulk actions, then move down
+		if wt := m.selectedWorktree(); wt != nil {
+			if m.markedWorktrees == nil {
+				m.markedWorktrees = make(map[string]bool)
+			}
+			if m.markedWorktrees[wt.Path] {
+				delete(m.markedWorktrees, wt.Path)
+			} else {
+				m.markedWorktrees[wt.Path] = true
+			}
+			m.moveSelection(1)
+		}
+		return m, nil
 
-	case "a":
-		// Open create from existing branch modal
-		m.modal = branchSelectModal
-		m.modalFocused = 0
-		m.createNewBranch = false
-		m.branchIndex = 0
-		m.searchInput.SetValue("")
-		m.searchInput.Focus()
-		m.filteredBranches = nil
-		return m, m.loadBranches
+	case "X":
+		// Open bulk actions for marked worktrees
+		if len(m.markedWorktrees) == 0 {
+			cmd = m.showWarningNotification("Mark worktrees with space first")
+			return m, cmd
+		}
+		m.bulkStage = 0
+		m.bulkActionIndex = 0
+		m.bulkAction = ""
+		m.bulkForce = false
+		m.bulkResults = nil
+		m.modal = bulkActionModal
+		return m, nil
 
-	case "d":
+	case "A":
+		// Send a prompt to the agent of the marked worktrees (or the selected one) without attaching
+		targets := m.markedWorktreeList()
+		if len(targets) == 0 {
+			if wt := m.selectedWorktree(); wt != nil {
+				targets = []git.Worktree{*wt}
+			}
+		}
+		if len(targets) == 0 {
+			return m, nil
+		}
+		m.promptTargets = targets
+		m.promptInput.Reset()
+		m.modal = sendPromptModal
+		return m, m.promptInput.Focus()
+
+	case "V":
+		// Read and answer the review threads of the selected worktree's latest PR
+		wt := m.selectedWorktree()
+		if wt == nil {
+			return m, nil
+		}
+		prs, _ := wt.PRs.([]config.PRInfo)
+		if len(prs) == 0 {
+			return m, m.showWarningNotification("No PR for " + wt.Branch)
+		}
+		m.reviewPR = prs[len(prs)-1]
+		m.reviewWorktree = *wt
+		m.reviewThreads = nil
+		m.reviewErr = nil
+		m.reviewIndex = 0
+		m.reviewShowResolved = false
+		m.reviewReplying = false
+		m.reviewLoading = true
+		m.modal = prReviewModal
+		return m, m.loadReviewThreads(m.reviewPR.URL)
+
+	case "J":
+		// Show the CI runs and jobs of the selected worktree's branch
+		wt := m.selectedWorktree()
+		if wt == nil || strings.HasPrefix(wt.Branch, "(detached") {
+			return m, nil
+		}
+		return m.openCIModal(*wt)
+
+	case "W":
+		// Cycle the details pane preview: agent window, terminal window, off
+		next := previewModes[0]
+		for i, mode := range previewModes {
+			if mode == m.previewMode {
+				next = previewModes[(i+1)%len(previewModes)]
+				break
+			}
+		}
+		m.previewMode = next
+		m.previewLines = nil
+		m.previewPath = ""
+		cmd = m.showInfoNotification("Preview: " + next)
+		if wt := m.selectedWorktree(); wt != nil && next != "off" && m.sessionRunning(wt.ClaudeSessionName) {
+			return m, tea.Batch(cmd, m.capturePanePreview(*wt))
+		}
+		return m, cmd
+
+	case "O":
+		// Cycle worktree list sort order (persisted per repository)
+		next := worktreeSortOrders[0]
+		for i, order := range worktreeSortOrders {
+			if order == m.worktreeSort {
+				next = worktreeSortOrders[(i+1)%len(worktreeSortOrders)]
+				break
+			}
+		}
+		m.worktreeSort = next
+		m.sortWorktrees()
+		if m.configManager != nil {
+			if err := m.configManager.SetWorktreeSort(m.repoPath, next); err != nil {
+				m.debugLog(fmt.Sprintf("Failed to save worktree sort order: %v", err))
+			}
+		}
+		return m, m.showInfoNotification("Sorted by " + worktreeSortLabel(next))
+
+	case "r":
+		// Refresh: pull latest commits, refresh PR statuses, and load PR details for all worktrees
+		cmd = m.showInfoNotification("Pulling latest commits and refreshing...")
+		return m, tea.Batch(cmd, m.refreshWithPull(), m.refreshPRStatuses(), m.loadPRDetailsForAllWorktrees(), m.checkSessionActivity())
+
+	case "n":
+		// Open create with custom name modal
+		m.stackParent = ""
+		m.modal = createWithNameModal
+		m.sessionNameInput.SetValue("")  // Start with empty input
+		m.sessionNameInput.Focus()       // Focus the input field
+		m.modalFocused = 0               // Focus on input field
+		return m, nil
+
+	case "T":
+		// Create a worktree stacked on the selected one (its PR targets the selected branch)
+		if wt := m.selectedWorktree(); wt != nil {
+			if wt.Branch == "" || strings.HasPrefix(wt.Branch, "(detached") {
+				return m, m.showWarningNotification("Can only stack on a branch")
+			}
+			if wt.Branch == m.baseBranch {
+				return m, m.showWarningNotification("Use 'n' to create a worktree from the base branch")
+			}
+			m.stackParent = wt.Branch
+			m.modal = createWithNameModal
+			m.sessionNameInput.SetValue("")
+			m.sessionNameInput.Focus()
+			m.modalFocused = 0
+		}
+		return m, nil
+
+	case "U":
+		// Restack: rebase the selected worktree's stack onto its parents, skipping merged ones
+		if wt := m.selectedWorktree(); wt != nil {
+			if m.baseBranch == "" {
+				return m, m.showWarningNotification("Base branch not set. Press 'b' to set base branch")
+			}
+			steps, err := planRestack(m.configManager.GetStacks(m.repoPath), m.stackParentGone, wt.Branch)
+			if err != nil {
+				return m, m.showErrorNotification(err.Error(), 4*time.Second)
+			}
+			paths := make(map[string]string)
+			for _, w := range m.worktrees {
+				paths[w.Branch] = w.Path
+			}
+			for i := range steps {
+				steps[i].path = paths[steps[i].branch]
+			}
+			if len(steps) == 0 {
+				return m, m.showInfoNotification("Not part of a stack. Press 'T' to stack a worktree on this one")
+			}
+			cmd = m.showInfoNotification(fmt.Sprintf("Restacking %d branches...", len(steps)))
+			return m, tea.Batch(cmd, m.restackWorktrees(steps))
+		}
+
+	case "b":
+		// Open change base branch modal (b for base branch)
+		m.modal = changeBaseBranchModal
+		m.modalFocused = 0
+		m.branchIndex = 0
+		m.searchInput.SetValue("")
+		m.searchInput.Focus()
+		m.filteredBranches = nil
+		// Try to find current base branch in the list
+		return m, m.loadBranches
+
+	case "a":
+		// Open create from existing branch modal
+		m.modal = branchSelectModal
+		m.modalFocused = 0
+		m.createNewBranch = false
+		m.branchIndex = 0
+		m.searchInput.SetValue("")
+		m.searchInput.Focus()
+		m.filteredBranches = nil
+		return m, m.loadBranches
+
+	case "d":
 		// Open delete modal
 		if wt := m.selectedWorktree(); wt != nil && !wt.IsCurrent {
 			// Check for uncommitted changes
@@ -1641,8 +2368,19 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.modal = sessionListModal
 		m.modalFocused = 0
 		m.sessionIndex = 0
+		m.sessionWindows = nil
+		m.sessionWindowsOf = ""
 		return m, m.loadSessions()
 
+	case "G":
+		// Find garbage (orphan sessions, prunable worktrees, merged branches, stale config) and offer to clean it
+		m.gcStage = 0
+		m.gcItems = nil
+		m.gcResults = nil
+		m.gcErr = nil
+		m.modal = gcModal
+		return m, m.scanGarbage()
+
 	case "E":
 		// Open config editor (Shift+E)
 		// Show scope selection modal first
@@ -1666,7 +2404,38 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 			// Fetch and check for updates (don't rely on cached status)
 			cmd = m.showInfoNotification("Checking for updates...")
-			return m, tea.Batch(cmd, m.checkAndPullFromBase(wt.Path, m.baseBranch))
+			return m, tea.Batch(cmd, m.checkAndPullFromBase(wt.Path, m.baseBranchFor(wt.Branch)))
+		}
+
+	case "z":
+		// Manage stashes for the selected worktree
+		if wt := m.selectedWorktree(); wt != nil {
+			m.modal = stashModal
+			m.stashWorktreePath = wt.Path
+			m.stashBranch = wt.Branch
+			m.stashIndex = 0
+			m.stashShowAll = false
+			m.stashInputActive = false
+			m.stashConfirmDrop = false
+			m.stashStatus = ""
+			m.stashMessageInput.SetValue("")
+			m.stashMessageInput.Blur()
+			return m, m.loadStashes(wt.Path)
+		}
+
+	case "R":
+		// Show per-worktree outcomes of the last refresh
+		if len(m.refreshResults) == 0 {
+			return m, m.showInfoNotification("No refresh results yet. Press 'r' to refresh.")
+		}
+		m.refreshSummaryIndex = 0
+		m.modal = refreshSummaryModal
+		return m, nil
+
+	case "C":
+		// Resolve conflicts of an in-progress merge/rebase in the selected worktree
+		if wt := m.selectedWorktree(); wt != nil {
+			return m, m.loadConflicts(wt.Path)
 		}
 
 	case "p":
@@ -1718,7 +2487,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if shouldAIRename {
 				// Start AI rename flow before push
 				cmd = m.showInfoNotification("🤖 Generating semantic branch name...")
-				return m, tea.Batch(cmd, m.generateBranchNameForPush(wt.Path, wt.Branch, m.baseBranch))
+				return m, tea.Batch(cmd, m.generateBranchNameForPush(wt.Path, wt.Branch, m.baseBranchFor(wt.Branch)))
 			} else {
 				// Normal push (no AI)
 				cmd = m.showInfoNotification("Pushing to remote...")
@@ -1735,8 +2504,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if existingPR != nil && existingPR.Status == "open" {
 					m.debugLog(fmt.Sprintf("P keybinding: found existing PR #%d for branch %s, opening in browser", existingPR.PRNumber, wt.Branch))
 					// Open the existing PR in the browser
-					cmd := exec.Command("gh", "pr", "view", existingPR.URL, "--web")
-					err := cmd.Start()
+					err := github.OpenInBrowser(existingPR.URL)
 					if err != nil {
 						return m, m.showErrorNotification("Failed to open PR in browser: "+err.Error(), 3*time.Second)
 					}
@@ -1758,12 +2526,21 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.modal = prListModal
 		m.prListIndex = 0
 		m.prListCreationMode = true // Set creation mode flag
+		m.prFilter = 0
+		m.prLabel = ""
+		m.prSearchInput.Placeholder = "Search PRs by #number, title or qualifiers like label:bug..."
 		m.prSearchInput.SetValue("")
 		m.prSearchInput.Focus()
+		m.prs = nil
 		m.filteredPRs = nil
-		m.prLoadingError = ""
 		m.debugLog("PR list modal state: prListCreationMode=true, repoPath=" + m.repoPath)
-		return m, m.loadPRs()
+		m, cmd := m.searchPRs(false)
+		// Labels to filter by come with the repository metadata
+		return m, tea.Batch(cmd, m.loadRepoMetadata(m.repoPath))
+
+	case "I":
+		// Create worktree from a GitHub issue (Shift+I)
+		return m.openIssuePicker()
 
 	case "L":
 		// Local merge: merge worktree branch into base branch locally (Shift+L)
@@ -1844,8 +2621,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if len(prs) == 1 {
 					// Only one PR - open it directly
 					m.debugLog(fmt.Sprintf("v keybinding: opening single PR %s", prs[0].URL))
-					cmd := exec.Command("gh", "pr", "view", prs[0].URL, "--web")
-					err := cmd.Start()
+					err := github.OpenInBrowser(prs[0].URL)
 					if err != nil {
 						return m, m.showErrorNotification("Failed to open PR in browser: "+err.Error(), 3*time.Second)
 					}
@@ -1858,6 +2634,7 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.prListMergeMode = false     // Ensure NOT merge mode
 					m.prListViewMode = true       // SET view mode
 					m.prListIndex = len(prs) - 1  // Default to most recent
+					m.prSearchInput.Placeholder = "Search PRs by number, title, author, or branch..."
 					m.prSearchInput.SetValue("")
 					m.prSearchInput.Focus()
 					// Convert config.PRInfo to github.PRInfo for display
@@ -1915,6 +2692,42 @@ func (m Model) handleMainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleWorktreeFilterInput handles keys while the main list filter input is focused
+func (m Model) handleWorktreeFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.switchInfo = SwitchInfo{}
+		return m, tea.Quit
+
+	case "esc":
+		// Clear filter and leave filter mode
+		m.worktreeFilterActive = false
+		m.worktreeFilterInput.SetValue("")
+		m.worktreeFilterInput.Blur()
+		return m, nil
+
+	case "enter":
+		// Keep filter applied and return to normal navigation
+		m.worktreeFilterActive = false
+		m.worktreeFilterInput.Blur()
+		return m, nil
+
+	case "up":
+		m.moveSelection(-1)
+		return m, nil
+
+	case "down":
+		m.moveSelection(1)
+		return m, nil
+	}
+
+	m.worktreeFilterInput, cmd = m.worktreeFilterInput.Update(msg)
+	m.ensureSelectionVisible()
+	return m, cmd
+}
+
 func (m Model) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -2009,6 +2822,33 @@ func (m Model) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case helperModal:
 		return m.handleHelperModalInput(msg)
+
+	case conflictResolutionModal:
+		return m.handleConflictResolutionModalInput(msg)
+
+	case stashModal:
+		return m.handleStashModalInput(msg)
+
+	case refreshSummaryModal:
+		return m.handleRefreshSummaryModalInput(msg)
+
+	case bulkActionModal:
+		return m.handleBulkActionModalInput(msg)
+
+	case sendPromptModal:
+		return m.handleSendPromptModalInput(msg)
+
+	case gcModal:
+		return m.handleGCModalInput(msg)
+
+	case prReviewModal:
+		return m.handlePRReviewModalInput(msg)
+
+	case issueListModal:
+		return m.handleIssueListModalInput(msg)
+
+	case ciModal:
+		return m.handleCIModalInput(msg)
 	}
 
 	return m, cmd
@@ -2235,6 +3075,7 @@ func (m Model) handleCreateWithNameModalInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 	switch msg.String() {
 	case "esc":
 		m.modal = noModal
+		m.stackParent = ""
 		m.sessionNameInput.Blur()
 		return m, nil
 
@@ -2286,10 +3127,13 @@ func (m Model) handleCreateWithNameModalInput(msg tea.KeyMsg) (tea.Model, tea.Cm
 			m.sessionNameInput.Blur()
 			notificationMsg := fmt.Sprintf("Creating worktree: %s\n  Path: %s\n  Claude will automatically continue previous conversations", sanitizedName, path)
 			cmd := m.showInfoNotification(notificationMsg)
-			return m, tea.Batch(cmd, m.createWorktreeWithSession(path, sanitizedName, true))
+			createCmd := m.createWorktreeWithSession(path, sanitizedName, true)
+			m.stackParent = ""
+			return m, tea.Batch(cmd, createCmd)
 		} else {
 			// Cancel button (modalFocused == 2)
 			m.modal = noModal
+			m.stackParent = ""
 			m.sessionNameInput.Blur()
 			return m, nil
 		}
@@ -2399,10 +3243,13 @@ func (m Model) handleSessionListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		incrementIndex:  func(m *Model) { m.sessionIndex++ },
 		decrementIndex:  func(m *Model) { m.sessionIndex-- },
 		onConfirm: func(m Model) (tea.Model, tea.Cmd) {
-			if m.sessionIndex >= 0 && m.sessionIndex < len(m.sessions) {
-				sess := m.sessions[m.sessionIndex]
-				// Attach via tmux
-				if err := m.sessionManager.Attach(sess.Name); err != nil {
+			if sess := m.selectedSession(); sess != nil {
+				// Attach via the multiplexer, directly to the selected window
+				windowName := ""
+				if w := m.selectedSessionWindow(); w != nil {
+					windowName = w.Name
+				}
+				if err := m.sessionManager.AttachWindow(sess.Name, windowName); err != nil {
 					m.showErrorNotification("Failed to attach to session", 3*time.Second)
 					return m, nil
 				}
@@ -2416,6 +3263,39 @@ func (m Model) handleSessionListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			return m, nil
 		},
 		onCustomKey: func(m Model, key string) (tea.Model, tea.Cmd) {
+			switch key {
+			case "left":
+				if m.sessionWindowIndex > 0 {
+					m.sessionWindowIndex--
+				}
+				return m, nil
+			case "right":
+				if m.sessionWindowIndex < len(m.sessionWindows)-1 {
+					m.sessionWindowIndex++
+				}
+				return m, nil
+			case "x":
+				// Kill the selected window, the session keeps running
+				sess, window := m.selectedSession(), m.selectedSessionWindow()
+				if sess == nil || window == nil {
+					return m, nil
+				}
+				return m, m.killSessionWindow(sess.Name, window.Name)
+			case "R":
+				// Restart the agent of the selected session
+				if sess := m.selectedSession(); sess != nil {
+					cmd := m.showInfoNotification("Restarting agent in " + sess.Branch + "...")
+					return m, tea.Batch(cmd, m.restartSessionAgent(*sess))
+				}
+				return m, nil
+			}
+			if key == "r" && len(m.restorableSessions) > 0 {
+				// Recreate all sessions lost with the previous tmux server
+				records := m.restorableSessions
+				m.restorableSessions = nil
+				cmd := m.showInfoNotification(fmt.Sprintf("Restoring %d session%s...", len(records), pluralize(len(records))))
+				return m, tea.Batch(cmd, m.restoreSessions(records))
+			}
 			if key == "d" && m.sessionIndex >= 0 && m.sessionIndex < len(m.sessions) {
 				// Kill selected session
 				sess := m.sessions[m.sessionIndex]
@@ -2432,7 +3312,15 @@ func (m Model) handleSessionListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			return m, nil
 		},
 	}
-	return m.handleListSelectionModalInput(msg, config)
+
+	model, cmd := m.handleListSelectionModalInput(msg, config)
+	// Moving to another session shows its windows
+	if updated, ok := model.(Model); ok && updated.modal == sessionListModal {
+		if windowsCmd := updated.selectSessionWindows(); windowsCmd != nil {
+			return updated, tea.Batch(cmd, windowsCmd)
+		}
+	}
+	return model, cmd
 }
 
 func (m Model) handleRenameModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -2633,55 +3521,61 @@ func (m Model) handleCommitModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handlePRContentModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	completions := m.prCompletions()
+
 	switch msg.String() {
 	case "esc":
 		m.modal = noModal
-		m.prTitleInput.Blur()
-		m.prDescriptionInput.Blur()
+		m = m.blurPRModal()
 		return m, nil
 
-	case "tab", "shift+tab":
-		// Cycle through: title input -> description input -> create button -> cancel button
-		m.prModalFocused = (m.prModalFocused + 1) % 4
+	case "tab":
+		// Cycle through: title -> description -> reviewers -> assignees -> labels -> milestone -> create -> cancel
+		m = m.focusPRModalField((m.prModalFocused + 1) % prModalFocusable)
+		return m, nil
 
-		// Update focus state
-		if m.prModalFocused == 0 {
-			m.prTitleInput.Focus()
-			m.prDescriptionInput.Blur()
-		} else if m.prModalFocused == 1 {
-			m.prTitleInput.Blur()
-			m.prDescriptionInput.Focus()
-		} else {
-			m.prTitleInput.Blur()
-			m.prDescriptionInput.Blur()
-		}
+	case "shift+tab":
+		m = m.focusPRModalField((m.prModalFocused + prModalFocusable - 1) % prModalFocusable)
+		return m, nil
+
+	case "ctrl+t":
+		// Replace the description with the next PR template
+		m = m.cyclePRTemplate()
 		return m, nil
 
+	case "up", "down":
+		// Move through the completions of the focused metadata field
+		if len(completions) > 0 {
+			if msg.String() == "up" {
+				m.prCompletionIndex = (m.prCompletionIndex + len(completions) - 1) % len(completions)
+			} else {
+				m.prCompletionIndex = (m.prCompletionIndex + 1) % len(completions)
+			}
+			return m, nil
+		}
+
 	case "g":
 		// Generate AI PR content (only if not focused on input fields and API key is configured)
-		if m.prModalFocused > 1 && m.configManager != nil && m.configManager.HasActiveAIProvider(m.repoPath) {
+		if m.prModalFocused >= prCreateFocus && m.configManager != nil && m.configManager.HasActiveAIProvider(m.repoPath) {
 			m.generatingPRContent = true
 			m.prSpinnerFrame = 0
 			return m, tea.Batch(
 				m.animateSpinner(),
-				m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranch),
+				m.generatePRContent(m.prModalWorktreePath, m.prModalBranch, m.baseBranchFor(m.prModalBranch)),
 			)
 		}
-		// If in input field (prModalFocused 0 or 1), fall through to handle text input
+		// If in an input field, fall through to handle text input
 
 	case "enter":
-		if m.prModalFocused == 0 {
-			// In title input, move to description
-			m.prModalFocused = 1
-			m.prTitleInput.Blur()
-			m.prDescriptionInput.Focus()
-			return m, nil
-		} else if m.prModalFocused == 1 {
-			// In description input, move to create button
-			m.prModalFocused = 2
-			m.prDescriptionInput.Blur()
+		if m.prModalFocused < prCreateFocus {
+			// Accept the highlighted completion, or move to the next field
+			if len(completions) > 0 {
+				m = m.acceptPRCompletion(completions[m.prCompletionIndex%len(completions)])
+				return m, nil
+			}
+			m = m.focusPRModalField(m.prModalFocused + 1)
 			return m, nil
-		} else if m.prModalFocused == 2 {
+		} else if m.prModalFocused == prCreateFocus {
 			// Create button
 			title := m.prTitleInput.Value()
 			description := m.prDescriptionInput.Value()
@@ -2691,31 +3585,39 @@ func (m Model) handlePRContentModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				cmd := m.showWarningNotification("PR title cannot be empty")
 				return m, cmd
 			}
+			metadata, err := m.prMetadataFromInputs()
+			if err != nil {
+				cmd := m.showWarningNotification(err.Error())
+				return m, cmd
+			}
 
 			// Create the PR
 			cmd := m.showInfoNotification("Creating draft PR...")
 			m.modal = noModal
-			m.prTitleInput.Blur()
-			m.prDescriptionInput.Blur()
+			m = m.blurPRModal()
 			return m, tea.Batch(
 				cmd,
-				m.createPR(m.prModalWorktreePath, m.prModalBranch, title, description),
+				m.createPR(m.prModalWorktreePath, m.prModalBranch, title, description, metadata),
 			)
 		} else {
-			// Cancel button (prModalFocused == 3)
+			// Cancel button
 			m.modal = noModal
-			m.prTitleInput.Blur()
-			m.prDescriptionInput.Blur()
+			m = m.blurPRModal()
 			return m, nil
 		}
 	}
 
 	// Handle text input
 	var cmd tea.Cmd
-	if m.prModalFocused == 0 {
+	switch {
+	case m.prModalFocused == 0:
 		m.prTitleInput, cmd = m.prTitleInput.Update(msg)
-	} else if m.prModalFocused == 1 {
+	case m.prModalFocused == 1:
 		m.prDescriptionInput, cmd = m.prDescriptionInput.Update(msg)
+	case m.prModalFocused < prCreateFocus:
+		field := m.prModalFocused - 2
+		m.prMetadataInputs[field], cmd = m.prMetadataInputs[field].Update(msg)
+		m.prCompletionIndex = 0
 	}
 
 	return m, cmd
@@ -2730,9 +3632,31 @@ func (m Model) handlePRListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.prListCreationMode = false
 		m.prListViewMode = false
 		m.prListIndex = 0
+		m.prLoading = false
 		m.prSearchInput.Blur()
 		return m, nil
 
+	case "ctrl+f":
+		// Cycle the PR browser's quick filters
+		if !m.prListCreationMode {
+			return m, nil
+		}
+		m.prFilter = (m.prFilter + 1) % len(github.PRFilters)
+		return m.searchPRs(false)
+
+	case "ctrl+l":
+		if !m.prListCreationMode {
+			return m, nil
+		}
+		return m.cyclePRLabel()
+
+	case "ctrl+r":
+		// Search again, bypassing the cache
+		if !m.prListCreationMode {
+			return m, nil
+		}
+		return m.searchPRs(true)
+
 	case "up":
 		if m.prListIndex > 0 {
 			m.prListIndex--
@@ -2747,6 +3671,10 @@ func (m Model) handlePRListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.prListIndex++
 		}
 		m.debugLog(fmt.Sprintf("handlePRListModalInput: DOWN pressed - prListIndex now %d (max %d)", m.prListIndex, len(filteredList)-1))
+		// Reaching the end of the browser's results loads the next page
+		if m.prListCreationMode && m.prListIndex >= len(filteredList)-1 {
+			return m.loadMorePRs()
+		}
 		return m, nil
 
 	case "tab":
@@ -2799,8 +3727,7 @@ func (m Model) handlePRListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.debugLog(fmt.Sprintf("handlePRListModalInput: VIEW MODE - opening selected PR in browser: %s", selectedPR.URL))
 			m.modal = noModal
 			m.prListViewMode = false
-			cmd := exec.Command("gh", "pr", "view", selectedPR.URL, "--web")
-			err := cmd.Start()
+			err := github.OpenInBrowser(selectedPR.URL)
 			if err != nil {
 				return m, m.showErrorNotification("Failed to open PR in browser: "+err.Error(), 3*time.Second)
 			}
@@ -2860,6 +3787,14 @@ func (m Model) handlePRListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// If search value changed, reset list index
 			if oldValue != newValue {
 				m.prListIndex = 0
+				// The PR browser searches on GitHub once the user stops typing
+				if m.prListCreationMode {
+					m.prSearchSeq++
+					seq := m.prSearchSeq
+					return m, tea.Tick(prSearchDebounce, func(t time.Time) tea.Msg {
+						return prSearchDebounceMsg{seq: seq}
+					})
+				}
 			}
 
 			return m, nil
@@ -2868,6 +3803,63 @@ func (m Model) handlePRListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+func (m Model) handleIssueListModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		m.issueLoading = false
+		m.issueSearchInput.Blur()
+		return m, nil
+
+	case "ctrl+f":
+		// Cycle the issue picker's quick filters
+		m.issueFilter = (m.issueFilter + 1) % len(github.IssueFilters)
+		return m.searchIssues()
+
+	case "ctrl+l":
+		return m.cycleIssueLabel()
+
+	case "ctrl+r":
+		return m.searchIssues()
+
+	case "up":
+		if m.issueListIndex > 0 {
+			m.issueListIndex--
+		}
+		return m, nil
+
+	case "down":
+		if m.issueListIndex < len(m.issues)-1 {
+			m.issueListIndex++
+		}
+		// Reaching the end of the results loads the next page
+		if m.issueListIndex >= len(m.issues)-1 {
+			return m.loadMoreIssues()
+		}
+		return m, nil
+
+	case "enter":
+		if m.issueListIndex >= len(m.issues) {
+			return m, nil
+		}
+		return m.startIssueWorktree(m.issues[m.issueListIndex])
+
+	default:
+		oldValue := m.issueSearchInput.Value()
+		m.issueSearchInput, _ = m.issueSearchInput.Update(msg)
+		if m.issueSearchInput.Value() == oldValue {
+			return m, nil
+		}
+		// Search on GitHub once the user stops typing
+		m.issueListIndex = 0
+		m.issueSearchSeq++
+		seq := m.issueSearchSeq
+		return m, tea.Tick(prSearchDebounce, func(t time.Time) tea.Msg {
+			return issueSearchDebounceMsg{seq: seq}
+		})
+	}
+}
+
 func (m Model) handleMergeStrategyModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
@@ -2972,43 +3964,373 @@ func (m Model) handleLocalMergeConfirmModalInput(msg tea.KeyMsg) (tea.Model, tea
 		} else {
 			m.localMergeFocused = 1 // Wrap around to cancel
 		}
-		return m, nil
+		return m, nil
+
+	case "right":
+		// Move focus right (forward)
+		if m.localMergeFocused < 1 {
+			m.localMergeFocused++
+		} else {
+			m.localMergeFocused = 0 // Wrap around to confirm
+		}
+		return m, nil
+
+	case "enter":
+		// Execute based on focused button
+		if m.localMergeFocused == 0 {
+			// Confirm button - execute merge
+			m.debugLog(fmt.Sprintf("Local merge confirmed: %s -> %s", m.localMergeBranch, m.localMergeTarget))
+
+			branch := m.localMergeBranch
+			target := m.localMergeTarget
+			worktree := m.localMergeWorktree
+
+			// Close modal
+			m.modal = noModal
+
+			// Show notification and execute merge
+			notifyCmd := m.showInfoNotification(fmt.Sprintf("Merging %s into %s...", branch, target))
+			return m, tea.Batch(
+				notifyCmd,
+				m.executeLocalMerge(worktree, branch, target),
+			)
+		} else {
+			// Cancel button - just close modal
+			m.modal = noModal
+			m.localMergeBranch = ""
+			m.localMergeTarget = ""
+			m.localMergeWorktree = ""
+			return m, nil
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) handleConflictResolutionModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Hunk view: resolve the open file hunk by hunk
+	if m.conflictFile != nil {
+		hunkCount := len(m.conflictFile.Hunks)
+		switch msg.String() {
+		case "esc":
+			// Back to the file list (unsaved choices are discarded)
+			m.conflictFile = nil
+			m.conflictStatus = ""
+			return m, nil
+
+		case "up", "k":
+			if m.conflictHunkIndex > 0 {
+				m.conflictHunkIndex--
+			}
+			return m, nil
+
+		case "down", "j":
+			if m.conflictHunkIndex < hunkCount-1 {
+				m.conflictHunkIndex++
+			}
+			return m, nil
+
+		case "o":
+			m.conflictFile.Hunks[m.conflictHunkIndex].Choice = git.ConflictOurs
+		case "t":
+			m.conflictFile.Hunks[m.conflictHunkIndex].Choice = git.ConflictTheirs
+		case "b":
+			m.conflictFile.Hunks[m.conflictHunkIndex].Choice = git.ConflictBoth
+		case "u":
+			m.conflictFile.Hunks[m.conflictHunkIndex].Choice = git.ConflictUnresolved
+			m.conflictFile.Hunks[m.conflictHunkIndex].Resolution = ""
+
+		case "i":
+			// Ask the AI provider for a suggested resolution
+			if m.generatingConflictFix {
+				return m, nil
+			}
+			if m.configManager == nil || !m.configManager.HasActiveAIProvider(m.repoPath) {
+				m.conflictStatus = "AI provider not configured. Configure one in settings (s)."
+				return m, nil
+			}
+			m.generatingConflictFix = true
+			m.conflictStatus = "Asking AI for a suggested resolution..."
+			return m, m.suggestConflictResolution(m.conflictFile.Path, m.conflictHunkIndex, m.conflictFile.Hunks[m.conflictHunkIndex])
+
+		case "e":
+			// The editor runs on its own, back to the file list so choices made here can't
+			// overwrite its edits. Opening the file again re-reads it
+			path := filepath.Join(m.conflictPath, m.conflictFile.Path)
+			m.conflictFile = nil
+			m.conflictStatus = "Editing in your editor. Press enter to reload the file, or 'a' to mark it resolved."
+			return m, m.openInEditor(path)
+
+		case "w", "enter":
+			// Write resolved file and stage it
+			if n := m.conflictFile.Unresolved(); n > 0 {
+				m.conflictStatus = fmt.Sprintf("%d hunk(s) still unresolved", n)
+				return m, nil
+			}
+			return m, m.resolveConflictFile(m.conflictPath, m.conflictFile)
+
+		default:
+			return m, nil
+		}
+
+		// Advance to the next unresolved hunk after a choice was made
+		if m.conflictFile.Hunks[m.conflictHunkIndex].Choice != git.ConflictUnresolved {
+			for i := m.conflictHunkIndex + 1; i < hunkCount; i++ {
+				if m.conflictFile.Hunks[i].Choice == git.ConflictUnresolved {
+					m.conflictHunkIndex = i
+					break
+				}
+			}
+		}
+		return m, nil
+	}
+
+	// File list view
+	switch msg.String() {
+	case "esc":
+		// Leave the merge in progress so it can be resumed later
+		m.modal = noModal
+		return m, m.showWarningNotification("The " + m.conflictOperation + " is still in progress. Press 'C' to resume conflict resolution.")
+
+	case "up", "k":
+		if m.conflictFileIndex > 0 {
+			m.conflictFileIndex--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.conflictFileIndex < len(m.conflictFiles)-1 {
+			m.conflictFileIndex++
+		}
+		return m, nil
+
+	case "enter":
+		if m.conflictFileIndex < len(m.conflictFiles) {
+			return m, m.loadConflictFile(m.conflictPath, m.conflictFiles[m.conflictFileIndex])
+		}
+		return m, nil
+
+	case "e":
+		if m.conflictFileIndex < len(m.conflictFiles) {
+			return m, m.openInEditor(filepath.Join(m.conflictPath, m.conflictFiles[m.conflictFileIndex]))
+		}
+		return m, nil
+
+	case "a":
+		// Mark file as resolved after editing it externally
+		if m.conflictFileIndex < len(m.conflictFiles) {
+			return m, m.markConflictResolved(m.conflictPath, m.conflictFiles[m.conflictFileIndex])
+		}
+		return m, nil
+
+	case "c":
+		if len(m.conflictFiles) > 0 {
+			m.conflictStatus = fmt.Sprintf("%d file(s) still have conflicts", len(m.conflictFiles))
+			return m, nil
+		}
+		m.conflictStatus = "Continuing " + m.conflictOperation + "..."
+		return m, m.continueMerge(m.conflictPath)
+
+	case "x":
+		m.conflictStatus = "Aborting " + m.conflictOperation + "..."
+		return m, m.abortMerge(m.conflictPath)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleStashModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	// Message input for a new stash
+	if m.stashInputActive {
+		switch msg.String() {
+		case "esc":
+			m.stashInputActive = false
+			m.stashMessageInput.Blur()
+			return m, nil
+
+		case "enter":
+			message := strings.TrimSpace(m.stashMessageInput.Value())
+			m.stashInputActive = false
+			m.stashMessageInput.Blur()
+			m.stashMessageInput.SetValue("")
+			m.stashStatus = "Stashing changes..."
+			return m, m.runStashAction(m.stashWorktreePath, "push", "", message)
+		}
+
+		m.stashMessageInput, cmd = m.stashMessageInput.Update(msg)
+		return m, cmd
+	}
+
+	visible := m.visibleStashes()
+	key := msg.String()
+
+	// Any key other than 'd' cancels a pending drop confirmation
+	if key != "d" {
+		m.stashConfirmDrop = false
+	}
+
+	switch key {
+	case "esc", "q":
+		m.modal = noModal
+		return m, nil
+
+	case "up", "k":
+		if m.stashIndex > 0 {
+			m.stashIndex--
+		}
+
+	case "down", "j":
+		if m.stashIndex < len(visible)-1 {
+			m.stashIndex++
+		}
+
+	case "n":
+		// Stash current changes (asks for an optional message)
+		m.stashInputActive = true
+		m.stashStatus = ""
+		return m, m.stashMessageInput.Focus()
+
+	case "f":
+		// Toggle between this branch's stashes and all stashes
+		m.stashShowAll = !m.stashShowAll
+		m.stashIndex = 0
+
+	case "enter", "p":
+		if m.stashIndex < len(visible) {
+			return m, m.runStashAction(m.stashWorktreePath, "pop", visible[m.stashIndex].Ref, "")
+		}
+
+	case "a":
+		if m.stashIndex < len(visible) {
+			return m, m.runStashAction(m.stashWorktreePath, "apply", visible[m.stashIndex].Ref, "")
+		}
+
+	case "d":
+		if m.stashIndex < len(visible) {
+			// Dropping a stash can't be undone, so ask for confirmation first
+			if !m.stashConfirmDrop {
+				m.stashConfirmDrop = true
+				m.stashStatus = "Press 'd' again to drop " + visible[m.stashIndex].Ref
+				return m, nil
+			}
+			m.stashConfirmDrop = false
+			return m, m.runStashAction(m.stashWorktreePath, "drop", visible[m.stashIndex].Ref, "")
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) handleRefreshSummaryModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "enter":
+		m.modal = noModal
+		return m, nil
+
+	case "up", "k":
+		if m.refreshSummaryIndex > 0 {
+			m.refreshSummaryIndex--
+		}
+
+	case "down", "j":
+		if m.refreshSummaryIndex < len(m.refreshResults)-1 {
+			m.refreshSummaryIndex++
+		}
+
+	case "g":
+		// Jump to the selected worktree in the main list
+		if m.refreshSummaryIndex < len(m.refreshResults) {
+			path := m.refreshResults[m.refreshSummaryIndex].path
+			for i, wt := range m.worktrees {
+				if wt.Path == path {
+					m.selectedIndex = i
+					break
+				}
+			}
+		}
+		m.modal = noModal
+	}
+
+	return m, nil
+}
+
+func (m Model) handleBulkActionModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.bulkStage {
+	case 0:
+		// Choose action
+		switch msg.String() {
+		case "esc", "q":
+			m.modal = noModal
+		case "up", "k":
+			if m.bulkActionIndex > 0 {
+				m.bulkActionIndex--
+			}
+		case "down", "j":
+			if m.bulkActionIndex < len(bulkActions)-1 {
+				m.bulkActionIndex++
+			}
+		case "enter":
+			m.bulkAction = bulkActions[m.bulkActionIndex].id
+			m.bulkStage = 1
+		}
 
-	case "right":
-		// Move focus right (forward)
-		if m.localMergeFocused < 1 {
-			m.localMergeFocused++
-		} else {
-			m.localMergeFocused = 0 // Wrap around to confirm
+	case 1:
+		// Confirmation summary
+		switch msg.String() {
+		case "esc":
+			m.bulkStage = 0
+		case "f":
+			if m.bulkAction == "delete" {
+				m.bulkForce = !m.bulkForce
+			}
+		case "enter", "y":
+			m.bulkStage = 2
+			return m, m.runBulkAction(m.bulkAction, m.markedWorktreeList())
 		}
-		return m, nil
 
-	case "enter":
-		// Execute based on focused button
-		if m.localMergeFocused == 0 {
-			// Confirm button - execute merge
-			m.debugLog(fmt.Sprintf("Local merge confirmed: %s -> %s", m.localMergeBranch, m.localMergeTarget))
+	case 3:
+		// Results
+		switch msg.String() {
+		case "esc", "q", "enter":
+			m.modal = noModal
+			m.bulkResults = nil
+		}
+	}
 
-			branch := m.localMergeBranch
-			target := m.localMergeTarget
-			worktree := m.localMergeWorktree
+	return m, nil
+}
 
-			// Close modal
+func (m Model) handleGCModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.gcStage {
+	case 0:
+		// Scanning
+		if msg.String() == "esc" || msg.String() == "q" {
 			m.modal = noModal
+		}
 
-			// Show notification and execute merge
-			notifyCmd := m.showInfoNotification(fmt.Sprintf("Merging %s into %s...", branch, target))
-			return m, tea.Batch(
-				notifyCmd,
-				m.executeLocalMerge(worktree, branch, target),
-			)
-		} else {
-			// Cancel button - just close modal
+	case 1:
+		// Dry run list
+		switch msg.String() {
+		case "esc", "q":
 			m.modal = noModal
-			m.localMergeBranch = ""
-			m.localMergeTarget = ""
-			m.localMergeWorktree = ""
-			return m, nil
+		case "enter", "y":
+			if m.gcErr != nil || len(m.gcItems) == 0 {
+				m.modal = noModal
+				break
+			}
+			m.gcStage = 2
+			return m, m.cleanGarbage(m.gcItems)
+		}
+
+	case 3:
+		// Results
+		switch msg.String() {
+		case "esc", "q", "enter":
+			m.modal = noModal
+			m.gcItems = nil
+			m.gcResults = nil
 		}
 	}
 
@@ -3020,6 +4342,10 @@ func (m Model) handlePostMergeCleanupModalInput(msg tea.KeyMsg) (tea.Model, tea.
 	case "esc":
 		// Close modal without action
 		m.modal = noModal
+		if m.postMergePR != nil {
+			m.postMergePR = nil
+			return m.nextMergedCleanup(), nil
+		}
 		return m, nil
 
 	case "up":
@@ -3038,6 +4364,18 @@ func (m Model) handlePostMergeCleanupModalInput(msg tea.KeyMsg) (tea.Model, tea.
 		branch := m.localMergeBranch
 		worktree := m.localMergeWorktree
 
+		if pr := m.postMergePR; pr != nil {
+			// PR merged on GitHub: clean up everything, or keep it and move to the next one
+			m.postMergePR = nil
+			m.modal = noModal
+			if m.postMergeDeleteIndex == 0 {
+				m.debugLog(fmt.Sprintf("Post-merge cleanup: cleaning up %s after PR #%d merged", pr.branch, pr.number))
+				notifyCmd := m.showInfoNotification("Cleaning up merged worktree...")
+				return m, tea.Batch(notifyCmd, m.cleanupMergedWorktrees([]mergedPR{*pr}))
+			}
+			return m.nextMergedCleanup(), nil
+		}
+
 		if m.postMergeDeleteIndex == 0 {
 			// User chose to delete worktree
 			m.debugLog(fmt.Sprintf("Post-merge cleanup: deleting worktree %s (branch: %s)", worktree, branch))
@@ -3202,7 +4540,7 @@ func (m Model) handleSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "down":
-		if m.settingsIndex < 7 { // Now 8 settings (editor, theme, base branch, tmux config, AI integration, debug logs, PR default state, hooks)
+		if m.settingsIndex < 13 { // Now 14 settings (editor, theme, base branch, tmux config, AI integration, debug logs, PR default state, hooks, autostash, agent, agent hooks, notifications, multiplexer, merged PR cleanup)
 			m.settingsIndex++
 		}
 
@@ -3254,6 +4592,42 @@ func (m Model) handleSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		msg = tea.KeyMsg{Type: tea.KeyEnter}
 		return m.handleSettingsModalInput(msg)
 
+	case "z":
+		// Quick key for Auto-stash on Refresh
+		m.settingsIndex = 8
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "g":
+		// Quick key for AI Agent
+		m.settingsIndex = 9
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "k":
+		// Quick key for Agent State Hooks
+		m.settingsIndex = 10
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "n":
+		// Quick key for Agent Notifications
+		m.settingsIndex = 11
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "m":
+		// Quick key for Multiplexer
+		m.settingsIndex = 12
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
+	case "x":
+		// Quick key for Merged PR Cleanup
+		m.settingsIndex = 13
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+		return m.handleSettingsModalInput(msg)
+
 	case "enter":
 		// Open the selected setting's modal
 		switch m.settingsIndex {
@@ -3361,6 +4735,137 @@ func (m Model) handleSettingsModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.hooksSelectedHookType = 0
 			m.hooksSelectedHook = 0
 			return m, nil
+
+		case 8:
+			// Auto-stash setting - toggle autostash during refresh
+			if m.configManager != nil {
+				enabled := m.configManager.GetAutoStash(m.repoPath)
+				if err := m.configManager.SetAutoStash(m.repoPath, !enabled); err != nil {
+					cmd := m.showErrorNotification("Failed to save auto-stash setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				if !enabled {
+					cmd := m.showSuccessNotification("Auto-stash on refresh enabled", 2*time.Second)
+					return m, cmd
+				} else {
+					cmd := m.showSuccessNotification("Auto-stash on refresh disabled", 2*time.Second)
+					return m, cmd
+				}
+			}
+			return m, nil
+
+		case 9:
+			// AI Agent setting - cycle through the built-in claude agent and configured agent profiles
+			if m.configManager != nil {
+				names := m.configManager.GetAgentNames()
+				current := m.configManager.GetAgentName(m.repoPath)
+				next := names[0]
+				for i, name := range names {
+					if name == current {
+						next = names[(i+1)%len(names)]
+						break
+					}
+				}
+				if err := m.configManager.SetAgent(m.repoPath, next); err != nil {
+					cmd := m.showErrorNotification("Failed to save agent setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				m.applyAgent()
+				if len(names) == 1 {
+					cmd := m.showInfoNotification("Add agent profiles under \"agents\" in ~/.config/jean/config.json")
+					return m, cmd
+				}
+				cmd := m.showSuccessNotification("AI agent: "+next, 2*time.Second)
+				return m, cmd
+			}
+			return m, nil
+
+		case 10:
+			// Agent State Hooks setting - install/remove Claude Code hooks reporting agent state
+			if m.agentHooksInstalled {
+				if err := m.sessionManager.RemoveAgentStateHooks(); err != nil {
+					cmd := m.showErrorNotification("Failed to remove agent hooks: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				m.agentHooksInstalled = false
+				cmd := m.showSuccessNotification("Agent state hooks removed from ~/.claude/settings.json", 3*time.Second)
+				return m, cmd
+			}
+
+			jeanPath, err := os.Executable()
+			if err != nil {
+				jeanPath = "jean"
+			}
+			if err := m.sessionManager.InstallAgentStateHooks(jeanPath); err != nil {
+				cmd := m.showErrorNotification("Failed to install agent hooks: "+err.Error(), 3*time.Second)
+				return m, cmd
+			}
+			m.agentHooksInstalled = true
+			cmd := m.showSuccessNotification("Agent state hooks installed, restart running agents to use them", 3*time.Second)
+			return m, cmd
+
+		case 11:
+			// Agent Notifications setting - mute/unmute notifications for this repository
+			if m.configManager != nil {
+				muted := !m.configManager.GetNotificationsMuted(m.repoPath)
+				if err := m.configManager.SetNotificationsMuted(m.repoPath, muted); err != nil {
+					cmd := m.showErrorNotification("Failed to save notification setting", 3*time.Second)
+					return m, cmd
+				}
+				status := "on"
+				if muted {
+					status = "muted"
+				}
+				cmd := m.showSuccessNotification("Agent notifications "+status, 2*time.Second)
+				return m, cmd
+			}
+			return m, nil
+
+		case 12:
+			// Multiplexer setting - switch between tmux and Zellij for new sessions
+			if m.configManager != nil {
+				current := m.configManager.GetMultiplexer()
+				next := config.Multiplexers[0]
+				for i, name := range config.Multiplexers {
+					if name == current {
+						next = config.Multiplexers[(i+1)%len(config.Multiplexers)]
+						break
+					}
+				}
+				if err := m.configManager.SetMultiplexer(next); err != nil {
+					cmd := m.showErrorNotification("Failed to save multiplexer setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				mux := session.NewMultiplexer(next)
+				m.sessionManager.SetMultiplexer(mux)
+				if !mux.IsAvailable() {
+					cmd := m.showWarningNotification(fmt.Sprintf("Multiplexer: %s (not installed)", next))
+					return m, cmd
+				}
+				cmd := m.showSuccessNotification("Multiplexer: "+next+", running sessions of the other one are left alone", 3*time.Second)
+				return m, tea.Batch(cmd, m.loadSessions())
+			}
+			return m, nil
+
+		case 13:
+			// Merged PR Cleanup setting - cycle through ask, auto and off
+			if m.configManager != nil {
+				current := m.configManager.GetMergedPRCleanup(m.repoPath)
+				next := config.MergedPRCleanupPolicies[0]
+				for i, policy := range config.MergedPRCleanupPolicies {
+					if policy == current {
+						next = config.MergedPRCleanupPolicies[(i+1)%len(config.MergedPRCleanupPolicies)]
+						break
+					}
+				}
+				if err := m.configManager.SetMergedPRCleanup(m.repoPath, next); err != nil {
+					cmd := m.showErrorNotification("Failed to save merged PR cleanup setting: "+err.Error(), 3*time.Second)
+					return m, cmd
+				}
+				cmd := m.showSuccessNotification("Merged PR cleanup: "+mergedPRCleanupLabel(next), 2*time.Second)
+				return m, cmd
+			}
+			return m, nil
 		}
 	}
 
@@ -3718,10 +5223,28 @@ func (m Model) handleTmuxConfigModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// refreshNeedsAttention reports whether any worktree was skipped or failed during refresh
+func refreshNeedsAttention(results []refreshResult) bool {
+	for _, result := range results {
+		if result.err != nil {
+			return true
+		}
+		switch result.outcome {
+		case refreshSkippedDirty, refreshDiverged, refreshFailed:
+			return true
+		}
+	}
+	return false
+}
+
 // buildRefreshStatusMessage constructs a detailed status message based on refresh results
 func buildRefreshStatusMessage(msg refreshWithPullMsg) string {
 	// If everything was already up to date
+	problems := refreshProblemSummary(msg.results)
 	if msg.upToDate && len(msg.updatedBranches) == 0 && !msg.mergedBaseBranch {
+		if problems != "" {
+			return "Already up to date (" + problems + ")"
+		}
 		return "Already up to date (0 new commits)"
 	}
 
@@ -3745,15 +5268,46 @@ func buildRefreshStatusMessage(msg refreshWithPullMsg) string {
 	}
 
 	// Summary message
+	summary := fmt.Sprintf("Pulled %d commits: %s", totalCommits, strings.Join(branchDetails, ", "))
 	if totalCommits == 0 {
-		return "Refreshed (no new commits)"
+		summary = "Refreshed (no new commits)"
+	} else if len(branchDetails) == 1 {
+		summary = fmt.Sprintf("Pulled %d new commits in %s", totalCommits, branchDetails[0])
+	}
+
+	// Mention worktrees that were not updated
+	if problems != "" {
+		summary += " (" + problems + ")"
 	}
 
-	if len(branchDetails) == 1 {
-		return fmt.Sprintf("Pulled %d new commits in %s", totalCommits, branchDetails[0])
+	return summary
+}
+
+// refreshProblemSummary counts skipped, diverged and failed worktrees (e.g. "1 skipped dirty, 2 failed")
+func refreshProblemSummary(results []refreshResult) string {
+	var dirty, diverged, failed int
+	for _, result := range results {
+		switch result.outcome {
+		case refreshSkippedDirty:
+			dirty++
+		case refreshDiverged:
+			diverged++
+		case refreshFailed:
+			failed++
+		}
 	}
 
-	return fmt.Sprintf("Pulled %d commits: %s", totalCommits, strings.Join(branchDetails, ", "))
+	var parts []string
+	if dirty > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped dirty", dirty))
+	}
+	if diverged > 0 {
+		parts = append(parts, fmt.Sprintf("%d diverged", diverged))
+	}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", failed))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (m Model) handleOnboardingModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -4677,3 +6231,227 @@ func (m Model) handleConfigEditorModalInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	return m, nil
 }
+
+// handlePRReviewModalInput handles the review threads modal and its reply editor
+func (m Model) handlePRReviewModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.reviewReplying {
+		switch msg.String() {
+		case "esc":
+			m.reviewReplying = false
+			m.reviewReplyInput.Blur()
+			return m, nil
+
+		case "enter":
+			thread := m.selectedReviewThread()
+			reply := strings.TrimSpace(m.reviewReplyInput.Value())
+			if thread == nil {
+				return m, nil
+			}
+			if reply == "" {
+				return m, m.showWarningNotification("Reply cannot be empty")
+			}
+			m.reviewReplying = false
+			m.reviewReplyInput.Blur()
+			cmd := m.showInfoNotification("Posting reply...")
+			return m, tea.Batch(cmd, m.replyToReviewThread(m.reviewPR.URL, thread.ID, reply))
+		}
+
+		var cmd tea.Cmd
+		m.reviewReplyInput, cmd = m.reviewReplyInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.reviewIndex > 0 {
+			m.reviewIndex--
+		}
+		return m, nil
+
+	case "down":
+		if m.reviewIndex < len(m.visibleReviewThreads())-1 {
+			m.reviewIndex++
+		}
+		return m, nil
+
+	case "h":
+		// Show or hide resolved threads
+		m.reviewShowResolved = !m.reviewShowResolved
+		m.reviewIndex = 0
+		return m, nil
+
+	case "R":
+		// Reload the threads, e.g. after replying on GitHub
+		m.reviewLoading = true
+		return m, m.loadReviewThreads(m.reviewPR.URL)
+
+	case "r":
+		if m.selectedReviewThread() == nil {
+			return m, nil
+		}
+		m.reviewReplying = true
+		m.reviewReplyInput.Reset()
+		return m, m.reviewReplyInput.Focus()
+
+	case "x":
+		// Resolve the selected thread, or unresolve it when it is resolved already
+		thread := m.selectedReviewThread()
+		if thread == nil {
+			return m, nil
+		}
+		return m, m.resolveReviewThread(m.reviewPR.URL, thread.ID, !thread.IsResolved)
+
+	case "a":
+		// Send the thread as a task to the worktree's agent
+		thread := m.selectedReviewThread()
+		if thread == nil {
+			return m, nil
+		}
+		if !m.sessionRunning(m.reviewWorktree.ClaudeSessionName) {
+			return m, m.showWarningNotification("No running session for " + m.reviewWorktree.Branch + ", open it with Enter first")
+		}
+		cmd := m.showInfoNotification("Sending thread to the agent...")
+		return m, tea.Batch(cmd, m.sendPrompt(reviewThreadPrompt(m.reviewPR, *thread), []git.Worktree{m.reviewWorktree}))
+	}
+
+	return m, nil
+}
+
+func (m Model) handleCIModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.ciLogJob != nil {
+		switch msg.String() {
+		case "esc", "q":
+			m.ciLogJob = nil
+			m.ciLogLines = nil
+			return m, nil
+
+		case "up", "k":
+			m.ciLogScroll = min(m.ciLogScroll+1, len(m.ciLogLines))
+		case "down", "j":
+			m.ciLogScroll = max(m.ciLogScroll-1, 0)
+		case "pgup":
+			m.ciLogScroll = min(m.ciLogScroll+10, len(m.ciLogLines))
+		case "pgdown":
+			m.ciLogScroll = max(m.ciLogScroll-10, 0)
+		case "g":
+			m.ciLogScroll = len(m.ciLogLines)
+		case "G":
+			m.ciLogScroll = 0
+
+		case "R":
+			return m.openCILog(*m.ciLogJob)
+
+		case "a":
+			// Send the end of the log to the agent
+			run := m.selectedCIRun()
+			if m.ciLogLines == nil || run == nil {
+				return m, nil
+			}
+			return m.sendCIFailure(m.ciWorktree, *run, *m.ciLogJob, m.ciLogLines)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.modal = noModal
+		return m, nil
+
+	case "up":
+		if m.ciFocusJobs {
+			if m.ciJobIndex > 0 {
+				m.ciJobIndex--
+			}
+			return m, nil
+		}
+		return m.selectCIRun(m.ciRunIndex - 1)
+
+	case "down":
+		if m.ciFocusJobs {
+			if m.ciJobIndex < len(m.ciJobs)-1 {
+				m.ciJobIndex++
+			}
+			return m, nil
+		}
+		return m.selectCIRun(m.ciRunIndex + 1)
+
+	case "tab":
+		// Move between the runs and the jobs of the selected run
+		m.ciFocusJobs = !m.ciFocusJobs && len(m.ciJobs) > 0
+		return m, nil
+
+	case "enter", "l":
+		// Show the log of the selected job, or of the first failed one
+		job := m.targetCIJob()
+		if job == nil {
+			if len(m.ciJobs) == 0 {
+				return m, nil
+			}
+			job = &m.ciJobs[0]
+		}
+		return m.openCILog(*job)
+
+	case "a":
+		// Send the log of the failed job to the agent as a fix-it task
+		job := m.targetCIJob()
+		if job == nil || job.State() != "failure" {
+			return m, m.showWarningNotification("No failed job to send")
+		}
+		cmd := m.showInfoNotification("Fetching the log of " + job.Name + "...")
+		return m, tea.Batch(cmd, m.loadCILog(*job, true))
+
+	case "r":
+		// Re-run the failed jobs of the selected run
+		run := m.selectedCIRun()
+		if run == nil {
+			return m, nil
+		}
+		if state := run.State(); state != "failure" && state != "cancelled" {
+			return m, m.showWarningNotification("Only failed or cancelled runs can be re-run")
+		}
+		return m, m.rerunFailedCIJobs(run.ID)
+
+	case "o":
+		// Open the selected run in the browser
+		if run := m.selectedCIRun(); run != nil {
+			if err := github.OpenInBrowser(run.URL); err != nil {
+				return m, m.showErrorNotification("Failed to open run in browser: "+err.Error(), 3*time.Second)
+			}
+		}
+		return m, nil
+
+	case "R":
+		m.ciLoading = true
+		return m, m.loadCIRuns(m.ciWorktree)
+	}
+
+	return m, nil
+}
+
+// handleSendPromptModalInput handles the prompt editor for sending text to agent sessions
+func (m Model) handleSendPromptModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.modal = noModal
+		m.promptInput.Blur()
+		return m, nil
+
+	case "enter":
+		prompt := strings.TrimSpace(m.promptInput.Value())
+		if prompt == "" {
+			return m, m.showWarningNotification("Prompt cannot be empty")
+		}
+		m.modal = noModal
+		m.promptInput.Blur()
+		cmd := m.showInfoNotification(fmt.Sprintf("Sending prompt to %d worktree%s...", len(m.promptTargets), pluralize(len(m.promptTargets))))
+		return m, tea.Batch(cmd, m.sendPrompt(prompt, m.promptTargets))
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
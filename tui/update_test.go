@@ -1,10 +1,23 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/gc"
+	"github.com/coollabsio/jean-tui/git"
+	"github.com/coollabsio/jean-tui/github"
 	"github.com/coollabsio/jean-tui/session"
 )
 
@@ -380,7 +393,652 @@ func TestListSelectionModalInput_BoundaryCheck_Down(t *testing.T) {
 	}
 }
 
+// TestConflictResolutionModalInput_ChooseSide tests picking a side advances to the next unresolved hunk
+func TestConflictResolutionModalInput_ChooseSide(t *testing.T) {
+	m := setupTestModel()
+	m.modal = conflictResolutionModal
+	m.conflictFile = git.ParseConflictFile("a.txt", "<<<<<<< HEAD\na\n=======\nb\n>>>>>>> main\n<<<<<<< HEAD\nc\n=======\nd\n>>>>>>> main\n")
+
+	resultModel, _ := m.handleConflictResolutionModalInput(
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}},
+	)
+
+	result := resultModel.(Model)
+	if result.conflictFile.Hunks[0].Choice != git.ConflictTheirs {
+		t.Errorf("Expected first hunk to keep theirs, got %v", result.conflictFile.Hunks[0].Choice)
+	}
+	if result.conflictHunkIndex != 1 {
+		t.Errorf("Expected conflictHunkIndex 1, got %d", result.conflictHunkIndex)
+	}
+}
+
+// TestConflictResolutionModalInput_EditLeavesHunkView tests that editing a file externally drops
+// the parsed hunks, so saving can't overwrite the edits with stale content
+func TestConflictResolutionModalInput_EditLeavesHunkView(t *testing.T) {
+	m := setupTestModel()
+	m.modal = conflictResolutionModal
+	m.conflictPath = "/repo"
+	m.conflictFiles = []string{"a.txt"}
+	m.conflictFile = git.ParseConflictFile("a.txt", "<<<<<<< HEAD\na\n=======\nb\n>>>>>>> main\n")
+	m.conflictFile.Hunks[0].Choice = git.ConflictOurs
+
+	resultModel, cmd := m.handleConflictResolutionModalInput(
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}},
+	)
+
+	result := resultModel.(Model)
+	if result.conflictFile != nil || cmd == nil {
+		t.Error("Expected the editor to open from the file list")
+	}
+	if !strings.Contains(result.conflictStatus, "reload") {
+		t.Errorf("Expected a hint to reload the file, got %q", result.conflictStatus)
+	}
+}
+
+// TestConflictResolutionModalInput_SaveRequiresAllHunks tests that saving is blocked while hunks are unresolved
+func TestConflictResolutionModalInput_SaveRequiresAllHunks(t *testing.T) {
+	m := setupTestModel()
+	m.modal = conflictResolutionModal
+	m.conflictFile = git.ParseConflictFile("a.txt", "<<<<<<< HEAD\na\n=======\nb\n>>>>>>> main\n")
+
+	resultModel, cmd := m.handleConflictResolutionModalInput(
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}},
+	)
+
+	if cmd != nil {
+		t.Error("Expected no command when hunks are unresolved")
+	}
+	if resultModel.(Model).conflictStatus == "" {
+		t.Error("Expected status message about unresolved hunks")
+	}
+}
+
+// TestBuildRefreshStatusMessage_ReportsProblems tests that skipped and failed worktrees are mentioned
+func TestBuildRefreshStatusMessage_ReportsProblems(t *testing.T) {
+	msg := refreshWithPullMsg{
+		updatedBranches: map[string]int{"main": 3},
+		results: []refreshResult{
+			{branch: "main", outcome: refreshUpdated, commits: 3},
+			{branch: "dirty", outcome: refreshSkippedDirty},
+			{branch: "broken", outcome: refreshFailed},
+		},
+	}
+
+	expected := "Pulled 3 new commits in main (+3) (1 skipped dirty, 1 failed)"
+	if got := buildRefreshStatusMessage(msg); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if !refreshNeedsAttention(msg.results) {
+		t.Error("Expected refresh with skipped worktrees to need attention")
+	}
+	if refreshNeedsAttention(msg.results[:1]) {
+		t.Error("Expected successful refresh not to need attention")
+	}
+}
+
+// TestBulkActions_MarkConfirmAndResults tests marking worktrees and the bulk action flow
+func TestBulkActions_MarkConfirmAndResults(t *testing.T) {
+	m := setupTestModel()
+	m.baseBranch = "main"
+	m.worktrees = []git.Worktree{
+		{Branch: "main", Path: "/repo", IsCurrent: true},
+		{Branch: "clean", Path: "/repo/.workspaces/clean"},
+		{Branch: "dirty", Path: "/repo/.workspaces/dirty", HasUncommitted: true},
+	}
+
+	// Mark all worktrees with space (selection advances after each mark)
+	var resultModel tea.Model = m
+	for range m.worktrees {
+		resultModel, _ = resultModel.(Model).handleMainInput(tea.KeyMsg{Type: tea.KeySpace})
+	}
+	m = resultModel.(Model)
+	if len(m.markedWorktrees) != 3 {
+		t.Fatalf("Expected 3 marked worktrees, got %d", len(m.markedWorktrees))
+	}
+
+	resultModel, _ = m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	m = resultModel.(Model)
+	if m.modal != bulkActionModal || m.bulkStage != 0 {
+		t.Fatalf("Expected bulk action modal on action stage, got modal %d stage %d", m.modal, m.bulkStage)
+	}
+
+	// Choose "delete" and review the confirmation summary
+	resultModel, _ = m.handleBulkActionModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.bulkStage != 1 || m.bulkAction != "delete" {
+		t.Fatalf("Expected confirmation for delete, got stage %d action %q", m.bulkStage, m.bulkAction)
+	}
+	if m.bulkSkipReason("delete", m.worktrees[0]) == "" {
+		t.Error("Expected root worktree to be skipped")
+	}
+	if m.bulkSkipReason("delete", m.worktrees[2]) == "" {
+		t.Error("Expected dirty worktree to be skipped without force")
+	}
+
+	resultModel, _ = m.handleBulkActionModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = resultModel.(Model)
+	if m.bulkSkipReason("delete", m.worktrees[2]) != "" {
+		t.Error("Expected dirty worktree to be processed with force")
+	}
+	if m.bulkSkipReason("pull", m.worktrees[2]) == "" {
+		t.Error("Expected dirty worktree to be skipped by pull from base")
+	}
+
+	// Successful items are unmarked; skipped and failed stay marked
+	resultModel, _ = m.Update(bulkActionCompletedMsg{
+		action: "delete",
+		results: []bulkResult{
+			{branch: "main", path: "/repo", skipped: "main repository can't be deleted"},
+			{branch: "clean", path: "/repo/.workspaces/clean"},
+			{branch: "dirty", path: "/repo/.workspaces/dirty", err: errors.New("locked")},
+		},
+	})
+	m = resultModel.(Model)
+	if m.bulkStage != 3 || len(m.bulkResults) != 3 {
+		t.Fatalf("Expected results stage with 3 results, got stage %d with %d", m.bulkStage, len(m.bulkResults))
+	}
+	if m.markedWorktrees["/repo/.workspaces/clean"] || !m.markedWorktrees["/repo/.workspaces/dirty"] || !m.markedWorktrees["/repo"] {
+		t.Errorf("Unexpected marks after results: %v", m.markedWorktrees)
+	}
+}
+
+// TestToSessionLayout tests converting the configured tmux layout
+func TestToSessionLayout(t *testing.T) {
+	if toSessionLayout(nil) != nil {
+		t.Error("Expected nil layout when none is configured")
+	}
+
+	layout := toSessionLayout(&config.TmuxLayout{Windows: []config.TmuxWindow{
+		{Name: "dev", Layout: "tiled", Panes: []config.TmuxPane{{Command: "make dev"}, {Command: "make test", Split: "horizontal", Size: "30%"}}},
+	}})
+	if len(layout.Windows) != 1 || layout.Windows[0].Arrangement != "tiled" {
+		t.Fatalf("Unexpected layout: %+v", layout)
+	}
+	expected := session.LayoutPane{Command: "make test", Split: "horizontal", Size: "30%"}
+	if len(layout.Windows[0].Panes) != 2 || layout.Windows[0].Panes[1] != expected {
+		t.Errorf("Expected second pane %+v, got %+v", expected, layout.Windows[0].Panes)
+	}
+}
+
+// TestApplyAgentStates_NotifiesOnTransitionToWaiting tests that only new waiting agents notify
+func TestApplyAgentStates_NotifiesOnTransitionToWaiting(t *testing.T) {
+	m := setupTestModel()
+	m.repoPath = "/repo"
+	m.agentsWaiting = make(map[string]bool)
+	m.notifier = session.NewNotifier([]string{session.NotifyBell}, "", time.Minute)
+	m.worktrees = []git.Worktree{
+		{Branch: "busy", Path: "/repo/.workspaces/busy"},
+		{Branch: "ready", Path: "/repo/.workspaces/ready"},
+	}
+
+	// Agents already waiting at startup don't notify
+	if cmd := m.applyAgentStates([]agentStateUpdate{
+		{path: "/repo/.workspaces/busy", branch: "busy", state: session.AgentWorking},
+		{path: "/repo/.workspaces/ready", branch: "ready", aiWaiting: true, state: session.AgentIdle},
+	}); cmd != nil {
+		t.Error("Expected no notification on the first check")
+	}
+	if !m.worktrees[1].AIWaiting || m.worktrees[1].AgentState != session.AgentIdle {
+		t.Errorf("Expected worktree state to be updated, got %+v", m.worktrees[1])
+	}
+
+	cmd := m.applyAgentStates([]agentStateUpdate{
+		{path: "/repo/.workspaces/busy", branch: "busy", aiWaiting: true, state: session.AgentWaiting, message: "Claude needs your permission to use Bash"},
+		{path: "/repo/.workspaces/ready", branch: "ready", aiWaiting: true, state: session.AgentIdle},
+	})
+	if cmd == nil {
+		t.Fatal("Expected a notification when an agent starts waiting")
+	}
+	if m.notification == nil || m.notification.Message != "repo/busy: Claude needs your permission to use Bash" {
+		t.Errorf("Unexpected in-app notification: %+v", m.notification)
+	}
+
+	// The bell goes out with the next frame, not from a goroutine
+	if !strings.HasPrefix(m.View(), "\a") {
+		t.Error("Expected the bell to be rendered with the next frame")
+	}
+	updated, _ := m.Update(terminalSequenceWrittenMsg{})
+	if m = updated.(Model); strings.Contains(m.View(), "\a") {
+		t.Error("Expected the bell to be rendered only once")
+	}
+
+	// Stopped sessions are forgotten
+	m.applyAgentStates(nil)
+	if len(m.agentsWaiting) != 0 {
+		t.Errorf("Expected stopped sessions to be forgotten, got %v", m.agentsWaiting)
+	}
+
+	if n := agentNotification("repo", agentStateUpdate{branch: "x", state: session.AgentIdle, message: "Finished"}); n.Message != "Agent finished" {
+		t.Errorf("Expected finished message, got %q", n.Message)
+	}
+}
+
+// TestSendPrompt_TargetsMarkedOrSelectedWorktrees tests opening the send prompt modal and its results
+func TestSendPrompt_TargetsMarkedOrSelectedWorktrees(t *testing.T) {
+	m := setupTestModel()
+	m.promptInput = textarea.New()
+	m.markedWorktrees = make(map[string]bool)
+	m.worktrees = []git.Worktree{
+		{Branch: "one", Path: "/repo/.workspaces/one"},
+		{Branch: "two", Path: "/repo/.workspaces/two"},
+		{Branch: "three", Path: "/repo/.workspaces/three"},
+	}
+	m.selectedIndex = 1
+
+	resultModel, _ := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = resultModel.(Model)
+	if m.modal != sendPromptModal || len(m.promptTargets) != 1 || m.promptTargets[0].Branch != "two" {
+		t.Fatalf("Expected prompt modal for the selected worktree, got modal %d targets %v", m.modal, m.promptTargets)
+	}
+
+	// Empty prompts are not sent
+	resultModel, _ = m.handleSendPromptModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.modal != sendPromptModal {
+		t.Error("Expected modal to stay open for an empty prompt")
+	}
+
+	m.modal = noModal
+	m.markedWorktrees["/repo/.workspaces/one"] = true
+	m.markedWorktrees["/repo/.workspaces/three"] = true
+	resultModel, _ = m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = resultModel.(Model)
+	if len(m.promptTargets) != 2 || m.promptTargets[0].Branch != "one" || m.promptTargets[1].Branch != "three" {
+		t.Fatalf("Expected marked worktrees as targets, got %v", m.promptTargets)
+	}
+
+	resultModel, _ = m.Update(promptSentMsg{results: []bulkResult{
+		{branch: "one", path: "/repo/.workspaces/one"},
+		{branch: "three", path: "/repo/.workspaces/three", skipped: "no running session"},
+	}})
+	m = resultModel.(Model)
+	if m.notification == nil || m.notification.Message != "Prompt sent to 1 worktree, 1 without a running session" {
+		t.Errorf("Unexpected notification: %+v", m.notification)
+	}
+}
+
+// TestPanePreview_FitsPanelAndFollowsSelection tests the details pane preview
+func TestPanePreview_FitsPanelAndFollowsSelection(t *testing.T) {
+	m := setupTestModel()
+	m.height = 40
+	m.previewMode = "agent"
+	m.worktrees = []git.Worktree{
+		{Branch: "one", Path: "/repo/.workspaces/one"},
+		{Branch: "two", Path: "/repo/.workspaces/two"},
+	}
+
+	var lines []string
+	for i := 0; i < panePreviewLines; i++ {
+		lines = append(lines, "\x1b[32m"+strings.Repeat("x", 100)+"\x1b[0m")
+	}
+
+	// Captures for a worktree that is no longer selected are dropped
+	resultModel, _ := m.Update(panePreviewMsg{path: "/repo/.workspaces/two", window: "claude", lines: lines})
+	m = resultModel.(Model)
+	if m.previewLines != nil {
+		t.Fatal("Expected capture of an unselected worktree to be ignored")
+	}
+
+	resultModel, _ = m.Update(panePreviewMsg{path: "/repo/.workspaces/one", window: "claude", lines: lines})
+	m = resultModel.(Model)
+	preview := m.renderPanePreview(&m.worktrees[0], 20)
+	if !strings.Contains(preview, "Preview (claude)") {
+		t.Fatalf("Expected preview header, got %q", preview)
+	}
+	// 40 rows - 4 (help bar) - 2 (padding) - 20 used - 3 (header) = 11 lines
+	if got := strings.Count(preview, "\n") - 2; got != 11 {
+		t.Errorf("Expected 11 preview lines, got %d", got)
+	}
+	for _, line := range strings.Split(preview, "\n")[3:] {
+		if w := lipgloss.Width(line); w > (m.width-6)/2-4 {
+			t.Errorf("Expected preview line to fit the panel, got width %d", w)
+		}
+	}
+
+	if m.renderPanePreview(&m.worktrees[0], 33) != "" {
+		t.Error("Expected no preview when it doesn't fit")
+	}
+	m.previewMode = "off"
+	if m.renderPanePreview(&m.worktrees[0], 0) != "" {
+		t.Error("Expected no preview when turned off")
+	}
+}
+
+// TestRestoreSessions_OfferedOnceAndRestoredFromSessionList tests the session restore flow
+func TestRestoreSessions_OfferedOnceAndRestoredFromSessionList(t *testing.T) {
+	m := setupTestModel()
+	records := []session.SessionRecord{{Name: "jean-repo-login", Path: "/repo/.workspaces/login", Windows: []session.WindowRecord{{Index: 1, Name: "terminal"}}}}
+
+	resultModel, _ := m.Update(sessionsLoadedMsg{restorable: records})
+	m = resultModel.(Model)
+	if !m.restoreOffered || m.notification == nil || !strings.Contains(m.notification.Message, "press S then r") {
+		t.Fatalf("Expected restore offer, got %+v", m.notification)
+	}
+
+	m.notification = nil
+	resultModel, _ = m.Update(sessionsLoadedMsg{restorable: records})
+	m = resultModel.(Model)
+	if m.notification != nil {
+		t.Error("Expected restore to be offered only once")
+	}
+
+	m.modal = sessionListModal
+	resultModel, cmd := m.handleSessionListModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = resultModel.(Model)
+	if cmd == nil || m.restorableSessions != nil {
+		t.Error("Expected r to start restoring the recorded sessions")
+	}
+}
+
+// TestSessionList_WindowsWithUsageAndActions tests the windows panel of the session list
+func TestSessionList_WindowsWithUsageAndActions(t *testing.T) {
+	m := setupTestModel()
+	m.width = 120
+	m.sessionManager = session.NewManager()
+	m.modal = sessionListModal
+	m.worktrees = []git.Worktree{{Branch: "login", Path: "/repo/.workspaces/login", ClaudeSessionName: "jean-repo-login", AgentState: session.AgentWorking}}
+
+	resultModel, cmd := m.Update(sessionsLoadedMsg{sessions: []session.Session{
+		{Name: "jean-repo-login", Branch: "repo-login"},
+		{Name: "jean-repo-api", Branch: "repo-api"},
+	}})
+	m = resultModel.(Model)
+	if cmd == nil || m.sessionWindowsOf != "jean-repo-login" {
+		t.Fatalf("Expected the windows of the selected session to load, got %q", m.sessionWindowsOf)
+	}
+
+	windows := []session.WindowInfo{
+		{Index: 1, Name: "terminal", Command: "zsh", PID: 100},
+		{Index: 2, Name: "claude", Command: "node", PID: 200, Active: true},
+	}
+	sampled := time.Now()
+	stats := map[int]session.ProcessStats{
+		100: {PID: 100, CPUTime: time.Second, MemoryKB: 4096},
+		200: {PID: 200, CPUTime: 10 * time.Second, MemoryKB: 300 * 1024},
+	}
+	resultModel, _ = m.Update(sessionWindowsMsg{sessionName: "jean-repo-login", windows: windows, stats: stats, sampledAt: sampled})
+	m = resultModel.(Model)
+	if m.sessionWindowIndex != 1 || m.sessionWindows[1].cpu != -1 {
+		t.Fatalf("Expected the active window selected and no CPU usage yet, got %+v", m.sessionWindows)
+	}
+
+	// The second sample gives the CPU usage: 1s of CPU time in 2s = 50%
+	stats[200] = session.ProcessStats{PID: 200, CPUTime: 11 * time.Second, MemoryKB: 300 * 1024}
+	resultModel, _ = m.Update(sessionWindowsMsg{sessionName: "jean-repo-login", windows: windows, stats: stats, sampledAt: sampled.Add(2 * time.Second)})
+	m = resultModel.(Model)
+	if m.sessionWindows[1].cpu != 50 {
+		t.Errorf("Expected 50%% CPU, got %v", m.sessionWindows[1].cpu)
+	}
+
+	view := m.renderSessionWindows()
+	for _, want := range []string{"Windows of repo-login", "zsh", "pid 200", "50.0%", "300.0 MB", "4.0 MB", "↻ working"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in windows panel, got:\n%s", want, view)
+		}
+	}
+
+	resultModel, _ = m.handleSessionListModalInput(tea.KeyMsg{Type: tea.KeyLeft})
+	m = resultModel.(Model)
+	if m.sessionWindowIndex != 0 {
+		t.Errorf("Expected left to select the terminal window, got %d", m.sessionWindowIndex)
+	}
+	_, cmd = m.handleSessionListModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if cmd == nil {
+		t.Error("Expected x to kill the selected window")
+	}
+
+	// Moving to another session drops the old windows and loads the new ones
+	resultModel, cmd = m.handleSessionListModalInput(tea.KeyMsg{Type: tea.KeyDown})
+	m = resultModel.(Model)
+	if cmd == nil || m.sessionWindowsOf != "jean-repo-api" || m.sessionWindows != nil {
+		t.Fatalf("Expected the windows of jean-repo-api to load, got %q %+v", m.sessionWindowsOf, m.sessionWindows)
+	}
+	resultModel, _ = m.Update(sessionWindowsMsg{sessionName: "jean-repo-login", windows: windows, stats: stats, sampledAt: sampled})
+	m = resultModel.(Model)
+	if m.sessionWindows != nil {
+		t.Error("Expected windows of a no longer selected session to be ignored")
+	}
+}
+
+// TestPRBadges_RenderedInListAndDetails tests the review, CI and merge badges of open PRs
+func TestPRBadges_RenderedInListAndDetails(t *testing.T) {
+	m := setupTestModel()
+	m.width = 160
+	m.height = 40
+	pr := config.PRInfo{URL: "https://github.com/o/r/pull/7", PRNumber: 7, Status: "open", PRDetails: config.PRDetails{
+		Checks: "failing", ReviewDecision: "review_required", Reviewers: []string{"alice", "backend"}, Mergeable: "conflicting", UnresolvedThreads: 2,
+	}}
+	m.worktrees = []git.Worktree{{Branch: "feature", Path: "/repo/.workspaces/feature", Commit: "abc1234", PRs: []config.PRInfo{pr}}}
+
+	badges := prBadges(pr)
+	if len(badges) != 4 || badges[0].short != "⚠ conflict" || badges[1].short != "✗ ci" {
+		t.Fatalf("Expected conflict and failing checks first, got %+v", badges)
+	}
+
+	list := m.renderWorktreeList()
+	for _, want := range []string{"⚠ conflict", "✗ ci", "◌ review", "💬2"} {
+		if !strings.Contains(list, want) {
+			t.Errorf("Expected %q in worktree list, got:\n%s", want, list)
+		}
+	}
+	details := m.renderDetails()
+	for _, want := range []string{"Merge conflicts", "Checks failing", "Review required", "2 unresolved threads", "Waiting on alice, backend"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("Expected %q in details, got:\n%s", want, details)
+		}
+	}
+
+	// Merged PRs need no attention
+	pr.Status = "merged"
+	if badges := prBadges(pr); badges != nil {
+		t.Errorf("Expected no badges for a merged PR, got %+v", badges)
+	}
+}
+
+// TestPRReviewModal_ThreadsRepliesAndAgentPrompt tests reading, answering and forwarding review threads
+func TestPRReviewModal_ThreadsRepliesAndAgentPrompt(t *testing.T) {
+	m := setupTestModel()
+	m.width = 120
+	m.height = 40
+	m.reviewReplyInput = textarea.New()
+	pr := config.PRInfo{URL: "https://github.com/o/r/pull/7", PRNumber: 7, Title: "Login", Status: "open"}
+	m.worktrees = []git.Worktree{{Branch: "login", Path: "/repo/.workspaces/login", ClaudeSessionName: "jean-repo-login", PRs: []config.PRInfo{pr}}}
+
+	resultModel, cmd := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}})
+	m = resultModel.(Model)
+	if m.modal != prReviewModal || !m.reviewLoading || cmd == nil || m.reviewPR.URL != pr.URL {
+		t.Fatalf("Expected the review modal to load the PR's threads, got modal %d", m.modal)
+	}
+
+	threads := []github.ReviewThread{
+		{ID: "T1", Path: "old.go", Line: 3, IsResolved: true, Comments: []github.ReviewComment{{Author: "bob", Body: "Typo"}}},
+		{ID: "T2", Path: "main.go", Line: 12, DiffHunk: "@@ -1,2 +1,3 @@\n ctx := ctx\n+x, _ := f()", Comments: []github.ReviewComment{
+			{Author: "alice", Body: "Handle the error"}, {Author: "me", Body: "Will do"},
+		}},
+	}
+	resultModel, _ = m.Update(reviewThreadsLoadedMsg{prURL: "https://github.com/o/r/pull/8", threads: threads})
+	m = resultModel.(Model)
+	if m.reviewThreads != nil {
+		t.Fatal("Expected threads of another PR to be ignored")
+	}
+	resultModel, _ = m.Update(reviewThreadsLoadedMsg{prURL: pr.URL, threads: threads})
+	m = resultModel.(Model)
+
+	// Resolved threads are hidden until h
+	if visible := m.visibleReviewThreads(); len(visible) != 1 || visible[0].ID != "T2" {
+		t.Fatalf("Expected only the unresolved thread, got %+v", visible)
+	}
+	view := m.renderPRReviewModal()
+	for _, want := range []string{"main.go:12", "+x, _ := f()", "Handle the error", "Will do", "(1 resolved)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in review modal, got:\n%s", want, view)
+		}
+	}
+	resultModel, _ = m.handlePRReviewModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m = resultModel.(Model)
+	if visible := m.visibleReviewThreads(); len(visible) != 2 || visible[1].ID != "T1" {
+		t.Fatalf("Expected resolved threads after the unresolved ones, got %+v", visible)
+	}
+
+	// Replying
+	resultModel, _ = m.handlePRReviewModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = resultModel.(Model)
+	if !m.reviewReplying {
+		t.Fatal("Expected r to open the reply editor")
+	}
+	m.reviewReplyInput.SetValue("Fixed in abc123")
+	resultModel, cmd = m.handlePRReviewModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.reviewReplying || cmd == nil {
+		t.Error("Expected enter to post the reply")
+	}
+
+	// Sending to the agent needs a running session
+	resultModel, _ = m.handlePRReviewModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = resultModel.(Model)
+	if m.notification == nil || !strings.Contains(m.notification.Message, "No running session") {
+		t.Errorf("Expected a warning without a session, got %+v", m.notification)
+	}
+	m.sessions = []session.Session{{Name: "jean-repo-login"}}
+	if _, cmd = m.handlePRReviewModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}); cmd == nil {
+		t.Error("Expected a to send the thread to the agent")
+	}
+
+	prompt := reviewThreadPrompt(pr, threads[1])
+	for _, want := range []string{"PR #7 at main.go:12", "```diff\n@@ -1,2 +1,3 @@", "alice: Handle the error\nme: Will do"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected %q in prompt, got:\n%s", want, prompt)
+		}
+	}
+}
+
 // Helper function to set up a basic test model
+// TestPRBrowser_SearchFiltersPagesAndCache tests searching PRs on GitHub from the worktree-from-PR modal
+func TestPRBrowser_SearchFiltersPagesAndCache(t *testing.T) {
+	m := setupTestModel()
+	m.width = 120
+	m.height = 40
+	m.prSearchInput = textinput.New()
+
+	resultModel, cmd := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	m = resultModel.(Model)
+	if m.modal != prListModal || !m.prListCreationMode || !m.prLoading || cmd == nil {
+		t.Fatalf("Expected the PR browser to search open PRs, got modal %d loading %v", m.modal, m.prLoading)
+	}
+	if view := m.renderPRListModal(); !strings.Contains(view, "Loading pull requests") {
+		t.Errorf("Expected a loading state, got:\n%s", view)
+	}
+
+	open := github.PRSearch{Filter: "open"}
+	first := &github.PRPage{Total: 3, HasMore: true, EndCursor: "c1", PRs: []github.PRInfo{
+		{Number: 12, Title: "Add login", HeadRefName: "login", Status: "open"},
+		{Number: 11, Title: "Dark mode", HeadRefName: "dark", Status: "open", IsDraft: true},
+	}}
+	resultModel, _ = m.Update(prsLoadedMsg{search: open, page: first})
+	m = resultModel.(Model)
+	view := m.renderPRListModal()
+	if len(m.prs) != 2 || m.prLoading || !strings.Contains(view, "Showing 2 of 3") || !strings.Contains(view, "#11 (draft)") {
+		t.Fatalf("Expected the first page, got %d PRs:\n%s", len(m.prs), view)
+	}
+
+	// Moving past the last PR loads the next page, which is appended
+	resultModel, _ = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyDown})
+	m = resultModel.(Model)
+	if !m.prLoading {
+		t.Fatal("Expected the next page to load at the end of the list")
+	}
+	next := &github.PRPage{Total: 3, PRs: []github.PRInfo{{Number: 5, Title: "Old fix", HeadRefName: "fix", Status: "open"}}}
+	resultModel, _ = m.Update(prsLoadedMsg{search: github.PRSearch{Filter: "open", After: "c1"}, page: next})
+	m = resultModel.(Model)
+	if len(m.prs) != 3 || m.prListIndex != 1 {
+		t.Fatalf("Expected 3 PRs with the selection kept, got %d at %d", len(m.prs), m.prListIndex)
+	}
+
+	// Typing searches on GitHub once the user stops, results of older searches are dropped
+	resultModel, cmd = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = resultModel.(Model)
+	if cmd == nil || m.prLoading {
+		t.Fatal("Expected the search to be debounced")
+	}
+	resultModel, _ = m.Update(prSearchDebounceMsg{seq: m.prSearchSeq - 1})
+	m = resultModel.(Model)
+	if m.prLoading {
+		t.Error("Expected an outdated debounce tick to be ignored")
+	}
+	resultModel, _ = m.Update(prSearchDebounceMsg{seq: m.prSearchSeq})
+	m = resultModel.(Model)
+	if !m.prLoading {
+		t.Fatal("Expected the search to start after the debounce")
+	}
+	resultModel, _ = m.Update(prsLoadedMsg{search: open, page: first})
+	m = resultModel.(Model)
+	if len(m.prs) != 3 {
+		t.Errorf("Expected results of the previous search to be ignored, got %d PRs", len(m.prs))
+	}
+	resultModel, _ = m.Update(prsLoadedMsg{search: github.PRSearch{Filter: "open", Text: "x"}, page: &github.PRPage{}})
+	m = resultModel.(Model)
+	if len(m.prs) != 0 || !strings.Contains(m.renderPRListModal(), "No PRs matching search") {
+		t.Errorf("Expected no results for x, got %d PRs", len(m.prs))
+	}
+
+	// Filters cycle with ctrl+f, cached searches are shown without asking GitHub again
+	m.prSearchInput.SetValue("")
+	resultModel, cmd = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = resultModel.(Model)
+	if github.PRFilters[m.prFilter] != "mine" || cmd == nil || !m.prLoading {
+		t.Fatalf("Expected a search for my PRs, got filter %q", github.PRFilters[m.prFilter])
+	}
+	m.prFilter = 0
+	m, cmd = m.searchPRs(false)
+	if cmd != nil || len(m.prs) != 3 || m.prLoading {
+		t.Errorf("Expected cached open PRs, got %d PRs (cmd %v)", len(m.prs), cmd != nil)
+	}
+	if _, cmd = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyCtrlR}); cmd == nil {
+		t.Error("Expected ctrl+r to search GitHub again")
+	}
+
+	// Ctrl+L cycles the repository's labels, then back to any label
+	resultModel, _ = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = resultModel.(Model)
+	if m.prLabel != "" || m.prLoading {
+		t.Fatalf("Expected no label filter without repository labels, got %q", m.prLabel)
+	}
+	m.prRepoMetadata = &github.RepoMetadata{Labels: []string{"bug", "ui"}}
+	resultModel, cmd = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = resultModel.(Model)
+	if m.prLabel != "bug" || cmd == nil || !m.prLoading {
+		t.Fatalf("Expected a search for label bug, got %q (loading %v)", m.prLabel, m.prLoading)
+	}
+	resultModel, _ = m.Update(prsLoadedMsg{search: open, page: first})
+	m = resultModel.(Model)
+	if !m.prLoading {
+		t.Error("Expected results without the label to be ignored")
+	}
+	bug := github.PRSearch{Filter: "open", Label: "bug"}
+	resultModel, _ = m.Update(prsLoadedMsg{search: bug, page: &github.PRPage{Total: 1, PRs: []github.PRInfo{{Number: 5, Title: "Old fix", HeadRefName: "fix", Status: "open"}}}})
+	m = resultModel.(Model)
+	view = m.renderPRListModal()
+	if len(m.prs) != 1 || !strings.Contains(view, "Label: bug") || !strings.Contains(view, "#5") {
+		t.Errorf("Expected the PRs labeled bug, got %d PRs:\n%s", len(m.prs), view)
+	}
+	for _, want := range []string{"ui", ""} {
+		resultModel, _ = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyCtrlL})
+		m = resultModel.(Model)
+		if m.prLabel != want {
+			t.Fatalf("Expected label %q, got %q", want, m.prLabel)
+		}
+	}
+	if len(m.prs) != 3 || m.prLoading {
+		t.Errorf("Expected cached open PRs without a label, got %d PRs", len(m.prs))
+	}
+
+	resultModel, cmd = m.handlePRListModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.modal != noModal || m.pendingPRInfo == nil || m.pendingPRInfo.Number != 12 || cmd == nil {
+		t.Errorf("Expected a worktree to be created from PR #12, got %+v", m.pendingPRInfo)
+	}
+}
+
 func setupTestModel() Model {
 	return Model{
 		width:  80,
@@ -388,3 +1046,660 @@ func setupTestModel() Model {
 		modal:  noModal,
 	}
 }
+
+// TestGCModal_DryRunBeforeCleaning tests that gc lists the garbage and only cleans after confirmation
+func TestGCModal_DryRunBeforeCleaning(t *testing.T) {
+	m := setupTestModel()
+
+	resultModel, cmd := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = resultModel.(Model)
+	if m.modal != gcModal || m.gcStage != 0 || cmd == nil {
+		t.Fatalf("Expected gc modal scanning, got modal %d stage %d", m.modal, m.gcStage)
+	}
+
+	items := []gc.Item{
+		{Kind: gc.OrphanSession, Name: "jean-repo-old", Reason: "no worktree for this branch"},
+		{Kind: gc.MergedBranch, Name: "done", Reason: "merged into main"},
+	}
+	resultModel, _ = m.Update(gcScannedMsg{items: items})
+	m = resultModel.(Model)
+	if m.gcStage != 1 || len(m.gcItems) != 2 {
+		t.Fatalf("Expected dry run list, got stage %d items %v", m.gcStage, m.gcItems)
+	}
+	if view := m.renderGCModal(); !strings.Contains(view, "jean-repo-old") || !strings.Contains(view, "dry run") {
+		t.Errorf("Expected dry run items in the modal, got:\n%s", view)
+	}
+
+	resultModel, cmd = m.handleGCModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.gcStage != 2 || cmd == nil {
+		t.Fatalf("Expected cleanup to start on enter, got stage %d", m.gcStage)
+	}
+
+	resultModel, _ = m.Update(gcCleanedMsg{results: []gc.Result{{Item: items[0]}, {Item: items[1], Err: errors.New("not fully merged")}}})
+	m = resultModel.(Model)
+	if m.gcStage != 3 || !strings.Contains(m.renderGCModal(), "not fully merged") {
+		t.Errorf("Expected results with the failure, got stage %d", m.gcStage)
+	}
+
+	// Nothing found: enter just closes
+	m.gcStage = 1
+	m.gcItems = nil
+	resultModel, cmd = m.handleGCModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.modal != noModal || cmd != nil {
+		t.Errorf("Expected modal to close without cleaning when nothing was found")
+	}
+}
+
+// TestStackedWorktrees_CreateAndPlanRestack tests stacking a worktree on another and planning
+// the rebases once the bottom PR merged
+func TestStackedWorktrees_CreateAndPlanRestack(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	m := setupTestModel()
+	m.configManager = configManager
+	m.repoPath = "/repo"
+	m.baseBranch = "main"
+	m.sessionNameInput = textinput.New()
+	m.worktrees = []git.Worktree{
+		{Branch: "main", Path: "/repo", Commit: "aaaaaaa"},
+		{Branch: "models", Path: "/repo/.workspaces/models", Commit: "bbbbbbb"},
+		{Branch: "api", Path: "/repo/.workspaces/api", Commit: "ccccccc"},
+		{Branch: "ui", Path: "/repo/.workspaces/ui", Commit: "ddddddd"},
+	}
+
+	// The base branch can't be stacked on
+	resultModel, _ := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if m = resultModel.(Model); m.modal == createWithNameModal {
+		t.Error("Expected stacking on the base branch to be refused")
+	}
+	m.selectedIndex = 1
+	resultModel, _ = m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = resultModel.(Model)
+	if m.modal != createWithNameModal || m.stackParent != "models" {
+		t.Fatalf("Expected the create modal stacked on models, got modal %d parent %q", m.modal, m.stackParent)
+	}
+	if view := m.renderCreateWithNameModal(); !strings.Contains(view, "Stacked On: models") {
+		t.Errorf("Expected the parent in the create modal, got:\n%s", view)
+	}
+	resultModel, _ = m.handleCreateWithNameModalInput(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = resultModel.(Model); m.stackParent != "" {
+		t.Error("Expected esc to forget the parent")
+	}
+
+	// models <- api <- ui, api's PR targets models
+	_ = configManager.SetStackEntry(m.repoPath, "api", &config.StackEntry{Parent: "models", ForkPoint: "f1"})
+	_ = configManager.SetStackEntry(m.repoPath, "ui", &config.StackEntry{Parent: "api", ForkPoint: "f2"})
+	if base := m.baseBranchFor("api"); base != "models" {
+		t.Errorf("Expected api's PR to target models, got %q", base)
+	}
+	if base := m.baseBranchFor("models"); base != "main" {
+		t.Errorf("Expected models' PR to target main, got %q", base)
+	}
+	if stale := m.needsRestack(); len(stale) != 0 {
+		t.Errorf("Expected nothing to restack yet, got %v", stale)
+	}
+
+	// models merged: api moves onto main and ui follows api
+	_ = configManager.AddPR(m.repoPath, "models", "https://github.com/o/r/pull/1", 1, "Models", "alice")
+	_ = configManager.UpdatePRStatus(m.repoPath, "models", "https://github.com/o/r/pull/1", "merged")
+	if stale := m.needsRestack(); len(stale) != 1 || stale[0] != "api" {
+		t.Errorf("Expected api to need a restack, got %v", stale)
+	}
+	m.selectedIndex = 2
+	if details := m.renderDetails(); !strings.Contains(details, "Stacked On: models") || !strings.Contains(details, "press 'U' to restack") {
+		t.Errorf("Expected the merged parent in details, got:\n%s", details)
+	}
+
+	steps, err := planRestack(configManager.GetStacks(m.repoPath), m.stackParentGone, "ui")
+	if err != nil {
+		t.Fatalf("planRestack failed: %v", err)
+	}
+	want := []restackStep{
+		{branch: "api", oldParent: "models", parent: "", forkPoint: "f1"},
+		{branch: "ui", oldParent: "api", parent: "api", forkPoint: "f2"},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("Expected %d steps, got %+v", len(want), steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("Step %d: expected %+v, got %+v", i, want[i], steps[i])
+		}
+	}
+
+	// Renaming keeps the stack together
+	if err := configManager.RenameStackBranch(m.repoPath, "api", "api-v2"); err != nil {
+		t.Fatalf("RenameStackBranch failed: %v", err)
+	}
+	if entry := configManager.GetStackEntry(m.repoPath, "ui"); entry == nil || entry.Parent != "api-v2" {
+		t.Errorf("Expected ui to follow the rename, got %+v", entry)
+	}
+	if steps, _ := planRestack(configManager.GetStacks(m.repoPath), m.stackParentGone, "models"); len(steps) != 1 || steps[0].branch != "ui" {
+		// api-v2 has no worktree in this test, so only ui is left to restack
+		t.Errorf("Expected only ui to restack, got %+v", steps)
+	}
+}
+
+// TestRestackWorktrees_OntoFetchedBase tests that a branch whose parent was merged upstream
+// moves onto the fetched base branch, not the stale local one
+func TestRestackWorktrees_OntoFetchedBase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	origin := t.TempDir()
+	gitIn(t, origin, "init", "-q", "--bare", "-b", "main")
+	repoPath := t.TempDir()
+	gitIn(t, repoPath, "init", "-q", "-b", "main")
+	gitIn(t, repoPath, "config", "user.email", "test@example.com")
+	gitIn(t, repoPath, "config", "user.name", "Test User")
+	gitIn(t, repoPath, "remote", "add", "origin", origin)
+	gitIn(t, repoPath, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	gitIn(t, repoPath, "push", "-q", "origin", "main")
+
+	// ui is stacked on api
+	apiPath := filepath.Join(repoPath, ".workspaces", "api")
+	uiPath := filepath.Join(repoPath, ".workspaces", "ui")
+	gitIn(t, repoPath, "worktree", "add", "-q", "-b", "api", apiPath)
+	if err := os.WriteFile(filepath.Join(apiPath, "api.go"), []byte("package api\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, apiPath, "add", "api.go")
+	gitIn(t, apiPath, "commit", "-q", "-m", "Add api")
+	gitIn(t, apiPath, "push", "-q", "origin", "api")
+	forkPoint := gitIn(t, apiPath, "rev-parse", "HEAD")
+	gitIn(t, repoPath, "worktree", "add", "-q", "-b", "ui", uiPath, "api")
+	gitIn(t, uiPath, "commit", "-q", "--allow-empty", "-m", "Add ui")
+
+	// api is squash-merged on GitHub, the local main doesn't have it
+	upstream := t.TempDir()
+	gitIn(t, upstream, "clone", "-q", origin, ".")
+	gitIn(t, upstream, "config", "user.email", "test@example.com")
+	gitIn(t, upstream, "config", "user.name", "Test User")
+	gitIn(t, upstream, "merge", "-q", "--squash", "origin/api")
+	gitIn(t, upstream, "commit", "-q", "-m", "Add api (#1)")
+	gitIn(t, upstream, "push", "-q", "origin", "main")
+
+	m := setupTestModel()
+	m.configManager = configManager
+	m.gitManager = git.NewManager(repoPath)
+	m.repoPath = repoPath
+	m.baseBranch = "main"
+
+	steps := []restackStep{{branch: "ui", path: uiPath, oldParent: "api", parent: "", forkPoint: forkPoint}}
+	msg, ok := m.restackWorktrees(steps)().(stackRestackedMsg)
+	if !ok || msg.err != nil || len(msg.restacked) != 1 {
+		t.Fatalf("Expected ui to be restacked, got %+v", msg)
+	}
+	if count := gitIn(t, repoPath, "rev-list", "--count", "origin/main..ui"); count != "1" {
+		t.Errorf("Expected ui to be one commit on top of origin/main, got %s", count)
+	}
+	if base := gitIn(t, repoPath, "merge-base", "origin/main", "ui"); base != gitIn(t, repoPath, "rev-parse", "origin/main") {
+		t.Error("Expected ui to be based on the fetched main")
+	}
+}
+
+// TestPlanRestack_StopsOnCycle tests that parents pointing at each other stop the restack
+func TestPlanRestack_StopsOnCycle(t *testing.T) {
+	stacks := map[string]config.StackEntry{
+		"a":  {Parent: "b"},
+		"b":  {Parent: "a"},
+		"ui": {Parent: "a"},
+	}
+	gone := func(string) bool { return false }
+	for _, branch := range []string{"a", "ui"} {
+		if steps, err := planRestack(stacks, gone, branch); err == nil {
+			t.Errorf("Expected a cycle error for %s, got %+v", branch, steps)
+		}
+	}
+}
+
+// TestMergedPRCleanup_AsksForEachMergedWorktree tests queueing the post-merge cleanup of PRs
+// merged on GitHub and the per-repository policy
+func TestMergedPRCleanup_AsksForEachMergedWorktree(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	m := setupTestModel()
+	m.configManager = configManager
+	m.repoPath = "/repo"
+	m.baseBranch = "main"
+	m.worktrees = []git.Worktree{
+		{Branch: "main", Path: "/repo", Commit: "aaaaaaa"},
+		{Branch: "login", Path: "/repo/.workspaces/login", Commit: "bbbbbbb"},
+		{Branch: "signup", Path: "/repo/.workspaces/signup", Commit: "ccccccc"},
+	}
+
+	if pr := m.mergedPRFor("main", "https://github.com/o/r/pull/1", 1); pr != nil {
+		t.Errorf("Expected the base branch never to be cleaned up, got %+v", pr)
+	}
+	login := m.mergedPRFor("login", "https://github.com/o/r/pull/7", 7)
+	signup := m.mergedPRFor("signup", "https://github.com/o/r/pull/8", 8)
+	if login == nil || login.path != "/repo/.workspaces/login" || signup == nil {
+		t.Fatalf("Expected merged PRs for the workspaces, got %+v %+v", login, signup)
+	}
+
+	// Both PRs merged: the first is offered, the second waits, refreshing again doesn't duplicate them
+	m, _ = m.offerMergedCleanup([]mergedPR{*login, *signup})
+	m, _ = m.offerMergedCleanup([]mergedPR{*login, *signup})
+	if m.modal != postMergeCleanupModal || m.postMergePR == nil || m.postMergePR.branch != "login" || len(m.mergedCleanupQueue) != 1 {
+		t.Fatalf("Expected login offered and signup queued, got %+v queue %+v", m.postMergePR, m.mergedCleanupQueue)
+	}
+	view := m.renderPostMergeCleanupModal()
+	for _, want := range []string{"PR #7 (login) was merged into main on GitHub", "Clean up", "1 more merged PR"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the cleanup modal, got:\n%s", want, view)
+		}
+	}
+
+	// Keeping login moves on to signup, cleaning signup up closes the modal
+	resultModel, _ := m.handlePostMergeCleanupModalInput(tea.KeyMsg{Type: tea.KeyEsc})
+	m = resultModel.(Model)
+	if m.postMergePR == nil || m.postMergePR.branch != "signup" || len(m.mergedCleanupQueue) != 0 {
+		t.Fatalf("Expected signup offered next, got %+v", m.postMergePR)
+	}
+	resultModel, cmd := m.handlePostMergeCleanupModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.modal != noModal || m.postMergePR != nil || cmd == nil {
+		t.Errorf("Expected the cleanup to start, got modal %d", m.modal)
+	}
+
+	// Other policies don't ask
+	_ = configManager.SetMergedPRCleanup(m.repoPath, "off")
+	if m, cmd = m.offerMergedCleanup([]mergedPR{*login}); m.modal != noModal || cmd != nil || len(m.mergedCleanupQueue) != 0 {
+		t.Error("Expected nothing to happen with the off policy")
+	}
+	_ = configManager.SetMergedPRCleanup(m.repoPath, "auto")
+	if m, cmd = m.offerMergedCleanup([]mergedPR{*login}); m.modal != noModal || cmd == nil {
+		t.Error("Expected the auto policy to clean up without asking")
+	}
+}
+
+// gitIn runs a git command in dir and fails the test on error
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TestCleanupMergedWorktrees_KeepsUnmergedCommits tests that merged cleanup only removes
+// worktrees whose local and remote commits are all in the merged PR
+func TestCleanupMergedWorktrees_KeepsUnmergedCommits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	origin := t.TempDir()
+	gitIn(t, origin, "init", "-q", "--bare", "-b", "main")
+	repoPath := t.TempDir()
+	gitIn(t, repoPath, "init", "-q", "-b", "main")
+	gitIn(t, repoPath, "config", "user.email", "test@example.com")
+	gitIn(t, repoPath, "config", "user.name", "Test User")
+	gitIn(t, repoPath, "remote", "add", "origin", origin)
+	gitIn(t, repoPath, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	gitIn(t, repoPath, "push", "-q", "origin", "main")
+
+	// login and signup are pushed and merged as PRs #7 and #8, signup got a commit afterwards
+	var merged []mergedPR
+	for i, branch := range []string{"login", "signup"} {
+		path := filepath.Join(repoPath, ".workspaces", branch)
+		gitIn(t, repoPath, "worktree", "add", "-q", "-b", branch, path)
+		gitIn(t, path, "commit", "-q", "--allow-empty", "-m", "Add "+branch)
+		gitIn(t, path, "push", "-q", "-u", "origin", branch)
+		number := 7 + i
+		gitIn(t, path, "push", "-q", "origin", fmt.Sprintf("%s:refs/pull/%d/head", branch, number))
+		merged = append(merged, mergedPR{branch: branch, path: path, number: number})
+	}
+	gitIn(t, merged[1].path, "commit", "-q", "--allow-empty", "-m", "Follow-up after the merge")
+
+	m := setupTestModel()
+	m.configManager = configManager
+	m.gitManager = git.NewManager(repoPath)
+	m.sessionManager = session.NewManager()
+	m.repoPath = repoPath
+	m.baseBranch = "main"
+
+	msg, ok := m.cleanupMergedWorktrees(merged)().(mergedCleanupMsg)
+	if !ok {
+		t.Fatal("Expected a mergedCleanupMsg")
+	}
+	if len(msg.cleaned) != 1 || msg.cleaned[0] != "login" || len(msg.unmerged) != 1 || msg.unmerged[0] != "signup" {
+		t.Fatalf("Expected login cleaned and signup kept, got %+v", msg)
+	}
+	if _, err := os.Stat(merged[0].path); !os.IsNotExist(err) {
+		t.Errorf("Expected login's worktree removed, got %v", err)
+	}
+	if _, err := os.Stat(merged[1].path); err != nil {
+		t.Errorf("Expected signup's worktree kept, got %v", err)
+	}
+	if branches := gitIn(t, repoPath, "branch", "--format=%(refname:short)", "--list", "login", "signup"); branches != "signup" {
+		t.Errorf("Expected only signup's branch left, got %q", branches)
+	}
+}
+
+// TestPRContentModal_TemplatesAndMetadataCompletion tests that the PR modal is seeded from the
+// repository's templates and completes reviewers and milestones
+func TestPRContentModal_TemplatesAndMetadataCompletion(t *testing.T) {
+	worktreePath := t.TempDir()
+	templateDir := filepath.Join(worktreePath, ".github", "PULL_REQUEST_TEMPLATE")
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(filepath.Join(templateDir, "feature.md"), []byte("## Feature\n"), 0644)
+	_ = os.WriteFile(filepath.Join(templateDir, "bugfix.md"), []byte("## Bug\n"), 0644)
+
+	m := setupTestModel()
+	m.prTitleInput = textinput.New()
+	m.prDescriptionInput = textarea.New()
+	for i := range m.prMetadataInputs {
+		m.prMetadataInputs[i] = textinput.New()
+	}
+	m.prModalWorktreePath = worktreePath
+	m.prRepoMetadata = &github.RepoMetadata{
+		Users:      []string{"alice", "alfred", "bob"},
+		Milestones: []github.Milestone{{Number: 3, Title: "v1.0"}},
+	}
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			resultModel, _ := m.handlePRContentModalInput(key)
+			m = resultModel.(Model)
+		}
+	}
+	typeText := func(text string) {
+		for _, r := range text {
+			press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	m, _ = m.openPRContentModal()
+	if m.modal != prContentModal || m.prModalFocused != 0 || m.prDescriptionInput.Value() != "## Bug" {
+		t.Fatalf("Expected the modal seeded with the first template, got %q", m.prDescriptionInput.Value())
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.prDescriptionInput.Value() != "## Feature" || !strings.Contains(m.renderPRContentModal(), "Template: feature.md") {
+		t.Errorf("Expected ctrl+t to switch to the next template, got %q", m.prDescriptionInput.Value())
+	}
+
+	// Tab to the reviewers, pick the second completion of "al"
+	press(tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	typeText("bob, al")
+	if got := m.prCompletions(); len(got) != 2 || got[0] != "alice" || got[1] != "alfred" {
+		t.Fatalf("Expected alice and alfred offered, got %v", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.prMetadataInputs[prReviewersField].Value(); got != "bob, alfred, " || m.prModalFocused != 2 {
+		t.Errorf("Expected the completion accepted, got %q", got)
+	}
+
+	// Enter without completions moves on; an unknown milestone keeps the modal open
+	press(tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyEnter})
+	typeText("v2")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prModalFocused != prCreateFocus {
+		t.Fatalf("Expected the create button focused, got %d", m.prModalFocused)
+	}
+	m.prTitleInput.SetValue("Add login")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.modal != prContentModal {
+		t.Fatal("Expected an unknown milestone to keep the modal open")
+	}
+
+	m.prMetadataInputs[prMilestoneField].SetValue("V1.0")
+	metadata, err := m.prMetadataFromInputs()
+	if err != nil || metadata.Milestone != 3 || len(metadata.Reviewers) != 2 || metadata.Reviewers[1] != "alfred" || len(metadata.Labels) != 0 {
+		t.Errorf("Expected reviewers and milestone 3, got %+v (%v)", metadata, err)
+	}
+}
+
+// TestPRContentModal_SuggestsCodeOwners tests that code owners of the changes are suggested as reviewers
+func TestPRContentModal_SuggestsCodeOwners(t *testing.T) {
+	m := setupTestModel()
+	m.prTitleInput = textinput.New()
+	m.prDescriptionInput = textarea.New()
+	for i := range m.prMetadataInputs {
+		m.prMetadataInputs[i] = textinput.New()
+	}
+	m.prModalWorktreePath = t.TempDir()
+	m.prRepoMetadata = &github.RepoMetadata{Viewer: "alice"}
+	m, _ = m.openPRContentModal()
+
+	signOffs := []github.CodeOwnerSignOff{
+		{Owner: "@org/backend", Files: []string{"api/server.go", "api/db.go"}},
+		{Owner: "@alice", Files: []string{"api/server.go"}},
+		{Owner: "docs@example.com", Files: []string{"README.md"}},
+	}
+	resultModel, _ := m.Update(codeOwnersLoadedMsg{worktreePath: "/elsewhere", path: "CODEOWNERS", signOffs: signOffs})
+	m = resultModel.(Model)
+	if len(m.prCodeOwners) != 0 {
+		t.Fatal("Expected code owners of another worktree to be ignored")
+	}
+
+	resultModel, _ = m.Update(codeOwnersLoadedMsg{worktreePath: m.prModalWorktreePath, path: ".github/CODEOWNERS", signOffs: signOffs})
+	m = resultModel.(Model)
+	if got := m.prMetadataInputs[prReviewersField].Value(); got != "org/backend" {
+		t.Errorf("Expected the team suggested without the PR's author, got %q", got)
+	}
+	view := m.renderPRContentModal()
+	for _, want := range []string{"Sign-off needed (.github/CODEOWNERS)", "@org/backend (2 files)", "docs@example.com (1 file)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the PR modal, got:\n%s", want, view)
+		}
+	}
+
+	// Reviewers typed by hand are kept, and the author is never requested
+	m.prMetadataInputs[prReviewersField].SetValue("bob, alice")
+	resultModel, _ = m.Update(codeOwnersLoadedMsg{worktreePath: m.prModalWorktreePath, path: ".github/CODEOWNERS", signOffs: signOffs})
+	m = resultModel.(Model)
+	if metadata, err := m.prMetadataFromInputs(); err != nil || len(metadata.Reviewers) != 1 || metadata.Reviewers[0] != "bob" {
+		t.Errorf("Expected only bob requested, got %+v (%v)", metadata, err)
+	}
+}
+
+// TestPRContentGenerated_OpensModalForMetadata tests that AI content generated outside the modal
+// opens it, so metadata can be set before the PR is created
+func TestPRContentGenerated_OpensModalForMetadata(t *testing.T) {
+	m := setupTestModel()
+	m.prTitleInput = textinput.New()
+	m.prDescriptionInput = textarea.New()
+	for i := range m.prMetadataInputs {
+		m.prMetadataInputs[i] = textinput.New()
+	}
+	worktreePath := t.TempDir()
+
+	resultModel, _ := m.Update(prContentGeneratedMsg{worktreePath: worktreePath, branch: "login", title: "Add login", description: "Adds a login page"})
+	m = resultModel.(Model)
+	if m.modal != prContentModal || m.prModalWorktreePath != worktreePath || m.prModalBranch != "login" {
+		t.Fatalf("Expected the PR content modal for login, got modal %d for %q", m.modal, m.prModalBranch)
+	}
+	if m.prTitleInput.Value() != "Add login" || m.prDescriptionInput.Value() != "Adds a login page" {
+		t.Errorf("Expected the generated content, got %q / %q", m.prTitleInput.Value(), m.prDescriptionInput.Value())
+	}
+}
+
+// TestIssuePicker_SearchAndWorktreeFromIssue tests picking an issue and linking its worktree to it
+func TestIssuePicker_SearchAndWorktreeFromIssue(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	m := setupTestModel()
+	m.width = 120
+	m.height = 40
+	m.configManager = configManager
+	m.sessionManager = session.NewManager()
+	m.repoPath = "/repo"
+	m.issueSearchInput = textinput.New()
+	m.prRepoMetadata = &github.RepoMetadata{Labels: []string{"bug", "ui"}}
+
+	resultModel, cmd := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = resultModel.(Model)
+	if m.modal != issueListModal || !m.issueLoading || cmd == nil {
+		t.Fatalf("Expected the issue picker to search assigned issues, got modal %d loading %v", m.modal, m.issueLoading)
+	}
+
+	assigned := github.IssueSearch{Filter: "assigned"}
+	page := &github.IssuePage{Total: 2, HasMore: true, EndCursor: "c1", Issues: []github.Issue{
+		{Number: 42, Title: "Fix login crash on Safari", Author: "bob", Labels: []string{"bug"}},
+		{Number: 7, Title: "Dark mode", Author: "carol"},
+	}}
+	resultModel, _ = m.Update(issuesLoadedMsg{search: assigned, page: page})
+	m = resultModel.(Model)
+	view := m.renderIssueListModal()
+	for _, want := range []string{"#42 - Fix login crash on Safari (by @bob) [bug]", "Showing 2 of 2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the issue picker, got:\n%s", want, view)
+		}
+	}
+
+	// Filtering by label searches again, pages of the previous search are dropped
+	resultModel, _ = m.handleIssueListModalInput(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = resultModel.(Model)
+	if m.issueLabel != "bug" || !m.issueLoading {
+		t.Fatalf("Expected a search for label bug, got %q (loading %v)", m.issueLabel, m.issueLoading)
+	}
+	resultModel, _ = m.Update(issuesLoadedMsg{search: assigned, page: &github.IssuePage{}})
+	m = resultModel.(Model)
+	if len(m.issues) != 2 || !m.issueLoading {
+		t.Errorf("Expected results of the previous search to be ignored, got %d issues", len(m.issues))
+	}
+
+	// Branches are named from the issue, and the worktree's PR closes it
+	if got := m.issueBranchName(page.Issues[0], page.Issues[0].Title); got != "42-fix-login-crash-on-safari" {
+		t.Errorf("Unexpected branch name %q", got)
+	}
+	long := github.Issue{Number: 1, Title: strings.Repeat("very long title ", 10)}
+	if got := m.issueBranchName(long, long.Title); len(got) > maxIssueBranchLength || strings.HasSuffix(got, "-") {
+		t.Errorf("Expected a trimmed branch name of at most %d characters, got %q", maxIssueBranchLength, got)
+	}
+	if prompt := issuePrompt(github.Issue{Number: 42, Title: "Crash", URL: "https://github.com/o/r/issues/42", Body: "Steps\n"}); prompt != "Work on GitHub issue #42: Crash\nhttps://github.com/o/r/issues/42\n\nSteps" {
+		t.Errorf("Unexpected prompt %q", prompt)
+	}
+
+	if err := configManager.SetBranchIssue(m.repoPath, "42-crash", &config.IssueInfo{Number: 42, Title: "Crash"}); err != nil {
+		t.Fatalf("SetBranchIssue failed: %v", err)
+	}
+	if got := m.withClosingReference("42-crash", "Fixes the crash."); got != "Fixes the crash.\n\nCloses #42" {
+		t.Errorf("Expected the PR to close the issue, got %q", got)
+	}
+	if got := m.withClosingReference("other", "Body"); got != "Body" {
+		t.Errorf("Expected branches without issue to be left alone, got %q", got)
+	}
+	m.modal = noModal
+	m.worktrees = []git.Worktree{{Branch: "42-crash", Path: "/repo/.workspaces/42-crash", Commit: "abc1234"}}
+	if details := m.renderDetails(); !strings.Contains(details, "Issue: #42 Crash") {
+		t.Errorf("Expected the issue in the details, got:\n%s", details)
+	}
+}
+
+// TestCIModal_RunsJobsLogAndAgentPrompt tests browsing CI runs, reading a failed job's log and
+// turning it into a task for the agent
+func TestCIModal_RunsJobsLogAndAgentPrompt(t *testing.T) {
+	m := setupTestModel()
+	m.width = 140
+	m.height = 40
+	m.worktrees = []git.Worktree{{Branch: "login", Path: "/repo/.workspaces/login", Commit: "abc1234", ClaudeSessionName: "jean-login"}}
+
+	resultModel, cmd := m.handleMainInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = resultModel.(Model)
+	if m.modal != ciModal || !m.ciLoading || cmd == nil {
+		t.Fatalf("Expected the CI modal to load runs, got modal %d loading %v", m.modal, m.ciLoading)
+	}
+	if view := m.renderCIModal(); !strings.Contains(view, "Loading workflow runs") {
+		t.Errorf("Expected a loading state, got:\n%s", view)
+	}
+
+	started := time.Now().Add(-time.Hour)
+	runs := []github.WorkflowRun{
+		{ID: 2, Name: "CI", Title: "Fix login", Status: "completed", Conclusion: "failure", StartedAt: started, UpdatedAt: started.Add(90 * time.Second)},
+		{ID: 1, Name: "CI", Title: "Add login", Status: "completed", Conclusion: "success"},
+	}
+	resultModel, _ = m.Update(ciRunsLoadedMsg{worktreePath: "/elsewhere", runs: runs})
+	if m = resultModel.(Model); m.ciRuns != nil {
+		t.Fatal("Expected runs of another worktree to be ignored")
+	}
+	resultModel, cmd = m.Update(ciRunsLoadedMsg{worktreePath: "/repo/.workspaces/login", runs: runs})
+	m = resultModel.(Model)
+	if len(m.ciRuns) != 2 || m.ciJobsRunID != 2 || cmd == nil {
+		t.Fatalf("Expected the jobs of the latest run to load, got %d runs, jobs of %d", len(m.ciRuns), m.ciJobsRunID)
+	}
+
+	job := github.WorkflowJob{ID: 20, Name: "test", Status: "completed", Conclusion: "failure", URL: "https://github.com/o/r/actions/runs/2/job/20",
+		Steps: []github.WorkflowStep{{Name: "Checkout", Conclusion: "success"}, {Name: "Run tests", Conclusion: "failure"}}}
+	resultModel, _ = m.Update(ciJobsLoadedMsg{runID: 1, jobs: []github.WorkflowJob{{ID: 10, Name: "stale"}}})
+	m = resultModel.(Model)
+	resultModel, _ = m.Update(ciJobsLoadedMsg{runID: 2, jobs: []github.WorkflowJob{{ID: 21, Name: "lint", Status: "completed", Conclusion: "success"}, job}})
+	m = resultModel.(Model)
+	view := m.renderCIModal()
+	for _, want := range []string{"CI Runs · login", "CI  Fix login  1m30s", "test", "failed at: Run tests"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the CI modal, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "stale") {
+		t.Error("Expected jobs of another run to be ignored")
+	}
+
+	// Enter opens the failed job's log, scrolled to its end
+	resultModel, _ = m.handleCIModalInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = resultModel.(Model)
+	if m.ciLogJob == nil || m.ciLogJob.ID != 20 {
+		t.Fatalf("Expected the log of the failed job, got %+v", m.ciLogJob)
+	}
+	lines := github.CleanJobLog("--- FAIL: TestLogin\n    login_test.go:12: expected 200\n##[error]Process completed with exit code 1.\nPost job cleanup.\n")
+	resultModel, _ = m.Update(ciLogLoadedMsg{job: job, lines: lines})
+	m = resultModel.(Model)
+	if view := m.renderCILog(); !strings.Contains(view, "Error: Process completed with exit code 1.") || !strings.Contains(view, "Lines 1-4 of 4") {
+		t.Errorf("Expected the log, got:\n%s", view)
+	}
+
+	prompt := ciFailurePrompt(m.ciWorktree.Branch, runs[0], job, lines)
+	for _, want := range []string{`CI failed on branch login: workflow "CI", job "test" at step "Run tests"`, job.URL, "login_test.go:12: expected 200", "exit code 1."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected %q in the prompt, got:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "Post job cleanup") {
+		t.Error("Expected the prompt to end at the last error")
+	}
+
+	// Without a running session the failure isn't sent, esc goes back to the runs
+	resultModel, _ = m.handleCIModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = resultModel.(Model)
+	resultModel, _ = m.handleCIModalInput(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = resultModel.(Model); m.ciLogJob != nil || m.modal != ciModal {
+		t.Error("Expected esc to close the log view only")
+	}
+
+	// Only failed runs can be re-run
+	resultModel, _ = m.handleCIModalInput(tea.KeyMsg{Type: tea.KeyDown})
+	m = resultModel.(Model)
+	if m.ciRunIndex != 1 || m.ciJobs != nil {
+		t.Fatalf("Expected the second run selected with its jobs loading, got %d", m.ciRunIndex)
+	}
+	if _, cmd := m.handleCIModalInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}}); cmd == nil {
+		t.Error("Expected a warning when re-running a successful run")
+	}
+
+	// A log sent to the agent belongs to the worktree and run it was requested for
+	resultModel, _ = m.Update(ciLogLoadedMsg{worktree: git.Worktree{Branch: "signup"}, run: runs[0], job: job, lines: lines, forAgent: true})
+	if m = resultModel.(Model); m.notification == nil || !strings.Contains(m.notification.Message, "No running session for signup") {
+		t.Errorf("Expected the failure to target signup, got %+v", m.notification)
+	}
+}
@@ -4,15 +4,25 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/coollabsio/jean-tui/config"
+	"github.com/coollabsio/jean-tui/git"
+	"github.com/coollabsio/jean-tui/github"
 	"github.com/coollabsio/jean-tui/internal/version"
+	"github.com/coollabsio/jean-tui/session"
 	"github.com/coollabsio/jean-tui/util"
 )
 
 // View renders the TUI
+// Pending notification escape sequences are prepended, so they reach the terminal through the renderer
 func (m Model) View() string {
+	return m.terminalSequence + m.view()
+}
+
+// view renders the current screen
+func (m Model) view() string {
 	if !m.ready {
 		return "Loading..."
 	}
@@ -85,7 +95,56 @@ func (m Model) renderWorktreeList() string {
 		return b.String()
 	}
 
-	for i, wt := range m.worktrees {
+	// Show filter input / applied filter and sort order
+	headerLines := 4
+	if m.worktreeFilterActive || m.worktreeFilterInput.Value() != "" {
+		if m.worktreeFilterActive {
+			b.WriteString(m.worktreeFilterInput.View())
+		} else {
+			b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("/ " + m.worktreeFilterInput.Value()))
+			b.WriteString(helpStyle.Render("  (esc to clear)"))
+		}
+		b.WriteString("\n")
+		headerLines++
+	}
+	visible := m.visibleWorktreeIndices()
+	if m.worktreeSort != "recent" || len(visible) != len(m.worktrees) {
+		info := "Sort: " + worktreeSortLabel(m.worktreeSort)
+		if len(visible) != len(m.worktrees) {
+			info += fmt.Sprintf(" • %d of %d worktrees", len(visible), len(m.worktrees))
+		}
+		b.WriteString(helpStyle.Render(info))
+		b.WriteString("\n")
+		headerLines++
+	}
+
+	if len(m.markedWorktrees) > 0 {
+		info := fmt.Sprintf("%d marked • X bulk actions • A send prompt • esc clear marks", len(m.markedWorktrees))
+		b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render(info))
+		b.WriteString("\n")
+		headerLines++
+	}
+
+	if len(visible) == 0 {
+		b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("No worktrees match the filter"))
+		return b.String()
+	}
+
+	// Scroll the list so the selected worktree stays visible
+	maxRows := m.height - 8 - headerLines
+	if maxRows < 3 {
+		maxRows = 3
+	}
+	start := 0
+	for pos, i := range visible {
+		if i == m.selectedIndex && pos >= maxRows {
+			start = pos - maxRows + 1
+		}
+	}
+	end := min(start+maxRows, len(visible))
+
+	for _, i := range visible[start:end] {
+		wt := m.worktrees[i]
 		var style lipgloss.Style
 		icon := "  "
 
@@ -110,6 +169,11 @@ func (m Model) renderWorktreeList() string {
 			icon = "  "
 		}
 
+		// Show mark for bulk actions
+		if m.markedWorktrees[wt.Path] {
+			icon += "◆ "
+		}
+
 		// Show branch name and shortened path
 		branch := wt.Branch
 		if branch == "" {
@@ -141,6 +205,18 @@ func (m Model) renderWorktreeList() string {
 				beadsIndicator := fmt.Sprintf(" [%d/%d]", wt.OpenIssues, wt.ClosedIssues)
 				line += normalItemStyle.Copy().Foreground(accentColor).Render(beadsIndicator)
 			}
+
+			// Show review, CI and merge badges of the latest open PR
+			if prs, ok := wt.PRs.([]config.PRInfo); ok && len(prs) > 0 {
+				for _, badge := range prBadges(prs[len(prs)-1]) {
+					line += normalItemStyle.Copy().Foreground(badge.color).Render(" " + badge.short)
+				}
+			}
+		}
+
+		// Show agent state reported by hooks
+		if icon, label, color := agentStateDisplay(wt.AgentState); icon != "" {
+			line += normalItemStyle.Copy().Foreground(color).Render(" " + icon + " " + label)
 		}
 
 
@@ -148,6 +224,11 @@ func (m Model) renderWorktreeList() string {
 		b.WriteString("\n")
 	}
 
+	if start > 0 || end < len(visible) {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d-%d of %d", start+1, end, len(visible))))
+		b.WriteString("\n")
+	}
+
 	return b.String()
 }
 
@@ -168,10 +249,18 @@ func (m Model) renderDetails() string {
 	b.WriteString(detailValueStyle.Render(wt.Branch))
 	b.WriteString("\n")
 
-	// Show base branch right after branch
+	// Show base branch right after branch, or the branch it is stacked on
 	if m.baseBranch != "" {
-		b.WriteString(detailKeyStyle.Render("Base Branch: "))
-		b.WriteString(detailValueStyle.Render(m.baseBranch))
+		if parent := m.baseBranchFor(wt.Branch); parent != m.baseBranch {
+			b.WriteString(detailKeyStyle.Render("Stacked On: "))
+			b.WriteString(detailValueStyle.Render(parent))
+			if m.stackParentGone(parent) {
+				b.WriteString(normalItemStyle.Copy().Foreground(warningColor).Render(" (merged, press 'U' to restack)"))
+			}
+		} else {
+			b.WriteString(detailKeyStyle.Render("Base Branch: "))
+			b.WriteString(detailValueStyle.Render(m.baseBranch))
+		}
 
 		// Show status on the same line if branch differs from base branch
 		if wt.Branch != m.baseBranch && !strings.HasPrefix(wt.Branch, "(detached") {
@@ -198,6 +287,13 @@ func (m Model) renderDetails() string {
 		b.WriteString("\n")
 	}
 
+	// Show the issue the worktree was created for
+	if issue := m.branchIssue(wt.Branch); issue != nil {
+		b.WriteString(detailKeyStyle.Render("Issue: "))
+		b.WriteString(detailValueStyle.Render(fmt.Sprintf("#%d %s", issue.Number, issue.Title)))
+		b.WriteString("\n")
+	}
+
 	details := []struct {
 		key   string
 		value string
@@ -256,6 +352,20 @@ func (m Model) renderDetails() string {
 				b.WriteString(",")
 			}
 			b.WriteString("\n")
+
+			// Show the review, CI and merge state of open PRs
+			if badges := prBadges(pr); len(badges) > 0 {
+				parts := make([]string, 0, len(badges))
+				for _, badge := range badges {
+					parts = append(parts, normalItemStyle.Copy().Foreground(badge.color).Render(badge.long))
+				}
+				b.WriteString("    " + strings.Join(parts, normalItemStyle.Copy().Foreground(mutedColor).Render(" · ")))
+				b.WriteString("\n")
+			}
+			if isOpenPR(pr) && len(pr.Reviewers) > 0 {
+				b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("    Waiting on " + strings.Join(pr.Reviewers, ", ")))
+				b.WriteString("\n")
+			}
 		}
 	}
 
@@ -279,8 +389,22 @@ func (m Model) renderDetails() string {
 		}
 	}
 
-	// Show AI Status if Claude is waiting for input
-	if wt.AIWaiting {
+	// Show AI Status reported by agent hooks, or if Claude is waiting for input
+	if icon, label, color := agentStateDisplay(wt.AgentState); icon != "" {
+		b.WriteString("\n")
+		b.WriteString(detailKeyStyle.Render("AI Status:"))
+		b.WriteString("\n")
+		b.WriteString(normalItemStyle.Copy().Foreground(color).Render(fmt.Sprintf("  %s %s", icon, label)))
+		b.WriteString("\n")
+		if wt.AgentMessage != "" {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  " + truncateString(wt.AgentMessage, max(20, m.width/2-8))))
+			b.WriteString("\n")
+		}
+		if wt.AgentState == session.AgentWaiting {
+			b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("  Press Enter to respond"))
+			b.WriteString("\n")
+		}
+	} else if wt.AIWaiting {
 		b.WriteString("\n")
 		b.WriteString(detailKeyStyle.Render("AI Status:"))
 		b.WriteString("\n")
@@ -324,6 +448,39 @@ func (m Model) renderDetails() string {
 	b.WriteString("\n")
 	b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("  Enter to start Claude"))
 
+	// Live preview of the agent (or terminal) window in the remaining space
+	b.WriteString(m.renderPanePreview(wt, strings.Count(b.String(), "\n")+1))
+
+	return b.String()
+}
+
+// renderPanePreview renders the last lines of the selected worktree's session window
+// usedLines is the number of details lines above it; the preview only takes the space left
+// in the panel and returns "" when nothing fits or there is nothing to show
+func (m Model) renderPanePreview(wt *git.Worktree, usedLines int) string {
+	if m.previewMode == "off" || m.previewPath != wt.Path || len(m.previewLines) == 0 {
+		return ""
+	}
+
+	// Panel content height is the panel height minus vertical padding, the header takes 3 lines
+	available := (m.height - 4) - 2 - usedLines - 3
+	if available < 3 {
+		return ""
+	}
+	lines := m.previewLines[max(0, len(m.previewLines)-available):]
+
+	var b strings.Builder
+	b.WriteString("\n\n")
+	b.WriteString(detailKeyStyle.Render(fmt.Sprintf("Preview (%s):", m.previewWindow)))
+	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  W to switch"))
+
+	// Cut lines to the panel width (keeping colors) and reset colors so they don't leak into the border
+	lineStyle := lipgloss.NewStyle().MaxWidth(max(10, (m.width-6)/2-4))
+	for _, line := range lines {
+		b.WriteString("\n")
+		b.WriteString(lineStyle.Render(line))
+		b.WriteString("\x1b[0m")
+	}
 	return b.String()
 }
 
@@ -411,6 +568,7 @@ func (m Model) renderNotificationOverlay(baseView, notification string) string {
 func (m Model) renderMinimalHelpBar() string {
 	keybindings := []string{
 		"↑/↓ nav",
+		"/ filter",
 		"n/a/N new/existing/PR",
 		"enter/t cli/terminal",
 		"c commit",
@@ -512,6 +670,24 @@ func (m Model) renderModal() string {
 		return m.renderOnboardingModal()
 	case gitInitModal:
 		return m.renderGitInitModal()
+	case conflictResolutionModal:
+		return m.renderConflictResolutionModal()
+	case stashModal:
+		return m.renderStashModal()
+	case refreshSummaryModal:
+		return m.renderRefreshSummaryModal()
+	case bulkActionModal:
+		return m.renderBulkActionModal()
+	case sendPromptModal:
+		return m.renderSendPromptModal()
+	case gcModal:
+		return m.renderGCModal()
+	case prReviewModal:
+		return m.renderPRReviewModal()
+	case issueListModal:
+		return m.renderIssueListModal()
+	case ciModal:
+		return m.renderCIModal()
 	}
 	return ""
 }
@@ -574,7 +750,11 @@ func (m Model) renderCreateModal() string {
 func (m Model) renderCreateWithNameModal() string {
 	var b strings.Builder
 
-	b.WriteString(modalTitleStyle.Render("Create New Worktree"))
+	title := "Create New Worktree"
+	if m.stackParent != "" {
+		title = "Create Stacked Worktree"
+	}
+	b.WriteString(modalTitleStyle.Render(title))
 	b.WriteString("\n\n")
 
 	// Session name input (starts empty)
@@ -596,6 +776,14 @@ func (m Model) renderCreateWithNameModal() string {
 	b.WriteString(helpStyle.Render("(leave empty for random name, or type a custom name)"))
 	b.WriteString("\n\n")
 
+	if m.stackParent != "" {
+		b.WriteString(detailKeyStyle.Render("Stacked On: "))
+		b.WriteString(detailValueStyle.Render(m.stackParent))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("(branches from it, PRs will target it)"))
+		b.WriteString("\n\n")
+	}
+
 	// Show info about what will be created
 	sessionName := m.sessionNameInput.Value()
 
@@ -919,13 +1107,26 @@ func min(a, b int) int {
 func (m Model) renderSessionListModal() string {
 	var b strings.Builder
 
-	b.WriteString(modalTitleStyle.Render("Active Tmux Sessions"))
+	b.WriteString(modalTitleStyle.Render("Active Sessions"))
 	b.WriteString("\n\n")
 
+	// Sessions lost with a previous tmux server (reboot, crash) can be recreated
+	restoreHelp := ""
+	if len(m.restorableSessions) > 0 {
+		b.WriteString(normalItemStyle.Copy().Foreground(warningColor).Render(fmt.Sprintf("%d session%s can be restored:", len(m.restorableSessions), pluralize(len(m.restorableSessions)))))
+		b.WriteString("\n")
+		for _, record := range m.restorableSessions {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(fmt.Sprintf("  ↺ %s (%d window%s)", strings.TrimPrefix(record.Name, "jean-"), len(record.Windows), pluralize(len(record.Windows)))))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		restoreHelp = " • r restore all"
+	}
+
 	if len(m.sessions) == 0 {
 		b.WriteString(normalItemStyle.Render("No active sessions found"))
 		b.WriteString("\n\n")
-		b.WriteString(helpStyle.Render("Press Esc to close"))
+		b.WriteString(helpStyle.Render("Press Esc to close" + restoreHelp))
 	} else {
 		// Show sessions
 		maxVisible := 10
@@ -973,7 +1174,10 @@ func (m Model) renderSessionListModal() string {
 		b.WriteString(helpStyle.Render(fmt.Sprintf("Showing %d-%d of %d sessions", start+1, end, len(m.sessions))))
 		b.WriteString("\n\n")
 
-		b.WriteString(helpStyle.Render("↑↓ navigate • Enter attach • d kill • Esc close"))
+		b.WriteString(m.renderSessionWindows())
+		b.WriteString("\n")
+
+		b.WriteString(helpStyle.Render("↑↓ session • ←→ window • Enter attach • x kill window • R restart agent • d kill session" + restoreHelp + " • Esc close"))
 	}
 
 	return lipgloss.Place(
@@ -983,6 +1187,83 @@ func (m Model) renderSessionListModal() string {
 	)
 }
 
+// renderSessionWindows renders the windows of the selected session with their program,
+// PID, CPU and memory usage, and the agent state on the agent window
+func (m Model) renderSessionWindows() string {
+	var b strings.Builder
+	sess := m.selectedSession()
+	if sess == nil {
+		return ""
+	}
+	b.WriteString(normalItemStyle.Render("Windows of " + sess.Branch + ":"))
+	b.WriteString("\n")
+	if len(m.sessionWindows) == 0 {
+		b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  Loading..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	nameWidth := 0
+	for _, window := range m.sessionWindows {
+		if len(window.Name) > nameWidth {
+			nameWidth = len(window.Name)
+		}
+	}
+	agentWindow := m.sessionManager.Agent().WindowName()
+	wt := m.worktreeForSession(sess.Name)
+
+	for i, window := range m.sessionWindows {
+		marker := " "
+		if window.Active {
+			marker = "*"
+		}
+		line := fmt.Sprintf("%s%d %-*s", marker, window.Index, nameWidth, window.Name)
+		if window.Command != "" {
+			line += fmt.Sprintf("  %-8s", window.Command)
+		}
+		if window.PID > 0 {
+			line += fmt.Sprintf("  pid %-7d", window.PID)
+		}
+		if window.stats != nil {
+			cpu := "  -  "
+			if window.cpu >= 0 {
+				cpu = fmt.Sprintf("%4.1f%%", window.cpu)
+			}
+			line += fmt.Sprintf("  cpu %s  mem %s", cpu, formatMemory(window.stats.MemoryKB))
+		}
+
+		style := normalItemStyle
+		if i == m.sessionWindowIndex {
+			style = selectedItemStyle
+		}
+		b.WriteString(style.Render(line))
+
+		// The agent state comes from the worktree the session belongs to
+		if window.Name == agentWindow && wt != nil {
+			icon, label, color := agentStateDisplay(wt.AgentState)
+			if icon == "" && wt.AIWaiting {
+				icon, label, color = agentStateDisplay(session.AgentWaiting)
+			}
+			if icon != "" {
+				b.WriteString(lipgloss.NewStyle().Foreground(color).Render(" " + icon + " " + label))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatMemory formats a memory size in KB as KB, MB or GB
+func formatMemory(kb int64) string {
+	switch {
+	case kb >= 1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(kb)/(1024*1024))
+	case kb >= 1024:
+		return fmt.Sprintf("%.1f MB", float64(kb)/1024)
+	}
+	return fmt.Sprintf("%d KB", kb)
+}
+
 func (m Model) renderRenameModal() string {
 	var b strings.Builder
 
@@ -1250,7 +1531,7 @@ func (m Model) renderCommitModal() string {
 	b.WriteString(buttons)
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("Tab: next • Enter: confirm • Esc: cancel"))
+	b.WriteString(helpStyle.Render("Tab: next • Enter: confirm • Alt+Enter: new line • ↑/↓: pick completion • Esc: cancel"))
 
 	// Center the modal
 	modalContent := b.String()
@@ -1284,6 +1565,13 @@ func (m Model) renderPRContentModal() string {
 
 	// Description input
 	b.WriteString(inputLabelStyle.Render("Description (optional):"))
+	if m.prTemplateIndex < len(m.prTemplates) {
+		template := "Template: " + m.prTemplates[m.prTemplateIndex].Name
+		if len(m.prTemplates) > 1 {
+			template += fmt.Sprintf(" (ctrl+t: next of %d)", len(m.prTemplates))
+		}
+		b.WriteString("  " + helpStyle.Render(template))
+	}
 	b.WriteString("\n")
 	descriptionStyle := normalItemStyle
 	if m.prModalFocused == 1 {
@@ -1292,6 +1580,35 @@ func (m Model) renderPRContentModal() string {
 	b.WriteString(descriptionStyle.Render(m.prDescriptionInput.View()))
 	b.WriteString("\n\n")
 
+	// Code owners of the changes, suggested as reviewers
+	if len(m.prCodeOwners) > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("Sign-off needed (%s): %s", m.prCodeOwnersPath, m.codeOwnersSummary())))
+		b.WriteString("\n")
+	}
+
+	// Reviewers, assignees, labels and milestone, one per line
+	for i, label := range prMetadataLabels {
+		fieldStyle := normalItemStyle
+		if m.prModalFocused == i+2 {
+			fieldStyle = selectedItemStyle
+		}
+		b.WriteString(inputLabelStyle.Render(fmt.Sprintf("%-10s", label+":")))
+		b.WriteString(fieldStyle.Render(m.prMetadataInputs[i].View()))
+		b.WriteString("\n")
+
+		if m.prModalFocused == i+2 {
+			for j, completion := range m.prCompletions() {
+				if j == m.prCompletionIndex {
+					b.WriteString(selectedItemStyle.Render("            › " + completion))
+				} else {
+					b.WriteString(helpStyle.Render("              " + completion))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	b.WriteString("\n")
+
 	// Spinner or status message
 	if m.generatingPRContent {
 		// Show spinner animation while generating
@@ -1315,9 +1632,9 @@ func (m Model) renderPRContentModal() string {
 	createStyle := normalItemStyle
 	cancelStyle := normalItemStyle
 
-	if m.prModalFocused == 2 {
+	if m.prModalFocused == prCreateFocus {
 		createStyle = selectedItemStyle
-	} else if m.prModalFocused == 3 {
+	} else if m.prModalFocused == prCancelFocus {
 		cancelStyle = selectedItemStyle
 	}
 
@@ -1330,7 +1647,7 @@ func (m Model) renderPRContentModal() string {
 	b.WriteString(buttons)
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("Tab: next • Enter: confirm • Esc: cancel"))
+	b.WriteString(helpStyle.Render("Tab: next • Enter: confirm • Alt+Enter: new line • ↑/↓: pick completion • Esc: cancel"))
 
 	// Center the modal
 	modalContent := b.String()
@@ -1373,7 +1690,7 @@ func (m Model) renderPRListModal() string {
 	}
 
 	// Handle loading case (no PRs loaded yet)
-	if len(m.prs) == 0 {
+	if len(m.prs) == 0 && (m.prLoading || !m.prListCreationMode) {
 		if len(m.filteredPRs) == 0 {
 			m.debugLog("renderPRListModal: displaying loading state (no PRs loaded yet)")
 			// Determine modal title based on mode
@@ -1423,18 +1740,27 @@ func (m Model) renderPRListModal() string {
 	b.WriteString(m.prSearchInput.View())
 	b.WriteString("\n\n")
 
+	// Quick filters and paging status of the PR browser
+	if m.prListCreationMode {
+		b.WriteString(m.renderPRBrowserStatus())
+		b.WriteString("\n\n")
+	}
+
 	// Show filtered PRs list
 	filteredPRs := m.filterPRs(m.prSearchInput.Value())
 
 	if len(filteredPRs) == 0 {
-		if m.prSearchInput.Value() != "" {
+		if m.prSearchInput.Value() != "" || (m.prListCreationMode && (m.prFilter != 0 || m.prLabel != "")) {
 			b.WriteString(helpStyle.Render("No PRs matching search"))
 		} else {
 			b.WriteString(helpStyle.Render("No open pull requests found"))
 		}
 	} else {
-		// Calculate max lines for list (leave room for header, search, buttons, help)
+		// Calculate max lines for list (leave room for header, search, filters, buttons, help)
 		maxLines := m.height - 15
+		if m.prListCreationMode {
+			maxLines -= 3
+		}
 		startIdx := 0
 		if m.prListIndex >= maxLines {
 			startIdx = m.prListIndex - maxLines + 1
@@ -1455,6 +1781,9 @@ func (m Model) renderPRListModal() string {
 			if statusDisplay == "" {
 				statusDisplay = "open" // default to open if not set
 			}
+			if pr.IsDraft && statusDisplay == "open" {
+				statusDisplay = "draft"
+			}
 			line := fmt.Sprintf("#%d (%s) - %s (by @%s) [%s]",
 				pr.Number,
 				statusDisplay,
@@ -1491,7 +1820,101 @@ func (m Model) renderPRListModal() string {
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("↑↓ navigate • Enter to create • Tab to focus • Esc to cancel"))
+	if m.prListCreationMode {
+		b.WriteString(helpStyle.Render("↑↓ navigate • Ctrl+F filter • Ctrl+L label • Ctrl+R refresh • Enter to create • Tab to focus • Esc to cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("↑↓ navigate • Enter to create • Tab to focus • Esc to cancel"))
+	}
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		modalStyle.Render(b.String()),
+	)
+}
+
+// renderPRBrowserStatus renders the quick filters with the active one highlighted, and how many
+// of the matching PRs are loaded
+func (m Model) renderPRBrowserStatus() string {
+	var filters []string
+	for i, filter := range github.PRFilters {
+		if i == m.prFilter {
+			filters = append(filters, selectedItemStyle.Render(filter))
+		} else {
+			filters = append(filters, helpStyle.Render(filter))
+		}
+	}
+	line := helpStyle.Render("Filter: ") + strings.Join(filters, helpStyle.Render(" · "))
+	if m.prLabel != "" {
+		line += helpStyle.Render("  Label: ") + selectedItemStyle.Render(m.prLabel)
+	}
+
+	var status string
+	cached := m.prSearchCache[prSearchKey(m.currentPRSearch())]
+	switch {
+	case m.prLoading:
+		status = "Searching GitHub..."
+	case cached != nil && cached.hasMore:
+		status = fmt.Sprintf("Showing %d of %d • ↓ at the end loads more", len(cached.prs), cached.total)
+	case cached != nil:
+		status = fmt.Sprintf("%d PRs", cached.total)
+	}
+	if status != "" {
+		line += "\n" + helpStyle.Render(status)
+	}
+	return line
+}
+
+func (m Model) renderIssueListModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Select Issue to Create Worktree"))
+	b.WriteString("\n\n")
+	b.WriteString(inputLabelStyle.Render("Search:"))
+	b.WriteString("\n")
+	b.WriteString(m.issueSearchInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.renderIssuePickerStatus())
+	b.WriteString("\n\n")
+
+	switch {
+	case m.issueLoadingError != "":
+		b.WriteString(errorStyle.Render("Error: " + m.issueLoadingError))
+	case len(m.issues) == 0 && m.issueLoading:
+		b.WriteString(helpStyle.Render("Loading issues..."))
+	case len(m.issues) == 0:
+		b.WriteString(helpStyle.Render("No issues matching search"))
+	default:
+		// Leave room for header, search, filters and help
+		maxLines := m.height - 18
+		if maxLines < 1 {
+			maxLines = 1
+		}
+		startIdx := 0
+		if m.issueListIndex >= maxLines {
+			startIdx = m.issueListIndex - maxLines + 1
+		}
+		endIdx := min(startIdx+maxLines, len(m.issues))
+
+		for i := startIdx; i < endIdx; i++ {
+			issue := m.issues[i]
+
+			// Format: #123 - Title (by @author) [label, label]
+			line := fmt.Sprintf("#%d - %s (by @%s)", issue.Number, issue.Title, issue.Author)
+			if len(issue.Labels) > 0 {
+				line += " [" + strings.Join(issue.Labels, ", ") + "]"
+			}
+			if i == m.issueListIndex {
+				b.WriteString(selectedItemStyle.Render("› " + line))
+			} else {
+				b.WriteString(normalItemStyle.Render("  " + line))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("↑↓ navigate • Ctrl+F filter • Ctrl+L label • Ctrl+R refresh • Enter to create • Esc to cancel"))
 
 	return lipgloss.Place(
 		m.width, m.height,
@@ -1500,6 +1923,37 @@ func (m Model) renderPRListModal() string {
 	)
 }
 
+// renderIssuePickerStatus renders the quick filters with the active one highlighted, the label
+// filter and how many of the matching issues are loaded
+func (m Model) renderIssuePickerStatus() string {
+	var filters []string
+	for i, filter := range github.IssueFilters {
+		if i == m.issueFilter {
+			filters = append(filters, selectedItemStyle.Render(filter))
+		} else {
+			filters = append(filters, helpStyle.Render(filter))
+		}
+	}
+	line := helpStyle.Render("Filter: ") + strings.Join(filters, helpStyle.Render(" · "))
+	if m.issueLabel != "" {
+		line += helpStyle.Render("  Label: ") + selectedItemStyle.Render(m.issueLabel)
+	}
+
+	var status string
+	switch {
+	case m.issueLoading:
+		status = "Searching GitHub..."
+	case m.issueHasMore:
+		status = fmt.Sprintf("Showing %d of %d • ↓ at the end loads more", len(m.issues), m.issueTotal)
+	case m.issueLoadingError == "":
+		status = fmt.Sprintf("%d issue%s", m.issueTotal, pluralize(m.issueTotal))
+	}
+	if status != "" {
+		line += "\n" + helpStyle.Render(status)
+	}
+	return line
+}
+
 func (m Model) renderEditorSelectModal() string {
 	var b strings.Builder
 
@@ -1684,6 +2138,72 @@ func (m Model) renderSettingsModal() string {
 				return "No hooks configured"
 			},
 		},
+		{
+			name:        "Auto-stash on Refresh",
+			key:         "z",
+			description: "Stash dirty worktrees during refresh, pull, then restore the changes",
+			getCurrent: func() string {
+				if m.configManager != nil && m.configManager.GetAutoStash(m.repoPath) {
+					return "Enabled"
+				}
+				return "Disabled"
+			},
+		},
+		{
+			name:        "AI Agent",
+			key:         "g",
+			description: "Coding agent started in the agent window (profiles from config \"agents\")",
+			getCurrent: func() string {
+				if m.configManager != nil {
+					return m.configManager.GetAgentName(m.repoPath)
+				}
+				return config.DefaultAgentName
+			},
+		},
+		{
+			name:        "Agent State Hooks",
+			key:         "k",
+			description: "Add/remove Claude Code hooks in ~/.claude/settings.json that report agent state",
+			getCurrent: func() string {
+				if m.agentHooksInstalled {
+					return "Installed"
+				}
+				return "Not installed"
+			},
+		},
+		{
+			name:        "Agent Notifications",
+			key:         "n",
+			description: "Notify when an agent in this repository needs input (methods are set in config.json)",
+			getCurrent: func() string {
+				if m.configManager != nil && m.configManager.GetNotificationsMuted(m.repoPath) {
+					return "Muted"
+				}
+				return "On"
+			},
+		},
+		{
+			name:        "Multiplexer",
+			key:         "m",
+			description: "Terminal multiplexer hosting worktree sessions (tmux or zellij)",
+			getCurrent: func() string {
+				if m.sessionManager != nil {
+					return m.sessionManager.Multiplexer().Name()
+				}
+				return session.MultiplexerTmux
+			},
+		},
+		{
+			name:        "Merged PR Cleanup",
+			key:         "x",
+			description: "When a worktree's PR merges on GitHub: remove worktree, sessions and branches, pull base",
+			getCurrent: func() string {
+				if m.configManager != nil {
+					return mergedPRCleanupLabel(m.configManager.GetMergedPRCleanup(m.repoPath))
+				}
+				return mergedPRCleanupLabel("ask")
+			},
+		},
 	}
 
 	// Render settings list
@@ -2107,7 +2627,14 @@ func (m Model) renderHelperModal() string {
 			}{
 				{"↑", "Move cursor up"},
 				{"↓", "Move cursor down"},
+				{"/", "Filter worktrees (fuzzy + is:dirty/behind/pr/waiting)"},
+				{"O", "Cycle sort order (recent, name, ahead/behind, PR)"},
+				{"space", "Mark/unmark worktree for bulk actions"},
+				{"X", "Bulk actions on marked worktrees (delete, pull, push, kill)"},
+				{"A", "Send a prompt to the agent (marked worktrees or selected)"},
+				{"W", "Switch details preview (agent window, terminal, off)"},
 				{"n", "Create new worktree (with AI)"},
+				{"T", "Create worktree stacked on the selected one"},
 				{"a", "Create new worktree (from existing branch)"},
 				{"enter", "Open CLI (Claude for now)"},
 				{"t", "Open terminal"},
@@ -2124,7 +2651,11 @@ func (m Model) renderHelperModal() string {
 				{"c", "Commit all uncommitted changes (with AI)"},
 				{"p", "Push to remote (with AI)"},
 				{"u", "Update from base branch (pull/merge)"},
+				{"U", "Restack: rebase stacked worktrees onto their parents"},
+				{"C", "Resolve merge/rebase conflicts"},
+				{"z", "Manage stashes (push/pop/apply/drop)"},
 				{"r", "Refresh status (fetch from remote, no merging)"},
+				{"R", "Show results of the last refresh"},
 				{"b", "Change base branch for new worktrees"},
 				{"B", "Rename current branch"},
 				{"K", "Checkout/switch branch in main repo"},
@@ -2138,8 +2669,11 @@ func (m Model) renderHelperModal() string {
 			}{
 				{"P", "Create new PR on GitHub"},
 				{"N", "Create worktree from existing PR"},
+				{"I", "Create worktree from GitHub issue"},
 				{"L", "Local merge (worktree → base branch)"},
 				{"v", "Open PR in default browser"},
+				{"V", "Review threads: reply, resolve, send to agent"},
+				{"J", "CI runs: jobs, logs, re-run, send failure to agent"},
 			},
 		},
 		{
@@ -2151,7 +2685,8 @@ func (m Model) renderHelperModal() string {
 				{"s", "Open settings"},
 				{"e", "Select default editor"},
 				{"E", "Edit config file (external editor)"},
-				{"S", "View tmux sessions"},
+				{"S", "View sessions and windows"},
+				{"G", "Clean up orphan sessions, stale worktrees, merged branches"},
 				{"h", "Show this help"},
 				{"q", "Quit application"},
 			},
@@ -2363,6 +2898,9 @@ func (m Model) renderPostMergeCleanupModal() string {
 
 	// Success message
 	successMsg := fmt.Sprintf("Successfully merged %s into %s", m.localMergeBranch, m.localMergeTarget)
+	if m.postMergePR != nil {
+		successMsg = fmt.Sprintf("PR #%d (%s) was merged into %s on GitHub", m.postMergePR.number, m.localMergeBranch, m.localMergeTarget)
+	}
 	b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render(successMsg))
 	b.WriteString("\n\n")
 
@@ -2378,6 +2916,10 @@ func (m Model) renderPostMergeCleanupModal() string {
 		{"Delete worktree", "Remove the worktree and keep workspace tidy"},
 		{"Keep worktree", "Keep it for reference or future work"},
 	}
+	if m.postMergePR != nil {
+		options[0].name = "Clean up"
+		options[0].description = fmt.Sprintf("Kill its sessions, remove the worktree, delete the local and remote branch and pull %s", m.baseBranch)
+	}
 
 	for i, option := range options {
 		isSelected := i == m.postMergeDeleteIndex
@@ -2398,6 +2940,11 @@ func (m Model) renderPostMergeCleanupModal() string {
 		b.WriteString("\n\n")
 	}
 
+	if len(m.mergedCleanupQueue) > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%d more merged PR%s to go", len(m.mergedCleanupQueue), pluralize(len(m.mergedCleanupQueue)))))
+		b.WriteString("\n")
+	}
+
 	// Help text
 	b.WriteString(helpStyle.Render("↑/↓ select • enter confirm • esc skip"))
 
@@ -2410,37 +2957,322 @@ func (m Model) renderPostMergeCleanupModal() string {
 	)
 }
 
-func (m Model) renderOnboardingModal() string {
+func (m Model) renderConflictResolutionModal() string {
 	var b strings.Builder
 
-	b.WriteString(modalTitleStyle.Render("Welcome to jean!"))
-	b.WriteString("\n\n")
-
-	// Brief introduction
-	b.WriteString(normalItemStyle.Render("jean is a TUI for managing Git worktrees with tmux + Claude integration."))
+	b.WriteString(modalTitleStyle.Render("⚔ Resolve Conflicts"))
 	b.WriteString("\n\n")
 
-	// Explain shell integration
-	b.WriteString(detailKeyStyle.Render("✓ Shell Integration"))
+	b.WriteString(detailKeyStyle.Render("Path: "))
+	b.WriteString(detailValueStyle.Render(m.conflictPath))
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("  Your shell wrapper is already configured and active!"))
+	if m.conflictOperation != "" {
+		b.WriteString(detailKeyStyle.Render("Operation: "))
+		b.WriteString(detailValueStyle.Render(m.conflictOperation + " in progress"))
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("  You can now switch worktrees seamlessly."))
-	b.WriteString("\n\n")
 
-	// Explain tmux config
-	b.WriteString(detailKeyStyle.Render("⚙  Tmux Configuration (Optional)"))
-	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("  We recommend installing our opinionated tmux config:"))
-	b.WriteString("\n")
-	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Mouse support for scrolling"))
-	b.WriteString("\n")
-	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Ctrl-D to detach (familiar keybinding)"))
-	b.WriteString("\n")
-	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • 10,000 line scrollback buffer"))
-	b.WriteString("\n")
-	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Clean status bar with repo:branch info"))
-	b.WriteString("\n")
+	helpText := ""
+	if m.conflictFile != nil {
+		m.renderConflictHunk(&b)
+		helpText = "↑/↓ hunk • o ours • t theirs • b both • u reset • i AI suggest • e editor • w save • esc back"
+	} else {
+		if len(m.conflictFiles) == 0 {
+			b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render("✓ All conflicts resolved"))
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("Press 'c' to continue the " + m.conflictOperation))
+			b.WriteString("\n")
+		} else {
+			b.WriteString(normalItemStyle.Render(fmt.Sprintf("%d conflicted file%s:", len(m.conflictFiles), pluralize(len(m.conflictFiles)))))
+			b.WriteString("\n")
+			for i, file := range m.conflictFiles {
+				if i == m.conflictFileIndex {
+					b.WriteString(selectedItemStyle.Render("▶ " + file))
+				} else {
+					b.WriteString(normalItemStyle.Render("  " + file))
+				}
+				b.WriteString("\n")
+			}
+		}
+		helpText = "↑/↓ select • enter resolve hunks • e editor • a mark resolved • c continue • x abort • esc close"
+	}
+
+	// Status message
+	if m.conflictStatus != "" {
+		b.WriteString("\n")
+		statusStyle := normalItemStyle.Copy().Foreground(mutedColor)
+		if strings.HasPrefix(m.conflictStatus, "Error") || strings.Contains(m.conflictStatus, "failed") {
+			statusStyle = normalItemStyle.Copy().Foreground(errorColor)
+		} else if strings.HasPrefix(m.conflictStatus, "✓") {
+			statusStyle = normalItemStyle.Copy().Foreground(successColor)
+		}
+		b.WriteString(statusStyle.Render(m.conflictStatus))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(helpText))
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// renderConflictHunk renders the ours/base/theirs sides of the selected hunk
+func (m Model) renderConflictHunk(b *strings.Builder) {
+	file := m.conflictFile
+	hunk := file.Hunks[m.conflictHunkIndex]
+
+	b.WriteString(detailKeyStyle.Render("File: "))
+	b.WriteString(detailValueStyle.Render(file.Path))
+	b.WriteString(helpStyle.Render(fmt.Sprintf("  hunk %d/%d • %d unresolved", m.conflictHunkIndex+1, len(file.Hunks), file.Unresolved())))
+	b.WriteString("\n\n")
+
+	// Limit each side so large hunks still fit on screen
+	maxLines := (m.height - 20) / 4
+	if maxLines < 3 {
+		maxLines = 3
+	}
+	writeSide := func(label string, color lipgloss.TerminalColor, text string) {
+		b.WriteString(normalItemStyle.Copy().Foreground(color).Bold(true).Render(label))
+		b.WriteString("\n")
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		if text == "" {
+			lines = []string{"(empty)"}
+		}
+		for i, line := range lines {
+			if i == maxLines {
+				b.WriteString(helpStyle.Render(fmt.Sprintf("  ... %d more line%s", len(lines)-maxLines, pluralize(len(lines)-maxLines))))
+				b.WriteString("\n")
+				break
+			}
+			b.WriteString(normalItemStyle.Render("  " + truncateString(line, m.width-14)))
+			b.WriteString("\n")
+		}
+	}
+
+	writeSide("Ours ("+hunk.OursLabel+")", successColor, hunk.Ours)
+	if hunk.Base != "" {
+		writeSide("Base", mutedColor, hunk.Base)
+	}
+	writeSide("Theirs ("+hunk.TheirsLabel+")", accentColor, hunk.Theirs)
+
+	b.WriteString("\n")
+	choice := "unresolved"
+	switch hunk.Choice {
+	case git.ConflictOurs:
+		choice = "keep ours"
+	case git.ConflictTheirs:
+		choice = "keep theirs"
+	case git.ConflictBoth:
+		choice = "keep both (ours, then theirs)"
+	case git.ConflictCustom:
+		choice = "AI suggestion"
+	}
+	b.WriteString(detailKeyStyle.Render("Resolution: "))
+	if hunk.Choice == git.ConflictUnresolved {
+		b.WriteString(normalItemStyle.Copy().Foreground(warningColor).Render(choice))
+	} else {
+		b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render(choice))
+	}
+	b.WriteString("\n")
+	if hunk.Choice == git.ConflictCustom {
+		writeSide("Suggested", warningColor, hunk.Resolution)
+	}
+}
+
+func (m Model) renderStashModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Stashes"))
+	b.WriteString("\n\n")
+
+	b.WriteString(detailKeyStyle.Render("Worktree: "))
+	b.WriteString(detailValueStyle.Render(m.stashBranch))
+	b.WriteString("\n")
+	scope := "this branch"
+	if m.stashShowAll {
+		scope = "all branches"
+	}
+	b.WriteString(detailKeyStyle.Render("Showing: "))
+	b.WriteString(detailValueStyle.Render(scope))
+	b.WriteString("\n\n")
+
+	if m.stashInputActive {
+		b.WriteString(inputLabelStyle.Render("Stash message:"))
+		b.WriteString("\n")
+		b.WriteString(m.stashMessageInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter stash (includes untracked files) • esc cancel"))
+	} else {
+		visible := m.visibleStashes()
+		if len(visible) == 0 {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("No stashes"))
+			b.WriteString("\n")
+		}
+
+		// Show a window of stashes around the selection
+		maxVisible := 10
+		start := m.stashIndex - maxVisible/2
+		if start < 0 {
+			start = 0
+		}
+		end := min(start+maxVisible, len(visible))
+
+		for i := start; i < end; i++ {
+			stash := visible[i]
+			line := fmt.Sprintf("%s  %s", stash.Ref, stash.Message)
+			if m.stashShowAll && stash.Branch != "" {
+				line += " [" + stash.Branch + "]"
+			}
+
+			if i == m.stashIndex {
+				b.WriteString(selectedItemStyle.Render("▶ " + line))
+			} else {
+				b.WriteString(normalItemStyle.Render("  " + line))
+			}
+			b.WriteString(helpStyle.Render("  " + stash.Date))
+			b.WriteString("\n")
+		}
+
+		if len(visible) > maxVisible {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("Showing %d-%d of %d stashes", start+1, end, len(visible))))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("↑↓ navigate • n stash changes • enter/p pop • a apply • d drop • f toggle all branches • esc close"))
+	}
+
+	// Status message
+	if m.stashStatus != "" {
+		b.WriteString("\n\n")
+		statusStyle := normalItemStyle.Copy().Foreground(warningColor)
+		if strings.HasPrefix(m.stashStatus, "Error") {
+			statusStyle = normalItemStyle.Copy().Foreground(errorColor)
+		} else if strings.HasPrefix(m.stashStatus, "✓") {
+			statusStyle = normalItemStyle.Copy().Foreground(successColor)
+		}
+		b.WriteString(statusStyle.Render(m.stashStatus))
+	}
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderRefreshSummaryModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Refresh Summary"))
+	b.WriteString("\n\n")
+
+	if len(m.refreshResults) == 0 {
+		b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("No worktrees were refreshed"))
+		b.WriteString("\n")
+	}
+
+	for i, result := range m.refreshResults {
+		icon, text, color := "·", "up to date", mutedColor
+		switch result.outcome {
+		case refreshUpdated:
+			icon, text, color = "✓", fmt.Sprintf("updated (+%d commit%s)", result.commits, pluralize(result.commits)), successColor
+		case refreshSkippedDirty:
+			icon, text, color = "●", "skipped: uncommitted changes", warningColor
+		case refreshSkippedNoRemote:
+			icon, text, color = "○", "skipped: branch not on remote", mutedColor
+		case refreshDiverged:
+			icon, text, color = "⇅", fmt.Sprintf("diverged: %d ahead, %d behind remote", result.ahead, result.behind), warningColor
+		case refreshFailed:
+			icon, text, color = "✗", "failed", errorColor
+		}
+		if result.autoStashed {
+			text += " (autostashed)"
+		}
+
+		branch := fmt.Sprintf("%s %-30s", icon, truncateString(result.branch, 30))
+		if i == m.refreshSummaryIndex {
+			b.WriteString(selectedItemStyle.Render("▶ " + branch))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + branch))
+		}
+		b.WriteString(normalItemStyle.Copy().Foreground(color).Render(" " + text))
+		b.WriteString("\n")
+
+		// Show the failure reason for the selected worktree
+		if i == m.refreshSummaryIndex && result.err != nil {
+			reason := strings.TrimSpace(result.err.Error())
+			for _, line := range strings.Split(reason, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					b.WriteString(normalItemStyle.Copy().Foreground(errorColor).Render("    " + truncateString(line, m.width-16)))
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	// Hint for dirty worktrees
+	for _, result := range m.refreshResults {
+		if result.outcome == refreshSkippedDirty {
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("Tip: enable Auto-stash on Refresh in settings (s → z) to update dirty worktrees"))
+			b.WriteString("\n")
+			break
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑↓ navigate • g go to worktree • esc close"))
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+func (m Model) renderOnboardingModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Welcome to jean!"))
+	b.WriteString("\n\n")
+
+	// Brief introduction
+	b.WriteString(normalItemStyle.Render("jean is a TUI for managing Git worktrees with tmux + Claude integration."))
+	b.WriteString("\n\n")
+
+	// Explain shell integration
+	b.WriteString(detailKeyStyle.Render("✓ Shell Integration"))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("  Your shell wrapper is already configured and active!"))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("  You can now switch worktrees seamlessly."))
+	b.WriteString("\n\n")
+
+	// Explain tmux config
+	b.WriteString(detailKeyStyle.Render("⚙  Tmux Configuration (Optional)"))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("  We recommend installing our opinionated tmux config:"))
+	b.WriteString("\n")
+	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Mouse support for scrolling"))
+	b.WriteString("\n")
+	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Ctrl-D to detach (familiar keybinding)"))
+	b.WriteString("\n")
+	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • 10,000 line scrollback buffer"))
+	b.WriteString("\n")
+	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Clean status bar with repo:branch info"))
+	b.WriteString("\n")
 	b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render("  • Shift+arrow keys for window navigation"))
 	b.WriteString("\n\n")
 
@@ -3079,3 +3911,587 @@ func (m Model) renderConfigEditorModal() string {
 		content,
 	)
 }
+
+func (m Model) renderBulkActionModal() string {
+	var b strings.Builder
+
+	marked := m.markedWorktreeList()
+
+	switch m.bulkStage {
+	case 0:
+		b.WriteString(modalTitleStyle.Render(fmt.Sprintf("Bulk Actions (%d worktree%s marked)", len(marked), pluralize(len(marked)))))
+		b.WriteString("\n\n")
+
+		for i, action := range bulkActions {
+			if i == m.bulkActionIndex {
+				b.WriteString(selectedItemStyle.Render("▶ " + action.name))
+			} else {
+				b.WriteString(normalItemStyle.Render("  " + action.name))
+			}
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("    " + action.description))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("↑↓ navigate • enter select • esc cancel"))
+
+	case 1, 2:
+		name := m.bulkAction
+		for _, action := range bulkActions {
+			if action.id == m.bulkAction {
+				name = action.name
+			}
+		}
+		b.WriteString(modalTitleStyle.Render("Confirm: " + name))
+		b.WriteString("\n\n")
+
+		willRun := 0
+		for _, wt := range marked {
+			reason := m.bulkSkipReason(m.bulkAction, wt)
+			line := fmt.Sprintf("  %-30s", truncateString(wt.Branch, 30))
+			if reason != "" {
+				b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(line))
+				b.WriteString(normalItemStyle.Copy().Foreground(warningColor).Render(" skip: " + reason))
+			} else {
+				willRun++
+				b.WriteString(normalItemStyle.Render(line))
+				if m.bulkAction == "delete" && wt.HasUncommitted {
+					b.WriteString(normalItemStyle.Copy().Foreground(errorColor).Render(" force delete (uncommitted changes will be lost)"))
+				}
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		if m.bulkStage == 2 {
+			b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render(fmt.Sprintf("Running on %d worktree%s...", willRun, pluralize(willRun))))
+			break
+		}
+
+		b.WriteString(normalItemStyle.Render(fmt.Sprintf("%d of %d worktree%s will be processed", willRun, len(marked), pluralize(len(marked)))))
+		b.WriteString("\n\n")
+		help := "enter/y confirm • esc back"
+		if m.bulkAction == "delete" {
+			force := "off"
+			if m.bulkForce {
+				force = "on"
+			}
+			help = fmt.Sprintf("enter/y confirm • f force (%s) • esc back", force)
+		}
+		b.WriteString(helpStyle.Render(help))
+
+	case 3:
+		b.WriteString(modalTitleStyle.Render("Bulk Action Results"))
+		b.WriteString("\n\n")
+
+		for _, result := range m.bulkResults {
+			icon, text, color := "✓", "done", successColor
+			if result.skipped != "" {
+				icon, text, color = "·", "skipped: "+result.skipped, mutedColor
+			} else if result.err != nil {
+				// Show only the first line of the error to keep the list compact
+				reason := strings.TrimSpace(result.err.Error())
+				if first, _, found := strings.Cut(reason, "\n"); found {
+					reason = first
+				}
+				icon, text, color = "✗", truncateString(reason, max(20, m.width-44)), errorColor
+			}
+			b.WriteString(normalItemStyle.Render(fmt.Sprintf("%s %-30s", icon, truncateString(result.branch, 30))))
+			b.WriteString(normalItemStyle.Copy().Foreground(color).Render(" " + text))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("Failed and skipped worktrees stay marked • esc close"))
+	}
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// renderGCModal renders the garbage found by the dry run, then the cleanup results
+func (m Model) renderGCModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Clean Up Repository"))
+	b.WriteString("\n\n")
+
+	switch m.gcStage {
+	case 0:
+		b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render("Looking for orphan sessions, stale worktrees and merged branches..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc cancel"))
+
+	case 1, 2:
+		if m.gcErr != nil {
+			b.WriteString(normalItemStyle.Copy().Foreground(errorColor).Render("Failed to scan: " + m.gcErr.Error()))
+			b.WriteString("\n\n")
+			b.WriteString(helpStyle.Render("esc close"))
+			break
+		}
+		if len(m.gcItems) == 0 {
+			b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render("✓ Nothing to clean up"))
+			b.WriteString("\n\n")
+			b.WriteString(helpStyle.Render("esc close"))
+			break
+		}
+
+		for _, item := range m.gcItems {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(fmt.Sprintf("  %-9s", item.Kind)))
+			b.WriteString(normalItemStyle.Render(fmt.Sprintf("%-30s", truncateString(item.Name, 30))))
+			b.WriteString(helpStyle.Render(" " + truncateString(item.Reason, max(20, m.width-52))))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		if m.gcStage == 2 {
+			b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Render(fmt.Sprintf("Cleaning %d item%s...", len(m.gcItems), pluralize(len(m.gcItems)))))
+			break
+		}
+		b.WriteString(normalItemStyle.Render(fmt.Sprintf("%d item%s will be removed (dry run, nothing changed yet)", len(m.gcItems), pluralize(len(m.gcItems)))))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter/y clean up • esc cancel"))
+
+	case 3:
+		for _, result := range m.gcResults {
+			icon, text, color := "✓", "removed", successColor
+			if result.Err != nil {
+				// Show only the first line of the error to keep the list compact
+				reason := strings.TrimSpace(result.Err.Error())
+				if first, _, found := strings.Cut(reason, "\n"); found {
+					reason = first
+				}
+				icon, text, color = "✗", truncateString(reason, max(20, m.width-54)), errorColor
+			}
+			b.WriteString(normalItemStyle.Render(fmt.Sprintf("%s %-9s%-30s", icon, result.Item.Kind, truncateString(result.Item.Name, 30))))
+			b.WriteString(normalItemStyle.Copy().Foreground(color).Render(" " + text))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("esc close"))
+	}
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// renderSendPromptModal renders the prompt editor for sending text to agent sessions
+func (m Model) renderSendPromptModal() string {
+	var b strings.Builder
+
+	b.WriteString(modalTitleStyle.Render("Send Prompt to Agent"))
+	b.WriteString("\n\n")
+
+	// Target worktrees, with a note for the ones without a running session
+	b.WriteString(fmt.Sprintf("To %d worktree%s:\n", len(m.promptTargets), pluralize(len(m.promptTargets))))
+	for _, wt := range m.promptTargets {
+		line := "  " + truncateString(wt.Branch, 40)
+		if !m.sessionRunning(wt.ClaudeSessionName) {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(line))
+			b.WriteString(normalItemStyle.Copy().Foreground(warningColor).Render(" no running session, will be skipped"))
+		} else {
+			b.WriteString(normalItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	m.promptInput.SetWidth(max(20, m.width-12))
+	b.WriteString(m.promptInput.View())
+	b.WriteString("\n\n")
+
+	b.WriteString(helpStyle.Render("enter send • alt+enter new line • esc cancel"))
+
+	// Center the modal
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// renderPRReviewModal renders the review threads of a PR: a list of threads,
+// then the diff context and comments of the selected one
+func (m Model) renderPRReviewModal() string {
+	var b strings.Builder
+	width := max(30, m.width-10)
+
+	title := fmt.Sprintf("Review Threads · PR #%d", m.reviewPR.PRNumber)
+	if m.reviewPR.Title != "" {
+		title += " " + m.reviewPR.Title
+	}
+	b.WriteString(modalTitleStyle.Render(truncateString(title, width)))
+	b.WriteString("\n\n")
+
+	threads := m.visibleReviewThreads()
+	resolvedCount := 0
+	for _, thread := range m.reviewThreads {
+		if thread.IsResolved {
+			resolvedCount++
+		}
+	}
+
+	switch {
+	case m.reviewLoading && m.reviewThreads == nil:
+		b.WriteString(normalItemStyle.Render("Loading review threads..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc close"))
+	case m.reviewErr != nil:
+		b.WriteString(errorStyle.Render(truncateString(m.reviewErr.Error(), width)))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("R retry • esc close"))
+	case len(threads) == 0:
+		text := "No review threads"
+		if resolvedCount > 0 {
+			text = fmt.Sprintf("No unresolved threads (%d resolved, press h to show them)", resolvedCount)
+		}
+		b.WriteString(normalItemStyle.Copy().Foreground(successColor).Render(text))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("h show resolved • R reload • esc close"))
+	default:
+		// Thread list, scrolled to keep the selection visible
+		maxVisible := 6
+		start := max(0, min(m.reviewIndex-maxVisible/2, len(threads)-maxVisible))
+		end := min(start+maxVisible, len(threads))
+		for i := start; i < end; i++ {
+			thread := threads[i]
+			icon, color := "●", warningColor
+			if thread.IsResolved {
+				icon, color = "✓", successColor
+			}
+			summary := thread.Location()
+			if len(thread.Comments) > 0 {
+				first := thread.Comments[0]
+				summary += "  " + first.Author + ": " + strings.Join(strings.Fields(first.Body), " ")
+			}
+			if replies := len(thread.Comments) - 1; replies > 0 {
+				summary += fmt.Sprintf(" (+%d)", replies)
+			}
+
+			style := normalItemStyle
+			prefix := "  "
+			if i == m.reviewIndex {
+				style = selectedItemStyle
+				prefix = "› "
+			}
+			b.WriteString(style.Render(prefix))
+			b.WriteString(normalItemStyle.Copy().Foreground(color).Render(icon + " "))
+			b.WriteString(style.Render(truncateString(summary, width-6)))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%d of %d threads (%d resolved)", m.reviewIndex+1, len(threads), resolvedCount)))
+		b.WriteString("\n\n")
+
+		if thread := m.selectedReviewThread(); thread != nil {
+			b.WriteString(m.renderReviewThread(*thread, width))
+		}
+
+		if m.reviewReplying {
+			m.reviewReplyInput.SetWidth(width)
+			b.WriteString("\n")
+			b.WriteString(m.reviewReplyInput.View())
+			b.WriteString("\n\n")
+			b.WriteString(helpStyle.Render("enter send • alt+enter new line • esc cancel"))
+		} else {
+			b.WriteString("\n")
+			b.WriteString(helpStyle.Render("↑↓ thread • r reply • x resolve/unresolve • a send to agent • h show resolved • R reload • esc close"))
+		}
+	}
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// ciStateIcon returns the icon and color of a run or job state
+func ciStateIcon(state string) (string, lipgloss.Color) {
+	switch state {
+	case "success":
+		return "✓", successColor
+	case "failure":
+		return "✗", errorColor
+	case "running":
+		return "↻", warningColor
+	case "cancelled":
+		return "⊘", mutedColor
+	case "skipped":
+		return "–", mutedColor
+	default:
+		return "◌", mutedColor
+	}
+}
+
+func (m Model) renderCIModal() string {
+	if m.ciLogJob != nil {
+		return m.renderCILog()
+	}
+
+	var b strings.Builder
+	width := max(30, m.width-10)
+	now := time.Now()
+
+	b.WriteString(modalTitleStyle.Render(truncateString("CI Runs · "+m.ciWorktree.Branch, width)))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.ciLoading && m.ciRuns == nil:
+		b.WriteString(normalItemStyle.Render("Loading workflow runs..."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("esc close"))
+	case m.ciErr != nil:
+		b.WriteString(errorStyle.Render(truncateString(m.ciErr.Error(), width)))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("R retry • esc close"))
+	case len(m.ciRuns) == 0:
+		b.WriteString(normalItemStyle.Render("No workflow runs for this branch (push it to run CI)"))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("R reload • esc close"))
+	default:
+		// Run list, scrolled to keep the selection visible
+		maxVisible := 6
+		start := max(0, min(m.ciRunIndex-maxVisible/2, len(m.ciRuns)-maxVisible))
+		end := min(start+maxVisible, len(m.ciRuns))
+		for i := start; i < end; i++ {
+			run := m.ciRuns[i]
+			icon, color := ciStateIcon(run.State())
+			summary := run.Name + "  " + run.Title
+			if duration := formatCIDuration(run.Duration(now)); duration != "" {
+				summary += "  " + duration
+			}
+			if run.Attempt > 1 {
+				summary += fmt.Sprintf("  (attempt %d)", run.Attempt)
+			}
+
+			style := normalItemStyle
+			prefix := "  "
+			if i == m.ciRunIndex && !m.ciFocusJobs {
+				style = selectedItemStyle
+				prefix = "› "
+			}
+			b.WriteString(style.Render(prefix))
+			b.WriteString(normalItemStyle.Copy().Foreground(color).Render(icon + " "))
+			b.WriteString(style.Render(truncateString(summary, width-6)))
+			b.WriteString("\n")
+		}
+		status := fmt.Sprintf("%d of %d runs", m.ciRunIndex+1, len(m.ciRuns))
+		if m.ciActive() {
+			status += " • refreshing every " + ciPollInterval.String()
+		}
+		b.WriteString(helpStyle.Render(status))
+		b.WriteString("\n\n")
+
+		// Jobs of the selected run, with the step failed jobs stopped at
+		b.WriteString(detailKeyStyle.Render("Jobs"))
+		b.WriteString("\n")
+		if len(m.ciJobs) == 0 {
+			b.WriteString(helpStyle.Render("  Loading jobs..."))
+			b.WriteString("\n")
+		}
+		for i, job := range m.ciJobs {
+			icon, color := ciStateIcon(job.State())
+			summary := job.Name
+			if duration := formatCIDuration(job.Duration(now)); duration != "" {
+				summary += "  " + duration
+			}
+			if step := job.FailedStep(); step != "" {
+				summary += "  failed at: " + step
+			}
+
+			style := normalItemStyle
+			prefix := "  "
+			if i == m.ciJobIndex && m.ciFocusJobs {
+				style = selectedItemStyle
+				prefix = "› "
+			}
+			b.WriteString(style.Render(prefix))
+			b.WriteString(normalItemStyle.Copy().Foreground(color).Render(icon + " "))
+			b.WriteString(style.Render(truncateString(summary, width-6)))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("↑↓ select • tab runs/jobs • enter log • a send failure to agent • r re-run failed • o open • R reload • esc close"))
+	}
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// renderCILog renders the end of a job's log, scrolled up by ciLogScroll lines
+func (m Model) renderCILog() string {
+	var b strings.Builder
+	width := max(30, m.width-10)
+	job := m.ciLogJob
+
+	icon, color := ciStateIcon(job.State())
+	b.WriteString(normalItemStyle.Copy().Foreground(color).Render(icon + " "))
+	b.WriteString(modalTitleStyle.Render(truncateString("Log · "+job.Name, width-2)))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.ciLogErr != nil:
+		b.WriteString(errorStyle.Render(truncateString(m.ciLogErr.Error(), width)))
+		b.WriteString("\n")
+	case m.ciLogLines == nil:
+		b.WriteString(normalItemStyle.Render("Loading log..."))
+		b.WriteString("\n")
+	default:
+		maxLines := max(5, m.height-12)
+		end := len(m.ciLogLines) - min(m.ciLogScroll, len(m.ciLogLines))
+		start := max(0, end-maxLines)
+		for _, line := range m.ciLogLines[start:end] {
+			style := normalItemStyle
+			switch {
+			case strings.HasPrefix(line, "Error: "):
+				style = errorStyle
+			case strings.HasPrefix(line, "Warning: "):
+				style = normalItemStyle.Copy().Foreground(warningColor)
+			case strings.HasPrefix(line, "▸ "):
+				style = normalItemStyle.Copy().Foreground(mutedColor)
+			}
+			b.WriteString(style.Render(truncateString(line, width)))
+			b.WriteString("\n")
+		}
+		b.WriteString(helpStyle.Render(fmt.Sprintf("Lines %d-%d of %d", start+1, end, len(m.ciLogLines))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑↓ scroll • g/G start/end • a send to agent • R reload • esc back"))
+
+	content := modalStyle.Width(m.width - 4).Render(b.String())
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// reviewHunkLines is the number of diff lines shown above the comments of a review thread
+const reviewHunkLines = 6
+
+// renderReviewThread renders the diff context and the comments of a review thread
+func (m Model) renderReviewThread(thread github.ReviewThread, width int) string {
+	var b strings.Builder
+
+	header := thread.Location()
+	if thread.IsOutdated {
+		header += " (outdated)"
+	}
+	b.WriteString(detailKeyStyle.Render(header))
+	b.WriteString("\n")
+
+	// The diff context ends at the commented line, show its last lines
+	if hunk := strings.TrimRight(thread.DiffHunk, "\n"); hunk != "" {
+		lines := strings.Split(hunk, "\n")
+		if len(lines) > reviewHunkLines {
+			lines = lines[len(lines)-reviewHunkLines:]
+		}
+		for _, line := range lines {
+			color := mutedColor
+			switch {
+			case strings.HasPrefix(line, "+"):
+				color = successColor
+			case strings.HasPrefix(line, "-"):
+				color = errorColor
+			}
+			b.WriteString(normalItemStyle.Copy().Foreground(color).Render("  " + truncateString(line, width-4)))
+			b.WriteString("\n")
+		}
+	}
+
+	bodyStyle := normalItemStyle.Copy().Width(width - 4)
+	for _, comment := range thread.Comments {
+		b.WriteString("\n")
+		b.WriteString(normalItemStyle.Copy().Foreground(accentColor).Bold(true).Render("  " + comment.Author))
+		if !comment.CreatedAt.IsZero() {
+			b.WriteString(normalItemStyle.Copy().Foreground(mutedColor).Render(" · " + comment.CreatedAt.Local().Format("2006-01-02 15:04")))
+		}
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().PaddingLeft(4).Render(bodyStyle.Render(strings.TrimSpace(comment.Body))))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// prBadge is a PR state badge, short for the worktree list and long for the details panel
+type prBadge struct {
+	short string
+	long  string
+	color lipgloss.Color
+}
+
+// isOpenPR reports whether a PR is still open (merged and closed PRs need no attention)
+func isOpenPR(pr config.PRInfo) bool {
+	return pr.Status == "open" || pr.Status == "draft"
+}
+
+// prBadges returns the badges of an open PR, the ones needing attention first
+func prBadges(pr config.PRInfo) []prBadge {
+	if !isOpenPR(pr) {
+		return nil
+	}
+
+	var badges []prBadge
+	if pr.Mergeable == "conflicting" {
+		badges = append(badges, prBadge{"⚠ conflict", "⚠ Merge conflicts", errorColor})
+	}
+	switch pr.Checks {
+	case "failing":
+		badges = append(badges, prBadge{"✗ ci", "✗ Checks failing", errorColor})
+	case "pending":
+		badges = append(badges, prBadge{"◌ ci", "◌ Checks running", warningColor})
+	case "passing":
+		badges = append(badges, prBadge{"✓ ci", "✓ Checks passing", successColor})
+	}
+	switch pr.ReviewDecision {
+	case "changes_requested":
+		badges = append(badges, prBadge{"✎ changes", "✎ Changes requested", errorColor})
+	case "review_required":
+		badges = append(badges, prBadge{"◌ review", "◌ Review required", warningColor})
+	case "approved":
+		badges = append(badges, prBadge{"✔ approved", "✔ Approved", successColor})
+	}
+	if pr.UnresolvedThreads > 0 {
+		badges = append(badges, prBadge{fmt.Sprintf("💬%d", pr.UnresolvedThreads), fmt.Sprintf("💬 %d unresolved thread%s", pr.UnresolvedThreads, pluralize(pr.UnresolvedThreads)), warningColor})
+	}
+	if pr.Draft {
+		badges = append(badges, prBadge{"draft", "Draft", mutedColor})
+	}
+	return badges
+}
+
+// agentStateDisplay returns the icon, label and color for an agent state ("" icon = unknown state)
+func agentStateDisplay(state string) (string, string, lipgloss.Color) {
+	switch state {
+	case session.AgentWorking:
+		return "↻", "working", accentColor
+	case session.AgentWaiting:
+		return "?", "needs input", warningColor
+	case session.AgentIdle:
+		return "✓", "idle", successColor
+	case session.AgentErrored:
+		return "✗", "errored", errorColor
+	}
+	return "", "", mutedColor
+}